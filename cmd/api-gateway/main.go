@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/gin-gonic/gin"
+	"github.com/moverq1337/VTBHack/internal/compare"
 	"github.com/moverq1337/VTBHack/internal/config"
 	"github.com/moverq1337/VTBHack/internal/db"
 	"github.com/moverq1337/VTBHack/internal/handlers"
+	"github.com/moverq1337/VTBHack/internal/lint"
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/moverq1337/VTBHack/internal/mobility"
+	"github.com/moverq1337/VTBHack/internal/nlp"
+	"github.com/moverq1337/VTBHack/internal/talent"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"github.com/moverq1337/VTBHack/internal/vectors"
 	"github.com/moverq1337/VTBHack/scripts"
 )
 
@@ -23,13 +32,45 @@ func main() {
 		log.Fatal(err)
 	}
 
+	nlpClient, err := nlp.NewClient(nlp.ConfigFrom(cfg))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer nlpClient.Close()
+
+	skills, err := taxonomy.NewStore(dbConn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Правки таксономии через resume-service подхватываются сверкой с БД
+	go skills.Watch(context.Background(), cfg.TaxonomySyncInterval)
+
+	// Связи навыков по совместной встречаемости пересчитываются при старте в фоне
+	go func() {
+		if count, err := skills.MineRelations(); err != nil {
+			log.Printf("Ошибка пересчёта связей навыков: %v", err)
+		} else {
+			log.Printf("Связей навыков по встречаемости: %d", count)
+		}
+	}()
+
+	// Индексы поиска похожих: из файлов с догрузкой из БД или заново из БД
+	embeddings, err := vectors.Open(dbConn, nlpClient, cfg.VectorIndexDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go embeddings.Run(context.Background(), cfg.VectorSyncInterval)
+
+	// Кластеры базы кандидатов пересчитываются по расписанию только здесь
+	go talent.Schedule(context.Background(), dbConn, skills, cfg.ClusterInterval)
+
 	r := gin.Default()
 
 	// Настройка CORS для фронтенда
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-HR-Key")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -40,7 +81,7 @@ func main() {
 	})
 
 	// Настройка маршрутов API Gateway
-	handlers.SetupRoutes(r, dbConn)
+	handlers.SetupRoutes(r, dbConn, nlpClient, skills, embeddings, matching.OptionsFrom(cfg), lint.OptionsFrom(cfg), compare.OptionsFrom(cfg), mobility.OptionsFrom(cfg))
 
 	log.Printf("API Gateway запущен на порту %s", cfg.HTTPPort)
 	if err := r.Run(cfg.HTTPPort); err != nil {
@@ -24,6 +24,9 @@ logging.basicConfig(
 )
 logger = logging.getLogger(__name__)
 
+# Куда отправлять итоги интервью, чтобы они попали в сравнение кандидатов
+RESULTS_URL = os.getenv('INTERVIEW_RESULTS_URL', 'http://api-gateway:8080/api/v1/interviews')
+
 class InterviewManager:
     def __init__(self):
         self.sessions: Dict[str, dict] = {}
@@ -209,6 +212,32 @@ class InterviewManager:
             logger.error(f"Исключение в Yandex STT: {e}")
             return "Ошибка распознавания речи", 0.0
 
+async def publish_results(session_id: str, results: dict):
+    """Сохраняет итог интервью в API; ошибка не мешает кандидату увидеть результат"""
+    if not RESULTS_URL:
+        return
+    payload = {
+        'session_id': session_id,
+        'candidate_id': results['candidate_id'],
+        'vacancy_id': results['vacancy_id'],
+        'answers': [
+            {'question': a['question'], 'answer': a['answer'], 'score': a['score']}
+            for a in results['answers']
+        ],
+        'duration': results['duration'],
+        'started_at': datetime.fromisoformat(results['start_time']).astimezone().isoformat(),
+        'completed_at': datetime.fromisoformat(results['end_time']).astimezone().isoformat(),
+    }
+    try:
+        async with aiohttp.ClientSession() as session:
+            async with session.post(RESULTS_URL, json=payload, timeout=aiohttp.ClientTimeout(total=10)) as response:
+                if response.status >= 300:
+                    logger.error(f"Итог интервью {session_id} не сохранён: {response.status}, {await response.text()}")
+                    return
+        logger.info(f"Итог интервью {session_id} сохранён")
+    except Exception as e:
+        logger.error(f"Ошибка сохранения итога интервью {session_id}: {e}")
+
 interview_manager = InterviewManager()
 
 async def handle_interview(websocket):
@@ -306,6 +335,7 @@ async def handle_interview(websocket):
                             'duration': results['duration']
                         }))
                         logger.info(f"Интервью завершено для сессии {session_id}")
+                        await publish_results(session_id, results)
 
                 except Exception as e:
                     logger.error(f"Ошибка обработки аудио: {e}")
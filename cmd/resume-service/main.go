@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/gin-gonic/gin"
+	"github.com/moverq1337/VTBHack/internal/compare"
 	"github.com/moverq1337/VTBHack/internal/config"
 	"github.com/moverq1337/VTBHack/internal/db"
 	"github.com/moverq1337/VTBHack/internal/handlers"
+	"github.com/moverq1337/VTBHack/internal/lint"
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/moverq1337/VTBHack/internal/mobility"
+	"github.com/moverq1337/VTBHack/internal/nlp"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"github.com/moverq1337/VTBHack/internal/vectors"
+	"github.com/moverq1337/VTBHack/scripts"
 )
 
 func main() {
@@ -15,18 +24,42 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Таксономия и индексы похожих читают таблицы при старте, поэтому схема
+	// нужна до них, даже если api-gateway ещё не запущен
+	scripts.Migrate()
+
 	dbConn, err := db.Connect(cfg.DBURL)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	nlpClient, err := nlp.NewClient(nlp.ConfigFrom(cfg))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer nlpClient.Close()
+
+	skills, err := taxonomy.NewStore(dbConn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Правки таксономии и связи, пересчитанные API Gateway, подхватываются сверкой с БД
+	go skills.Watch(context.Background(), cfg.TaxonomySyncInterval)
+
+	// Индексы поиска похожих: из файлов с догрузкой из БД или заново из БД
+	embeddings, err := vectors.Open(dbConn, nlpClient, cfg.VectorIndexDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go embeddings.Run(context.Background(), cfg.VectorSyncInterval)
+
 	r := gin.Default()
 
 	// Настройка CORS
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-HR-Key")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -37,7 +70,7 @@ func main() {
 	})
 
 	// Настройка маршрутов для Resume Service
-	handlers.SetupResumeRoutes(r, dbConn)
+	handlers.SetupResumeRoutes(r, dbConn, nlpClient, skills, embeddings, matching.OptionsFrom(cfg), lint.OptionsFrom(cfg), compare.OptionsFrom(cfg), mobility.OptionsFrom(cfg))
 
 	log.Printf("Resume Service запущен на порту %s", cfg.HTTPPort)
 	if err := r.Run(cfg.HTTPPort); err != nil {
@@ -4,6 +4,7 @@ from concurrent import futures
 import time
 import nlp_pb2
 import nlp_pb2_grpc
+from grpc_health.v1 import health, health_pb2, health_pb2_grpc
 import json
 import spacy
 import re
@@ -29,7 +30,8 @@ logger = logging.getLogger(__name__)
 logger.info("Загрузка моделей NLP...")
 try:
     nlp = spacy.load("ru_core_news_sm")
-    sentence_model = SentenceTransformer('paraphrase-multilingual-MiniLM-L12-v2')
+    SENTENCE_MODEL_NAME = 'paraphrase-multilingual-MiniLM-L12-v2'
+    sentence_model = SentenceTransformer(SENTENCE_MODEL_NAME)
     logger.info("Модели успешно загружены")
 except Exception as e:
     logger.error(f"Ошибка загрузки моделей: {e}")
@@ -74,7 +76,7 @@ class NLPService(nlp_pb2_grpc.NLPServiceServicer):
                         continue
 
                 if total_experience > 0:
-                    max_experience = total_experience / len(dates)  # Средний опыт
+                    max_experience = total_experience  # Суммарный стаж; точная история строится в Go
 
         logger.info(f"Найден опыт: {max_experience} лет")
         return max_experience
@@ -146,21 +148,40 @@ class NLPService(nlp_pb2_grpc.NLPServiceServicer):
         logger.info(f"Найдено образование: {result}")
         return result
 
+    def section_text(self, sections, include=None, exclude=None):
+        """Текст разделов указанных типов или всех, кроме исключённых"""
+        parts = [
+            s.text for s in sections
+            if (include is None or s.kind in include) and (exclude is None or s.kind not in exclude)
+        ]
+        return "\n\n".join(parts).strip()
+
     def ParseResume(self, request, context):
         """Парсинг резюме и извлечение структурированных данных"""
         logger.info(f"Начало парсинга резюме, длина текста: {len(request.text)} символов")
 
         text = request.text
 
+        # Разделы резюме размечает Go-сервис; без них разбираем текст целиком
+        skills_text = experience_text = education_text = languages_text = text
+        course_skills = None
+        if request.sections:
+            skills_text = self.section_text(request.sections, exclude=('courses', 'education'))
+            experience_text = self.section_text(request.sections, include=('experience',)) or text
+            education_text = self.section_text(request.sections, include=('education', 'courses')) or text
+            languages_text = self.section_text(request.sections, include=('languages',)) or text
+
         try:
             # Извлечение опыта работы
-            experience = self.extract_experience(text)
+            experience = self.extract_experience(experience_text)
 
-            # Извлечение навыков
-            skills = self.extract_skills(text)
+            # Извлечение навыков; навыки из курсов не считаются рабочими
+            skills = self.extract_skills(skills_text)
+            if request.sections:
+                course_skills = self.extract_skills(self.section_text(request.sections, include=('courses',)))
 
             # Извлечение образования
-            education = self.extract_education(text)
+            education = self.extract_education(education_text)
 
             # Извлечение языков
             languages = ['Русский']  # По умолчанию
@@ -173,7 +194,7 @@ class NLPService(nlp_pb2_grpc.NLPServiceServicer):
             }
 
             for lang, pattern in lang_patterns.items():
-                if re.search(pattern, text, re.IGNORECASE):
+                if re.search(pattern, languages_text, re.IGNORECASE):
                     languages.append(lang)
 
             parsed_data = {
@@ -182,6 +203,8 @@ class NLPService(nlp_pb2_grpc.NLPServiceServicer):
                 "education": education,
                 "languages": languages
             }
+            if course_skills is not None:
+                parsed_data["course_skills"] = course_skills
 
             logger.info(f"Результаты парсинга: {parsed_data}")
             return nlp_pb2.ParseResponse(parsed_data=json.dumps(parsed_data, ensure_ascii=False))
@@ -196,70 +219,157 @@ class NLPService(nlp_pb2_grpc.NLPServiceServicer):
                 "languages": ["Русский"]
             }, ensure_ascii=False))
 
-    def MatchResumeVacancy(self, request, context):
-        """Сопоставление резюме с вакансией с улучшенным анализом"""
-        logger.info(f"Сопоставление резюме с вакансией, длина текстов: {len(request.resume_text)}/{len(request.vacancy_text)}")
+    def score_pair(self, resume_text, vacancy_text, resume_embedding, vacancy_embedding):
+        """Комбинированная оценка пары по готовым эмбеддингам"""
+        base_score = cosine_similarity([resume_embedding], [vacancy_embedding])[0][0]
 
-        resume_text = request.resume_text
-        vacancy_text = request.vacancy_text
+        # Проверка соответствия навыков
+        resume_skills = self.extract_skills(resume_text)
+        vacancy_skills = self.extract_skills(vacancy_text)
 
-        try:
-            # Базовое сравнение на основе эмбеддингов
-            resume_embedding = sentence_model.encode([resume_text])
-            vacancy_embedding = sentence_model.encode([vacancy_text])
-            base_score = cosine_similarity(resume_embedding, vacancy_embedding)[0][0]
+        # Считаем совпадение навыков
+        matched_skills = 0
+        total_skills = 0
 
-            # Дополнительные факторы для улучшения оценки
-            additional_score = 0
+        for category, skills in vacancy_skills.items():
+            for skill in skills:
+                total_skills += 1
+                if any(s in str(resume_skills.values()).lower() for s in skill.lower().split()):
+                    matched_skills += 1
 
-            # Проверка соответствия навыков
-            resume_skills = self.extract_skills(resume_text)
-            vacancy_skills = self.extract_skills(vacancy_text)
+        skill_match_ratio = matched_skills / total_skills if total_skills > 0 else 0
 
-            # Считаем совпадение навыков
-            matched_skills = 0
-            total_skills = 0
+        # Проверка соответствия опыта
+        resume_exp = self.extract_experience(resume_text)
+        vacancy_exp = self.extract_experience(vacancy_text)
 
-            for category, skills in vacancy_skills.items():
-                for skill in skills:
-                    total_skills += 1
-                    if any(s in str(resume_skills.values()).lower() for s in skill.lower().split()):
-                        matched_skills += 1
+        exp_match = 1 if resume_exp >= vacancy_exp else resume_exp / vacancy_exp
 
-            skill_match_ratio = matched_skills / total_skills if total_skills > 0 else 0
+        # Комбинированная оценка
+        final_score = 0.5 * base_score + 0.3 * skill_match_ratio + 0.2 * exp_match
+        final_score = max(0, min(1, final_score))  # Нормализуем от 0 до 1
 
-            # Проверка соответствия опыта
-            resume_exp = self.extract_experience(resume_text)
-            vacancy_exp = self.extract_experience(vacancy_text)
+        logger.info(f"Базовый score: {base_score:.2f}, Совпадение навыков: {skill_match_ratio:.2f}, Совпадение опыта: {exp_match:.2f}")
+        logger.info(f"Итоговый score: {final_score:.2f}")
 
-            exp_match = 1 if resume_exp >= vacancy_exp else resume_exp / vacancy_exp
+        return float(final_score)
 
-            # Комбинированная оценка
-            final_score = 0.5 * base_score + 0.3 * skill_match_ratio + 0.2 * exp_match
-            final_score = max(0, min(1, final_score))  # Нормализуем от 0 до 1
+    def MatchResumeVacancy(self, request, context):
+        """Сопоставление резюме с вакансией с улучшенным анализом"""
+        logger.info(f"Сопоставление резюме с вакансией, длина текстов: {len(request.resume_text)}/{len(request.vacancy_text)}")
 
-            logger.info(f"Базовый score: {base_score:.2f}, Совпадение навыков: {skill_match_ratio:.2f}, Совпадение опыта: {exp_match:.2f}")
-            logger.info(f"Итоговый score: {final_score:.2f}")
+        resume_text = request.resume_text
+        vacancy_text = request.vacancy_text
 
+        try:
+            embeddings = sentence_model.encode([resume_text, vacancy_text])
+            final_score = self.score_pair(resume_text, vacancy_text, embeddings[0], embeddings[1])
             return nlp_pb2.MatchResponse(score=final_score)
 
         except Exception as e:
             logger.error(f"Ошибка при сопоставлении: {e}")
             return nlp_pb2.MatchResponse(score=0.0)
 
+    def BatchMatch(self, request, context):
+        """Пакетное сопоставление одной вакансии с несколькими резюме"""
+        logger.info(f"Пакетное сопоставление: вакансия {request.vacancy_id}, резюме: {len(request.resumes)}")
+
+        results = []
+        try:
+            # Эмбеддинги считаются одним батчем: вакансия первой, затем все резюме
+            embeddings = sentence_model.encode([request.vacancy_text] + [r.text for r in request.resumes])
+        except Exception as e:
+            logger.error(f"Ошибка вычисления эмбеддингов: {e}")
+            for r in request.resumes:
+                results.append(nlp_pb2.MatchResult(resume_id=r.id, vacancy_id=request.vacancy_id, error=str(e)))
+            return nlp_pb2.BatchMatchResponse(results=results)
+
+        for i, r in enumerate(request.resumes):
+            result = nlp_pb2.MatchResult(resume_id=r.id, vacancy_id=request.vacancy_id)
+            try:
+                result.score = self.score_pair(r.text, request.vacancy_text, embeddings[i + 1], embeddings[0])
+            except Exception as e:
+                logger.error(f"Ошибка сопоставления резюме {r.id}: {e}")
+                result.error = str(e)
+            results.append(result)
+
+        return nlp_pb2.BatchMatchResponse(results=results)
+
+    def StreamMatch(self, request_iterator, context):
+        """Потоковое сопоставление пар резюме-вакансия"""
+        # Тексты и эмбеддинги кэшируются в пределах одного потока,
+        # клиент передаёт текст только при первом упоминании идентификатора
+        texts = {}
+        embeddings = {}
+
+        def embedding_for(kind, item_id, text):
+            key = (kind, item_id)
+            if text:
+                texts[key] = text
+            if key not in texts:
+                raise ValueError(f"текст для {kind} {item_id} не передан")
+            if key not in embeddings:
+                embeddings[key] = sentence_model.encode([texts[key]])[0]
+            return texts[key], embeddings[key]
+
+        for item in request_iterator:
+            result = nlp_pb2.MatchResult(seq=item.seq, resume_id=item.resume_id, vacancy_id=item.vacancy_id)
+            try:
+                resume_text, resume_embedding = embedding_for('resume', item.resume_id, item.resume_text)
+                vacancy_text, vacancy_embedding = embedding_for('vacancy', item.vacancy_id, item.vacancy_text)
+                result.score = self.score_pair(resume_text, vacancy_text, resume_embedding, vacancy_embedding)
+            except Exception as e:
+                logger.error(f"Ошибка потокового сопоставления {item.resume_id}/{item.vacancy_id}: {e}")
+                result.error = str(e)
+            yield result
+
+    def Embed(self, request, context):
+        """Нормализованные векторы текстов для семантического поиска"""
+        logger.info(f"Вычисление векторов: {len(request.texts)}")
+        try:
+            vectors = sentence_model.encode(list(request.texts), normalize_embeddings=True)
+        except Exception as e:
+            logger.error(f"Ошибка вычисления векторов: {e}")
+            context.abort(grpc.StatusCode.INTERNAL, str(e))
+
+        return nlp_pb2.EmbedResponse(
+            embeddings=[nlp_pb2.Embedding(values=v.tolist()) for v in vectors],
+            model=SENTENCE_MODEL_NAME,
+        )
+
 def serve():
     logger.info("Запуск gRPC сервера на порту 50051")
-    server = grpc.server(futures.ThreadPoolExecutor(max_workers=10))
+    # Разрешаем keepalive-пинги клиента Go (раз в 30 секунд, в том числе без активных вызовов)
+    server = grpc.server(
+        futures.ThreadPoolExecutor(max_workers=10),
+        options=[
+            ('grpc.keepalive_permit_without_calls', 1),
+            ('grpc.http2.min_recv_ping_interval_without_data_ms', 10000),
+            ('grpc.http2.max_pings_without_data', 0),
+        ]
+    )
     nlp_pb2_grpc.add_NLPServiceServicer_to_server(NLPService(), server)
+
+    # Стандартный протокол grpc.health.v1: клиенты исключают реплику
+    # из балансировки, пока она не в статусе SERVING
+    health_servicer = health.HealthServicer()
+    health_pb2_grpc.add_HealthServicer_to_server(health_servicer, server)
+
     server.add_insecure_port('[::]:50051')
     server.start()
+
+    # Модели загружаются при импорте, поэтому к этому моменту сервис готов
+    for service in ('', 'pb.NLPService'):
+        health_servicer.set(service, health_pb2.HealthCheckResponse.SERVING)
     logger.info("gRPC сервер успешно запущен")
 
     try:
         server.wait_for_termination()
     except KeyboardInterrupt:
         logger.info("Остановка сервера...")
-        server.stop(0)
+        for service in ('', 'pb.NLPService'):
+            health_servicer.set(service, health_pb2.HealthCheckResponse.NOT_SERVING)
+        server.stop(5)
 
 if __name__ == '__main__':
     serve()
\ No newline at end of file
@@ -14,7 +14,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\tnlp.proto\x12\x02pb\"\x1c\n\x0cParseRequest\x12\x0c\n\x04text\x18\x01 \x01(\t\"$\n\rParseResponse\x12\x13\n\x0bparsed_data\x18\x01 \x01(\t\"9\n\x0cMatchRequest\x12\x13\n\x0bresume_text\x18\x01 \x01(\t\x12\x14\n\x0cvacancy_text\x18\x02 \x01(\t\"\x1e\n\rMatchResponse\x12\r\n\x05score\x18\x01 \x01(\x02\x32{\n\nNLPService\x12\x32\n\x0bParseResume\x12\x10.pb.ParseRequest\x1a\x11.pb.ParseResponse\x12\x39\n\x12MatchResumeVacancy\x12\x10.pb.MatchRequest\x1a\x11.pb.MatchResponseB+Z)github.com/moverq1337/VTBHack/internal/pbb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\tnlp.proto\x12\x02pb\";\n\x0cParseRequest\x12\x0c\n\x04text\x18\x01 \x01(\t\x12\x1d\n\x08sections\x18\x02 \x03(\x0b\x32\x0b.pb.Section\"R\n\x07Section\x12\x0c\n\x04kind\x18\x01 \x01(\t\x12\x0f\n\x07heading\x18\x02 \x01(\t\x12\x0c\n\x04text\x18\x03 \x01(\t\x12\r\n\x05start\x18\x04 \x01(\x05\x12\x0b\n\x03\x65nd\x18\x05 \x01(\x05\"$\n\rParseResponse\x12\x13\n\x0bparsed_data\x18\x01 \x01(\t\"9\n\x0cMatchRequest\x12\x13\n\x0bresume_text\x18\x01 \x01(\t\x12\x14\n\x0cvacancy_text\x18\x02 \x01(\t\"\x1e\n\rMatchResponse\x12\r\n\x05score\x18\x01 \x01(\x02\"&\n\nResumeItem\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0c\n\x04text\x18\x02 \x01(\t\"^\n\x11\x42\x61tchMatchRequest\x12\x12\n\nvacancy_id\x18\x01 \x01(\t\x12\x14\n\x0cvacancy_text\x18\x02 \x01(\t\x12\x1f\n\x07resumes\x18\x03 \x03(\x0b\x32\x0e.pb.ResumeItem\"6\n\x12\x42\x61tchMatchResponse\x12 \n\x07results\x18\x01 \x03(\x0b\x32\x0f.pb.MatchResult\"j\n\tMatchItem\x12\x0b\n\x03seq\x18\x01 \x01(\x04\x12\x11\n\tresume_id\x18\x02 \x01(\t\x12\x13\n\x0bresume_text\x18\x03 \x01(\t\x12\x12\n\nvacancy_id\x18\x04 \x01(\t\x12\x14\n\x0cvacancy_text\x18\x05 \x01(\t\"_\n\x0bMatchResult\x12\x0b\n\x03seq\x18\x01 \x01(\x04\x12\x11\n\tresume_id\x18\x02 \x01(\t\x12\x12\n\nvacancy_id\x18\x03 \x01(\t\x12\r\n\x05score\x18\x04 \x01(\x02\x12\r\n\x05\x65rror\x18\x05 \x01(\t\"\x1d\n\x0c\x45mbedRequest\x12\r\n\x05texts\x18\x01 \x03(\t\"\x1b\n\tEmbedding\x12\x0e\n\x06values\x18\x01 \x03(\x02\"A\n\rEmbedResponse\x12!\n\nembeddings\x18\x01 \x03(\x0b\x32\r.pb.Embedding\x12\r\n\x05model\x18\x02 \x01(\t2\x99\x02\n\nNLPService\x12\x32\n\x0bParseResume\x12\x10.pb.ParseRequest\x1a\x11.pb.ParseResponse\x12\x39\n\x12MatchResumeVacancy\x12\x10.pb.MatchRequest\x1a\x11.pb.MatchResponse\x12;\n\nBatchMatch\x12\x15.pb.BatchMatchRequest\x1a\x16.pb.BatchMatchResponse\x12\x31\n\x0bStreamMatch\x12\r.pb.MatchItem\x1a\x0f.pb.MatchResult(\x01\x30\x01\x12,\n\x05\x45mbed\x12\x10.pb.EmbedRequest\x1a\x11.pb.EmbedResponseB+Z)github.com/moverq1337/VTBHack/internal/pbb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -23,13 +23,31 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'Z)github.com/moverq1337/VTBHack/internal/pb'
   _globals['_PARSEREQUEST']._serialized_start=17
-  _globals['_PARSEREQUEST']._serialized_end=45
-  _globals['_PARSERESPONSE']._serialized_start=47
-  _globals['_PARSERESPONSE']._serialized_end=83
-  _globals['_MATCHREQUEST']._serialized_start=85
-  _globals['_MATCHREQUEST']._serialized_end=142
-  _globals['_MATCHRESPONSE']._serialized_start=144
-  _globals['_MATCHRESPONSE']._serialized_end=174
-  _globals['_NLPSERVICE']._serialized_start=176
-  _globals['_NLPSERVICE']._serialized_end=299
+  _globals['_PARSEREQUEST']._serialized_end=76
+  _globals['_SECTION']._serialized_start=78
+  _globals['_SECTION']._serialized_end=160
+  _globals['_PARSERESPONSE']._serialized_start=162
+  _globals['_PARSERESPONSE']._serialized_end=198
+  _globals['_MATCHREQUEST']._serialized_start=200
+  _globals['_MATCHREQUEST']._serialized_end=257
+  _globals['_MATCHRESPONSE']._serialized_start=259
+  _globals['_MATCHRESPONSE']._serialized_end=289
+  _globals['_RESUMEITEM']._serialized_start=291
+  _globals['_RESUMEITEM']._serialized_end=329
+  _globals['_BATCHMATCHREQUEST']._serialized_start=331
+  _globals['_BATCHMATCHREQUEST']._serialized_end=425
+  _globals['_BATCHMATCHRESPONSE']._serialized_start=427
+  _globals['_BATCHMATCHRESPONSE']._serialized_end=481
+  _globals['_MATCHITEM']._serialized_start=483
+  _globals['_MATCHITEM']._serialized_end=589
+  _globals['_MATCHRESULT']._serialized_start=591
+  _globals['_MATCHRESULT']._serialized_end=686
+  _globals['_EMBEDREQUEST']._serialized_start=688
+  _globals['_EMBEDREQUEST']._serialized_end=717
+  _globals['_EMBEDDING']._serialized_start=719
+  _globals['_EMBEDDING']._serialized_end=746
+  _globals['_EMBEDRESPONSE']._serialized_start=748
+  _globals['_EMBEDRESPONSE']._serialized_end=813
+  _globals['_NLPSERVICE']._serialized_start=816
+  _globals['_NLPSERVICE']._serialized_end=1097
 # @@protoc_insertion_point(module_scope)
@@ -49,6 +49,21 @@ class NLPServiceStub(object):
                 request_serializer=nlp__pb2.MatchRequest.SerializeToString,
                 response_deserializer=nlp__pb2.MatchResponse.FromString,
                 _registered_method=True)
+        self.BatchMatch = channel.unary_unary(
+                '/pb.NLPService/BatchMatch',
+                request_serializer=nlp__pb2.BatchMatchRequest.SerializeToString,
+                response_deserializer=nlp__pb2.BatchMatchResponse.FromString,
+                _registered_method=True)
+        self.StreamMatch = channel.stream_stream(
+                '/pb.NLPService/StreamMatch',
+                request_serializer=nlp__pb2.MatchItem.SerializeToString,
+                response_deserializer=nlp__pb2.MatchResult.FromString,
+                _registered_method=True)
+        self.Embed = channel.unary_unary(
+                '/pb.NLPService/Embed',
+                request_serializer=nlp__pb2.EmbedRequest.SerializeToString,
+                response_deserializer=nlp__pb2.EmbedResponse.FromString,
+                _registered_method=True)
 
 
 class NLPServiceServicer(object):
@@ -61,7 +76,27 @@ class NLPServiceServicer(object):
         raise NotImplementedError('Method not implemented!')
 
     def MatchResumeVacancy(self, request, context):
-        """Новый метод
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def BatchMatch(self, request, context):
+        """Пакетное сопоставление одной вакансии с несколькими резюме
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def StreamMatch(self, request_iterator, context):
+        """Потоковое сопоставление многих резюме со многими вакансиями
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def Embed(self, request, context):
+        """Векторы текстов для семантического поиска похожих резюме
         """
         context.set_code(grpc.StatusCode.UNIMPLEMENTED)
         context.set_details('Method not implemented!')
@@ -80,6 +115,21 @@ def add_NLPServiceServicer_to_server(servicer, server):
                     request_deserializer=nlp__pb2.MatchRequest.FromString,
                     response_serializer=nlp__pb2.MatchResponse.SerializeToString,
             ),
+            'BatchMatch': grpc.unary_unary_rpc_method_handler(
+                    servicer.BatchMatch,
+                    request_deserializer=nlp__pb2.BatchMatchRequest.FromString,
+                    response_serializer=nlp__pb2.BatchMatchResponse.SerializeToString,
+            ),
+            'StreamMatch': grpc.stream_stream_rpc_method_handler(
+                    servicer.StreamMatch,
+                    request_deserializer=nlp__pb2.MatchItem.FromString,
+                    response_serializer=nlp__pb2.MatchResult.SerializeToString,
+            ),
+            'Embed': grpc.unary_unary_rpc_method_handler(
+                    servicer.Embed,
+                    request_deserializer=nlp__pb2.EmbedRequest.FromString,
+                    response_serializer=nlp__pb2.EmbedResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'pb.NLPService', rpc_method_handlers)
@@ -144,3 +194,84 @@ class NLPService(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def BatchMatch(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/pb.NLPService/BatchMatch',
+            nlp__pb2.BatchMatchRequest.SerializeToString,
+            nlp__pb2.BatchMatchResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def StreamMatch(request_iterator,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.stream_stream(
+            request_iterator,
+            target,
+            '/pb.NLPService/StreamMatch',
+            nlp__pb2.MatchItem.SerializeToString,
+            nlp__pb2.MatchResult.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def Embed(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/pb.NLPService/Embed',
+            nlp__pb2.EmbedRequest.SerializeToString,
+            nlp__pb2.EmbedResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
@@ -0,0 +1,41 @@
+package ann
+
+import "sort"
+
+// candidate узел графа и его расстояние до запроса
+type candidate struct {
+	idx  int32
+	dist float32
+}
+
+// nearHeap куча с ближайшим кандидатом наверху
+type nearHeap []candidate
+
+func (h nearHeap) Len() int           { return len(h) }
+func (h nearHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+func (h nearHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *nearHeap) Push(x any)        { *h = append(*h, x.(candidate)) }
+func (h *nearHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// farHeap куча с самым дальним кандидатом наверху
+type farHeap []candidate
+
+func (h farHeap) Len() int           { return len(h) }
+func (h farHeap) Less(i, j int) bool { return h[i].dist > h[j].dist }
+func (h farHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *farHeap) Push(x any)        { *h = append(*h, x.(candidate)) }
+func (h *farHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+func sortCandidates(cs []candidate) {
+	sort.Slice(cs, func(i, j int) bool { return cs[i].dist < cs[j].dist })
+}
@@ -0,0 +1,366 @@
+package ann
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Параметры графа по умолчанию: M связей на узел, ширина поиска при
+// построении и при запросе. Для сотен тысяч векторов размерности 384
+// полнота поиска с ними выше 0.95.
+const (
+	DefaultM              = 16
+	DefaultEfConstruction = 200
+	DefaultEfSearch       = 64
+)
+
+// Result найденный вектор и его косинусная близость к запросу
+type Result struct {
+	ID         uuid.UUID `json:"id"`
+	Similarity float64   `json:"similarity"`
+}
+
+// Index приближённый поиск ближайших соседей по косинусной близости
+// (Hierarchical Navigable Small World). Векторы нормализуются при
+// добавлении. Удалённые узлы остаются в графе как переходные и не
+// попадают в результаты; Compact перестраивает граф без них.
+type Index struct {
+	mu sync.RWMutex
+
+	dim            int
+	m              int
+	efConstruction int
+	efSearch       int
+	levelMult      float64
+	rng            *rand.Rand
+
+	nodes    []*node
+	ids      map[uuid.UUID]int32
+	entry    int32
+	maxLevel int
+	deleted  int
+	version  uint64 // Растёт при каждом Add и Remove
+}
+
+type node struct {
+	id      uuid.UUID
+	vec     []float32
+	friends [][]int32 // Связи по уровням, от нижнего
+	deleted bool
+}
+
+// New создаёт пустой индекс для векторов размерности dim
+func New(dim int) *Index {
+	return &Index{
+		dim:            dim,
+		m:              DefaultM,
+		efConstruction: DefaultEfConstruction,
+		efSearch:       DefaultEfSearch,
+		levelMult:      1 / math.Log(DefaultM),
+		rng:            rand.New(rand.NewSource(1)),
+		ids:            map[uuid.UUID]int32{},
+		entry:          -1,
+	}
+}
+
+// Dim размерность векторов индекса
+func (x *Index) Dim() int {
+	return x.dim
+}
+
+// Len число векторов в индексе без удалённых
+func (x *Index) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.ids)
+}
+
+// Vector копия вектора по идентификатору
+func (x *Index) Vector(id uuid.UUID) ([]float32, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	i, ok := x.ids[id]
+	if !ok {
+		return nil, false
+	}
+	return append([]float32(nil), x.nodes[i].vec...), true
+}
+
+// Add добавляет вектор или заменяет прежний вектор с тем же идентификатором
+func (x *Index) Add(id uuid.UUID, vec []float32) bool {
+	if len(vec) != x.dim {
+		return false
+	}
+	vec = normalize(vec)
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.version++
+
+	if old, ok := x.ids[id]; ok {
+		x.nodes[old].deleted = true
+		x.deleted++
+	}
+
+	level := int(math.Floor(-math.Log(x.rng.Float64()) * x.levelMult))
+	n := &node{id: id, vec: vec, friends: make([][]int32, level+1)}
+	idx := int32(len(x.nodes))
+	x.nodes = append(x.nodes, n)
+	x.ids[id] = idx
+
+	if x.entry < 0 {
+		x.entry, x.maxLevel = idx, level
+		return true
+	}
+
+	// Спуск жадным поиском до уровня узла, затем связывание на каждом уровне
+	cur := x.entry
+	for l := x.maxLevel; l > level; l-- {
+		cur = x.greedy(vec, cur, l)
+	}
+	for l := min(level, x.maxLevel); l >= 0; l-- {
+		candidates := x.searchLayer(vec, []int32{cur}, x.efConstruction, l)
+		neighbours := x.selectNeighbours(candidates, x.maxFriends(l))
+		n.friends[l] = neighbours
+		for _, nb := range neighbours {
+			x.link(nb, idx, l)
+		}
+		cur = candidates[0].idx
+	}
+	if level > x.maxLevel {
+		x.entry, x.maxLevel = idx, level
+	}
+	return true
+}
+
+// Remove исключает вектор из результатов поиска
+func (x *Index) Remove(id uuid.UUID) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if i, ok := x.ids[id]; ok {
+		x.nodes[i].deleted = true
+		x.deleted++
+		x.version++
+		delete(x.ids, id)
+	}
+}
+
+// Version счётчик изменений индекса: по нему видно, менялся ли индекс,
+// пока строилась его копия
+func (x *Index) Version() uint64 {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.version
+}
+
+// Search возвращает до k ближайших векторов, начиная с самых близких.
+// ef - ширина поиска; меньше k она не бывает, 0 - значение по умолчанию.
+func (x *Index) Search(query []float32, k, ef int) []Result {
+	if len(query) != x.dim || k <= 0 {
+		return nil
+	}
+	query = normalize(query)
+	ef = max(ef, k, x.efSearch)
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	if x.entry < 0 {
+		return nil
+	}
+
+	cur := x.entry
+	for l := x.maxLevel; l > 0; l-- {
+		cur = x.greedy(query, cur, l)
+	}
+	candidates := x.searchLayer(query, []int32{cur}, ef, 0)
+
+	out := make([]Result, 0, k)
+	for _, c := range candidates {
+		n := x.nodes[c.idx]
+		if n.deleted {
+			continue
+		}
+		out = append(out, Result{ID: n.id, Similarity: 1 - float64(c.dist)})
+		if len(out) == k {
+			break
+		}
+	}
+	return out
+}
+
+// Compact перестраивает граф без удалённых узлов. Нужен, когда удалённых
+// накопилось много: они замедляют поиск.
+func (x *Index) Compact() *Index {
+	x.mu.RLock()
+	live := make([]*node, 0, len(x.ids))
+	for _, n := range x.nodes {
+		if !n.deleted {
+			live = append(live, n)
+		}
+	}
+	x.mu.RUnlock()
+
+	out := New(x.dim)
+	for _, n := range live {
+		out.Add(n.id, n.vec)
+	}
+	return out
+}
+
+// Garbage доля удалённых узлов в графе
+func (x *Index) Garbage() float64 {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	if len(x.nodes) == 0 {
+		return 0
+	}
+	return float64(x.deleted) / float64(len(x.nodes))
+}
+
+func (x *Index) maxFriends(level int) int {
+	if level == 0 {
+		return 2 * x.m
+	}
+	return x.m
+}
+
+// greedy переходит к ближайшему соседу, пока расстояние уменьшается
+func (x *Index) greedy(query []float32, cur int32, level int) int32 {
+	best := distance(query, x.nodes[cur].vec)
+	for changed := true; changed; {
+		changed = false
+		for _, nb := range x.friendsAt(cur, level) {
+			if d := distance(query, x.nodes[nb].vec); d < best {
+				best, cur, changed = d, nb, true
+			}
+		}
+	}
+	return cur
+}
+
+// searchLayer поиск ef ближайших на уровне; результат по возрастанию расстояния
+func (x *Index) searchLayer(query []float32, entries []int32, ef, level int) []candidate {
+	visited := map[int32]bool{}
+	var near nearHeap // Ближайший сверху: кандидаты на обход
+	var far farHeap   // Дальний сверху: текущие лучшие ef
+	for _, e := range entries {
+		c := candidate{e, distance(query, x.nodes[e].vec)}
+		visited[e] = true
+		heap.Push(&near, c)
+		heap.Push(&far, c)
+	}
+
+	for near.Len() > 0 {
+		c := heap.Pop(&near).(candidate)
+		if c.dist > far[0].dist && far.Len() >= ef {
+			break
+		}
+		for _, nb := range x.friendsAt(c.idx, level) {
+			if visited[nb] {
+				continue
+			}
+			visited[nb] = true
+			d := distance(query, x.nodes[nb].vec)
+			if far.Len() < ef || d < far[0].dist {
+				heap.Push(&near, candidate{nb, d})
+				heap.Push(&far, candidate{nb, d})
+				if far.Len() > ef {
+					heap.Pop(&far)
+				}
+			}
+		}
+	}
+
+	out := make([]candidate, far.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(&far).(candidate)
+	}
+	return out
+}
+
+// selectNeighbours эвристика отбора соседей из статьи HNSW: кандидат
+// берётся, если он ближе к узлу, чем к уже выбранным соседям. Так
+// связи расходятся в разные стороны, а не в один плотный кластер.
+func (x *Index) selectNeighbours(candidates []candidate, m int) []int32 {
+	out := make([]int32, 0, m)
+	var skipped []int32
+	for _, c := range candidates {
+		if len(out) == m {
+			break
+		}
+		good := true
+		for _, s := range out {
+			if distance(x.nodes[c.idx].vec, x.nodes[s].vec) < c.dist {
+				good = false
+				break
+			}
+		}
+		if good {
+			out = append(out, c.idx)
+		} else {
+			skipped = append(skipped, c.idx)
+		}
+	}
+	// Оставшиеся места заполняются отброшенными: граф не должен быть разреженным
+	for _, s := range skipped {
+		if len(out) == m {
+			break
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// link добавляет обратную связь; при переполнении связи узла отбираются заново
+func (x *Index) link(from, to int32, level int) {
+	n := x.nodes[from]
+	n.friends[level] = append(n.friends[level], to)
+	limit := x.maxFriends(level)
+	if len(n.friends[level]) <= limit {
+		return
+	}
+
+	candidates := make([]candidate, len(n.friends[level]))
+	for i, f := range n.friends[level] {
+		candidates[i] = candidate{f, distance(n.vec, x.nodes[f].vec)}
+	}
+	sortCandidates(candidates)
+	n.friends[level] = x.selectNeighbours(candidates, limit)
+}
+
+func (x *Index) friendsAt(i int32, level int) []int32 {
+	n := x.nodes[i]
+	if level >= len(n.friends) {
+		return nil
+	}
+	return n.friends[level]
+}
+
+// distance косинусное расстояние нормализованных векторов
+func distance(a, b []float32) float32 {
+	var dot float32
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+	return 1 - dot
+}
+
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, f := range v {
+		sum += float64(f) * float64(f)
+	}
+	out := make([]float32, len(v))
+	if sum == 0 {
+		return out
+	}
+	norm := float32(1 / math.Sqrt(sum))
+	for i, f := range v {
+		out[i] = f * norm
+	}
+	return out
+}
@@ -0,0 +1,217 @@
+package ann
+
+import (
+	"math/rand"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func randomVectors(rng *rand.Rand, n, dim int) map[uuid.UUID][]float32 {
+	out := make(map[uuid.UUID][]float32, n)
+	for range n {
+		vec := make([]float32, dim)
+		for i := range vec {
+			vec[i] = float32(rng.NormFloat64())
+		}
+		out[uuid.New()] = vec
+	}
+	return out
+}
+
+// exact k ближайших полным перебором
+func exact(vectors map[uuid.UUID][]float32, query []float32, k int) []uuid.UUID {
+	q := normalize(query)
+	type scored struct {
+		id   uuid.UUID
+		dist float32
+	}
+	all := make([]scored, 0, len(vectors))
+	for id, vec := range vectors {
+		all = append(all, scored{id, distance(q, normalize(vec))})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].dist < all[j].dist })
+	out := make([]uuid.UUID, k)
+	for i := range out {
+		out[i] = all[i].id
+	}
+	return out
+}
+
+// recall доля точных ближайших, найденных индексом
+func recall(x *Index, vectors map[uuid.UUID][]float32, queries [][]float32, k int) float64 {
+	found, total := 0, 0
+	for _, q := range queries {
+		got := map[uuid.UUID]bool{}
+		for _, r := range x.Search(q, k, 0) {
+			got[r.ID] = true
+		}
+		for _, id := range exact(vectors, q, k) {
+			if got[id] {
+				found++
+			}
+			total++
+		}
+	}
+	return float64(found) / float64(total)
+}
+
+func TestRecall(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	tests := []struct {
+		name string
+		n    int
+		dim  int
+		k    int
+		min  float64
+	}{
+		{"малый индекс", 200, 16, 5, 0.99},
+		{"размерность 64", 2000, 64, 10, 0.9},
+		{"ближайший", 2000, 32, 1, 0.95},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vectors := randomVectors(rng, tt.n, tt.dim)
+			x := New(tt.dim)
+			for id, vec := range vectors {
+				if !x.Add(id, vec) {
+					t.Fatalf("Add отклонил вектор размерности %d", tt.dim)
+				}
+			}
+			var queries [][]float32
+			for _, vec := range randomVectors(rng, 50, tt.dim) {
+				queries = append(queries, vec)
+			}
+			if r := recall(x, vectors, queries, tt.k); r < tt.min {
+				t.Errorf("полнота %.3f, ожидается не ниже %.2f", r, tt.min)
+			}
+		})
+	}
+}
+
+func TestAddReplaceRemove(t *testing.T) {
+	x := New(3)
+	a, b := uuid.New(), uuid.New()
+	if x.Add(a, []float32{1, 0}) {
+		t.Fatal("Add принял вектор другой размерности")
+	}
+	x.Add(a, []float32{1, 0, 0})
+	x.Add(b, []float32{0, 1, 0})
+
+	// Замена: старый узел помечается удалённым, в результатах только новый вектор
+	x.Add(a, []float32{0, 0, 1})
+	if got := x.Len(); got != 2 {
+		t.Errorf("Len = %d, want 2", got)
+	}
+	res := x.Search([]float32{0, 0, 1}, 1, 0)
+	if len(res) != 1 || res[0].ID != a || res[0].Similarity < 0.999 {
+		t.Errorf("Search после замены = %+v", res)
+	}
+
+	x.Remove(b)
+	for _, r := range x.Search([]float32{0, 1, 0}, 2, 0) {
+		if r.ID == b {
+			t.Error("удалённый вектор найден поиском")
+		}
+	}
+	if g := x.Garbage(); g != 2.0/3 {
+		t.Errorf("Garbage = %v, want 2/3", g)
+	}
+
+	c := x.Compact()
+	if c.Garbage() != 0 || c.Len() != 1 {
+		t.Errorf("Compact: Garbage = %v, Len = %d", c.Garbage(), c.Len())
+	}
+	if _, ok := c.Vector(a); !ok {
+		t.Error("Compact потерял живой вектор")
+	}
+}
+
+func TestVersion(t *testing.T) {
+	x := New(2)
+	id := uuid.New()
+	steps := []struct {
+		name string
+		do   func()
+		want uint64
+	}{
+		{"добавление", func() { x.Add(id, []float32{1, 0}) }, 1},
+		{"неверная размерность", func() { x.Add(id, []float32{1}) }, 1},
+		{"замена", func() { x.Add(id, []float32{0, 1}) }, 2},
+		{"удаление", func() { x.Remove(id) }, 3},
+		{"удаление отсутствующего", func() { x.Remove(id) }, 3},
+	}
+	for _, s := range steps {
+		s.do()
+		if got := x.Version(); got != s.want {
+			t.Errorf("%s: Version = %d, want %d", s.name, got, s.want)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	vectors := randomVectors(rng, 300, 8)
+	x := New(8)
+	for id, vec := range vectors {
+		x.Add(id, vec)
+	}
+	var removed uuid.UUID
+	for id := range vectors {
+		removed = id
+		break
+	}
+	x.Remove(removed)
+
+	path := filepath.Join(t.TempDir(), "resume.hnsw")
+	savedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := x.Save(path, "model-a", savedAt); err != nil {
+		t.Fatal(err)
+	}
+	y, model, at, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model != "model-a" || !at.Equal(savedAt) {
+		t.Errorf("Load: model %q, savedAt %v", model, at)
+	}
+	if y.Dim() != 8 || y.Len() != x.Len() || y.Garbage() != x.Garbage() {
+		t.Errorf("Load: dim %d, len %d, garbage %v; want 8, %d, %v", y.Dim(), y.Len(), y.Garbage(), x.Len(), x.Garbage())
+	}
+	if _, ok := y.Vector(removed); ok {
+		t.Error("удалённый вектор восстановлен из файла")
+	}
+
+	// Прочитанный граф отвечает так же, как исходный, и принимает новые векторы
+	for _, q := range randomVectors(rng, 20, 8) {
+		want, got := x.Search(q, 5, 0), y.Search(q, 5, 0)
+		if len(want) != len(got) {
+			t.Fatalf("Search: %d результатов после загрузки, было %d", len(got), len(want))
+		}
+		for i := range want {
+			if want[i].ID != got[i].ID {
+				t.Fatalf("Search: результат %d отличается после загрузки", i)
+			}
+		}
+	}
+	if !y.Add(uuid.New(), make([]float32, 8)) {
+		t.Error("загруженный индекс не принимает векторы")
+	}
+}
+
+func TestLoadEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vacancy.hnsw")
+	if err := New(0).Save(path, "model-a", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	x, _, _, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Dim() != 0 || x.Len() != 0 || x.Search([]float32{1}, 1, 0) != nil {
+		t.Errorf("пустой индекс: dim %d, len %d", x.Dim(), x.Len())
+	}
+}
@@ -0,0 +1,124 @@
+package ann
+
+import (
+	"bufio"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// formatVersion версия формата файла индекса; файл другой версии не читается
+const formatVersion = 1
+
+// snapshot содержимое файла индекса
+type snapshot struct {
+	Version  int
+	Model    string // Модель, которой посчитаны векторы
+	Dim      int
+	SavedAt  time.Time
+	Entry    int32
+	MaxLevel int
+	Nodes    []snapshotNode
+}
+
+type snapshotNode struct {
+	ID      uuid.UUID
+	Vec     []float32
+	Friends [][]int32
+	Deleted bool
+}
+
+// Save записывает индекс в файл: сначала во временный, затем переименованием,
+// чтобы при падении процесса не остался наполовину записанный файл
+func (x *Index) Save(path, model string, savedAt time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	if err := x.encode(w, model, savedAt); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (x *Index) encode(w io.Writer, model string, savedAt time.Time) error {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	s := snapshot{
+		Version:  formatVersion,
+		Model:    model,
+		Dim:      x.dim,
+		SavedAt:  savedAt,
+		Entry:    x.entry,
+		MaxLevel: x.maxLevel,
+		Nodes:    make([]snapshotNode, len(x.nodes)),
+	}
+	for i, n := range x.nodes {
+		s.Nodes[i] = snapshotNode{ID: n.id, Vec: n.vec, Friends: n.friends, Deleted: n.deleted}
+	}
+	return gob.NewEncoder(w).Encode(s)
+}
+
+// Load читает индекс из файла. Возвращает модель векторов и время
+// сохранения: по ним видно, не устарел ли файл.
+func Load(path string) (*Index, string, time.Time, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	defer f.Close()
+
+	var s snapshot
+	if err := gob.NewDecoder(bufio.NewReader(f)).Decode(&s); err != nil {
+		return nil, "", time.Time{}, fmt.Errorf("чтение индекса %s: %w", path, err)
+	}
+	if s.Version != formatVersion {
+		return nil, "", time.Time{}, fmt.Errorf("индекс %s: неподдерживаемая версия формата %d", path, s.Version)
+	}
+
+	x := New(s.Dim)
+	x.entry, x.maxLevel = s.Entry, s.MaxLevel
+	x.nodes = make([]*node, len(s.Nodes))
+	for i, n := range s.Nodes {
+		if len(n.Vec) != s.Dim {
+			return nil, "", time.Time{}, fmt.Errorf("индекс %s: узел %d неверной размерности", path, i)
+		}
+		for _, level := range n.Friends {
+			for _, f := range level {
+				if f < 0 || int(f) >= len(s.Nodes) {
+					return nil, "", time.Time{}, fmt.Errorf("индекс %s: узел %d ссылается на несуществующий", path, i)
+				}
+			}
+		}
+		x.nodes[i] = &node{id: n.ID, vec: n.Vec, friends: n.Friends, deleted: n.Deleted}
+		if n.Deleted {
+			x.deleted++
+		} else {
+			x.ids[n.ID] = int32(i)
+		}
+	}
+	if len(x.nodes) > 0 && (x.entry < 0 || int(x.entry) >= len(x.nodes)) {
+		return nil, "", time.Time{}, fmt.Errorf("индекс %s: неверная точка входа", path)
+	}
+	return x, s.Model, s.SavedAt, nil
+}
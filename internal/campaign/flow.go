@@ -0,0 +1,178 @@
+package campaign
+
+import (
+	"container/heap"
+	"math"
+)
+
+// scale переводит оценку в целую стоимость ребра с точностью до 1e-6
+const scale = 1_000_000
+
+// Unassigned резюме не получило вакансию
+const Unassigned = -1
+
+// Solve распределяет резюме по вакансиям так, чтобы сумма оценок
+// назначенных пар была наибольшей: резюме получает не больше одной
+// вакансии, вакансия j - не больше capacity[j] резюме. scores[i][j] -
+// оценка резюме i для вакансии j; пары с NaN и оценкой ниже minScore не
+// назначаются. Возвращает номер вакансии для каждого резюме или Unassigned.
+//
+// Задача решается потоком минимальной стоимости: исток → резюме →
+// вакансия → сток. Ребро пары стоит C - оценка, где C - наибольшая оценка,
+// поэтому стоимости неотрицательны и кратчайшие пути ищутся Дейкстрой с
+// потенциалами. Каждый дополняющий путь увеличивает поток на единицу, а
+// сумму оценок - на C минус его стоимость; поток растёт, пока это
+// приращение положительно.
+func Solve(scores [][]float64, capacity []int, minScore float64) []int {
+	n, m := len(scores), len(capacity)
+	assign := make([]int, n)
+	for i := range assign {
+		assign[i] = Unassigned
+	}
+
+	var top int64
+	for _, row := range scores {
+		for j, s := range row {
+			if eligible(s, minScore) && j < m {
+				top = max(top, int64(math.Round(s*scale)))
+			}
+		}
+	}
+
+	source, sink := n+m, n+m+1
+	g := newGraph(n + m + 2)
+	pairs := make([][]int, n) // Индексы рёбер пар по резюме
+	for i, row := range scores {
+		g.add(source, i, 1, 0)
+		for j, s := range row {
+			if j < m && capacity[j] > 0 && eligible(s, minScore) {
+				pairs[i] = append(pairs[i], g.add(i, n+j, 1, top-int64(math.Round(s*scale))))
+			}
+		}
+	}
+	for j, c := range capacity {
+		if c > 0 {
+			g.add(n+j, sink, c, 0)
+		}
+	}
+
+	for g.augment(source, sink, top) {
+	}
+
+	for i := range pairs {
+		for _, e := range pairs[i] {
+			if edge := g.edges[e]; edge.cap == 0 {
+				assign[i] = edge.to - n
+			}
+		}
+	}
+	return assign
+}
+
+func eligible(score, minScore float64) bool {
+	return !math.IsNaN(score) && score >= minScore
+}
+
+// edge ребро остаточной сети; обратное ребро хранится по индексу e^1
+type edge struct {
+	to   int
+	cap  int
+	cost int64
+}
+
+type graph struct {
+	adj   [][]int
+	edges []edge
+	dual  []int64 // Потенциалы вершин для неотрицательных приведённых стоимостей
+}
+
+func newGraph(n int) *graph {
+	return &graph{adj: make([][]int, n), dual: make([]int64, n)}
+}
+
+// add добавляет ребро и возвращает его индекс
+func (g *graph) add(from, to, cap int, cost int64) int {
+	g.adj[from] = append(g.adj[from], len(g.edges))
+	g.edges = append(g.edges, edge{to: to, cap: cap, cost: cost})
+	g.adj[to] = append(g.adj[to], len(g.edges))
+	g.edges = append(g.edges, edge{to: from, cap: 0, cost: -cost})
+	return len(g.edges) - 2
+}
+
+// augment находит кратчайший путь из s в t и пускает по нему единицу
+// потока, если стоимость пути меньше limit. false - пути нет или он не
+// дешевле limit.
+func (g *graph) augment(s, t int, limit int64) bool {
+	n := len(g.adj)
+	dist := make([]int64, n)
+	prev := make([]int, n)
+	visited := make([]bool, n)
+	for v := range dist {
+		dist[v] = math.MaxInt64
+		prev[v] = -1
+	}
+	dist[s] = 0
+	queue := &distHeap{{v: s}}
+	for queue.Len() > 0 {
+		cur := heap.Pop(queue).(distItem)
+		if visited[cur.v] {
+			continue
+		}
+		visited[cur.v] = true
+		if cur.v == t {
+			break
+		}
+		for _, e := range g.adj[cur.v] {
+			edge := g.edges[e]
+			if edge.cap == 0 {
+				continue
+			}
+			// Приведённая стоимость неотрицательна благодаря потенциалам
+			reduced := edge.cost - g.dual[edge.to] + g.dual[cur.v]
+			if d := dist[cur.v] + reduced; d < dist[edge.to] {
+				dist[edge.to] = d
+				prev[edge.to] = e
+				heap.Push(queue, distItem{v: edge.to, dist: d})
+			}
+		}
+	}
+	if !visited[t] {
+		return false
+	}
+	for v := range visited {
+		if visited[v] {
+			g.dual[v] -= dist[t] - dist[v]
+		}
+	}
+
+	var cost int64
+	for v := t; v != s; v = g.edges[prev[v]^1].to {
+		cost += g.edges[prev[v]].cost
+	}
+	if cost >= limit {
+		return false
+	}
+	for v := t; v != s; v = g.edges[prev[v]^1].to {
+		g.edges[prev[v]].cap--
+		g.edges[prev[v]^1].cap++
+	}
+	return true
+}
+
+type distItem struct {
+	v    int
+	dist int64
+}
+
+type distHeap []distItem
+
+func (h distHeap) Len() int           { return len(h) }
+func (h distHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+func (h distHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *distHeap) Push(x any)        { *h = append(*h, x.(distItem)) }
+func (h *distHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,157 @@
+package campaign
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var nan = math.NaN()
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		scores   [][]float64
+		capacity []int
+		minScore float64
+		want     []int
+	}{
+		{
+			name:     "каждому лучшая вакансия",
+			scores:   [][]float64{{0.9, 0.1}, {0.2, 0.8}},
+			capacity: []int{1, 1},
+			want:     []int{0, 1},
+		},
+		{
+			// Жадно первое резюме заняло бы вакансию 0 (0.9 + 0.3 = 1.2),
+			// оптимум отдаёт его на вторую по оценке (0.8 + 0.85 = 1.65)
+			name:     "уступить ради общего итога",
+			scores:   [][]float64{{0.9, 0.8}, {0.85, 0.3}},
+			capacity: []int{1, 1},
+			want:     []int{1, 0},
+		},
+		{
+			name:     "несколько мест на вакансии",
+			scores:   [][]float64{{0.9}, {0.8}, {0.7}},
+			capacity: []int{2},
+			want:     []int{0, 0, Unassigned},
+		},
+		{
+			name:     "порог оценки",
+			scores:   [][]float64{{0.3, 0.6}, {0.4, 0.2}},
+			capacity: []int{1, 1},
+			minScore: 0.5,
+			want:     []int{1, Unassigned},
+		},
+		{
+			name:     "неоценённая пара не назначается",
+			scores:   [][]float64{{nan, 0.4}, {0.5, nan}},
+			capacity: []int{1, 1},
+			want:     []int{1, 0},
+		},
+		{
+			name:     "закрытая вакансия",
+			scores:   [][]float64{{0.9, 0.5}, {0.8, 0.4}},
+			capacity: []int{0, 1},
+			want:     []int{1, Unassigned},
+		},
+		{
+			name:     "мест больше, чем резюме",
+			scores:   [][]float64{{0.5, 0.6, 0.7}},
+			capacity: []int{3, 3, 3},
+			want:     []int{2},
+		},
+		{
+			name:     "нет резюме",
+			scores:   [][]float64{},
+			capacity: []int{1},
+			want:     []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Solve(tt.scores, tt.capacity, tt.minScore)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Solve = %v, want %v", got, tt.want)
+			}
+			checkFeasible(t, tt.scores, tt.capacity, tt.minScore, got)
+		})
+	}
+}
+
+// TestSolveOptimal сверяет сумму оценок с полным перебором на случайных матрицах
+func TestSolveOptimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		n, m := 1+rng.Intn(5), 1+rng.Intn(3)
+		scores := make([][]float64, n)
+		for i := range scores {
+			scores[i] = make([]float64, m)
+			for j := range scores[i] {
+				scores[i][j] = math.Round(rng.Float64()*1000) / 1000
+				if rng.Intn(8) == 0 {
+					scores[i][j] = nan
+				}
+			}
+		}
+		capacity := make([]int, m)
+		for j := range capacity {
+			capacity[j] = rng.Intn(3)
+		}
+		minScore := []float64{0, 0.3, 0.6}[rng.Intn(3)]
+
+		got := Solve(scores, capacity, minScore)
+		checkFeasible(t, scores, capacity, minScore, got)
+		want := bestTotal(scores, capacity, minScore, 0, make([]int, m))
+		if total := totalScore(scores, got); math.Abs(total-want) > 1e-6 {
+			t.Fatalf("scores %v, capacity %v, min %.1f: сумма %.3f, оптимум %.3f (%v)",
+				scores, capacity, minScore, total, want, got)
+		}
+	}
+}
+
+func checkFeasible(t *testing.T, scores [][]float64, capacity []int, minScore float64, assign []int) {
+	t.Helper()
+	used := make([]int, len(capacity))
+	for i, j := range assign {
+		if j == Unassigned {
+			continue
+		}
+		if !eligible(scores[i][j], minScore) {
+			t.Fatalf("резюме %d назначено на вакансию %d с оценкой %v", i, j, scores[i][j])
+		}
+		used[j]++
+	}
+	for j, n := range used {
+		if n > capacity[j] {
+			t.Fatalf("на вакансию %d назначено %d резюме при %d местах", j, n, capacity[j])
+		}
+	}
+}
+
+func totalScore(scores [][]float64, assign []int) float64 {
+	var total float64
+	for i, j := range assign {
+		if j != Unassigned {
+			total += scores[i][j]
+		}
+	}
+	return total
+}
+
+// bestTotal наибольшая сумма оценок перебором назначений резюме с i-го
+func bestTotal(scores [][]float64, capacity []int, minScore float64, i int, used []int) float64 {
+	if i == len(scores) {
+		return 0
+	}
+	best := bestTotal(scores, capacity, minScore, i+1, used)
+	for j, s := range scores[i] {
+		if used[j] < capacity[j] && eligible(s, minScore) {
+			used[j]++
+			best = max(best, s+bestTotal(scores, capacity, minScore, i+1, used))
+			used[j]--
+		}
+	}
+	return best
+}
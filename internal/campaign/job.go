@@ -0,0 +1,366 @@
+// Package campaign распределяет кандидатов кампании найма по нескольким
+// похожим вакансиям: считает оценки всех пар резюме × вакансии и выбирает
+// назначение с наибольшей суммарной оценкой при ограничении числа мест.
+package campaign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var log = logrus.New()
+
+// Ограничения одной кампании
+const (
+	MaxResumes   = 1000
+	MaxVacancies = 50
+)
+
+// runTimeout на расчёт всей матрицы
+const runTimeout = 2 * time.Hour
+
+// ErrNotFound резюме или вакансия из запроса не найдены
+var ErrNotFound = errors.New("не найдено")
+
+// ErrNotDone расчёт ещё идёт или завершился ошибкой
+var ErrNotDone = errors.New("матрица ещё не рассчитана")
+
+// Score оценка одной пары; Err - пару оценить не удалось
+type Score struct {
+	Value    float64
+	Degraded bool // Оценка получена резервным скорером
+	Err      error
+}
+
+// Scorer оценивает все пары резюме × вакансии и возвращает оценки
+// scores[j][i] резюме i для вакансии j. Расчёт вызывает его
+// последовательно для групп не больше vacanciesPerStep вакансий.
+type Scorer func(ctx context.Context, vacancies []models.Vacancy, resumes []models.Resume) [][]Score
+
+// vacanciesPerStep вакансий оцениваются за один вызов Scorer; после
+// каждого вызова сохраняются строки матрицы и прогресс
+const vacanciesPerStep = 10
+
+// Opening вакансия кампании и число мест на ней
+type Opening struct {
+	VacancyID uuid.UUID `json:"vacancy_id"`
+	Headcount int       `json:"headcount"` // 0 - одно место
+}
+
+// Request параметры кампании
+type Request struct {
+	Name      string      `json:"name"`
+	ResumeIDs []uuid.UUID `json:"resume_ids"`
+	Vacancies []Opening   `json:"vacancies"`
+	MinScore  float64     `json:"min_score"` // Пары с меньшей оценкой не назначаются
+}
+
+// Validate проверяет запрос, убирает повторы и проставляет одно место
+// вакансиям без headcount
+func (r *Request) Validate() error {
+	r.ResumeIDs = unique(r.ResumeIDs)
+	if len(r.ResumeIDs) == 0 || len(r.ResumeIDs) > MaxResumes {
+		return fmt.Errorf("количество резюме должно быть от 1 до %d", MaxResumes)
+	}
+	seen := map[uuid.UUID]bool{}
+	openings := r.Vacancies[:0]
+	for _, o := range r.Vacancies {
+		if o.Headcount < 0 {
+			return errors.New("headcount не может быть отрицательным")
+		}
+		if o.Headcount == 0 {
+			o.Headcount = 1
+		}
+		if !seen[o.VacancyID] {
+			seen[o.VacancyID] = true
+			openings = append(openings, o)
+		}
+	}
+	r.Vacancies = openings
+	if len(r.Vacancies) == 0 || len(r.Vacancies) > MaxVacancies {
+		return fmt.Errorf("количество вакансий должно быть от 1 до %d", MaxVacancies)
+	}
+	if r.MinScore < 0 || r.MinScore > 1 {
+		return errors.New("min_score должен быть числом от 0 до 1")
+	}
+	return nil
+}
+
+// Start создаёт расчёт и считает матрицу в фоне. Все резюме и вакансии
+// запроса должны существовать.
+func Start(db *gorm.DB, scorer Scorer, req Request) (models.MatchRun, error) {
+	if err := req.Validate(); err != nil {
+		return models.MatchRun{}, err
+	}
+
+	vacancyIDs := make([]uuid.UUID, len(req.Vacancies))
+	for i, o := range req.Vacancies {
+		vacancyIDs[i] = o.VacancyID
+	}
+	var count int64
+	if err := db.Model(&models.Vacancy{}).Where("id IN ?", vacancyIDs).Count(&count).Error; err != nil {
+		return models.MatchRun{}, err
+	}
+	if missing := len(vacancyIDs) - int(count); missing > 0 {
+		return models.MatchRun{}, fmt.Errorf("%w: вакансий %d", ErrNotFound, missing)
+	}
+	if err := db.Model(&models.Resume{}).Where("id IN ?", req.ResumeIDs).Count(&count).Error; err != nil {
+		return models.MatchRun{}, err
+	}
+	if missing := len(req.ResumeIDs) - int(count); missing > 0 {
+		return models.MatchRun{}, fmt.Errorf("%w: резюме %d", ErrNotFound, missing)
+	}
+
+	run := models.MatchRun{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Resumes:   len(req.ResumeIDs),
+		Vacancies: len(req.Vacancies),
+		MinScore:  req.MinScore,
+		Status:    models.MatchRunRunning,
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&run).Error; err != nil {
+			return err
+		}
+		openings := make([]models.MatchRunVacancy, len(req.Vacancies))
+		for i, o := range req.Vacancies {
+			openings[i] = models.MatchRunVacancy{RunID: run.ID, VacancyID: o.VacancyID, Position: i, Headcount: o.Headcount}
+		}
+		return tx.Create(&openings).Error
+	})
+	if err != nil {
+		return models.MatchRun{}, err
+	}
+	go execute(db, scorer, run, req.ResumeIDs)
+	return run, nil
+}
+
+// Expire показывает расчёт, который числится выполняющимся дольше
+// runTimeout, упавшим: процесс, который его считал, остановился, и сам
+// статус уже не обновится. Запись в БД не меняется.
+func Expire(run *models.MatchRun) {
+	if run.Status == models.MatchRunRunning && time.Since(run.CreatedAt) > runTimeout {
+		run.Status, run.Error = models.MatchRunFailed, "расчёт прерван: не завершился за отведённое время"
+	}
+}
+
+// execute считает матрицу, распределяет кандидатов и записывает итог
+func execute(db *gorm.DB, scorer Scorer, run models.MatchRun, resumeIDs []uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
+	defer cancel()
+
+	started := time.Now()
+	err := score(ctx, db, scorer, &run, resumeIDs)
+	if err == nil {
+		err = assign(db, &run)
+	}
+	now := time.Now()
+	run.FinishedAt = &now
+	if err != nil {
+		log.WithError(err).Error("Ошибка расчёта матрицы сопоставления")
+		run.Status, run.Error = models.MatchRunFailed, err.Error()
+	} else {
+		run.Status = models.MatchRunDone
+		log.Infof("Матрица %d × %d рассчитана за %s, назначено %d резюме",
+			run.Resumes, run.Vacancies, time.Since(started).Round(time.Second), run.Assigned)
+	}
+	if err := db.Select("status", "error", "finished_at").Updates(&run).Error; err != nil {
+		log.WithError(err).Error("Ошибка сохранения расчёта матрицы")
+	}
+}
+
+// score оценивает все пары группами вакансий и после каждой группы
+// сохраняет строки матрицы и прогресс
+func score(ctx context.Context, db *gorm.DB, scorer Scorer, run *models.MatchRun, resumeIDs []uuid.UUID) error {
+	var resumes []models.Resume
+	if err := db.Where("id IN ?", resumeIDs).Find(&resumes).Error; err != nil {
+		return err
+	}
+	openings, err := loadOpenings(db, run.ID)
+	if err != nil {
+		return err
+	}
+
+	vacancies := make([]models.Vacancy, len(openings))
+	for j, o := range openings {
+		if err := db.First(&vacancies[j], "id = ?", o.VacancyID).Error; err != nil {
+			return fmt.Errorf("вакансия %s: %w", o.VacancyID, err)
+		}
+	}
+
+	for start := 0; start < len(vacancies); start += vacanciesPerStep {
+		step := vacancies[start:min(start+vacanciesPerStep, len(vacancies))]
+		scores := scorer(ctx, step, resumes)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		cells := make([]models.MatchCell, 0, len(step)*len(resumes))
+		for j, vacancy := range step {
+			for i, r := range resumes {
+				s := scores[j][i]
+				cell := models.MatchCell{RunID: run.ID, ResumeID: r.ID, VacancyID: vacancy.ID, Score: s.Value, Degraded: s.Degraded}
+				if s.Err != nil {
+					cell.Score, cell.Error = 0, s.Err.Error()
+					run.Failed++
+				}
+				run.Degraded = run.Degraded || s.Degraded
+				cells = append(cells, cell)
+			}
+		}
+		if err := db.CreateInBatches(&cells, 1000).Error; err != nil {
+			return err
+		}
+		run.Scored += len(cells)
+		if err := db.Select("scored", "failed", "degraded").Updates(run).Error; err != nil {
+			return err
+		}
+	}
+	if run.Failed == run.Scored {
+		return errors.New("не удалось оценить ни одной пары")
+	}
+	return nil
+}
+
+// Reassign заново распределяет кандидатов готовой матрицы: с новым числом
+// мест на вакансиях и новой минимальной оценкой, без повторной оценки пар.
+// Вакансии вне headcounts сохраняют прежнее число мест, 0 закрывает вакансию.
+func Reassign(db *gorm.DB, run *models.MatchRun, headcounts map[uuid.UUID]int, minScore float64) error {
+	if run.Status != models.MatchRunDone {
+		return ErrNotDone
+	}
+	if minScore < 0 || minScore > 1 {
+		return errors.New("min_score должен быть числом от 0 до 1")
+	}
+	openings, err := loadOpenings(db, run.ID)
+	if err != nil {
+		return err
+	}
+	known := make(map[uuid.UUID]bool, len(openings))
+	for _, o := range openings {
+		known[o.VacancyID] = true
+	}
+	for id, n := range headcounts {
+		if !known[id] {
+			return fmt.Errorf("%w: вакансия %s не входит в кампанию", ErrNotFound, id)
+		}
+		if n < 0 {
+			return errors.New("headcount не может быть отрицательным")
+		}
+	}
+
+	for id, n := range headcounts {
+		err := db.Model(&models.MatchRunVacancy{}).
+			Where("run_id = ? AND vacancy_id = ?", run.ID, id).Update("headcount", n).Error
+		if err != nil {
+			return err
+		}
+	}
+	run.MinScore = minScore
+	if err := db.Model(run).Update("min_score", minScore).Error; err != nil {
+		return err
+	}
+	return assign(db, run)
+}
+
+// assign решает задачу назначения по сохранённой матрице и отмечает
+// назначенные пары
+func assign(db *gorm.DB, run *models.MatchRun) error {
+	openings, err := loadOpenings(db, run.ID)
+	if err != nil {
+		return err
+	}
+	var cells []models.MatchCell
+	if err := db.Where("run_id = ?", run.ID).Find(&cells).Error; err != nil {
+		return err
+	}
+
+	column := make(map[uuid.UUID]int, len(openings))
+	capacity := make([]int, len(openings))
+	for j, o := range openings {
+		column[o.VacancyID] = j
+		capacity[j] = o.Headcount
+	}
+	row := map[uuid.UUID]int{}
+	var resumes []uuid.UUID
+	var scores [][]float64
+	for _, cell := range cells {
+		i, ok := row[cell.ResumeID]
+		if !ok {
+			i = len(resumes)
+			row[cell.ResumeID] = i
+			resumes = append(resumes, cell.ResumeID)
+			scores = append(scores, nanRow(len(openings)))
+		}
+		if j, ok := column[cell.VacancyID]; ok && cell.Error == "" {
+			scores[i][j] = cell.Score
+		}
+	}
+
+	chosen := make([][]uuid.UUID, len(openings))
+	run.Assigned, run.TotalScore = 0, 0
+	for i, j := range Solve(scores, capacity, run.MinScore) {
+		if j == Unassigned {
+			continue
+		}
+		chosen[j] = append(chosen[j], resumes[i])
+		run.Assigned++
+		run.TotalScore += scores[i][j]
+	}
+	run.TotalScore = math.Round(run.TotalScore*1000) / 1000
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.MatchCell{}).Where("run_id = ? AND assigned", run.ID).Update("assigned", false).Error
+		if err != nil {
+			return err
+		}
+		for j, ids := range chosen {
+			if len(ids) == 0 {
+				continue
+			}
+			err := tx.Model(&models.MatchCell{}).
+				Where("run_id = ? AND vacancy_id = ? AND resume_id IN ?", run.ID, openings[j].VacancyID, ids).
+				Update("assigned", true).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Model(run).Select("assigned", "total_score").Updates(run).Error
+	})
+}
+
+// loadOpenings вакансии кампании в порядке запроса
+func loadOpenings(db *gorm.DB, runID uuid.UUID) ([]models.MatchRunVacancy, error) {
+	var openings []models.MatchRunVacancy
+	err := db.Where("run_id = ?", runID).Order("position").Find(&openings).Error
+	return openings, err
+}
+
+func nanRow(n int) []float64 {
+	row := make([]float64, n)
+	for j := range row {
+		row[j] = math.NaN()
+	}
+	return row
+}
+
+func unique(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	out := ids[:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
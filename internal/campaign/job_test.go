@@ -0,0 +1,65 @@
+package campaign
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+)
+
+func TestRequestValidate(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	tests := []struct {
+		name    string
+		req     Request
+		wantErr bool
+	}{
+		{"пустые резюме", Request{Vacancies: []Opening{{VacancyID: a}}}, true},
+		{"нет вакансий", Request{ResumeIDs: []uuid.UUID{a}}, true},
+		{"отрицательное число мест", Request{ResumeIDs: []uuid.UUID{a}, Vacancies: []Opening{{VacancyID: b, Headcount: -1}}}, true},
+		{"порог больше 1", Request{ResumeIDs: []uuid.UUID{a}, Vacancies: []Opening{{VacancyID: b}}, MinScore: 1.5}, true},
+		{"верный запрос", Request{ResumeIDs: []uuid.UUID{a, a}, Vacancies: []Opening{{VacancyID: b}, {VacancyID: b, Headcount: 3}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	req := Request{ResumeIDs: []uuid.UUID{a, b, a}, Vacancies: []Opening{{VacancyID: b}, {VacancyID: b, Headcount: 3}}}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.ResumeIDs) != 2 || len(req.Vacancies) != 1 || req.Vacancies[0].Headcount != 1 {
+		t.Errorf("повторы не убраны или не проставлено одно место: %+v", req)
+	}
+}
+
+func TestExpire(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		age    time.Duration
+		want   string
+	}{
+		{"идёт", models.MatchRunRunning, time.Minute, models.MatchRunRunning},
+		{"завис", models.MatchRunRunning, runTimeout + time.Minute, models.MatchRunFailed},
+		{"давно готов", models.MatchRunDone, 24 * time.Hour, models.MatchRunDone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := models.MatchRun{Status: tt.status, CreatedAt: time.Now().Add(-tt.age)}
+			Expire(&run)
+			if run.Status != tt.want {
+				t.Errorf("Status = %s, want %s", run.Status, tt.want)
+			}
+			if (run.Error != "") != (tt.want == models.MatchRunFailed) {
+				t.Errorf("Error = %q", run.Error)
+			}
+		})
+	}
+}
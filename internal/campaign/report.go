@@ -0,0 +1,188 @@
+package campaign
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"gorm.io/gorm"
+)
+
+// Candidate резюме на вакансии: назначенное или запасное
+type Candidate struct {
+	ResumeID uuid.UUID `json:"resume_id"`
+	Score    float64   `json:"score"`
+	// Для запасных: куда резюме назначено сейчас и с какой оценкой;
+	// null - резюме свободно и его можно взять без перестановок
+	AssignedTo    *uuid.UUID `json:"assigned_to,omitempty"`
+	AssignedScore *float64   `json:"assigned_score,omitempty"`
+}
+
+// Placement назначение на одну вакансию
+type Placement struct {
+	VacancyID uuid.UUID   `json:"vacancy_id"`
+	Title     string      `json:"title"`
+	Headcount int         `json:"headcount"`
+	Assigned  []Candidate `json:"assigned"`   // От лучшей оценки
+	RunnersUp []Candidate `json:"runners_up"` // Лучшие из не назначенных сюда
+}
+
+// Spare резюме без вакансии и его лучший вариант
+type Spare struct {
+	ResumeID  uuid.UUID  `json:"resume_id"`
+	VacancyID *uuid.UUID `json:"best_vacancy_id"` // null - ни одной оценки не получено
+	Score     float64    `json:"best_score"`
+}
+
+// Report назначение кампании с запасными вариантами
+type Report struct {
+	Run        models.MatchRun `json:"run"`
+	Vacancies  []Placement     `json:"vacancies"`
+	Unassigned []Spare         `json:"unassigned"`
+}
+
+// Assignment собирает назначение готовой матрицы: по каждой вакансии
+// назначенные резюме и до runnersUp запасных не ниже минимальной оценки -
+// резюме, оставшиеся без вакансии, и назначенные на другие вакансии
+func Assignment(db *gorm.DB, run models.MatchRun, runnersUp int) (Report, error) {
+	if run.Status != models.MatchRunDone {
+		return Report{}, ErrNotDone
+	}
+	openings, err := loadOpenings(db, run.ID)
+	if err != nil {
+		return Report{}, err
+	}
+	cells, err := loadCells(db, run.ID)
+	if err != nil {
+		return Report{}, err
+	}
+	titles, err := vacancyTitles(db, openings)
+	if err != nil {
+		return Report{}, err
+	}
+
+	// Назначение и лучший вариант каждого резюме
+	assigned := map[uuid.UUID]models.MatchCell{}
+	best := map[uuid.UUID]models.MatchCell{}
+	var order []uuid.UUID
+	for _, cell := range cells {
+		if cell.Assigned {
+			assigned[cell.ResumeID] = cell
+		}
+		b, seen := best[cell.ResumeID]
+		if !seen {
+			order = append(order, cell.ResumeID)
+		}
+		if !seen || cell.Error == "" && (b.Error != "" || cell.Score > b.Score) {
+			best[cell.ResumeID] = cell
+		}
+	}
+
+	byVacancy := map[uuid.UUID][]models.MatchCell{}
+	for _, cell := range cells {
+		byVacancy[cell.VacancyID] = append(byVacancy[cell.VacancyID], cell)
+	}
+
+	report := Report{Run: run, Vacancies: make([]Placement, 0, len(openings)), Unassigned: []Spare{}}
+	for _, o := range openings {
+		p := Placement{VacancyID: o.VacancyID, Title: titles[o.VacancyID], Headcount: o.Headcount, Assigned: []Candidate{}, RunnersUp: []Candidate{}}
+		// Ячейки отсортированы по убыванию оценки
+		for _, cell := range byVacancy[o.VacancyID] {
+			switch {
+			case cell.Assigned:
+				p.Assigned = append(p.Assigned, Candidate{ResumeID: cell.ResumeID, Score: cell.Score})
+			case cell.Error != "" || cell.Score < run.MinScore || len(p.RunnersUp) == runnersUp:
+			default:
+				c := Candidate{ResumeID: cell.ResumeID, Score: cell.Score}
+				if other, ok := assigned[cell.ResumeID]; ok {
+					c.AssignedTo, c.AssignedScore = &other.VacancyID, &other.Score
+				}
+				p.RunnersUp = append(p.RunnersUp, c)
+			}
+		}
+		report.Vacancies = append(report.Vacancies, p)
+	}
+
+	for _, id := range order {
+		if _, ok := assigned[id]; ok {
+			continue
+		}
+		spare := Spare{ResumeID: id}
+		if b := best[id]; b.Error == "" {
+			spare.VacancyID, spare.Score = &b.VacancyID, b.Score
+		}
+		report.Unassigned = append(report.Unassigned, spare)
+	}
+	sort.SliceStable(report.Unassigned, func(i, j int) bool { return report.Unassigned[i].Score > report.Unassigned[j].Score })
+	return report, nil
+}
+
+// Row строка матрицы: оценки резюме в порядке вакансий кампании;
+// null - пару оценить не удалось
+type Row struct {
+	ResumeID   uuid.UUID  `json:"resume_id"`
+	Scores     []*float64 `json:"scores"`
+	AssignedTo *uuid.UUID `json:"assigned_to"`
+}
+
+// Matrix матрица оценок: вакансии по столбцам, резюме по строкам в
+// порядке лучшей оценки
+func Matrix(db *gorm.DB, run models.MatchRun) ([]models.MatchRunVacancy, []Row, error) {
+	openings, err := loadOpenings(db, run.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	cells, err := loadCells(db, run.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	column := make(map[uuid.UUID]int, len(openings))
+	for j, o := range openings {
+		column[o.VacancyID] = j
+	}
+	index := map[uuid.UUID]int{}
+	rows := []Row{}
+	for _, cell := range cells {
+		i, ok := index[cell.ResumeID]
+		if !ok {
+			i = len(rows)
+			index[cell.ResumeID] = i
+			rows = append(rows, Row{ResumeID: cell.ResumeID, Scores: make([]*float64, len(openings))})
+		}
+		j, ok := column[cell.VacancyID]
+		if !ok {
+			continue
+		}
+		if cell.Error == "" {
+			rows[i].Scores[j] = &cell.Score
+		}
+		if cell.Assigned {
+			rows[i].AssignedTo = &cell.VacancyID
+		}
+	}
+	return openings, rows, nil
+}
+
+// loadCells ячейки матрицы по убыванию оценки
+func loadCells(db *gorm.DB, runID uuid.UUID) ([]models.MatchCell, error) {
+	var cells []models.MatchCell
+	err := db.Where("run_id = ?", runID).Order("score DESC, resume_id").Find(&cells).Error
+	return cells, err
+}
+
+func vacancyTitles(db *gorm.DB, openings []models.MatchRunVacancy) (map[uuid.UUID]string, error) {
+	ids := make([]uuid.UUID, len(openings))
+	for i, o := range openings {
+		ids[i] = o.VacancyID
+	}
+	var vacancies []models.Vacancy
+	if err := db.Select("id", "title").Where("id IN ?", ids).Find(&vacancies).Error; err != nil {
+		return nil, err
+	}
+	titles := make(map[uuid.UUID]string, len(vacancies))
+	for _, v := range vacancies {
+		titles[v.ID] = v.Title
+	}
+	return titles, nil
+}
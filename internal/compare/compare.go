@@ -0,0 +1,204 @@
+// Package compare сводит нескольких кандидатов на одну вакансию в общую
+// таблицу: оценки по одним и тем же критериям, закрытые и недостающие
+// требования, стаж, зарплатные ожидания и результаты интервью.
+package compare
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/salary"
+	"github.com/moverq1337/VTBHack/internal/timeline"
+)
+
+// Сравнивать можно от MinResumes до MaxResumes резюме
+const (
+	MinResumes = 2
+	MaxResumes = 5
+)
+
+// Entry данные одного кандидата для сравнения
+type Entry struct {
+	Resume      models.Resume
+	Explanation matching.Explanation
+	Degraded    bool // Близость посчитана резервным скорером
+	History     timeline.Timeline
+	Salary      *salary.Amount // nil - ожидания не указаны
+	SalaryRUB   float64        // Ожидания до вычета налога в рублях; 0 - не пересчитать
+	Interview   *models.InterviewResult
+}
+
+// Period место работы в истории кандидата
+type Period struct {
+	Company string `json:"company"`
+	Title   string `json:"title"`
+	Start   string `json:"start"` // 2006-01
+	End     string `json:"end"`
+	Current bool   `json:"current"`
+	Months  int    `json:"months"`
+}
+
+// Interview итог последнего интервью кандидата по вакансии
+type Interview struct {
+	ID          uuid.UUID `json:"id"`
+	Score       float64   `json:"score"` // Средняя оценка ответа 0..1
+	Questions   int       `json:"questions"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// Candidate столбец таблицы: итог по кандидату
+type Candidate struct {
+	Label        string             `json:"label"` // "Кандидат 1" в порядке запроса
+	ResumeID     uuid.UUID          `json:"resume_id"`
+	CandidateID  uuid.UUID          `json:"candidate_id"`
+	Score        float64            `json:"score"`
+	Degraded     bool               `json:"degraded"`
+	Matched      []string           `json:"matched"` // Закрытые требования без семантической близости
+	Missing      []string           `json:"missing"` // Незакрытые обязательные требования
+	TotalMonths  int                `json:"total_months"`
+	Periods      []Period           `json:"periods"`
+	Gaps         int                `json:"gap_months"` // Месяцев без работы между периодами
+	Salary       *salary.Amount     `json:"salary"`
+	SalaryRUB    float64            `json:"salary_rub,omitempty"`
+	Interview    *Interview         `json:"interview"`
+	Contribution map[string]float64 `json:"contribution"` // Вклад категорий в итоговую оценку
+}
+
+// Cell оценка кандидата по критерию
+type Cell struct {
+	Score       float64 `json:"score"`
+	Matched     bool    `json:"matched"`
+	Weight      float64 `json:"weight"`
+	ResumeValue string  `json:"resume_value,omitempty"`
+	Via         string  `json:"via,omitempty"`
+	Note        string  `json:"note,omitempty"`
+}
+
+// Row строка таблицы: критерий и оценки кандидатов в порядке столбцов;
+// null - критерий к кандидату не применялся
+type Row struct {
+	Category    string  `json:"category"`
+	Requirement string  `json:"requirement"`
+	Priority    string  `json:"priority,omitempty"`
+	Cells       []*Cell `json:"cells"`
+	Spread      float64 `json:"spread"` // Разница лучшей и худшей оценки: чем больше, тем сильнее критерий различает кандидатов
+}
+
+// Comparison таблица сравнения кандидатов на вакансию
+type Comparison struct {
+	VacancyID  uuid.UUID   `json:"vacancy_id"`
+	Title      string      `json:"title"`
+	Candidates []Candidate `json:"candidates"`
+	Criteria   []Row       `json:"criteria"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// Build выравнивает критерии кандидатов в строки: строки идут в порядке
+// первого появления критерия, то есть в порядке требований вакансии
+func Build(vacancy models.Vacancy, entries []Entry) Comparison {
+	cmp := Comparison{
+		VacancyID:  vacancy.ID,
+		Title:      vacancy.Title,
+		Candidates: make([]Candidate, len(entries)),
+		Criteria:   []Row{},
+		CreatedAt:  time.Now(),
+	}
+
+	index := map[string]int{}
+	for i, e := range entries {
+		cmp.Candidates[i] = candidate(i, e)
+		for _, cr := range e.Explanation.Criteria {
+			key := cr.Category + "\x00" + cr.Requirement
+			r, ok := index[key]
+			if !ok {
+				r = len(cmp.Criteria)
+				index[key] = r
+				cmp.Criteria = append(cmp.Criteria, Row{
+					Category:    cr.Category,
+					Requirement: cr.Requirement,
+					Priority:    cr.Priority,
+					Cells:       make([]*Cell, len(entries)),
+				})
+			}
+			cmp.Criteria[r].Cells[i] = &Cell{
+				Score:       cr.Score,
+				Matched:     cr.Matched,
+				Weight:      cr.Weight,
+				ResumeValue: cr.ResumeValue,
+				Via:         cr.Via,
+				Note:        cr.Note,
+			}
+		}
+	}
+
+	for r := range cmp.Criteria {
+		row := &cmp.Criteria[r]
+		lo, hi, seen := 0.0, 0.0, false
+		for _, cell := range row.Cells {
+			score := 0.0
+			if cell != nil {
+				score = cell.Score
+			}
+			if !seen || score < lo {
+				lo = score
+			}
+			if !seen || score > hi {
+				hi = score
+			}
+			seen = true
+		}
+		row.Spread = hi - lo
+	}
+	return cmp
+}
+
+func candidate(i int, e Entry) Candidate {
+	c := Candidate{
+		Label:        fmt.Sprintf("Кандидат %d", i+1),
+		ResumeID:     e.Resume.ID,
+		CandidateID:  e.Resume.CandidateID,
+		Score:        e.Explanation.Score,
+		Degraded:     e.Degraded,
+		Matched:      []string{},
+		Missing:      e.Explanation.Missing,
+		TotalMonths:  e.History.TotalMonths,
+		Periods:      make([]Period, 0, len(e.History.Periods)),
+		Salary:       e.Salary,
+		SalaryRUB:    e.SalaryRUB,
+		Contribution: map[string]float64{},
+	}
+	if c.Missing == nil {
+		c.Missing = []string{}
+	}
+	for _, cr := range e.Explanation.Criteria {
+		c.Contribution[cr.Category] += cr.Contribution
+		if cr.Matched && cr.Category != matching.CategorySemantic {
+			c.Matched = append(c.Matched, cr.Requirement)
+		}
+	}
+	for _, p := range e.History.Periods {
+		c.Periods = append(c.Periods, Period{
+			Company: p.Company,
+			Title:   p.Title,
+			Start:   p.Start.Format("2006-01"),
+			End:     p.End.Format("2006-01"),
+			Current: p.Current,
+			Months:  p.Months(),
+		})
+	}
+	for _, g := range e.History.Gaps {
+		c.Gaps += g.Months
+	}
+	if e.Interview != nil {
+		c.Interview = &Interview{
+			ID:          e.Interview.ID,
+			Score:       e.Interview.Score,
+			Questions:   e.Interview.Questions,
+			CompletedAt: e.Interview.CompletedAt,
+		}
+	}
+	return c
+}
@@ -0,0 +1,206 @@
+package compare
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/unidoc/unioffice/color"
+	"github.com/unidoc/unioffice/document"
+	"github.com/unidoc/unioffice/measurement"
+	"github.com/unidoc/unioffice/schema/soo/wml"
+)
+
+// categoryNames названия категорий критериев в выгрузке
+var categoryNames = map[string]string{
+	matching.CategorySemantic:    "Близость по смыслу",
+	matching.CategorySkills:      "Навыки",
+	matching.CategoryRequirement: "Требования",
+	matching.CategoryExperience:  "Опыт",
+	matching.CategorySeniority:   "Уровень",
+	matching.CategoryLocation:    "Местоположение",
+	matching.CategorySalary:      "Зарплата",
+}
+
+var headerFill = color.RGB(0xD9, 0xE2, 0xF3)
+
+// DOCX выгружает сравнение в документ Word: альбомный лист, сводная
+// таблица по кандидатам и таблица оценок по критериям
+func DOCX(cmp Comparison) ([]byte, error) {
+	doc := document.New()
+	defer doc.Close()
+	doc.BodySection().SetPageSizeAndOrientation(297*measurement.Millimeter, 210*measurement.Millimeter, wml.ST_PageOrientationLandscape)
+	doc.BodySection().SetPageMargins(15*measurement.Millimeter, 15*measurement.Millimeter, 15*measurement.Millimeter, 15*measurement.Millimeter, 0, 0, 0)
+
+	heading(doc, "Сравнение кандидатов: "+cmp.Title, 16)
+	doc.AddParagraph().AddRun().AddText("Сформировано " + cmp.CreatedAt.Format("02.01.2006 15:04"))
+
+	heading(doc, "Итог", 13)
+	summary := newTable(doc)
+	header := summary.AddRow()
+	headerCell(header, "")
+	for _, c := range cmp.Candidates {
+		headerCell(header, c.Label+"\n"+c.ResumeID.String()[:8])
+	}
+	best := 0
+	for i, c := range cmp.Candidates {
+		if c.Score > cmp.Candidates[best].Score {
+			best = i
+		}
+	}
+	summaryRow(summary, "Оценка", cmp.Candidates, func(i int, c Candidate) (string, bool) {
+		value := percent(c.Score)
+		if c.Degraded {
+			value += " (резервная оценка)"
+		}
+		return value, i == best
+	})
+	summaryRow(summary, "Стаж", cmp.Candidates, func(_ int, c Candidate) (string, bool) {
+		value := months(c.TotalMonths)
+		if c.Gaps > 0 {
+			value += ", перерывы " + months(c.Gaps)
+		}
+		return value, false
+	})
+	summaryRow(summary, "История работы", cmp.Candidates, func(_ int, c Candidate) (string, bool) {
+		lines := make([]string, 0, len(c.Periods))
+		for _, p := range c.Periods {
+			end := p.End
+			if p.Current {
+				end = "н. в."
+			}
+			lines = append(lines, strings.TrimSpace(fmt.Sprintf("%s – %s %s, %s", p.Start, end, p.Title, p.Company)))
+		}
+		return orDash(strings.Join(lines, "\n")), false
+	})
+	summaryRow(summary, "Ожидания по зарплате", cmp.Candidates, func(_ int, c Candidate) (string, bool) {
+		if c.Salary == nil {
+			return "—", false
+		}
+		value := fmt.Sprintf("%d %s", c.Salary.Value, c.Salary.Currency)
+		if c.Salary.Basis != "" {
+			value += " " + string(c.Salary.Basis)
+		}
+		if c.SalaryRUB > 0 && c.Salary.Currency != "RUB" {
+			value += fmt.Sprintf(" (≈%.0f RUB gross)", c.SalaryRUB)
+		}
+		return value, false
+	})
+	summaryRow(summary, "Интервью", cmp.Candidates, func(_ int, c Candidate) (string, bool) {
+		if c.Interview == nil {
+			return "не проводилось", false
+		}
+		return fmt.Sprintf("%s, вопросов: %d, %s", percent(c.Interview.Score), c.Interview.Questions, c.Interview.CompletedAt.Format("02.01.2006")), false
+	})
+	summaryRow(summary, "Закрытые требования", cmp.Candidates, func(_ int, c Candidate) (string, bool) {
+		return orDash(strings.Join(c.Matched, "\n")), false
+	})
+	summaryRow(summary, "Недостающие требования", cmp.Candidates, func(_ int, c Candidate) (string, bool) {
+		return orDash(strings.Join(c.Missing, "\n")), false
+	})
+
+	heading(doc, "Оценки по критериям", 13)
+	criteria := newTable(doc)
+	header = criteria.AddRow()
+	headerCell(header, "Критерий")
+	headerCell(header, "Категория")
+	for _, c := range cmp.Candidates {
+		headerCell(header, c.Label)
+	}
+	for _, r := range cmp.Criteria {
+		row := criteria.AddRow()
+		requirement := r.Requirement
+		if r.Priority != "" {
+			requirement += " (" + r.Priority + ")"
+		}
+		addCell(row, requirement, false)
+		addCell(row, categoryNames[r.Category], false)
+		top := 0.0
+		for _, c := range r.Cells {
+			if c != nil && c.Score > top {
+				top = c.Score
+			}
+		}
+		for _, c := range r.Cells {
+			if c == nil {
+				addCell(row, "—", false)
+				continue
+			}
+			value := percent(c.Score)
+			if c.Matched {
+				value = "✓ " + value
+			} else {
+				value = "✗ " + value
+			}
+			if c.Via != "" {
+				value += "\nчерез " + c.Via
+			}
+			addCell(row, value, r.Spread > 0 && c.Score == top)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Save(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func heading(doc *document.Document, s string, size measurement.Distance) {
+	run := doc.AddParagraph().AddRun()
+	run.Properties().SetBold(true)
+	run.Properties().SetSize(size)
+	run.AddText(s)
+}
+
+func newTable(doc *document.Document) document.Table {
+	table := doc.AddTable()
+	table.Properties().SetWidthPercent(100)
+	table.Properties().Borders().SetAll(wml.ST_BorderSingle, color.Auto, 0.5*measurement.Point)
+	return table
+}
+
+func summaryRow(table document.Table, title string, candidates []Candidate, value func(int, Candidate) (string, bool)) {
+	row := table.AddRow()
+	addCell(row, title, true)
+	for i, c := range candidates {
+		s, bold := value(i, c)
+		addCell(row, s, bold)
+	}
+}
+
+func headerCell(row document.Row, s string) {
+	c := addCell(row, s, true)
+	c.Properties().SetShading(wml.ST_ShdClear, color.Auto, headerFill)
+}
+
+// addCell добавляет ячейку; переводы строк становятся отдельными абзацами
+func addCell(row document.Row, s string, bold bool) document.Cell {
+	c := row.AddCell()
+	for _, line := range strings.Split(s, "\n") {
+		run := c.AddParagraph().AddRun()
+		run.Properties().SetBold(bold)
+		run.Properties().SetSize(9)
+		run.AddText(line)
+	}
+	return c
+}
+
+func percent(v float64) string {
+	return fmt.Sprintf("%.0f%%", v*100)
+}
+
+func months(n int) string {
+	if n < 12 {
+		return fmt.Sprintf("%d мес.", n)
+	}
+	return fmt.Sprintf("%d г. %d мес.", n/12, n%12)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
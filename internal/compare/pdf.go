@@ -0,0 +1,84 @@
+package compare
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/moverq1337/VTBHack/internal/config"
+)
+
+// Options настройки выгрузки сравнения
+type Options struct {
+	ConverterURL string // Адрес сервиса преобразования DOCX в PDF
+}
+
+// OptionsFrom собирает настройки выгрузки из конфигурации сервиса
+func OptionsFrom(cfg *config.Config) Options {
+	return Options{ConverterURL: cfg.PDFConverterURL}
+}
+
+// ErrNoConverter адрес сервиса преобразования в PDF не задан
+var ErrNoConverter = errors.New("преобразование в PDF не настроено")
+
+// maxPDFSize ограничивает ответ сервиса преобразования
+const maxPDFSize = 20 << 20
+
+// ErrPDFTooLarge PDF больше maxPDFSize
+var ErrPDFTooLarge = fmt.Errorf("PDF больше %d МБ", maxPDFSize>>20)
+
+// converter клиент сервиса преобразования: LibreOffice может зависнуть на
+// документе, и запрос не должен ждать вечно
+var converter = &http.Client{Timeout: time.Minute}
+
+// PDF преобразует DOCX в PDF сервисом Gotenberg (LibreOffice), чтобы PDF
+// выглядел так же, как документ Word, и кириллица не требовала своих шрифтов
+func PDF(ctx context.Context, converterURL string, docx []byte) ([]byte, error) {
+	if converterURL == "" {
+		return nil, ErrNoConverter
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("files", "comparison.docx")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(docx); err != nil {
+		return nil, err
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimRight(converterURL, "/") + "/forms/libreoffice/convert"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	resp, err := converter.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Лишний байт отличает ответ ровно на пределе от обрезанного
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPDFSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("сервис преобразования ответил %d: %s", resp.StatusCode, bytes.TrimSpace(data[:min(len(data), 200)]))
+	}
+	if len(data) > maxPDFSize {
+		return nil, ErrPDFTooLarge
+	}
+	return data, nil
+}
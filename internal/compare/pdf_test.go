@@ -0,0 +1,62 @@
+package compare
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPDF(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		size    int
+		wantErr error
+	}{
+		{"готовый PDF", http.StatusOK, 1000, nil},
+		{"ровно на пределе", http.StatusOK, maxPDFSize, nil},
+		{"больше предела", http.StatusOK, maxPDFSize + 1, ErrPDFTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/forms/libreoffice/convert" {
+					http.NotFound(w, r)
+					return
+				}
+				if _, _, err := r.FormFile("files"); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(tt.status)
+				w.Write(bytes.Repeat([]byte{'x'}, tt.size))
+			}))
+			defer srv.Close()
+
+			data, err := PDF(context.Background(), srv.URL+"/", []byte("docx"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PDF() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && len(data) != tt.size {
+				t.Errorf("len = %d, want %d", len(data), tt.size)
+			}
+		})
+	}
+}
+
+func TestPDFErrors(t *testing.T) {
+	if _, err := PDF(context.Background(), "", nil); !errors.Is(err, ErrNoConverter) {
+		t.Errorf("без адреса: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "LibreOffice упал", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	if _, err := PDF(context.Background(), srv.URL, []byte("docx")); err == nil {
+		t.Error("ответ 503 должен быть ошибкой")
+	}
+}
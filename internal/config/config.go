@@ -2,16 +2,48 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	DBURL        string
+	GRPCHost     string
 	GRPCPort     string
 	HTTPPort     string
 	RedisAddr    string
 	KafkaBrokers string
+
+	NLPTimeout          time.Duration
+	NLPMaxAttempts      int
+	NLPBreakerThreshold int
+	NLPBreakerCooldown  time.Duration
+	NLPKeepaliveTime    time.Duration
+	NLPTargets          []string
+	NLPBalancer         string
+	NLPHealthInterval   time.Duration
+	NLPFallback         bool
+
+	RecencyHalfLife float64 // Лет, за которые зачёт неиспользуемого навыка падает вдвое
+	RecencyGrace    float64 // Лет после последнего использования без затухания
+	RecencyFloor    float64 // Минимальная доля зачёта давно не используемого навыка
+	RecencyUnknown  float64 // Доля зачёта навыка, давность использования которого неизвестна
+
+	VacancyLintStrict bool // Вакансию с ошибками проверки нельзя опубликовать
+
+	VectorIndexDir     string        // Каталог файлов индексов поиска похожих
+	VectorSyncInterval time.Duration // Как часто индексы сверяются с БД и сохраняются
+
+	ClusterInterval time.Duration // Как часто база резюме кластеризуется заново; 0 - только вручную
+
+	TaxonomySyncInterval time.Duration // Как часто таксономия сверяется с БД; 0 - только после своих изменений
+
+	PDFConverterURL string // Адрес Gotenberg для выгрузки в PDF; пусто - выгрузка в PDF отключена
+
+	HRAPIKey string // Ключ доступа HR к профилям сотрудников; пусто - доступ закрыт
 }
 
 func Load() (*Config, error) {
@@ -21,9 +53,89 @@ func Load() (*Config, error) {
 
 	return &Config{
 		DBURL:        os.Getenv("DB_URL"),        // e.g., postgres://user:pass@db:5432/hrdb
+		GRPCHost:     os.Getenv("GRPC_HOST"),     // e.g., scoring-service
 		GRPCPort:     os.Getenv("GRPC_PORT"),     // e.g., :50051
 		HTTPPort:     os.Getenv("HTTP_PORT"),     // e.g., :8080
 		RedisAddr:    os.Getenv("REDIS_ADDR"),    // e.g., redis:6379
 		KafkaBrokers: os.Getenv("KAFKA_BROKERS"), // e.g., kafka:9092
+
+		NLPTimeout:          getDuration("NLP_TIMEOUT", 15*time.Second),
+		NLPMaxAttempts:      getInt("NLP_MAX_ATTEMPTS", 3),
+		NLPBreakerThreshold: getInt("NLP_BREAKER_THRESHOLD", 5),
+		NLPBreakerCooldown:  getDuration("NLP_BREAKER_COOLDOWN", 30*time.Second),
+		NLPKeepaliveTime:    getDuration("NLP_KEEPALIVE_TIME", 30*time.Second),
+		NLPTargets:          getList("NLP_TARGETS"),    // e.g., scoring-1:50051,scoring-2:50051
+		NLPBalancer:         os.Getenv("NLP_BALANCER"), // round_robin или least_request
+		NLPHealthInterval:   getDuration("NLP_HEALTH_INTERVAL", 10*time.Second),
+		NLPFallback:         getBool("NLP_FALLBACK", true),
+
+		RecencyHalfLife: getFloat("RECENCY_HALF_LIFE", 4),
+		RecencyGrace:    getFloat("RECENCY_GRACE", 2),
+		RecencyFloor:    getFloat("RECENCY_FLOOR", 0.3),
+		RecencyUnknown:  getFloat("RECENCY_UNKNOWN", 0.8),
+
+		VacancyLintStrict: getBool("VACANCY_LINT_STRICT", false),
+
+		VectorIndexDir:     getString("VECTOR_INDEX_DIR", "data/vectors"),
+		VectorSyncInterval: getDuration("VECTOR_SYNC_INTERVAL", time.Minute),
+
+		ClusterInterval: getDuration("CLUSTER_INTERVAL", 24*time.Hour),
+
+		TaxonomySyncInterval: getDuration("TAXONOMY_SYNC_INTERVAL", 30*time.Second),
+
+		PDFConverterURL: getString("PDF_CONVERTER_URL", ""), // e.g., http://gotenberg:3000
+
+		HRAPIKey: getString("HR_API_KEY", ""),
 	}, nil
 }
+
+// getDuration читает длительность в формате time.ParseDuration, например "15s"
+func getDuration(key string, def time.Duration) time.Duration {
+	if v, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return v
+	}
+	return def
+}
+
+// getString читает строку; пустая заменяется значением по умолчанию
+func getString(key, def string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return def
+}
+
+// getList читает список значений через запятую
+func getList(key string) []string {
+	var out []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// getBool читает логическое значение: true/false, 1/0
+func getBool(key string, def bool) bool {
+	if v, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return v
+	}
+	return def
+}
+
+// getInt читает целое число
+func getInt(key string, def int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return def
+}
+
+// getFloat читает дробное число
+func getFloat(key string, def float64) float64 {
+	if v, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,142 @@
+package contacts
+
+import (
+	"sort"
+
+	"github.com/moverq1337/VTBHack/internal/segment"
+)
+
+// Kind тип контакта
+type Kind string
+
+const (
+	KindEmail     Kind = "email"
+	KindPhone     Kind = "phone"
+	KindTelegram  Kind = "telegram"
+	KindGitHub    Kind = "github"
+	KindGitLab    Kind = "gitlab"
+	KindLinkedIn  Kind = "linkedin"
+	KindHH        Kind = "hh"
+	KindCity      Kind = "city"
+	KindBirthDate Kind = "birth_date"
+)
+
+// Contact контакт или ссылка кандидата. Value нормализовано: телефон в E.164,
+// почта и ник Telegram в нижнем регистре, ссылки в каноническом виде,
+// дата рождения в формате 2006-01-02.
+type Contact struct {
+	Kind       Kind    `json:"kind"`
+	Value      string  `json:"value"`
+	Raw        string  `json:"raw"`
+	Confidence float64 `json:"confidence"`
+	Start      int     `json:"start"` // Смещение в тексте резюме, в рунах
+	End        int     `json:"end"`
+	Header     bool    `json:"header,omitempty"` // Из шапки резюме: только по таким контактам резюме связывается с кандидатом
+}
+
+// Contacts контакты кандидата, по одному на пару тип-значение
+type Contacts []Contact
+
+// Best возвращает контакт типа с наибольшей уверенностью
+func (cs Contacts) Best(kind Kind) (Contact, bool) {
+	var best Contact
+	found := false
+	for _, c := range cs {
+		if c.Kind == kind && (!found || c.Confidence > best.Confidence) {
+			best, found = c, true
+		}
+	}
+	return best, found
+}
+
+// Header оставляет контакты из шапки резюме
+func (cs Contacts) Header() Contacts {
+	var out Contacts
+	for _, c := range cs {
+		if c.Header {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Value значение лучшего контакта типа; пустая строка, если контакта нет
+func (cs Contacts) Value(kind Kind) string {
+	c, _ := cs.Best(kind)
+	return c.Value
+}
+
+// Merge объединяет контакты, оставляя для повторов большую уверенность
+func Merge(a, b Contacts) Contacts {
+	type key struct {
+		kind  Kind
+		value string
+	}
+	index := make(map[key]int)
+	var out Contacts
+	for _, c := range append(append(Contacts{}, a...), b...) {
+		k := key{c.Kind, c.Value}
+		if i, ok := index[k]; ok {
+			header := out[i].Header || c.Header
+			if c.Confidence > out[i].Confidence {
+				out[i] = c
+			}
+			out[i].Header = header
+			continue
+		}
+		index[k] = len(out)
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Confidence > out[j].Confidence
+	})
+	return out
+}
+
+// headerRunes длина шапки резюме без заголовков разделов
+const headerRunes = 500
+
+// inHeader проверяет, что контакт в шапке резюме: в разделе контактов. Если
+// заголовков нет, весь текст считается одним разделом, и шапкой служат
+// первые headerRunes символов. Почта и телефон из опыта работы часто
+// общие для сотрудников работодателя.
+func inHeader(sections []segment.Section, pos int) bool {
+	structured := false
+	for _, s := range sections {
+		if s.Heading != "" {
+			structured = true
+			break
+		}
+	}
+	if !structured {
+		return pos < headerRunes
+	}
+	for _, s := range segment.Of(sections, segment.KindContacts) {
+		if pos >= s.Start && pos < s.End {
+			return true
+		}
+	}
+	return false
+}
+
+// sectionFactor снижает уверенность для контактов вне раздела контактов:
+// в рекомендациях и прочих разделах чаще встречаются чужие телефоны и почта
+func sectionFactor(sections []segment.Section, pos int) float64 {
+	for _, s := range sections {
+		if pos < s.Start || pos >= s.End {
+			continue
+		}
+		switch s.Kind {
+		case segment.KindContacts, segment.KindSummary:
+			return 1
+		case segment.KindOther:
+			return 0.6
+		default:
+			return 0.85
+		}
+	}
+	return 1
+}
@@ -0,0 +1,140 @@
+package contacts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moverq1337/VTBHack/internal/segment"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		raw        string
+		want       string
+		confidence float64
+		ok         bool
+	}{
+		{"+7 (999) 123-45-67", "+79991234567", 0.9, true},
+		{"8-999-123-45-67", "+79991234567", 0.9, true},
+		{"8 (495) 123 45 67", "+74951234567", 0.8, true},
+		{"+7 495 123-45-67", "+74951234567", 0.8, true},
+		{"999 123-45-67", "+79991234567", 0.6, true}, // Мобильный без кода страны
+		{"+44 20 7946 0958", "+442079460958", 0.75, true},
+		{"+7 999 123-45", "", 0, false},
+		{"+1 234", "", 0, false},
+		{"8 800 555 35", "", 0, false},
+		{"495 123-45-67", "", 0, false},
+	}
+	for _, tt := range tests {
+		got, confidence, ok := normalizePhone(tt.raw)
+		if got != tt.want || confidence != tt.confidence || ok != tt.ok {
+			t.Errorf("normalizePhone(%q) = %q, %v, %v; want %q, %v, %v", tt.raw, got, confidence, ok, tt.want, tt.confidence, tt.ok)
+		}
+	}
+}
+
+func TestParseBirthDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"15.03.1990", "1990-03-15"},
+		{"15 марта 1990", "1990-03-15"},
+		{"5 Mar. 1990", "1990-03-05"},
+		{"1990-03-15", "1990-03-15"},
+		{"31.02.1990", ""}, // Нет такого дня
+		{"15.13.1990", ""},
+		{"01.01.1900", ""}, // Старше 90 лет
+		{"01.01." + time.Now().Format("2006"), ""},
+	}
+	for _, tt := range tests {
+		date, ok := parseBirthDate(tt.in)
+		got := ""
+		if ok {
+			got = date.Format("2006-01-02")
+		}
+		if got != tt.want {
+			t.Errorf("parseBirthDate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	text := "Иванов Иван\nМосква\n+7 (999) 123-45-67\nIvan.Petrov@Example.com\nTelegram: @Ivan_Dev\n" +
+		"https://www.GitHub.com/IvanDev\nlinkedin.com/in/ivan-petrov\nДата рождения: 15 марта 1990\n\n" +
+		"Опыт работы\n2019 — настоящее время ООО Ромашка\nТелефон офиса: 8 (495) 123-45-67, hr@romashka.ru\n\n" +
+		"Рекомендации\nПётр Сидоров, +7 916 000-11-22\n"
+
+	type want struct {
+		confidence float64
+		header     bool
+	}
+	tests := map[Kind]map[string]want{
+		KindEmail: {
+			"ivan.petrov@example.com": {0.95, true},
+			"hr@romashka.ru":          {0.81, false}, // Почта работодателя в опыте работы
+		},
+		KindPhone: {
+			"+79991234567": {0.9, true},
+			"+74951234567": {0.68, false},
+			"+79160001122": {0.54, false}, // Телефон рекомендателя
+		},
+		// Подпись и @ник дают одно значение с большей уверенностью
+		KindTelegram:  {"@ivan_dev": {0.9, true}},
+		KindGitHub:    {"https://github.com/ivandev": {0.9, true}},
+		KindLinkedIn:  {"https://www.linkedin.com/in/ivan-petrov": {0.9, true}},
+		KindBirthDate: {"1990-03-15": {0.9, true}},
+	}
+
+	got := Extract(text, segment.Segment(text))
+	seen := map[Kind]int{}
+	for _, c := range got {
+		seen[c.Kind]++
+		if []rune(text)[c.Start] != []rune(c.Raw)[0] {
+			t.Errorf("%s %s: смещение %d не указывает на %q", c.Kind, c.Value, c.Start, c.Raw)
+		}
+		values, ok := tests[c.Kind]
+		if !ok {
+			continue
+		}
+		w, ok := values[c.Value]
+		if !ok {
+			t.Errorf("лишний контакт %+v", c)
+			continue
+		}
+		if c.Confidence != w.confidence || c.Header != w.header {
+			t.Errorf("%s %s: уверенность %v, шапка %v; want %v, %v", c.Kind, c.Value, c.Confidence, c.Header, w.confidence, w.header)
+		}
+	}
+	for kind, values := range tests {
+		if seen[kind] != len(values) {
+			t.Errorf("%s: найдено %d, want %d", kind, seen[kind], len(values))
+		}
+	}
+	if city := got.Value(KindCity); city != "Москва" {
+		t.Errorf("город = %q", city)
+	}
+	if best, _ := got.Best(KindPhone); best.Value != "+79991234567" {
+		t.Errorf("лучший телефон = %+v", best)
+	}
+	if header := got.Header(); len(header) != 6 {
+		t.Errorf("контактов в шапке %d, want 6: %+v", len(header), header)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := Contacts{{Kind: KindEmail, Value: "a@b.ru", Confidence: 0.6, Header: true}}
+	b := Contacts{
+		{Kind: KindEmail, Value: "a@b.ru", Confidence: 0.9},
+		{Kind: KindPhone, Value: "+79991234567", Confidence: 0.8},
+		{Kind: KindEmail, Value: "c@d.ru", Confidence: 0.95},
+	}
+	got := Merge(a, b)
+	if len(got) != 3 {
+		t.Fatalf("Merge = %+v", got)
+	}
+	// Повтор берёт большую уверенность и сохраняет признак шапки; внутри типа - по убыванию уверенности
+	if got[0].Value != "c@d.ru" || got[1].Value != "a@b.ru" || got[1].Confidence != 0.9 || !got[1].Header || got[2].Kind != KindPhone {
+		t.Errorf("Merge = %+v", got)
+	}
+}
@@ -0,0 +1,213 @@
+package contacts
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/moverq1337/VTBHack/internal/geo"
+	"github.com/moverq1337/VTBHack/internal/segment"
+	"github.com/moverq1337/VTBHack/internal/timeline"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}`)
+	// phonePattern кандидаты в телефоны: +7 (999) 123-45-67, 8-999-123-45-67,
+	// 8 (495) 123 45 67, +44 20 7946 0958, 999 123-45-67
+	phonePattern = regexp.MustCompile(`(?:\+ ?\d|\b[89])[\d \t\-().]{8,18}\d`)
+
+	telegramLink    = regexp.MustCompile(`(?i)(?:https?://)?(?:t|telegram)\.me/([a-z0-9_]{5,32})`)
+	telegramLabel   = regexp.MustCompile(`(?i)(?:^|[^\pL])(?:telegram|телеграм|tg)\s*[:\-–]?\s*@?([a-z][a-z0-9_]{4,31})`)
+	telegramHandle  = regexp.MustCompile(`(?i)(?:^|[\s,;(])@([a-z][a-z0-9_]{4,31})`)
+	githubPattern   = regexp.MustCompile(`(?i)(?:https?://)?(?:www\.)?github\.com/([a-z0-9][a-z0-9-]{0,38})`)
+	gitlabPattern   = regexp.MustCompile(`(?i)(?:https?://)?(?:www\.)?gitlab\.com/([a-z0-9][a-z0-9_.\-]{0,254})`)
+	linkedinPattern = regexp.MustCompile(`(?i)(?:https?://)?(?:[a-z]{2,3}\.)?linkedin\.com/in/([\w\-%]+)`)
+	hhPattern       = regexp.MustCompile(`(?i)(?:https?://)?(?:[a-z]+\.)?hh\.ru/resume/([0-9a-f]+)`)
+
+	// cityLabel город в шаблонах hh.ru ("Проживает: Москва"), SuperJob ("Город: ...") и английских резюме
+	cityLabel = regexp.MustCompile(`(?i)(?:проживает|город|место жительства|местонахождение|location|city)\s*[:\-–]\s*([\pL][\pL\- ]{1,40})`)
+	// birthLabel дата рождения: "родился 15 марта 1990", "Дата рождения: 15.03.1990", "DOB: 1990-03-15"
+	birthLabel = regexp.MustCompile(`(?i)(?:родил(?:ся|ась)|дата рождения|д\.\s?р\.|date of birth|born|dob)\s*[:\-–]?\s*(\d{1,2}[\s./\-]+(?:\pL+\.?|\d{1,2})[\s./\-]+\d{4}|\d{4}-\d{2}-\d{2})`)
+	birthParts = regexp.MustCompile(`^(\d{1,2})[\s./\-]+(\pL+|\d{1,2})\.?[\s./\-]+(\d{4})$`)
+)
+
+// Extract находит в тексте резюме контакты и ссылки кандидата. Разделы
+// нужны для оценки уверенности: контакт из шапки резюме надёжнее, чем
+// телефон в рекомендациях. Без разделов уверенность не снижается.
+func Extract(text string, sections []segment.Section) Contacts {
+	x := extractor{text: text, byteToRune: runeIndex(text), sections: sections}
+
+	x.each(emailPattern, 0, func(m string) (Kind, string, float64, bool) {
+		return KindEmail, strings.ToLower(m), 0.95, true
+	})
+	x.each(phonePattern, 0, func(m string) (Kind, string, float64, bool) {
+		value, confidence, ok := normalizePhone(m)
+		return KindPhone, value, confidence, ok
+	})
+	x.each(telegramLink, 1, telegram(0.95))
+	x.each(telegramLabel, 1, telegram(0.9))
+	x.each(telegramHandle, 1, telegram(0.6))
+	x.each(githubPattern, 1, profile(KindGitHub, "https://github.com/"))
+	x.each(gitlabPattern, 1, profile(KindGitLab, "https://gitlab.com/"))
+	x.each(linkedinPattern, 1, profile(KindLinkedIn, "https://www.linkedin.com/in/"))
+	x.each(hhPattern, 1, profile(KindHH, "https://hh.ru/resume/"))
+	x.each(cityLabel, 1, func(m string) (Kind, string, float64, bool) {
+		city := strings.TrimSpace(m)
+		if known, ok := geo.LookupCity(city); ok {
+			return KindCity, known.Name, 0.9, true
+		}
+		return KindCity, city, 0.8, city != ""
+	})
+	x.cities()
+	x.each(birthLabel, 1, func(m string) (Kind, string, float64, bool) {
+		date, ok := parseBirthDate(m)
+		if !ok {
+			return KindBirthDate, "", 0, false
+		}
+		return KindBirthDate, date.Format("2006-01-02"), 0.9, true
+	})
+
+	return Merge(nil, x.found)
+}
+
+type extractor struct {
+	text       string
+	byteToRune map[int]int
+	sections   []segment.Section
+	found      Contacts
+}
+
+// each добавляет контакты по совпадениям шаблона; group - номер группы со значением
+func (x *extractor) each(pattern *regexp.Regexp, group int, parse func(string) (Kind, string, float64, bool)) {
+	for _, loc := range pattern.FindAllStringSubmatchIndex(x.text, -1) {
+		from, to := loc[2*group], loc[2*group+1]
+		if from < 0 {
+			continue
+		}
+		kind, value, confidence, ok := parse(x.text[from:to])
+		if !ok {
+			continue
+		}
+		start := x.byteToRune[from]
+		x.found = append(x.found, Contact{
+			Kind:       kind,
+			Value:      value,
+			Raw:        x.text[from:to],
+			Confidence: round(confidence * sectionFactor(x.sections, start)),
+			Start:      start,
+			End:        x.byteToRune[to],
+			Header:     inHeader(x.sections, start),
+		})
+	}
+}
+
+// cities ищет города справочника без подписи в строках раздела контактов
+func (x *extractor) cities() {
+	for _, s := range segment.Of(x.sections, segment.KindContacts) {
+		offset := s.Start
+		for _, line := range strings.Split(s.Text, "\n") {
+			name, _, _ := strings.Cut(line, ",")
+			name = strings.TrimSpace(name)
+			if city, ok := geo.LookupCity(name); ok {
+				start := offset + len([]rune(line)) - len([]rune(strings.TrimLeftFunc(line, unicode.IsSpace)))
+				x.found = append(x.found, Contact{Kind: KindCity, Value: city.Name, Raw: name, Confidence: 0.6, Start: start, End: start + len([]rune(name))})
+			}
+			offset += len([]rune(line)) + 1
+		}
+	}
+}
+
+func telegram(confidence float64) func(string) (Kind, string, float64, bool) {
+	return func(m string) (Kind, string, float64, bool) {
+		return KindTelegram, "@" + strings.ToLower(m), confidence, true
+	}
+}
+
+func profile(kind Kind, prefix string) func(string) (Kind, string, float64, bool) {
+	return func(m string) (Kind, string, float64, bool) {
+		return kind, prefix + strings.ToLower(strings.TrimRight(m, ".-_")), 0.9, true
+	}
+}
+
+// normalizePhone приводит номер к E.164. Российские номера записываются
+// через 8 или +7, мобильный номер может быть указан без кода страны.
+func normalizePhone(raw string) (string, float64, bool) {
+	var digits strings.Builder
+	for _, r := range raw {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	d := digits.String()
+	plus := strings.HasPrefix(raw, "+")
+
+	switch {
+	case plus && strings.HasPrefix(d, "7"):
+		if len(d) != 11 {
+			return "", 0, false
+		}
+	case plus:
+		if len(d) < 10 || len(d) > 15 {
+			return "", 0, false
+		}
+		return "+" + d, 0.75, true
+	case strings.HasPrefix(d, "8") && len(d) == 11:
+		d = "7" + d[1:]
+	case strings.HasPrefix(d, "9") && len(d) == 10:
+		return "+7" + d, 0.6, true
+	default:
+		return "", 0, false
+	}
+
+	if d[1] == '9' {
+		return "+" + d, 0.9, true
+	}
+	return "+" + d, 0.8, true
+}
+
+// parseBirthDate разбирает дату рождения; возраст кандидата от 14 до 90 лет
+func parseBirthDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	var day, month, year int
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		day, month, year = t.Day(), int(t.Month()), t.Year()
+	} else if m := birthParts.FindStringSubmatch(s); m != nil {
+		day, _ = strconv.Atoi(m[1])
+		year, _ = strconv.Atoi(m[3])
+		if n, err := strconv.Atoi(m[2]); err == nil {
+			month = n
+		} else {
+			month = int(timeline.MonthByName(m[2]))
+		}
+	} else {
+		return time.Time{}, false
+	}
+
+	now := time.Now()
+	if month < 1 || month > 12 || day < 1 || year < now.Year()-90 || year > now.Year()-14 {
+		return time.Time{}, false
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Day() != day {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// runeIndex сопоставляет байтовым смещениям строки смещения в рунах
+func runeIndex(text string) map[int]int {
+	idx := make(map[int]int, len(text)+1)
+	n := 0
+	for i := range text {
+		idx[i] = n
+		n++
+	}
+	idx[len(text)] = n
+	return idx
+}
+
+func round(v float64) float64 {
+	return float64(int(v*100+0.5)) / 100
+}
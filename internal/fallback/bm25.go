@@ -0,0 +1,80 @@
+package fallback
+
+import (
+	"math"
+
+	"github.com/moverq1337/VTBHack/internal/textproc"
+)
+
+// Параметры BM25
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// lexicalScore оценивает, насколько резюме покрывает термины вакансии.
+// Термины вакансии служат запросом BM25 к резюме. IDF считается по
+// предложениям вакансии: термины, которые повторяются в каждом пункте,
+// весят меньше специфичных требований. Сумма нормируется на максимально
+// достижимое значение, поэтому результат лежит в [0, 1).
+func lexicalScore(resumeText, vacancyText string) float64 {
+	resumeStems := textproc.Stems(resumeText)
+	vacancyStems := textproc.Stems(vacancyText)
+	if len(resumeStems) == 0 || len(vacancyStems) == 0 {
+		return 0
+	}
+
+	idf := sentenceIDF(vacancyText)
+
+	tf := make(map[string]int, len(resumeStems))
+	for _, s := range resumeStems {
+		tf[s]++
+	}
+
+	dl := float64(len(resumeStems))
+	avgdl := float64(len(resumeStems)+len(vacancyStems)) / 2
+
+	seen := make(map[string]bool, len(vacancyStems))
+	var score, maxScore float64
+	for _, term := range vacancyStems {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+
+		w := idf(term)
+		maxScore += w
+		if f := float64(tf[term]); f > 0 {
+			sat := f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*dl/avgdl))
+			score += w * sat / (bm25K1 + 1)
+		}
+	}
+
+	if maxScore == 0 {
+		return 0
+	}
+	return score / maxScore
+}
+
+// sentenceIDF строит функцию IDF по предложениям переданных текстов
+func sentenceIDF(texts ...string) func(term string) float64 {
+	df := make(map[string]int)
+	n := 0
+	for _, text := range texts {
+		for _, s := range textproc.Sentences(text) {
+			n++
+			seen := make(map[string]bool)
+			for _, stem := range textproc.Stems(s.Text) {
+				if !seen[stem] {
+					seen[stem] = true
+					df[stem]++
+				}
+			}
+		}
+	}
+
+	return func(term string) float64 {
+		d := float64(df[term])
+		return math.Log(1 + (float64(n)-d+0.5)/(d+0.5))
+	}
+}
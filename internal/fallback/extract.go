@@ -0,0 +1,106 @@
+package fallback
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moverq1337/VTBHack/internal/textproc"
+	"github.com/moverq1337/VTBHack/internal/timeline"
+)
+
+// Паттерны перенесены из scoring-service, чтобы резервный разбор
+// возвращал данные той же структуры
+var (
+	experiencePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)опыт работы.*?(\d+)[^\d]*(?:год|лет)`),
+		regexp.MustCompile(`(?i)опыт[^\d\n]{0,30}(\d+)[^\d]*(?:год|лет)`),
+		regexp.MustCompile(`(?i)(\d+)[^\d]*лет.*?опыт`),
+		regexp.MustCompile(`(?i)стаж.*?(\d+)[^\d]*(?:год|лет)`),
+		regexp.MustCompile(`(?i)работаю.*?(\d+)[^\d]*(?:год|лет)`),
+		regexp.MustCompile(`(?i)experience.*?(\d+)[^\d]*year`),
+		regexp.MustCompile(`(?i)(\d+)\+?\s*years?`),
+	}
+
+	educationLevels = []string{
+		"высшее образование", "среднее специальное", "неоконченное высшее",
+		"бакалавр", "магистр", "кандидат наук", "доктор наук",
+	}
+	universityPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)([А-ЯЁ][а-яё]+\s*(университет|институт|академия))`),
+		regexp.MustCompile(`(?i)([А-ЯЁ][а-яё]+\s*государственный\s*(университет|институт))`),
+		regexp.MustCompile(`(МГУ|СПбГУ|МФТИ|МГТУ|ВШЭ)`),
+	}
+
+	languagePatterns = []struct {
+		name    string
+		pattern *regexp.Regexp
+	}{
+		{"Английский", regexp.MustCompile(`(?i)английск|english`)},
+		{"Немецкий", regexp.MustCompile(`(?i)немецк|german`)},
+		{"Французский", regexp.MustCompile(`(?i)французск|french`)},
+		{"Испанский", regexp.MustCompile(`(?i)испанск|spanish`)},
+		{"Китайский", regexp.MustCompile(`(?i)китайск|chinese`)},
+	}
+)
+
+// maxPlausibleYears отсекает числа, которые не могут быть стажем
+const maxPlausibleYears = 50
+
+// extractExperience оценивает опыт работы в годах
+func extractExperience(text string) float64 {
+	best := 0
+	for _, p := range experiencePatterns {
+		for _, m := range p.FindAllStringSubmatch(text, -1) {
+			if years, err := strconv.Atoi(m[1]); err == nil && years <= maxPlausibleYears && years > best {
+				best = years
+			}
+		}
+	}
+	if best > 0 {
+		return float64(best)
+	}
+
+	// Если стаж не указан явно, считаем его по истории работы
+	// без двойного учёта пересекающихся периодов
+	return min(timeline.Extract(text, time.Now(), nil).Years(), maxPlausibleYears)
+}
+
+// extractEducation находит уровни образования и учебные заведения
+func extractEducation(text string) map[string][]string {
+	normalized := textproc.Normalize(text)
+	levels := []string{}
+	for _, level := range educationLevels {
+		if strings.Contains(normalized, level) {
+			levels = append(levels, level)
+		}
+	}
+
+	institutions := []string{}
+	seen := make(map[string]bool)
+	for _, p := range universityPatterns {
+		for _, m := range p.FindAllString(text, -1) {
+			if !seen[m] {
+				seen[m] = true
+				institutions = append(institutions, m)
+			}
+		}
+	}
+
+	return map[string][]string{
+		"levels":       levels,
+		"institutions": institutions,
+	}
+}
+
+// extractLanguages находит упомянутые иностранные языки
+func extractLanguages(text string) []string {
+	languages := []string{"Русский"}
+	for _, l := range languagePatterns {
+		if l.pattern.MatchString(text) {
+			languages = append(languages, l.name)
+		}
+	}
+	return languages
+}
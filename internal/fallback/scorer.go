@@ -0,0 +1,243 @@
+package fallback
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/moverq1337/VTBHack/internal/pb"
+	"github.com/moverq1337/VTBHack/internal/textproc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Веса компонентов оценки совпадают с весами scoring-service
+const (
+	lexicalWeight    = 0.5
+	skillsWeight     = 0.3
+	experienceWeight = 0.2
+)
+
+// Scorer резервный скорер на Go. Реализует pb.NLPServiceClient,
+// поэтому подставляется вместо NLP-сервиса без изменений у вызывающих.
+type Scorer struct {
+	skills []textproc.SkillEntry
+}
+
+var _ pb.NLPServiceClient = (*Scorer)(nil)
+
+// New создаёт скорер со встроенным словарём навыков
+func New() *Scorer {
+	return &Scorer{skills: textproc.DefaultSkills}
+}
+
+// ParseResume извлекает навыки, опыт, образование и языки. Если переданы
+// разделы резюме, стаж считается только по опыту работы, а навыки из
+// курсов возвращаются отдельно и в общий список не входят.
+func (s *Scorer) ParseResume(ctx context.Context, in *pb.ParseRequest, opts ...grpc.CallOption) (*pb.ParseResponse, error) {
+	text := in.Text
+	skillsText, experienceText, educationText, languagesText := text, text, text, text
+	var courseSkills map[string][]string
+	if len(in.Sections) > 0 {
+		skillsText = sectionText(in.Sections, false, "courses", "education")
+		experienceText = orText(sectionText(in.Sections, true, "experience"), text)
+		educationText = orText(sectionText(in.Sections, true, "education", "courses"), text)
+		languagesText = orText(sectionText(in.Sections, true, "languages"), text)
+		courseSkills = textproc.SkillsByCategory(textproc.FindSkills(sectionText(in.Sections, true, "courses"), s.skills))
+	}
+
+	parsed := map[string]interface{}{
+		"skills":     textproc.SkillsByCategory(textproc.FindSkills(skillsText, s.skills)),
+		"experience": extractExperience(experienceText),
+		"education":  extractEducation(educationText),
+		"languages":  extractLanguages(languagesText),
+		"degraded":   true,
+	}
+	if courseSkills != nil {
+		parsed["course_skills"] = courseSkills
+	}
+
+	data, err := json.Marshal(parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ParseResponse{ParsedData: string(data)}, nil
+}
+
+// MatchResumeVacancy оценивает пару резюме-вакансия
+func (s *Scorer) MatchResumeVacancy(ctx context.Context, in *pb.MatchRequest, opts ...grpc.CallOption) (*pb.MatchResponse, error) {
+	return &pb.MatchResponse{Score: s.score(in.ResumeText, in.VacancyText)}, nil
+}
+
+// BatchMatch оценивает резюме относительно одной вакансии
+func (s *Scorer) BatchMatch(ctx context.Context, in *pb.BatchMatchRequest, opts ...grpc.CallOption) (*pb.BatchMatchResponse, error) {
+	resp := &pb.BatchMatchResponse{Results: make([]*pb.MatchResult, 0, len(in.Resumes))}
+	for _, r := range in.Resumes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp.Results = append(resp.Results, &pb.MatchResult{
+			ResumeId:  r.Id,
+			VacancyId: in.VacancyId,
+			Score:     s.score(r.Text, in.VacancyText),
+		})
+	}
+	return resp, nil
+}
+
+// StreamMatch оценивает пары синхронно при отправке и отдаёт результаты через Recv
+func (s *Scorer) StreamMatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[pb.MatchItem, pb.MatchResult], error) {
+	st := &localStream{ctx: ctx, scorer: s, texts: make(map[string]string)}
+	st.cond = sync.NewCond(&st.mu)
+	// Отмена контекста будит ожидающий Recv
+	context.AfterFunc(ctx, func() { st.CloseSend() })
+	return st, nil
+}
+
+// Embed не поддерживается: векторы другой модели несравнимы с векторами
+// NLP-сервиса и испортили бы индекс похожих резюме
+func (s *Scorer) Embed(ctx context.Context, in *pb.EmbedRequest, opts ...grpc.CallOption) (*pb.EmbedResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "резервный скорер не вычисляет векторы")
+}
+
+// score комбинирует лексическое сходство, покрытие навыков и опыт
+func (s *Scorer) score(resumeText, vacancyText string) float32 {
+	lexical := lexicalScore(resumeText, vacancyText)
+
+	vacancySkills := textproc.FindSkills(vacancyText, s.skills)
+	resumeSkills := make(map[string]bool)
+	for _, m := range textproc.FindSkills(resumeText, s.skills) {
+		resumeSkills[m.Name] = true
+	}
+
+	weightLexical, weightSkills := lexicalWeight, skillsWeight
+	skillRatio := 0.0
+	if len(vacancySkills) > 0 {
+		matched := 0
+		for _, m := range vacancySkills {
+			if resumeSkills[m.Name] {
+				matched++
+			}
+		}
+		skillRatio = float64(matched) / float64(len(vacancySkills))
+	} else {
+		// В вакансии нет навыков из словаря: их вес переходит лексической оценке
+		weightLexical, weightSkills = lexicalWeight+skillsWeight, 0
+	}
+
+	expMatch := 1.0
+	if required := extractExperience(vacancyText); required > 0 {
+		if have := extractExperience(resumeText); have < required {
+			expMatch = have / required
+		}
+	}
+
+	score := weightLexical*lexical + weightSkills*skillRatio + experienceWeight*expMatch
+	return float32(max(0, min(1, score)))
+}
+
+// localStream реализует двунаправленный поток в памяти процесса
+type localStream struct {
+	ctx    context.Context
+	scorer *Scorer
+	texts  map[string]string
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	results []*pb.MatchResult
+	closed  bool
+}
+
+func (st *localStream) Send(item *pb.MatchItem) error {
+	if err := st.ctx.Err(); err != nil {
+		return err
+	}
+
+	resumeKey, vacancyKey := "resume:"+item.ResumeId, "vacancy:"+item.VacancyId
+	if item.ResumeText != "" {
+		st.texts[resumeKey] = item.ResumeText
+	}
+	if item.VacancyText != "" {
+		st.texts[vacancyKey] = item.VacancyText
+	}
+
+	res := &pb.MatchResult{Seq: item.Seq, ResumeId: item.ResumeId, VacancyId: item.VacancyId}
+	resumeText, okResume := st.texts[resumeKey]
+	vacancyText, okVacancy := st.texts[vacancyKey]
+	if okResume && okVacancy {
+		res.Score = st.scorer.score(resumeText, vacancyText)
+	} else {
+		res.Error = "текст резюме или вакансии не передан"
+	}
+
+	st.mu.Lock()
+	st.results = append(st.results, res)
+	st.mu.Unlock()
+	st.cond.Signal()
+	return nil
+}
+
+func (st *localStream) Recv() (*pb.MatchResult, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	for len(st.results) == 0 && !st.closed {
+		st.cond.Wait()
+	}
+	if len(st.results) == 0 {
+		if err := st.ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	res := st.results[0]
+	st.results = st.results[1:]
+	return res, nil
+}
+
+func (st *localStream) CloseSend() error {
+	st.mu.Lock()
+	st.closed = true
+	st.mu.Unlock()
+	st.cond.Broadcast()
+	return nil
+}
+
+func (st *localStream) Context() context.Context     { return st.ctx }
+func (st *localStream) Header() (metadata.MD, error) { return metadata.MD{}, nil }
+func (st *localStream) Trailer() metadata.MD         { return metadata.MD{} }
+func (st *localStream) SendMsg(m interface{}) error  { return st.Send(m.(*pb.MatchItem)) }
+func (st *localStream) RecvMsg(m interface{}) error {
+	res, err := st.Recv()
+	if err != nil {
+		return err
+	}
+	proto.Merge(m.(*pb.MatchResult), res)
+	return nil
+}
+
+// sectionText склеивает текст разделов указанных типов (include) или всех
+// остальных разделов (!include)
+func sectionText(sections []*pb.Section, include bool, kinds ...string) string {
+	var parts []string
+	for _, sec := range sections {
+		if slices.Contains(kinds, sec.Kind) == include {
+			parts = append(parts, sec.Text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func orText(text, fallback string) string {
+	if strings.TrimSpace(text) == "" {
+		return fallback
+	}
+	return text
+}
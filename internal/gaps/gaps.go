@@ -0,0 +1,343 @@
+// Package gaps находит в обосновании оценки незакрытые и слабо закрытые
+// требования вакансии и подбирает к ним учебные материалы из каталога,
+// чтобы вместо отказа можно было предложить план развития.
+package gaps
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/requirements"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"github.com/moverq1337/VTBHack/internal/textproc"
+)
+
+// Серьёзность пробела
+const (
+	SeverityCritical = "critical" // Обязательное требование почти не закрыто
+	SeverityMajor    = "major"    // Обязательное требование не закрыто
+	SeverityMinor    = "minor"    // Требование закрыто слабо или желательное не закрыто
+)
+
+// Вид пробела
+const (
+	KindMissing = "missing"
+	KindWeak    = "weak"
+)
+
+// Как материал связан с пробелом
+const (
+	MatchSkill   = "skill"   // Тот же навык
+	MatchRelated = "related" // Более общий или более частный навык
+	MatchKeyword = "keyword" // Ключевые слова требования
+)
+
+// Пороги оценки критерия
+const (
+	criticalBelow = 0.25 // Обязательное требование с меньшей оценкой - критичный пробел
+	strongFrom    = 0.8  // Закрытое требование с меньшей оценкой - слабое
+)
+
+// DefaultResources материалов на пробел по умолчанию
+const DefaultResources = 3
+
+// untrainable категории, которые не закрываются обучением: стаж и уровень
+// растут со временем, а не после курса
+var untrainable = map[string]bool{
+	matching.CategorySemantic:   true,
+	matching.CategoryExperience: true,
+	matching.CategorySeniority:  true,
+	matching.CategoryLocation:   true,
+	matching.CategorySalary:     true,
+}
+
+var severityRank = map[string]int{SeverityCritical: 0, SeverityMajor: 1, SeverityMinor: 2}
+
+// Suggestion материал каталога для пробела
+type Suggestion struct {
+	models.LearningResource
+	Match string `json:"match"`           // skill, related или keyword
+	Skill string `json:"skill,omitempty"` // Навык материала, через который он подобран
+}
+
+// Gap незакрытое или слабо закрытое требование
+type Gap struct {
+	Requirement string       `json:"requirement"`
+	Category    string       `json:"category"`
+	Priority    string       `json:"priority,omitempty"`
+	Severity    string       `json:"severity"`
+	Kind        string       `json:"kind"` // missing или weak
+	Score       float64      `json:"score"`
+	Weight      float64      `json:"weight"` // Доля критерия в итоговой оценке
+	Note        string       `json:"note,omitempty"`
+	Skills      []string     `json:"skills"` // Навыки таксономии в требовании
+	Resources   []Suggestion `json:"resources"`
+}
+
+// Report пробелы кандидата по вакансии и план развития
+type Report struct {
+	Score     float64                   `json:"score"`
+	Potential float64                   `json:"potential"` // Оценка, если закрыть все пробелы
+	Gaps      []Gap                     `json:"gaps"`
+	Severity  map[string]int            `json:"severity"`   // Число пробелов по серьёзности
+	Plan      []models.LearningResource `json:"plan"`       // Первый материал каждого пробела без повторов
+	PlanHours int                       `json:"plan_hours"` // Суммарная длительность плана
+	Uncovered []string                  `json:"uncovered"`  // Пробелы, для которых в каталоге нет материалов
+}
+
+// Analyze выбирает пробелы из обоснования оценки и подбирает к каждому до
+// perGap материалов каталога. Пробелы идут от критичных к мелким, внутри
+// серьёзности - по убыванию веса критерия.
+func Analyze(exp matching.Explanation, t *taxonomy.Taxonomy, catalog []models.LearningResource, perGap int) Report {
+	if perGap <= 0 {
+		perGap = DefaultResources
+	}
+	idx := newIndex(catalog, t)
+
+	report := Report{
+		Score:     exp.Score,
+		Potential: exp.Score,
+		Gaps:      []Gap{},
+		Severity:  map[string]int{SeverityCritical: 0, SeverityMajor: 0, SeverityMinor: 0},
+		Plan:      []models.LearningResource{},
+		Uncovered: []string{},
+	}
+	for _, c := range exp.Criteria {
+		severity, kind, ok := classify(c)
+		if !ok {
+			continue
+		}
+		gap := Gap{
+			Requirement: c.Requirement,
+			Category:    c.Category,
+			Priority:    c.Priority,
+			Severity:    severity,
+			Kind:        kind,
+			Score:       c.Score,
+			Weight:      c.Weight,
+			Note:        c.Note,
+			Skills:      gapSkills(c, t),
+		}
+		gap.Resources = idx.suggest(gap, perGap)
+		report.Gaps = append(report.Gaps, gap)
+		report.Severity[severity]++
+		report.Potential += (1 - c.Score) * c.Weight
+	}
+	if report.Potential > 1 {
+		report.Potential = 1
+	}
+
+	sort.SliceStable(report.Gaps, func(i, j int) bool {
+		a, b := report.Gaps[i], report.Gaps[j]
+		if a.Severity != b.Severity {
+			return severityRank[a.Severity] < severityRank[b.Severity]
+		}
+		return a.Weight > b.Weight
+	})
+
+	planned := map[uuid.UUID]bool{}
+	for _, g := range report.Gaps {
+		if len(g.Resources) == 0 {
+			report.Uncovered = append(report.Uncovered, g.Requirement)
+			continue
+		}
+		first := g.Resources[0].LearningResource
+		if !planned[first.ID] {
+			planned[first.ID] = true
+			report.Plan = append(report.Plan, first)
+			report.PlanHours += first.Hours
+		}
+	}
+	return report
+}
+
+// classify решает, является ли критерий пробелом, и оценивает его серьёзность
+func classify(c matching.Criterion) (severity, kind string, ok bool) {
+	if untrainable[c.Category] {
+		return "", "", false
+	}
+	nice := c.Priority == string(requirements.PriorityNice)
+	switch {
+	case c.Matched && c.Score >= strongFrom:
+		return "", "", false
+	case c.Matched:
+		return SeverityMinor, KindWeak, true
+	case nice:
+		return SeverityMinor, KindMissing, true
+	case c.Score < criticalBelow:
+		return SeverityCritical, KindMissing, true
+	default:
+		return SeverityMajor, KindMissing, true
+	}
+}
+
+// gapSkills находит навыки таксономии, которых касается требование
+func gapSkills(c matching.Criterion, t *taxonomy.Taxonomy) []string {
+	if c.Category == matching.CategorySkills {
+		if s, ok := t.Lookup(c.Requirement); ok {
+			return []string{s.Name}
+		}
+		return []string{c.Requirement}
+	}
+	skills := []string{}
+	seen := map[string]bool{}
+	for _, name := range t.Extract(c.Requirement) {
+		if !seen[name] {
+			seen[name] = true
+			skills = append(skills, name)
+		}
+	}
+	return skills
+}
+
+// index каталог, разложенный по навыкам и ключевым словам
+type index struct {
+	taxonomy *taxonomy.Taxonomy
+	bySkill  map[string][]int // Нормализованное название навыка -> материалы
+	keywords [][][]string     // Основы слов ключевых фраз материала
+	catalog  []models.LearningResource
+}
+
+func newIndex(catalog []models.LearningResource, t *taxonomy.Taxonomy) *index {
+	idx := &index{taxonomy: t, bySkill: map[string][]int{}, keywords: make([][][]string, len(catalog)), catalog: catalog}
+	for i, r := range catalog {
+		for _, name := range splitList(r.Skills) {
+			key := skillKey(t, name)
+			idx.bySkill[key] = append(idx.bySkill[key], i)
+		}
+		for _, phrase := range splitList(r.Keywords) {
+			if stems := contentStems(phrase); len(stems) > 0 {
+				idx.keywords[i] = append(idx.keywords[i], stems)
+			}
+		}
+	}
+	return idx
+}
+
+// suggest подбирает материалы: сначала по тому же навыку, затем по
+// общему или частному навыку, затем по ключевым словам требования.
+// Внутри группы внутреннее обучение идёт первым, затем более короткое.
+func (idx *index) suggest(g Gap, limit int) []Suggestion {
+	type candidate struct {
+		i     int
+		tier  int
+		match string
+		skill string
+	}
+	best := map[int]candidate{}
+	add := func(i, tier int, match, skill string) {
+		if cur, ok := best[i]; !ok || tier < cur.tier {
+			best[i] = candidate{i, tier, match, skill}
+		}
+	}
+
+	for _, name := range g.Skills {
+		for _, i := range idx.bySkill[skillKey(idx.taxonomy, name)] {
+			add(i, 0, MatchSkill, name)
+		}
+		for _, rel := range idx.family(name) {
+			for _, i := range idx.bySkill[skillKey(idx.taxonomy, rel)] {
+				add(i, 1, MatchRelated, rel)
+			}
+		}
+	}
+	stems := map[string]bool{}
+	for _, s := range contentStems(g.Requirement) {
+		stems[s] = true
+	}
+	for i, kws := range idx.keywords {
+		for _, kw := range kws {
+			if containsAll(stems, kw) {
+				add(i, 2, MatchKeyword, "")
+				break
+			}
+		}
+	}
+
+	found := make([]candidate, 0, len(best))
+	for _, c := range best {
+		found = append(found, c)
+	}
+	sort.Slice(found, func(a, b int) bool {
+		x, y := found[a], found[b]
+		rx, ry := idx.catalog[x.i], idx.catalog[y.i]
+		switch {
+		case x.tier != y.tier:
+			return x.tier < y.tier
+		case rx.Internal != ry.Internal:
+			return rx.Internal
+		case rx.Hours != ry.Hours:
+			return rx.Hours < ry.Hours
+		}
+		return rx.Title < ry.Title
+	})
+
+	out := make([]Suggestion, 0, min(limit, len(found)))
+	for _, c := range found[:min(limit, len(found))] {
+		out = append(out, Suggestion{LearningResource: idx.catalog[c.i], Match: c.match, Skill: c.skill})
+	}
+	return out
+}
+
+// family возвращает общий навык и частные навыки: курс по SQL подходит
+// для пробела по PostgreSQL, курс по PostgreSQL - для пробела по SQL
+func (idx *index) family(name string) []string {
+	s, ok := idx.taxonomy.Lookup(name)
+	if !ok {
+		return nil
+	}
+	var names []string
+	if s.ParentID != nil {
+		if p, ok := idx.taxonomy.Get(*s.ParentID); ok {
+			names = append(names, p.Name)
+		}
+	}
+	for _, id := range idx.taxonomy.Descendants(s.ID) {
+		if d, ok := idx.taxonomy.Get(id); ok {
+			names = append(names, d.Name)
+		}
+	}
+	return names
+}
+
+// skillKey приводит навык к каноническому написанию; неизвестный навык
+// сравнивается по нормализованному написанию
+func skillKey(t *taxonomy.Taxonomy, name string) string {
+	if s, ok := t.Lookup(name); ok {
+		return s.Slug
+	}
+	return taxonomy.NormalizeAlias(name)
+}
+
+// contentStems основы слов фразы без однобуквенных
+func contentStems(text string) []string {
+	var stems []string
+	for _, s := range textproc.Stems(text) {
+		if len([]rune(s)) >= 2 {
+			stems = append(stems, s)
+		}
+	}
+	return stems
+}
+
+func containsAll(set map[string]bool, stems []string) bool {
+	for _, s := range stems {
+		if !set[s] {
+			return false
+		}
+	}
+	return true
+}
+
+func splitList(list string) []string {
+	var out []string
+	for _, part := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ';' || r == '\n' }) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,173 @@
+package gaps
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+)
+
+func testTaxonomy() *taxonomy.Taxonomy {
+	sql := uuid.New()
+	skills := []models.Skill{
+		{ID: sql, Slug: "sql", Name: "SQL"},
+		{ID: uuid.New(), Slug: "postgresql", Name: "PostgreSQL", ParentID: &sql},
+		{ID: uuid.New(), Slug: "go", Name: "Go", Aliases: []models.SkillAlias{{Alias: "golang"}}},
+		{ID: uuid.New(), Slug: "kafka", Name: "Kafka"},
+		{ID: uuid.New(), Slug: "docker", Name: "Docker"},
+	}
+	return taxonomy.New(skills, nil)
+}
+
+func resource(title, skills, keywords string, hours int, internal bool) models.LearningResource {
+	return models.LearningResource{ID: uuid.New(), Title: title, Skills: skills, Keywords: keywords, Hours: hours, Internal: internal}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		c        matching.Criterion
+		severity string
+		kind     string
+		ok       bool
+	}{
+		{"закрыто полностью", matching.Criterion{Category: matching.CategorySkills, Matched: true, Score: 0.9}, "", "", false},
+		{"закрыто слабо", matching.Criterion{Category: matching.CategorySkills, Matched: true, Score: 0.5}, SeverityMinor, KindWeak, true},
+		{"обязательное почти не закрыто", matching.Criterion{Category: matching.CategorySkills, Priority: "must", Score: 0.1}, SeverityCritical, KindMissing, true},
+		{"обязательное не закрыто", matching.Criterion{Category: matching.CategoryRequirement, Priority: "must", Score: 0.4}, SeverityMajor, KindMissing, true},
+		{"желательное не закрыто", matching.Criterion{Category: matching.CategorySkills, Priority: "nice", Score: 0}, SeverityMinor, KindMissing, true},
+		{"семантика не учится", matching.Criterion{Category: matching.CategorySemantic, Score: 0}, "", "", false},
+		{"город не учится", matching.Criterion{Category: matching.CategoryLocation, Score: 0}, "", "", false},
+		{"зарплата не учится", matching.Criterion{Category: matching.CategorySalary, Score: 0}, "", "", false},
+		{"стаж не учится", matching.Criterion{Category: matching.CategoryExperience, Priority: "must", Score: 0}, "", "", false},
+		{"уровень не учится", matching.Criterion{Category: matching.CategorySeniority, Score: 0.2}, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			severity, kind, ok := classify(tt.c)
+			if severity != tt.severity || kind != tt.kind || ok != tt.ok {
+				t.Errorf("classify = %q, %q, %v; want %q, %q, %v", severity, kind, ok, tt.severity, tt.kind, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	tx := testTaxonomy()
+	goCourse := resource("Go для бэкенда", "Go", "", 40, false)
+	goInternal := resource("Go в банке", "Go", "", 60, true)
+	sqlCourse := resource("Основы SQL", "SQL", "", 20, false)
+	mongo := resource("MongoDB", "MongoDB", "", 10, false)
+	agile := resource("Scrum на практике", "", "работа по scrum", 8, false)
+	catalog := []models.LearningResource{goCourse, goInternal, sqlCourse, mongo, agile}
+
+	exp := matching.Explanation{
+		Score: 0.55,
+		Criteria: []matching.Criterion{
+			{Category: matching.CategorySemantic, Requirement: "Близость текстов", Score: 0.2, Weight: 0.3},
+			{Category: matching.CategorySkills, Requirement: "golang", Priority: "must", Score: 0, Weight: 0.2},
+			{Category: matching.CategorySkills, Requirement: "PostgreSQL", Priority: "must", Matched: true, Score: 0.5, Weight: 0.1},
+			{Category: matching.CategorySkills, Requirement: "Kafka", Priority: "must", Score: 0.4, Weight: 0.15},
+			{Category: matching.CategoryRequirement, Requirement: "Опыт работы по Scrum", Priority: "nice", Score: 0, Weight: 0.05},
+			{Category: matching.CategorySkills, Requirement: "Docker", Priority: "must", Matched: true, Score: 1, Weight: 0.2},
+			// Стаж и уровень не пробелы: курс "Go" не должен попасть в план ради "опыт Go 5+ лет"
+			{Category: matching.CategoryExperience, Requirement: "Опыт Go 5+ лет", Priority: "must", Score: 0.3, Weight: 0.1},
+			{Category: matching.CategorySeniority, Requirement: "Senior", Score: 0, Weight: 0.1},
+		},
+	}
+	report := Analyze(exp, tx, catalog, 0)
+
+	type gapView struct {
+		requirement string
+		severity    string
+		kind        string
+		resources   []string
+		match       string
+	}
+	var got []gapView
+	for _, g := range report.Gaps {
+		v := gapView{requirement: g.Requirement, severity: g.Severity, kind: g.Kind}
+		for _, r := range g.Resources {
+			v.resources = append(v.resources, r.Title)
+		}
+		if len(g.Resources) > 0 {
+			v.match = g.Resources[0].Match
+		}
+		got = append(got, v)
+	}
+	want := []gapView{
+		// Критичный первым; внутреннее обучение впереди более короткого
+		{"golang", SeverityCritical, KindMissing, []string{"Go в банке", "Go для бэкенда"}, MatchSkill},
+		{"Kafka", SeverityMajor, KindMissing, nil, ""},
+		// Среди мелких - по убыванию веса; PostgreSQL закрывается курсом по общему SQL
+		{"PostgreSQL", SeverityMinor, KindWeak, []string{"Основы SQL"}, MatchRelated},
+		{"Опыт работы по Scrum", SeverityMinor, KindMissing, []string{"Scrum на практике"}, MatchKeyword},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("gaps =\n%+v\nwant\n%+v", got, want)
+	}
+
+	wantSeverity := map[string]int{SeverityCritical: 1, SeverityMajor: 1, SeverityMinor: 2}
+	if !reflect.DeepEqual(report.Severity, wantSeverity) {
+		t.Errorf("Severity = %v, want %v", report.Severity, wantSeverity)
+	}
+	// 0.55 + 1*0.2 + 0.6*0.15 + 0.5*0.1 + 1*0.05
+	if math.Abs(report.Potential-0.94) > 1e-9 {
+		t.Errorf("Potential = %v, want 0.94", report.Potential)
+	}
+	if !reflect.DeepEqual(report.Uncovered, []string{"Kafka"}) {
+		t.Errorf("Uncovered = %v", report.Uncovered)
+	}
+	var plan []string
+	for _, r := range report.Plan {
+		plan = append(plan, r.Title)
+	}
+	if !reflect.DeepEqual(plan, []string{"Go в банке", "Основы SQL", "Scrum на практике"}) || report.PlanHours != 88 {
+		t.Errorf("Plan = %v, %d часов", plan, report.PlanHours)
+	}
+}
+
+func TestAnalyzeLimit(t *testing.T) {
+	tx := testTaxonomy()
+	var catalog []models.LearningResource
+	for _, title := range []string{"A", "B", "C", "D", "E"} {
+		catalog = append(catalog, resource(title, "Docker", "", 10, false))
+	}
+	exp := matching.Explanation{Criteria: []matching.Criterion{
+		{Category: matching.CategorySkills, Requirement: "Docker", Priority: "must", Score: 0, Weight: 1},
+	}}
+	tests := []struct {
+		perGap int
+		want   int
+	}{
+		{0, DefaultResources},
+		{1, 1},
+		{10, 5},
+	}
+	for _, tt := range tests {
+		report := Analyze(exp, tx, catalog, tt.perGap)
+		if got := len(report.Gaps[0].Resources); got != tt.want {
+			t.Errorf("perGap %d: материалов %d, want %d", tt.perGap, got, tt.want)
+		}
+	}
+	if report := Analyze(exp, tx, catalog, 0); report.Potential != 1 {
+		t.Errorf("Potential = %v, want 1", report.Potential)
+	}
+}
+
+func TestAnalyzeNoGaps(t *testing.T) {
+	exp := matching.Explanation{Score: 0.9, Criteria: []matching.Criterion{
+		{Category: matching.CategorySkills, Requirement: "Go", Matched: true, Score: 1, Weight: 1},
+	}}
+	report := Analyze(exp, testTaxonomy(), nil, 3)
+	if len(report.Gaps) != 0 || len(report.Plan) != 0 || len(report.Uncovered) != 0 || report.Potential != 0.9 {
+		t.Errorf("report = %+v", report)
+	}
+	if report.Gaps == nil || report.Plan == nil || report.Uncovered == nil {
+		t.Error("пустые списки должны сериализоваться как []")
+	}
+}
@@ -0,0 +1,177 @@
+package geo
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/moverq1337/VTBHack/internal/textproc"
+)
+
+//go:embed data/*.csv
+var data embed.FS
+
+// earthRadius средний радиус Земли, км
+const earthRadius = 6371.0
+
+// City город справочника
+type City struct {
+	Name    string   `json:"name"`
+	Region  string   `json:"region"`
+	Lat     float64  `json:"lat"`
+	Lon     float64  `json:"lon"`
+	Aliases []string `json:"-"`
+}
+
+// Region субъект РФ; Center - административный центр, по нему считается расстояние
+type Region struct {
+	Name    string   `json:"name"`
+	Center  string   `json:"center"`
+	Aliases []string `json:"-"`
+}
+
+// dictionary справочник городов и регионов с поиском по названию и синонимам
+type dictionary struct {
+	cities  []City
+	regions []Region
+	byCity  map[string]int
+	byReg   map[string]int
+}
+
+// dict встроенный справочник; загружается при старте, ошибка в данных - ошибка сборки
+var dict = mustLoad()
+
+func mustLoad() *dictionary {
+	d, err := load()
+	if err != nil {
+		panic(fmt.Sprintf("geo: %v", err))
+	}
+	return d
+}
+
+func load() (*dictionary, error) {
+	d := &dictionary{byCity: make(map[string]int), byReg: make(map[string]int)}
+
+	err := readCSV("data/cities.csv", 5, func(f []string) error {
+		lat, err := strconv.ParseFloat(f[2], 64)
+		if err != nil {
+			return err
+		}
+		lon, err := strconv.ParseFloat(f[3], 64)
+		if err != nil {
+			return err
+		}
+		c := City{Name: f[0], Region: f[1], Lat: lat, Lon: lon, Aliases: splitAliases(f[4])}
+		for _, key := range append([]string{c.Name}, c.Aliases...) {
+			d.byCity[normalize(key)] = len(d.cities)
+		}
+		d.cities = append(d.cities, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readCSV("data/regions.csv", 3, func(f []string) error {
+		r := Region{Name: f[0], Center: f[1], Aliases: splitAliases(f[2])}
+		if _, ok := d.byCity[normalize(r.Center)]; !ok {
+			return fmt.Errorf("region %s: unknown center %s", r.Name, r.Center)
+		}
+		for _, key := range append([]string{r.Name}, r.Aliases...) {
+			d.byReg[normalize(key)] = len(d.regions)
+		}
+		d.regions = append(d.regions, r)
+		return nil
+	})
+	return d, err
+}
+
+func readCSV(name string, fields int, row func([]string) error) error {
+	f, err := data.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		parts := strings.Split(text, ";")
+		if len(parts) != fields {
+			return fmt.Errorf("%s:%d: expected %d fields, got %d", name, line, fields, len(parts))
+		}
+		if err := row(parts); err != nil {
+			return fmt.Errorf("%s:%d: %w", name, line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func splitAliases(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "|")
+}
+
+// LookupCity находит город по названию или синониму: "СПб", "Питер", "г. Москва"
+func LookupCity(name string) (City, bool) {
+	if i, ok := dict.byCity[normalize(name)]; ok {
+		return dict.cities[i], true
+	}
+	return City{}, false
+}
+
+// LookupRegion находит регион по названию или синониму: "Подмосковье", "Свердловская обл."
+func LookupRegion(name string) (Region, bool) {
+	if i, ok := dict.byReg[normalize(name)]; ok {
+		return dict.regions[i], true
+	}
+	return Region{}, false
+}
+
+// Resolve находит точку для расстояния: город, а если это регион - его центр
+func Resolve(name string) (City, bool) {
+	if c, ok := LookupCity(name); ok {
+		return c, true
+	}
+	if r, ok := LookupRegion(name); ok {
+		return LookupCity(r.Center)
+	}
+	return City{}, false
+}
+
+// Distance расстояние между городами по большому кругу, км
+func Distance(a, b City) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// normalize приводит название к ключу справочника: регистр, ё, "г." и
+// сокращения "обл."/"респ."
+func normalize(name string) string {
+	key := textproc.Normalize(strings.TrimSpace(name))
+	key = strings.Trim(key, " .,;:()")
+	for _, prefix := range []string{"г. ", "г.", "гор. ", "город "} {
+		key = strings.TrimPrefix(key, prefix)
+	}
+	fields := strings.Fields(key)
+	for i, f := range fields {
+		switch strings.TrimSuffix(f, ".") {
+		case "обл":
+			fields[i] = "область"
+		case "респ":
+			fields[i] = "республика"
+		}
+	}
+	return strings.Join(fields, " ")
+}
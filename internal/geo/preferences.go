@@ -0,0 +1,75 @@
+package geo
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/moverq1337/VTBHack/internal/textproc"
+)
+
+// Relocation готовность кандидата к переезду
+type Relocation string
+
+const (
+	RelocationUnknown  Relocation = ""
+	RelocationReady    Relocation = "ready"
+	RelocationNotReady Relocation = "not_ready"
+)
+
+var (
+	// notReadyPattern проверяется первым: "не готов к переезду" содержит "готов к переезду"
+	notReadyPattern = regexp.MustCompile(`не\s+готов[а]?\s+к\s+переезду|переезд\s+невозможен|not\s+(?:willing|open|ready)\s+to\s+relocat|no\s+relocation|relocation\s*:\s*no`)
+	readyPattern    = regexp.MustCompile(`(?:готов[а]?\s+к\s+переезду|готов[а]?\s+переехать|рассматриваю\s+переезд|хочу\s+переехать|open\s+to\s+relocation|(?:willing|ready)\s+to\s+relocate|relocation\s*:\s*(?:yes|possible))(?:\s*(?:в|to)?\s*[:\-–]\s*([^\n]+))?`)
+	// remotePattern пожелание удалённой работы в шапке резюме (hh.ru: "График работы: удаленная работа")
+	remotePattern = regexp.MustCompile(`удаленн\pL*\s+работ\pL*|удаленно|дистанционн\pL*|remote|work\s+from\s+home|wfh`)
+	// remoteFormat удалённый формат в типе занятости вакансии: "Удаленная", "remote"
+	remoteFormat  = regexp.MustCompile(`удаленн|дистанционн|remote|home\s+office`)
+	listSeparator = regexp.MustCompile(`[,;]|\s+и\s+|\s+and\s+`)
+)
+
+// Location местоположение кандидата и его пожелания
+type Location struct {
+	City   string `json:"city"`
+	Region string `json:"region"`
+	Preferences
+}
+
+// Preferences пожелания кандидата к месту работы
+type Preferences struct {
+	Relocation       Relocation `json:"relocation"`
+	RelocationCities []string   `json:"relocation_cities"` // Города, куда готов переехать; пусто - куда угодно
+	Remote           bool       `json:"remote"`            // Рассматривает удалённую работу
+}
+
+// ParsePreferences находит в тексте готовность к переезду и пожелание
+// удалённой работы. Текст должен быть шапкой резюме без опыта работы:
+// в описании должностей "удалённая команда" не говорит о пожеланиях кандидата.
+func ParsePreferences(text string) Preferences {
+	key := textproc.Normalize(text)
+	var p Preferences
+
+	if notReadyPattern.MatchString(key) {
+		p.Relocation = RelocationNotReady
+	} else if m := readyPattern.FindStringSubmatch(key); m != nil {
+		p.Relocation = RelocationReady
+		p.RelocationCities = parseCities(m[1])
+	}
+	p.Remote = remotePattern.MatchString(key)
+	return p
+}
+
+// IsRemote сообщает, что тип занятости или график вакансии допускают удалённую работу
+func IsRemote(text string) bool {
+	return remoteFormat.MatchString(textproc.Normalize(text))
+}
+
+// parseCities разбирает список городов "Санкт-Петербург, Казань и Сочи"
+func parseCities(list string) []string {
+	var out []string
+	for _, part := range listSeparator.Split(list, -1) {
+		if c, ok := Resolve(strings.TrimSpace(part)); ok {
+			out = append(out, c.Name)
+		}
+	}
+	return out
+}
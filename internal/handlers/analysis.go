@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"gorm.io/gorm"
+)
+
+// AnalysisExplanation возвращает обоснование оценки: критерии, их вклад и недостающие требования
+func AnalysisExplanation(c *gin.Context, db *gorm.DB) {
+	analysis, explanation, ok := loadExplanation(c, db)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"analysis_id": analysis.ID.String(),
+		"resume_id":   analysis.ResumeID.String(),
+		"vacancy_id":  analysis.VacancyID.String(),
+		"match_score": analysis.MatchScore,
+		"score":       analysis.Score,
+		"degraded":    analysis.Degraded,
+		"explanation": explanation,
+	})
+}
+
+// AnalysisHighlights возвращает текст резюме с диапазонами подсветки.
+// Смещения указаны в символах текста, конец диапазона не включается.
+func AnalysisHighlights(c *gin.Context, db *gorm.DB) {
+	analysis, explanation, ok := loadExplanation(c, db)
+	if !ok {
+		return
+	}
+
+	var resume models.Resume
+	if err := db.First(&resume, "id = ?", analysis.ResumeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Резюме не найдено"})
+		return
+	}
+
+	highlights := explanation.Highlights()
+	if highlights == nil {
+		highlights = []matching.Highlight{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"analysis_id": analysis.ID.String(),
+		"resume_id":   resume.ID.String(),
+		"text":        resume.Text,
+		"highlights":  highlights,
+		"missing":     explanation.Missing,
+	})
+}
+
+// loadExplanation загружает анализ по :id и разбирает сохранённое обоснование
+func loadExplanation(c *gin.Context, db *gorm.DB) (models.AnalysisResult, matching.Explanation, bool) {
+	var analysis models.AnalysisResult
+	var explanation matching.Explanation
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор анализа"})
+		return analysis, explanation, false
+	}
+
+	if err := db.First(&analysis, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Анализ не найден"})
+		return analysis, explanation, false
+	}
+
+	if err := json.Unmarshal([]byte(analysis.Explanation), &explanation); err != nil {
+		log.WithError(err).Error("Ошибка разбора обоснования оценки")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Обоснование оценки повреждено"})
+		return analysis, explanation, false
+	}
+	if explanation.Missing == nil {
+		explanation.Missing = []string{}
+	}
+
+	return analysis, explanation, true
+}
+
+// experienceYears достаёт опыт кандидата в годах из данных парсинга
+func experienceYears(parsedData map[string]interface{}) float64 {
+	years, _ := parsedData["experience"].(float64)
+	return years
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/talent"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"gorm.io/gorm"
+)
+
+// clusterRunSorts поля сортировки списка запусков кластеризации
+var clusterRunSorts = map[string]sortField{
+	"created_at": {"created_at", kindTime},
+}
+
+// clusterMemberSorts поля сортировки резюме кластера
+var clusterMemberSorts = map[string]sortField{
+	"similarity": {"similarity", kindFloat},
+	"created_at": {"created_at", kindTime},
+}
+
+// clusterView кластер с разобранными навыками
+type clusterView struct {
+	models.Cluster
+	TopSkills []talent.TopSkill `json:"top_skills"`
+}
+
+// memberResume резюме кластера и его близость к центру кластера
+type memberResume struct {
+	models.Resume
+	Similarity float64
+}
+
+// StartClusterRun запускает кластеризацию базы резюме в фоне. Тело
+// необязательно: {"source": "embeddings" | "skills", "k": 12}.
+func StartClusterRun(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	var opts talent.Options
+	if err := c.ShouldBindJSON(&opts); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
+		return
+	}
+
+	if err := opts.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	run, err := talent.Start(db, skills, opts)
+	switch {
+	case errors.Is(err, talent.ErrRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": "Кластеризация уже выполняется"})
+		return
+	case err != nil:
+		log.WithError(err).Error("Ошибка запуска кластеризации")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка запуска кластеризации"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": run})
+}
+
+// ListClusterRuns возвращает страницу запусков кластеризации, от новых
+func ListClusterRuns(c *gin.Context, db *gorm.DB) {
+	q, err := parseListQuery(c, clusterRunSorts, "-created_at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var runs []models.ClusterRun
+	if err := q.apply(db.Model(&models.ClusterRun{})).Find(&runs).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения запусков кластеризации")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения запусков"})
+		return
+	}
+	runs, next := page(q, runs, func(r models.ClusterRun) (any, uuid.UUID) { return r.CreatedAt, r.ID })
+	c.JSON(http.StatusOK, listResponse(runs, next))
+}
+
+// GetClusterRun возвращает запуск и его кластеры
+func GetClusterRun(c *gin.Context, db *gorm.DB) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор запуска"})
+		return
+	}
+	var run models.ClusterRun
+	if err := db.First(&run, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Запуск не найден"})
+		return
+	}
+	respondClusters(c, db, run)
+}
+
+// LatestClusters возвращает кластеры последнего завершённого запуска
+func LatestClusters(c *gin.Context, db *gorm.DB) {
+	var run models.ClusterRun
+	err := db.Where("status = ?", models.ClusterRunDone).Order("created_at DESC").First(&run).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Кластеризация ещё не выполнялась"})
+		return
+	}
+	respondClusters(c, db, run)
+}
+
+func respondClusters(c *gin.Context, db *gorm.DB, run models.ClusterRun) {
+	var clusters []models.Cluster
+	if err := db.Where("run_id = ?", run.ID).Order("number").Find(&clusters).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения кластеров")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения кластеров"})
+		return
+	}
+	views := make([]clusterView, len(clusters))
+	for i, cl := range clusters {
+		views[i] = clusterView{Cluster: cl, TopSkills: []talent.TopSkill{}}
+		if err := json.Unmarshal([]byte(cl.TopSkills), &views[i].TopSkills); err != nil {
+			log.WithError(err).Error("Ошибка разбора навыков кластера")
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"run": run, "clusters": views}})
+}
+
+// ClusterResumes возвращает страницу резюме кластера, по умолчанию от
+// самых типичных для кластера
+func ClusterResumes(c *gin.Context, db *gorm.DB) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор кластера"})
+		return
+	}
+	q, err := parseListQuery(c, clusterMemberSorts, "-similarity")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var cluster models.Cluster
+	if err := db.First(&cluster, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Кластер не найден"})
+		return
+	}
+
+	members := db.Model(&models.Resume{}).
+		Select("resumes.*, cluster_members.similarity").
+		Joins("JOIN cluster_members ON cluster_members.resume_id = resumes.id").
+		Where("cluster_members.cluster_id = ?", cluster.ID)
+	var rows []memberResume
+	if err := q.apply(db.Table("(?) AS r", members)).Find(&rows).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения резюме кластера")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения резюме"})
+		return
+	}
+	rows, next := page(q, rows, func(r memberResume) (any, uuid.UUID) {
+		if q.sort.column == "created_at" {
+			return r.CreatedAt, r.ID
+		}
+		return r.Similarity, r.ID
+	})
+
+	hits := make([]similarHit, len(rows))
+	for i, r := range rows {
+		hits[i] = similarHit{resumeItem: resumeItem{Resume: r.Resume, Text: preview(r.Text, previewLength)}, Similarity: r.Similarity}
+	}
+	c.JSON(http.StatusOK, listResponse(hits, next))
+}
+
+// SkillCombinations возвращает самые частые сочетания навыков в резюме:
+// size - 2 или 3 навыка, min_support - минимум резюме с сочетанием
+func SkillCombinations(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	size, err := intParam(c, "size", 2)
+	if err != nil || size < 2 || size > 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "size должен быть 2 или 3"})
+		return
+	}
+	minSupport, err := intParam(c, "min_support", 2)
+	if err != nil || minSupport < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_support должен быть положительным числом"})
+		return
+	}
+	limit, err := intParam(c, "limit", defaultPageSize)
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit должен быть положительным числом"})
+		return
+	}
+
+	combinations, err := talent.SkillCombinations(db, skills.Current(), size, minSupport, min(limit, maxPageSize))
+	if err != nil {
+		log.WithError(err).Error("Ошибка подсчёта сочетаний навыков")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка построения отчёта"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": combinations})
+}
+
+// SkillSupplyDemand сравнивает спрос на навыки в опубликованных вакансиях
+// с их предложением в резюме. sort: scarcity (по умолчанию), demand,
+// supply, shortfall - меньше всего резюме на вакансию.
+func SkillSupplyDemand(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	sortBy := c.DefaultQuery("sort", "scarcity")
+	if _, ok := talent.BalanceSorts[sortBy]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неизвестное поле сортировки: " + sortBy})
+		return
+	}
+	limit, err := intParam(c, "limit", defaultPageSize)
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit должен быть положительным числом"})
+		return
+	}
+	limit = min(limit, maxPageSize)
+
+	balances, err := talent.SupplyDemand(db, skills.Current())
+	if err != nil {
+		log.WithError(err).Error("Ошибка построения отчёта о спросе на навыки")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка построения отчёта"})
+		return
+	}
+	talent.SortBalances(balances, sortBy)
+	if len(balances) > limit {
+		balances = balances[:limit]
+	}
+	c.JSON(http.StatusOK, gin.H{"data": balances})
+}
+
+// intParam читает целый параметр запроса; без параметра - значение по умолчанию
+func intParam(c *gin.Context, name string, def int) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, nil
+	}
+	return strconv.Atoi(raw)
+}
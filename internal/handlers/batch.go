@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/nlp"
+	"github.com/moverq1337/VTBHack/internal/pb"
+	"gorm.io/gorm"
+)
+
+// maxBatchResumes ограничивает размер одного запроса пакетного анализа
+const maxBatchResumes = 1000
+
+// AnalyzeBatch ранжирует несколько резюме относительно одной вакансии
+func AnalyzeBatch(c *gin.Context, db *gorm.DB, nlpClient *nlp.Client) {
+	type BatchRequest struct {
+		VacancyID uuid.UUID   `json:"vacancy_id"`
+		ResumeIDs []uuid.UUID `json:"resume_ids"`
+		BatchSize int         `json:"batch_size"`
+	}
+
+	var req BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+	if len(req.ResumeIDs) == 0 || len(req.ResumeIDs) > maxBatchResumes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Количество резюме должно быть от 1 до 1000"})
+		return
+	}
+
+	var vacancy models.Vacancy
+	if err := db.First(&vacancy, "id = ?", req.VacancyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Вакансия не найдена"})
+		return
+	}
+
+	var resumes []models.Resume
+	if err := db.Where("id IN ?", req.ResumeIDs).Find(&resumes).Error; err != nil {
+		log.WithError(err).Error("Ошибка загрузки резюме")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка загрузки резюме"})
+		return
+	}
+
+	items := make([]*pb.ResumeItem, 0, len(resumes))
+	found := make(map[uuid.UUID]bool, len(resumes))
+	for _, r := range resumes {
+		items = append(items, &pb.ResumeItem{Id: r.ID.String(), Text: r.Text})
+		found[r.ID] = true
+	}
+
+	outcomes := nlpClient.BatchMatchWithFallback(c.Request.Context(), vacancy.ID.String(), vacancyText(vacancy), items, req.BatchSize)
+
+	type rankedItem struct {
+		ResumeID string  `json:"resume_id"`
+		Score    float32 `json:"score"`
+		Degraded bool    `json:"degraded"`
+	}
+	type failedItem struct {
+		ResumeID string `json:"resume_id"`
+		Error    string `json:"error"`
+	}
+
+	ranked := make([]rankedItem, 0, len(outcomes))
+	failed := make([]failedItem, 0)
+	for _, o := range outcomes {
+		if o.Err != nil {
+			failed = append(failed, failedItem{ResumeID: o.ResumeID, Error: o.Err.Error()})
+			continue
+		}
+		ranked = append(ranked, rankedItem{ResumeID: o.ResumeID, Score: o.Score, Degraded: o.Degraded})
+	}
+	for _, id := range req.ResumeIDs {
+		if !found[id] {
+			failed = append(failed, failedItem{ResumeID: id.String(), Error: "Резюме не найдено"})
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+
+	c.JSON(http.StatusOK, gin.H{
+		"vacancy_id": vacancy.ID.String(),
+		"ranked":     ranked,
+		"failed":     failed,
+	})
+}
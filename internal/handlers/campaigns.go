@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/campaign"
+	"github.com/moverq1337/VTBHack/internal/geo"
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/nlp"
+	"github.com/moverq1337/VTBHack/internal/salary"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"github.com/moverq1337/VTBHack/internal/timeline"
+	"gorm.io/gorm"
+)
+
+// defaultRunnersUp запасных кандидатов на вакансию в назначении по умолчанию
+const defaultRunnersUp = 3
+
+// matchRunSorts поля сортировки списка расчётов матрицы
+var matchRunSorts = map[string]sortField{
+	"created_at": {"created_at", kindTime},
+}
+
+// StartMatchRun запускает расчёт матрицы резюме × вакансии кампании найма
+// и распределение кандидатов по вакансиям с учётом числа мест
+func StartMatchRun(c *gin.Context, db *gorm.DB, nlpClient *nlp.Client, skills *taxonomy.Store, opts matching.Options) {
+	var req campaign.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	run, err := campaign.Start(db, matrixScorer(db, nlpClient, skills.Current(), opts), req)
+	switch {
+	case errors.Is(err, campaign.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.WithError(err).Error("Ошибка запуска расчёта матрицы")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка запуска расчёта"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": run})
+}
+
+// matrixScorer оценивает пары так же, как анализ одного резюме: близость
+// от NLP-сервиса одним потоком на группу вакансий (текст каждого резюме
+// уходит в поток один раз) и разбор по требованиям. Стаж берётся из
+// истории работы; данные резюме готовятся один раз на расчёт.
+func matrixScorer(db *gorm.DB, nlpClient *nlp.Client, t *taxonomy.Taxonomy, opts matching.Options) campaign.Scorer {
+	type resumeInput struct {
+		history  timeline.Timeline
+		location geo.Location
+		salary   salary.Amount
+	}
+	matcher := matching.NewEngine(t, opts)
+	rates := loadRates(db)
+	inputs := map[uuid.UUID]resumeInput{}
+
+	// scoreVacancy разбирает резюме по требованиям вакансии с близостью из outcomes
+	scoreVacancy := func(vacancy models.Vacancy, resumes []models.Resume, outcomes []nlp.MatchOutcome) []campaign.Score {
+		requirements := vacancyRequirements(vacancy, t)
+		scores := make([]campaign.Score, len(resumes))
+		for i, r := range resumes {
+			if outcomes[i].Err != nil {
+				scores[i].Err = outcomes[i].Err
+				continue
+			}
+			in, ok := inputs[r.ID]
+			if !ok {
+				history, err := resumeTimeline(db, r, t)
+				if err != nil {
+					log.WithError(err).Error("Ошибка загрузки истории работы")
+				}
+				expectation, _ := resumeSalary(r, resumeSections(r))
+				in = resumeInput{history: history, location: resumeLocation(r), salary: expectation}
+				inputs[r.ID] = in
+			}
+			explanation := matcher.Evaluate(matching.Input{
+				ResumeText:    r.Text,
+				ResumeYears:   in.history.Years(),
+				Vacancy:       vacancy,
+				Requirements:  requirements,
+				SemanticScore: float64(outcomes[i].Score),
+				History:       in.history,
+				Location:      in.location,
+				Salary:        in.salary,
+				Rates:         rates,
+			})
+			scores[i] = campaign.Score{Value: explanation.Score, Degraded: outcomes[i].Degraded}
+		}
+		return scores
+	}
+
+	return func(ctx context.Context, vacancies []models.Vacancy, resumes []models.Resume) [][]campaign.Score {
+		pairs := make([]nlp.MatchPair, 0, len(vacancies)*len(resumes))
+		for _, v := range vacancies {
+			text := vacancyText(v)
+			for _, r := range resumes {
+				pairs = append(pairs, nlp.MatchPair{ResumeID: r.ID.String(), ResumeText: r.Text, VacancyID: v.ID.String(), VacancyText: text})
+			}
+		}
+		outcomes := nlpClient.StreamMatchWithFallback(ctx, pairs, 0)
+
+		scores := make([][]campaign.Score, len(vacancies))
+		for j, vacancy := range vacancies {
+			scores[j] = scoreVacancy(vacancy, resumes, outcomes[j*len(resumes):(j+1)*len(resumes)])
+		}
+		return scores
+	}
+}
+
+// ListMatchRuns возвращает страницу расчётов матрицы, от новых
+func ListMatchRuns(c *gin.Context, db *gorm.DB) {
+	q, err := parseListQuery(c, matchRunSorts, "-created_at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var runs []models.MatchRun
+	if err := q.apply(db.Model(&models.MatchRun{})).Find(&runs).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения расчётов матрицы")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения расчётов"})
+		return
+	}
+	runs, next := page(q, runs, func(r models.MatchRun) (any, uuid.UUID) { return r.CreatedAt, r.ID })
+	for i := range runs {
+		campaign.Expire(&runs[i])
+	}
+	c.JSON(http.StatusOK, listResponse(runs, next))
+}
+
+// GetMatchRun возвращает расчёт с прогрессом: scored из resumes × vacancies пар
+func GetMatchRun(c *gin.Context, db *gorm.DB) {
+	run, ok := loadMatchRun(c, db)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": run})
+}
+
+// DeleteMatchRun удаляет расчёт вместе с матрицей
+func DeleteMatchRun(c *gin.Context, db *gorm.DB) {
+	run, ok := loadMatchRun(c, db)
+	if !ok {
+		return
+	}
+	if run.Status == models.MatchRunRunning {
+		c.JSON(http.StatusConflict, gin.H{"error": "Расчёт ещё выполняется"})
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("run_id = ?", run.ID).Delete(&models.MatchCell{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("run_id = ?", run.ID).Delete(&models.MatchRunVacancy{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&run).Error
+	})
+	if err != nil {
+		log.WithError(err).Error("Ошибка удаления расчёта матрицы")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления расчёта"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// MatchRunMatrix возвращает полную матрицу оценок: вакансии по столбцам,
+// резюме по строкам
+func MatchRunMatrix(c *gin.Context, db *gorm.DB) {
+	run, ok := loadMatchRun(c, db)
+	if !ok {
+		return
+	}
+	openings, rows, err := campaign.Matrix(db, run)
+	if err != nil {
+		log.WithError(err).Error("Ошибка получения матрицы")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения матрицы"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"run": run, "vacancies": openings, "resumes": rows}})
+}
+
+// MatchRunAssignment возвращает распределение кандидатов по вакансиям и
+// до runners_up запасных кандидатов на каждую
+func MatchRunAssignment(c *gin.Context, db *gorm.DB) {
+	run, ok := loadMatchRun(c, db)
+	if !ok {
+		return
+	}
+	runnersUp, err := intParam(c, "runners_up", defaultRunnersUp)
+	if err != nil || runnersUp < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "runners_up должен быть неотрицательным числом"})
+		return
+	}
+	respondAssignment(c, db, run, min(runnersUp, maxPageSize))
+}
+
+// ReassignMatchRun заново распределяет кандидатов готовой матрицы с
+// другим числом мест или минимальной оценкой, не оценивая пары повторно.
+// Тело: {"vacancies": [{"vacancy_id": ..., "headcount": 2}], "min_score": 0.5}
+func ReassignMatchRun(c *gin.Context, db *gorm.DB) {
+	run, ok := loadMatchRun(c, db)
+	if !ok {
+		return
+	}
+	var req struct {
+		Vacancies []campaign.Opening `json:"vacancies"`
+		MinScore  *float64           `json:"min_score"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+	headcounts := make(map[uuid.UUID]int, len(req.Vacancies))
+	for _, o := range req.Vacancies {
+		if o.Headcount < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "headcount не может быть отрицательным"})
+			return
+		}
+		headcounts[o.VacancyID] = o.Headcount
+	}
+	minScore := run.MinScore
+	if req.MinScore != nil {
+		if *req.MinScore < 0 || *req.MinScore > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "min_score должен быть числом от 0 до 1"})
+			return
+		}
+		minScore = *req.MinScore
+	}
+
+	err := campaign.Reassign(db, &run, headcounts, minScore)
+	switch {
+	case errors.Is(err, campaign.ErrNotDone):
+		c.JSON(http.StatusConflict, gin.H{"error": "Матрица ещё не рассчитана"})
+		return
+	case errors.Is(err, campaign.ErrNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.WithError(err).Error("Ошибка распределения кандидатов")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка распределения кандидатов"})
+		return
+	}
+	respondAssignment(c, db, run, defaultRunnersUp)
+}
+
+func respondAssignment(c *gin.Context, db *gorm.DB, run models.MatchRun, runnersUp int) {
+	report, err := campaign.Assignment(db, run, runnersUp)
+	switch {
+	case errors.Is(err, campaign.ErrNotDone):
+		c.JSON(http.StatusConflict, gin.H{"error": "Матрица ещё не рассчитана"})
+		return
+	case err != nil:
+		log.WithError(err).Error("Ошибка получения распределения")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения распределения"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// loadMatchRun загружает расчёт по идентификатору из пути, отвечая 400 или 404.
+// Зависший расчёт возвращается упавшим: его можно удалить.
+func loadMatchRun(c *gin.Context, db *gorm.DB) (models.MatchRun, bool) {
+	var run models.MatchRun
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор расчёта"})
+		return run, false
+	}
+	if err := db.First(&run, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Расчёт не найден"})
+		return run, false
+	}
+	campaign.Expire(&run)
+	return run, true
+}
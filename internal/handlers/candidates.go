@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/contacts"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dedupConfidence минимальная уверенность контакта, по которому резюме
+// связывается с уже известным кандидатом
+const dedupConfidence = 0.7
+
+// dedupKinds контакты, однозначно указывающие на человека, и колонки кандидата
+var dedupKinds = []struct {
+	kind   contacts.Kind
+	column string
+}{
+	{contacts.KindEmail, "email"},
+	{contacts.KindPhone, "phone"},
+	{contacts.KindTelegram, "telegram"},
+	{contacts.KindGitHub, "github"},
+	{contacts.KindLinkedIn, "linkedin"},
+	{contacts.KindHH, "hh_url"},
+}
+
+// outreachLinks ссылки для связи с кандидатом по типу контакта
+var outreachLinks = map[contacts.Kind]func(string) string{
+	contacts.KindEmail:    func(v string) string { return "mailto:" + v },
+	contacts.KindPhone:    func(v string) string { return "tel:" + v },
+	contacts.KindTelegram: func(v string) string { return "https://t.me/" + v[1:] },
+	contacts.KindLinkedIn: func(v string) string { return v },
+	contacts.KindHH:       func(v string) string { return v },
+}
+
+// GetCandidate возвращает кандидата с контактами и его резюме
+func GetCandidate(c *gin.Context, db *gorm.DB) {
+	candidate, ok := loadCandidate(c, db)
+	if !ok {
+		return
+	}
+
+	var resumes []models.Resume
+	if err := db.Select("id", "created_at").Where("candidate_id = ?", candidate.ID).Order("created_at").Find(&resumes).Error; err != nil {
+		log.WithError(err).Error("Ошибка загрузки резюме кандидата")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка загрузки резюме кандидата"})
+		return
+	}
+	resumeList := make([]gin.H, 0, len(resumes))
+	for _, r := range resumes {
+		resumeList = append(resumeList, gin.H{"resume_id": r.ID.String(), "created_at": r.CreatedAt})
+	}
+
+	var birthDate string
+	if candidate.BirthDate != nil {
+		birthDate = candidate.BirthDate.Format("2006-01-02")
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"candidate_id": candidate.ID.String(),
+		"email":        candidate.Email,
+		"phone":        candidate.Phone,
+		"telegram":     candidate.Telegram,
+		"github":       candidate.GitHub,
+		"gitlab":       candidate.GitLab,
+		"linkedin":     candidate.LinkedIn,
+		"hh_url":       candidate.HHURL,
+		"city":         candidate.City,
+		"birth_date":   birthDate,
+		"contacts":     decodeContacts(candidate.Contacts),
+		"resumes":      resumeList,
+	})
+}
+
+// CandidateOutreach возвращает каналы связи с кандидатом по убыванию уверенности
+func CandidateOutreach(c *gin.Context, db *gorm.DB) {
+	candidate, ok := loadCandidate(c, db)
+	if !ok {
+		return
+	}
+
+	found := decodeContacts(candidate.Contacts)
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Confidence > found[j].Confidence })
+
+	channels := make([]gin.H, 0, len(found))
+	for _, ct := range found {
+		link, ok := outreachLinks[ct.Kind]
+		if !ok {
+			continue
+		}
+		channels = append(channels, gin.H{
+			"channel":    ct.Kind,
+			"value":      ct.Value,
+			"link":       link(ct.Value),
+			"confidence": ct.Confidence,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"candidate_id": candidate.ID.String(),
+		"channels":     channels,
+	})
+}
+
+func loadCandidate(c *gin.Context, db *gorm.DB) (models.Candidate, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор кандидата"})
+		return models.Candidate{}, false
+	}
+
+	var candidate models.Candidate
+	if err := db.First(&candidate, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Кандидат не найден"})
+		return models.Candidate{}, false
+	}
+	return candidate, true
+}
+
+// upsertCandidate связывает резюме с кандидатом: если почта, телефон или
+// профиль из шапки резюме уже известны, контакты дописываются к
+// существующей записи, иначе создаётся кандидат с идентификатором id.
+// Почта и телефон уникальны: если кандидата с теми же контактами создали
+// одновременно, резюме привязывается к нему.
+func upsertCandidate(db *gorm.DB, id uuid.UUID, found contacts.Contacts) (candidate models.Candidate, duplicate bool, err error) {
+	header := found.Header()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for attempt := 0; attempt < 2; attempt++ {
+			query := tx.Where("1 = 0")
+			for _, k := range dedupKinds {
+				if ct, ok := header.Best(k.kind); ok && ct.Confidence >= dedupConfidence {
+					query = query.Or(k.column+" = ?", ct.Value)
+				}
+			}
+
+			candidate = models.Candidate{}
+			err := query.Order("created_at").First(&candidate).Error
+			switch {
+			case err == nil:
+				duplicate = true
+				previous := candidate
+				applyContacts(&candidate, contacts.Merge(decodeContacts(candidate.Contacts), found))
+				if err := keepUnique(tx, &candidate, previous); err != nil {
+					return err
+				}
+				return tx.Save(&candidate).Error
+			case err != gorm.ErrRecordNotFound:
+				return err
+			}
+
+			candidate = models.Candidate{ID: id}
+			applyContacts(&candidate, found)
+			if err := keepUnique(tx, &candidate, models.Candidate{}); err != nil {
+				return err
+			}
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&candidate)
+			if result.Error != nil || result.RowsAffected > 0 {
+				return result.Error
+			}
+			// Кандидата с той же почтой или телефоном только что создал
+			// другой запрос: ищем его заново
+		}
+		return errors.New("кандидат с теми же контактами изменяется одновременно")
+	})
+	return candidate, duplicate, err
+}
+
+// keepUnique не даёт записать кандидату почту или телефон другого
+// кандидата: такое значение заменяется прежним
+func keepUnique(tx *gorm.DB, candidate *models.Candidate, previous models.Candidate) error {
+	for _, field := range []struct {
+		column   string
+		value    *string
+		previous string
+	}{
+		{"email", &candidate.Email, previous.Email},
+		{"phone", &candidate.Phone, previous.Phone},
+	} {
+		if *field.value == "" || *field.value == field.previous {
+			continue
+		}
+		var taken int64
+		if err := tx.Model(&models.Candidate{}).Where(field.column+" = ? AND id <> ?", *field.value, candidate.ID).Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			*field.value = field.previous
+		}
+	}
+	return nil
+}
+
+// applyContacts заполняет поля кандидата лучшими контактами каждого типа.
+// Поля, по которым кандидаты связываются, берутся только из шапки резюме;
+// если там контакта нет, прежнее значение сохраняется.
+func applyContacts(candidate *models.Candidate, cs contacts.Contacts) {
+	header := cs.Header()
+	identity := func(field *string, kind contacts.Kind) {
+		if v := header.Value(kind); v != "" {
+			*field = v
+		}
+	}
+	identity(&candidate.Email, contacts.KindEmail)
+	identity(&candidate.Phone, contacts.KindPhone)
+	identity(&candidate.Telegram, contacts.KindTelegram)
+	identity(&candidate.GitHub, contacts.KindGitHub)
+	identity(&candidate.LinkedIn, contacts.KindLinkedIn)
+	identity(&candidate.HHURL, contacts.KindHH)
+	candidate.GitLab = cs.Value(contacts.KindGitLab)
+	candidate.City = cs.Value(contacts.KindCity)
+	candidate.BirthDate = nil
+	if v := cs.Value(contacts.KindBirthDate); v != "" {
+		if date, err := time.Parse("2006-01-02", v); err == nil {
+			candidate.BirthDate = &date
+		}
+	}
+
+	data, err := json.Marshal(cs)
+	if err != nil || len(cs) == 0 {
+		candidate.Contacts = "[]"
+		return
+	}
+	candidate.Contacts = string(data)
+}
+
+func decodeContacts(s string) contacts.Contacts {
+	found := contacts.Contacts{}
+	if s != "" {
+		if err := json.Unmarshal([]byte(s), &found); err != nil {
+			log.WithError(err).Error("Ошибка разбора контактов кандидата")
+		}
+	}
+	return found
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/compare"
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/nlp"
+	"github.com/moverq1337/VTBHack/internal/pb"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"gorm.io/gorm"
+)
+
+const docxContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// CompareCandidates сравнивает от 2 до 5 резюме по вакансии: оценки по
+// одним и тем же критериям, закрытые и недостающие требования, история
+// работы, ожидания по зарплате и последнее интервью. Резюме оцениваются
+// заново по текущим требованиям вакансии. format=docx или pdf отдаёт
+// таблицу файлом.
+func CompareCandidates(c *gin.Context, db *gorm.DB, nlpClient *nlp.Client, skills *taxonomy.Store, opts matching.Options, compareOpts compare.Options) {
+	vacancy, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "docx" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format должен быть json, docx или pdf"})
+		return
+	}
+
+	var ids []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+	for _, raw := range c.QueryArray("resume_ids") {
+		for _, part := range strings.Split(raw, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(part))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор резюме: " + part})
+				return
+			}
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) < compare.MinResumes || len(ids) > compare.MaxResumes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Для сравнения нужно от %d до %d резюме", compare.MinResumes, compare.MaxResumes)})
+		return
+	}
+
+	var rows []models.Resume
+	if err := db.Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		log.WithError(err).Error("Ошибка загрузки резюме")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка загрузки резюме"})
+		return
+	}
+	byID := make(map[uuid.UUID]models.Resume, len(rows))
+	for _, r := range rows {
+		byID[r.ID] = r
+	}
+
+	t := skills.Current()
+	matcher := matching.NewEngine(t, opts)
+	requirements := vacancyRequirements(vacancy, t)
+	rates := loadRates(db)
+	entries := make([]compare.Entry, 0, len(ids))
+	for _, id := range ids {
+		resume, ok := byID[id]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Резюме не найдено: " + id.String()})
+			return
+		}
+
+		matchResp, degraded, err := nlpClient.Match(c.Request.Context(), &pb.MatchRequest{
+			ResumeText:  resume.Text,
+			VacancyText: vacancyText(vacancy),
+		})
+		if err != nil {
+			log.WithError(err).Error("Ошибка сопоставления")
+			if nlp.IsUnavailable(err) {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Сервис анализа недоступен"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сопоставления с вакансией"})
+			return
+		}
+
+		history, err := resumeTimeline(db, resume, t)
+		if err != nil {
+			log.WithError(err).Error("Ошибка загрузки истории работы")
+		}
+		expectation, hasSalary := resumeSalary(resume, resumeSections(resume))
+		entry := compare.Entry{
+			Resume: resume,
+			Explanation: matcher.Evaluate(matching.Input{
+				ResumeText:    resume.Text,
+				ResumeYears:   history.Years(),
+				Vacancy:       vacancy,
+				Requirements:  requirements,
+				SemanticScore: float64(matchResp.Score),
+				History:       history,
+				Location:      resumeLocation(resume),
+				Salary:        expectation,
+				Rates:         rates,
+			}),
+			Degraded: degraded,
+			History:  history,
+			Salary:   salaryResponse(expectation, hasSalary),
+		}
+		if hasSalary {
+			entry.SalaryRUB, _ = rates.GrossRUB(expectation)
+		}
+
+		// Интервью могли начать по кандидату или по резюме
+		var interview models.InterviewResult
+		candidates := []uuid.UUID{resume.ID}
+		if resume.CandidateID != uuid.Nil {
+			candidates = append(candidates, resume.CandidateID)
+		}
+		err = db.Where("vacancy_id = ? AND candidate_id IN ?", vacancy.ID, candidates).
+			Order("completed_at DESC").Limit(1).Find(&interview).Error
+		if err != nil {
+			log.WithError(err).Error("Ошибка загрузки результатов интервью")
+		} else if interview.ID != uuid.Nil {
+			entry.Interview = &interview
+		}
+		entries = append(entries, entry)
+	}
+
+	cmp := compare.Build(vacancy, entries)
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{"data": cmp})
+		return
+	}
+	respondComparisonFile(c, cmp, format, compareOpts)
+}
+
+// respondComparisonFile отдаёт сравнение файлом DOCX или PDF. PDF
+// получается из того же DOCX сервисом по адресу PDF_CONVERTER_URL.
+func respondComparisonFile(c *gin.Context, cmp compare.Comparison, format string, opts compare.Options) {
+	if err := unidocLicense(); err != nil {
+		log.WithError(err).Error("Ошибка инициализации UniDoc license")
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Выгрузка документов не настроена"})
+		return
+	}
+	data, err := compare.DOCX(cmp)
+	if err != nil {
+		log.WithError(err).Error("Ошибка формирования DOCX")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка формирования документа"})
+		return
+	}
+	contentType := docxContentType
+	if format == "pdf" {
+		data, err = compare.PDF(c.Request.Context(), opts.ConverterURL, data)
+		switch {
+		case errors.Is(err, compare.ErrNoConverter):
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "Выгрузка в PDF не настроена"})
+			return
+		case err != nil:
+			log.WithError(err).Error("Ошибка преобразования в PDF")
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Ошибка преобразования в PDF"})
+			return
+		}
+		contentType = "application/pdf"
+	}
+
+	filename := fmt.Sprintf("comparison-%s.%s", cmp.VacancyID.String()[:8], format)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, contentType, data)
+}
@@ -0,0 +1,519 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/gaps"
+	"github.com/moverq1337/VTBHack/internal/geo"
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/moverq1337/VTBHack/internal/mobility"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/nlp"
+	"github.com/moverq1337/VTBHack/internal/pb"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"gorm.io/gorm"
+)
+
+// maxImportSize предел размера выгрузки сотрудников
+const maxImportSize = 20 << 20
+
+// hrKeyHeader заголовок с ключом HR
+const hrKeyHeader = "X-HR-Key"
+
+// employeeSorts поля сортировки списка сотрудников
+var employeeSorts = map[string]sortField{
+	"full_name":   {"full_name", kindString},
+	"imported_at": {"imported_at", kindTime},
+}
+
+// internalSorts поля сортировки внутреннего шорт-листа и вакансий сотрудника
+var internalSorts = map[string]sortField{
+	"score":     {"score", kindFloat},
+	"scored_at": {"scored_at", kindTime},
+}
+
+// scoredEmployee сотрудник с оценкой для вакансии
+type scoredEmployee struct {
+	models.Employee
+	Score       float64
+	Degraded    bool
+	Explanation string
+	ScoredAt    time.Time
+}
+
+// scoredVacancy вакансия с оценкой сотрудника
+type scoredVacancy struct {
+	ID          uuid.UUID
+	Title       string
+	City        string
+	Score       float64
+	Degraded    bool
+	Explanation string
+	ScoredAt    time.Time
+}
+
+// internalCandidate строка внутреннего шорт-листа
+type internalCandidate struct {
+	Employee models.Employee `json:"employee"`
+	Score    float64         `json:"score"`
+	Degraded bool            `json:"degraded"`
+	Missing  []string        `json:"missing"` // Незакрытые обязательные требования
+	ScoredAt time.Time       `json:"scored_at"`
+}
+
+// employeeVacancy вакансия, для которой оценён сотрудник
+type employeeVacancy struct {
+	VacancyID uuid.UUID `json:"vacancy_id"`
+	Title     string    `json:"title"`
+	City      string    `json:"city"`
+	Score     float64   `json:"score"`
+	Degraded  bool      `json:"degraded"`
+	Missing   []string  `json:"missing"`
+	ScoredAt  time.Time `json:"scored_at"`
+}
+
+// requireHR пускает к профилям сотрудников только с ключом HR. Пока ключ
+// не настроен, профили закрыты для всех: в них данные, которые сотрудник
+// не разрешал показывать.
+func requireHR(access mobility.Options) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hrAllowed(c, access) {
+			c.Abort()
+		}
+	}
+}
+
+// hrAllowed проверяет ключ HR в запросе и при отказе отвечает 401 или 403
+func hrAllowed(c *gin.Context, access mobility.Options) bool {
+	switch {
+	case !access.Configured():
+		c.JSON(http.StatusForbidden, gin.H{"error": "Доступ HR не настроен"})
+		return false
+	case !access.HR(c.GetHeader(hrKeyHeader)):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Нужен ключ HR в заголовке " + hrKeyHeader})
+		return false
+	}
+	return true
+}
+
+// newMobilityRunner запускает фоновую оценку сотрудников тем же движком,
+// что и резюме
+func newMobilityRunner(db *gorm.DB, nlpClient *nlp.Client, skills *taxonomy.Store, opts matching.Options) *mobility.Runner {
+	return mobility.NewRunner(db, func() mobility.Scorer {
+		return employeeScorer(db, nlpClient, skills.Current(), opts)
+	})
+}
+
+// employeeScorer оценивает сотрудников как резюме: близость от NLP-сервиса
+// пакетом по вакансии (с резервным скорером) и разбор по требованиям
+func employeeScorer(db *gorm.DB, nlpClient *nlp.Client, t *taxonomy.Taxonomy, opts matching.Options) mobility.Scorer {
+	matcher := matching.NewEngine(t, opts)
+	rates := loadRates(db)
+
+	return func(ctx context.Context, vacancy models.Vacancy, employees []models.Employee) []mobility.Score {
+		texts := make([]string, len(employees))
+		items := make([]*pb.ResumeItem, len(employees))
+		for i, e := range employees {
+			texts[i] = mobility.Text(e)
+			items[i] = &pb.ResumeItem{Id: e.ID.String(), Text: texts[i]}
+		}
+		outcomes := nlpClient.BatchMatchWithFallback(ctx, vacancy.ID.String(), vacancyText(vacancy), items, 0)
+		requirements := vacancyRequirements(vacancy, t)
+
+		now := time.Now()
+		scores := make([]mobility.Score, len(employees))
+		for i, e := range employees {
+			if outcomes[i].Err != nil {
+				scores[i].Err = outcomes[i].Err
+				continue
+			}
+			history := mobility.Timeline(e, now)
+			scores[i] = mobility.Score{
+				Explanation: matcher.Evaluate(matching.Input{
+					ResumeText:    texts[i],
+					ResumeYears:   mobility.Years(e, history),
+					Vacancy:       vacancy,
+					Requirements:  requirements,
+					SemanticScore: float64(outcomes[i].Score),
+					History:       history,
+					Location: geo.Location{
+						City:        e.City,
+						Region:      e.Region,
+						Preferences: geo.Preferences{Relocation: geo.Relocation(e.Relocation)},
+					},
+					Rates: rates,
+					Now:   now,
+				}),
+				Degraded: outcomes[i].Degraded,
+			}
+		}
+		return scores
+	}
+}
+
+// ImportEmployees загружает выгрузку сотрудников из HR-системы: файл CSV
+// или JSON в поле file или тело запроса (text/csv, application/json).
+// Строки с ошибками пропускаются и перечисляются в ответе; загруженные
+// сотрудники оцениваются для опубликованных вакансий в фоне.
+func ImportEmployees(c *gin.Context, db *gorm.DB, skills *taxonomy.Store, employees *mobility.Runner) {
+	var body io.Reader
+	format := c.Query("format")
+	if file, err := c.FormFile("file"); err == nil {
+		if file.Size > maxImportSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Файл больше 20 МБ"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Файл не загружен: " + err.Error()})
+			return
+		}
+		defer f.Close()
+		body = f
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file.Filename)), ".")
+		}
+	} else {
+		body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+		if format == "" {
+			format = "json"
+			if strings.Contains(c.ContentType(), "csv") {
+				format = "csv"
+			}
+		}
+	}
+
+	var rows []mobility.Row
+	var rowErrors []mobility.RowError
+	var err error
+	switch format {
+	case "csv":
+		rows, rowErrors, err = mobility.ParseCSV(body)
+	case "json":
+		rows, rowErrors, err = mobility.ParseJSON(body)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Поддерживаются выгрузки CSV и JSON"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ошибка чтения выгрузки: " + err.Error()})
+		return
+	}
+
+	result, err := mobility.Import(db, skills.Current(), rows, rowErrors)
+	if err != nil {
+		log.WithError(err).Error("Ошибка импорта сотрудников")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	if len(result.IDs) > 0 {
+		employees.Employees(result.IDs)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// ListEmployees возвращает страницу сотрудников для HR
+func ListEmployees(c *gin.Context, db *gorm.DB) {
+	q, err := parseListQuery(c, employeeSorts, "full_name")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	f := newFilters(c, db.Model(&models.Employee{}))
+	f.equal("department", "department")
+	f.equal("role_family", "role_family")
+	f.equal("city", "city")
+	f.contains("q", "full_name")
+	f.boolean("open_to_move", "open_to_move")
+	f.boolean("share_with_managers", "share_with_managers")
+	f.boolean("active", "active")
+	if f.err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": f.err.Error()})
+		return
+	}
+
+	var rows []models.Employee
+	if err := q.apply(f.tx).Find(&rows).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения сотрудников")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения сотрудников"})
+		return
+	}
+	rows, next := page(q, rows, func(e models.Employee) (any, uuid.UUID) {
+		if q.sort.column == "imported_at" {
+			return e.ImportedAt, e.ID
+		}
+		return e.FullName, e.ID
+	})
+	c.JSON(http.StatusOK, listResponse(rows, next))
+}
+
+// GetEmployee возвращает профиль сотрудника
+func GetEmployee(c *gin.Context, db *gorm.DB) {
+	employee, ok := loadEmployee(c, db)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": employee})
+}
+
+// PatchEmployee меняет согласия сотрудника и активность. Остальной профиль
+// приходит из HR-системы и меняется повторным импортом.
+func PatchEmployee(c *gin.Context, db *gorm.DB, employees *mobility.Runner) {
+	employee, ok := loadEmployee(c, db)
+	if !ok {
+		return
+	}
+	var patch struct {
+		OpenToMove        *bool `json:"open_to_move"`
+		ShareWithManagers *bool `json:"share_with_managers"`
+		Active            *bool `json:"active"`
+	}
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
+		return
+	}
+
+	wasActive := employee.Active
+	if patch.OpenToMove != nil {
+		employee.OpenToMove = *patch.OpenToMove
+	}
+	if patch.ShareWithManagers != nil {
+		employee.ShareWithManagers = *patch.ShareWithManagers
+	}
+	if patch.Active != nil {
+		employee.Active = *patch.Active
+	}
+	err := db.Model(&employee).Select("OpenToMove", "ShareWithManagers", "Active").Updates(&employee).Error
+	if err != nil {
+		log.WithError(err).Error("Ошибка сохранения сотрудника")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	if employee.Active != wasActive {
+		employees.Employees([]uuid.UUID{employee.ID})
+	}
+	c.JSON(http.StatusOK, gin.H{"data": employee})
+}
+
+// DeleteEmployee удаляет сотрудника вместе с его оценками
+func DeleteEmployee(c *gin.Context, db *gorm.DB) {
+	employee, ok := loadEmployee(c, db)
+	if !ok {
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("employee_id = ?", employee.ID).Delete(&models.EmployeeMatch{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&employee).Error
+	})
+	if err != nil {
+		log.WithError(err).Error("Ошибка удаления сотрудника")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления сотрудника"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// EmployeeVacancies возвращает опубликованные вакансии, для которых оценён
+// сотрудник, от лучшей оценки. Список для HR: согласия не проверяются.
+func EmployeeVacancies(c *gin.Context, db *gorm.DB) {
+	employee, ok := loadEmployee(c, db)
+	if !ok {
+		return
+	}
+	q, err := parseListQuery(c, internalSorts, "-score")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	inner := db.Model(&models.EmployeeMatch{}).
+		Select("vacancies.id, vacancies.title, vacancies.city, employee_matches.score, employee_matches.degraded, employee_matches.explanation, employee_matches.scored_at").
+		Joins("JOIN vacancies ON vacancies.id = employee_matches.vacancy_id").
+		Where("employee_matches.employee_id = ? AND vacancies.status = ?", employee.ID, models.VacancyPublished)
+	f := newFilters(c, db.Table("(?) AS m", inner))
+	f.number("min_score", "score", ">=")
+	if f.err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": f.err.Error()})
+		return
+	}
+
+	var rows []scoredVacancy
+	if err := q.apply(f.tx).Find(&rows).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения вакансий сотрудника")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения вакансий"})
+		return
+	}
+	items := make([]employeeVacancy, len(rows))
+	for i, r := range rows {
+		items[i] = employeeVacancy{
+			VacancyID: r.ID,
+			Title:     r.Title,
+			City:      r.City,
+			Score:     r.Score,
+			Degraded:  r.Degraded,
+			Missing:   missingRequirements(r.Explanation),
+			ScoredAt:  r.ScoredAt,
+		}
+	}
+	items, next := page(q, items, func(v employeeVacancy) (any, uuid.UUID) {
+		if q.sort.column == "scored_at" {
+			return v.ScoredAt, v.VacancyID
+		}
+		return v.Score, v.VacancyID
+	})
+	c.JSON(http.StatusOK, listResponse(items, next))
+}
+
+// EmployeeGaps возвращает пробелы сотрудника по вакансии vacancy_id и план
+// развития из каталога обучения
+func EmployeeGaps(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	employee, ok := loadEmployee(c, db)
+	if !ok {
+		return
+	}
+	vacancyID, err := uuid.Parse(c.Query("vacancy_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор вакансии"})
+		return
+	}
+	perGap, err := intParam(c, "resources", gaps.DefaultResources)
+	if err != nil || perGap < 1 || perGap > maxGapResources {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "resources: ожидается число от 1 до 10"})
+		return
+	}
+
+	var match models.EmployeeMatch
+	if err := db.First(&match, "employee_id = ? AND vacancy_id = ?", employee.ID, vacancyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Сотрудник ещё не оценён для вакансии"})
+		return
+	}
+	var explanation matching.Explanation
+	if err := json.Unmarshal([]byte(match.Explanation), &explanation); err != nil {
+		log.WithError(err).Error("Ошибка разбора обоснования оценки")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Обоснование оценки повреждено"})
+		return
+	}
+	var catalog []models.LearningResource
+	if err := db.Where("archived_at IS NULL").Find(&catalog).Error; err != nil {
+		log.WithError(err).Error("Ошибка загрузки каталога обучения")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка загрузки каталога обучения"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"employee_id": employee.ID.String(),
+		"vacancy_id":  vacancyID.String(),
+		"degraded":    match.Degraded,
+		"data":        gaps.Analyze(explanation, skills.Current(), catalog, perGap),
+	})
+}
+
+// InternalShortlist возвращает сотрудников, подходящих на вакансию, от
+// лучшей оценки. audience=manager (по умолчанию) - только согласившиеся на
+// переход и на показ руководителям, audience=hr - все согласившиеся на
+// переход, только с ключом HR. Фильтры: min_score, department.
+func InternalShortlist(c *gin.Context, db *gorm.DB, access mobility.Options) {
+	audience := c.DefaultQuery("audience", mobility.AudienceManager)
+	if audience == mobility.AudienceHR && !hrAllowed(c, access) {
+		return
+	}
+	vacancy, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+	q, err := parseListQuery(c, internalSorts, "-score")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	inner, err := mobility.Visible(db.Model(&models.Employee{}), audience, "employees")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	inner = inner.
+		Select("employees.*, employee_matches.score, employee_matches.degraded, employee_matches.explanation, employee_matches.scored_at").
+		Joins("JOIN employee_matches ON employee_matches.employee_id = employees.id").
+		Where("employee_matches.vacancy_id = ?", vacancy.ID)
+	f := newFilters(c, db.Table("(?) AS s", inner))
+	f.number("min_score", "score", ">=")
+	f.equal("department", "department")
+	if f.err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": f.err.Error()})
+		return
+	}
+
+	var rows []scoredEmployee
+	if err := q.apply(f.tx).Find(&rows).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения внутреннего шорт-листа")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения шорт-листа"})
+		return
+	}
+	items := make([]internalCandidate, len(rows))
+	for i, r := range rows {
+		items[i] = internalCandidate{
+			Employee: r.Employee,
+			Score:    r.Score,
+			Degraded: r.Degraded,
+			Missing:  missingRequirements(r.Explanation),
+			ScoredAt: r.ScoredAt,
+		}
+	}
+	items, next := page(q, items, func(e internalCandidate) (any, uuid.UUID) {
+		if q.sort.column == "scored_at" {
+			return e.ScoredAt, e.Employee.ID
+		}
+		return e.Score, e.Employee.ID
+	})
+	c.JSON(http.StatusOK, listResponse(items, next))
+}
+
+// RefreshInternalShortlist заново оценивает сотрудников для вакансии в фоне,
+// например после правки таксономии навыков
+func RefreshInternalShortlist(c *gin.Context, db *gorm.DB, employees *mobility.Runner) {
+	vacancy, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+	if vacancy.Status != models.VacancyPublished {
+		c.JSON(http.StatusConflict, gin.H{"error": "Сотрудники оцениваются только для опубликованных вакансий"})
+		return
+	}
+	employees.Vacancy(vacancy.ID)
+	c.JSON(http.StatusAccepted, gin.H{"vacancy_id": vacancy.ID.String()})
+}
+
+// missingRequirements достаёт незакрытые требования из сохранённого обоснования
+func missingRequirements(raw string) []string {
+	var explanation matching.Explanation
+	if err := json.Unmarshal([]byte(raw), &explanation); err != nil || explanation.Missing == nil {
+		return []string{}
+	}
+	return explanation.Missing
+}
+
+func loadEmployee(c *gin.Context, db *gorm.DB) (models.Employee, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор сотрудника"})
+		return models.Employee{}, false
+	}
+
+	var employee models.Employee
+	if err := db.First(&employee, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Сотрудник не найден"})
+		return models.Employee{}, false
+	}
+	return employee, true
+}
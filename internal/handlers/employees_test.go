@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moverq1337/VTBHack/internal/mobility"
+)
+
+func TestRequireHR(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name   string
+		access mobility.Options
+		path   string
+		key    string
+		want   int
+	}{
+		{"ключ не настроен", mobility.Options{}, "/employees", "", http.StatusForbidden},
+		{"ключ не настроен, любой заголовок", mobility.Options{}, "/employees", "x", http.StatusForbidden},
+		{"без ключа", mobility.Options{HRKey: "secret"}, "/employees", "", http.StatusUnauthorized},
+		{"неверный ключ", mobility.Options{HRKey: "secret"}, "/employees", "wrong", http.StatusUnauthorized},
+		{"верный ключ", mobility.Options{HRKey: "secret"}, "/employees", "secret", http.StatusOK},
+		{"шорт-лист для HR без ключа", mobility.Options{HRKey: "secret"}, "/shortlist?audience=hr", "", http.StatusUnauthorized},
+		{"шорт-лист для HR, ключ не настроен", mobility.Options{}, "/shortlist?audience=hr", "secret", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/employees", requireHR(tt.access), func(c *gin.Context) { c.Status(http.StatusOK) })
+			// До проверки ключа шорт-лист не обращается к БД
+			r.GET("/shortlist", func(c *gin.Context) { InternalShortlist(c, nil, tt.access) })
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(hrKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("код %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -4,18 +4,43 @@ import (
 	"os"
 
 	"github.com/gin-gonic/gin"
+	"github.com/moverq1337/VTBHack/internal/compare"
+	"github.com/moverq1337/VTBHack/internal/lint"
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/moverq1337/VTBHack/internal/mobility"
+	"github.com/moverq1337/VTBHack/internal/nlp"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"github.com/moverq1337/VTBHack/internal/vectors"
 	"gorm.io/gorm"
 )
 
 // SetupRoutes настраивает маршруты для API Gateway
-func SetupRoutes(r *gin.Engine, db *gorm.DB) {
+func SetupRoutes(r *gin.Engine, db *gorm.DB, nlpClient *nlp.Client, skills *taxonomy.Store, embeddings *vectors.Store, opts matching.Options, lintOpts lint.Options, compareOpts compare.Options, access mobility.Options) {
+	employees := newMobilityRunner(db, nlpClient, skills, opts)
 	api := r.Group("/api")
 	{
-		api.POST("/upload/resume", func(c *gin.Context) { UploadResume(c, db) })
-		api.POST("/upload/vacancy", func(c *gin.Context) { UploadVacancy(c, db) })
-		api.POST("/analyze", func(c *gin.Context) { AnalyzeResume(c, db) })
+		api.POST("/upload/resume", func(c *gin.Context) { UploadResume(c, db, nlpClient, skills, embeddings) })
+		api.POST("/upload/vacancy", func(c *gin.Context) { UploadVacancy(c, db, skills, lintOpts, embeddings, employees) })
+		api.POST("/analyze", func(c *gin.Context) { AnalyzeResume(c, db, nlpClient, skills, opts) })
+		api.POST("/analyze/batch", func(c *gin.Context) { AnalyzeBatch(c, db, nlpClient) })
+		api.GET("/analyses/:id/explanation", func(c *gin.Context) { AnalysisExplanation(c, db) })
+		api.GET("/analyses/:id/highlights", func(c *gin.Context) { AnalysisHighlights(c, db) })
+		api.GET("/analyses/:id/gaps", func(c *gin.Context) { AnalysisGaps(c, db, skills) })
+		api.GET("/resumes/:id/timeline", func(c *gin.Context) { ResumeTimeline(c, db, skills) })
+		api.GET("/resumes/:id/sections", func(c *gin.Context) { ResumeSections(c, db) })
+		api.PUT("/vacancies/:id", func(c *gin.Context) { UpdateVacancy(c, db, skills, lintOpts, embeddings, employees) })
+		api.POST("/vacancies/:id/publish", func(c *gin.Context) { PublishVacancy(c, db, skills, lintOpts, employees) })
+		api.GET("/vacancies/:id/lint", func(c *gin.Context) { VacancyLint(c, db) })
+		api.GET("/vacancies/:id/requirements", func(c *gin.Context) { VacancyRequirements(c, db, skills) })
+		api.GET("/vacancies/:id/compare", func(c *gin.Context) { CompareCandidates(c, db, nlpClient, skills, opts, compareOpts) })
+		api.PUT("/vacancies/:id/requirements", func(c *gin.Context) { UpdateVacancyRequirements(c, db, skills, lintOpts, employees) })
+		api.POST("/vacancies/:id/requirements/parse", func(c *gin.Context) { ParseVacancyRequirements(c, db, skills, lintOpts, employees) })
+		api.GET("/candidates/:id", func(c *gin.Context) { GetCandidate(c, db) })
+		api.GET("/candidates/:id/outreach", func(c *gin.Context) { CandidateOutreach(c, db) })
 		api.GET("/health", HealthCheck)
 	}
+	setupV1Routes(api.Group("/v1"), db, skills, embeddings, employees, lintOpts, access)
+	setupMatchRoutes(api.Group("/v1/match-runs"), db, nlpClient, skills, opts)
 
 	r.GET("/interview", func(c *gin.Context) {
 		// Логируем попытку доступа к файлу
@@ -32,17 +57,167 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 	})
 
 	r.GET("/health", HealthCheck)
+	r.GET("/admin/nlp", func(c *gin.Context) { NLPStatus(c, nlpClient) })
+	r.GET("/admin/rates", func(c *gin.Context) { ListRates(c, db) })
+	r.PUT("/admin/rates/:currency", func(c *gin.Context) { SetRate(c, db) })
+	setupSkillRoutes(r, skills)
 }
 
 // SetupResumeRoutes настраивает маршруты для Resume Service
-func SetupResumeRoutes(r *gin.Engine, db *gorm.DB) {
-	r.POST("/upload/resume", func(c *gin.Context) { UploadResume(c, db) })
-	r.POST("/upload/vacancy", func(c *gin.Context) { UploadVacancy(c, db) })
-	r.POST("/analyze", func(c *gin.Context) { AnalyzeResume(c, db) })
+func SetupResumeRoutes(r *gin.Engine, db *gorm.DB, nlpClient *nlp.Client, skills *taxonomy.Store, embeddings *vectors.Store, opts matching.Options, lintOpts lint.Options, compareOpts compare.Options, access mobility.Options) {
+	employees := newMobilityRunner(db, nlpClient, skills, opts)
+	r.POST("/upload/resume", func(c *gin.Context) { UploadResume(c, db, nlpClient, skills, embeddings) })
+	r.POST("/upload/vacancy", func(c *gin.Context) { UploadVacancy(c, db, skills, lintOpts, embeddings, employees) })
+	r.POST("/analyze", func(c *gin.Context) { AnalyzeResume(c, db, nlpClient, skills, opts) })
+	r.POST("/analyze/batch", func(c *gin.Context) { AnalyzeBatch(c, db, nlpClient) })
+	r.GET("/analyses/:id/explanation", func(c *gin.Context) { AnalysisExplanation(c, db) })
+	r.GET("/analyses/:id/highlights", func(c *gin.Context) { AnalysisHighlights(c, db) })
+	r.GET("/analyses/:id/gaps", func(c *gin.Context) { AnalysisGaps(c, db, skills) })
+	r.GET("/resumes/:id/timeline", func(c *gin.Context) { ResumeTimeline(c, db, skills) })
+	r.GET("/resumes/:id/sections", func(c *gin.Context) { ResumeSections(c, db) })
+	r.PUT("/vacancies/:id", func(c *gin.Context) { UpdateVacancy(c, db, skills, lintOpts, embeddings, employees) })
+	r.POST("/vacancies/:id/publish", func(c *gin.Context) { PublishVacancy(c, db, skills, lintOpts, employees) })
+	r.GET("/vacancies/:id/lint", func(c *gin.Context) { VacancyLint(c, db) })
+	r.GET("/vacancies/:id/requirements", func(c *gin.Context) { VacancyRequirements(c, db, skills) })
+	r.GET("/vacancies/:id/compare", func(c *gin.Context) { CompareCandidates(c, db, nlpClient, skills, opts, compareOpts) })
+	r.PUT("/vacancies/:id/requirements", func(c *gin.Context) { UpdateVacancyRequirements(c, db, skills, lintOpts, employees) })
+	r.POST("/vacancies/:id/requirements/parse", func(c *gin.Context) { ParseVacancyRequirements(c, db, skills, lintOpts, employees) })
+	r.GET("/candidates/:id", func(c *gin.Context) { GetCandidate(c, db) })
+	r.GET("/candidates/:id/outreach", func(c *gin.Context) { CandidateOutreach(c, db) })
 	r.GET("/health", HealthCheck)
+	r.GET("/admin/nlp", func(c *gin.Context) { NLPStatus(c, nlpClient) })
+	r.GET("/admin/rates", func(c *gin.Context) { ListRates(c, db) })
+	r.PUT("/admin/rates/:currency", func(c *gin.Context) { SetRate(c, db) })
+	setupSkillRoutes(r, skills)
+	setupV1Routes(r.Group("/v1"), db, skills, embeddings, employees, lintOpts, access)
+	setupMatchRoutes(r.Group("/v1/match-runs"), db, nlpClient, skills, opts)
+}
+
+// setupV1Routes настраивает ресурсы REST API v1: вакансии, резюме, анализы,
+// сохранённые запросы поиска, поиск похожих, каталог обучения и сотрудники
+// для внутренних переводов (только с ключом HR)
+func setupV1Routes(v1 *gin.RouterGroup, db *gorm.DB, skills *taxonomy.Store, embeddings *vectors.Store, employees *mobility.Runner, lintOpts lint.Options, access mobility.Options) {
+	vacancies := v1.Group("/vacancies")
+	{
+		vacancies.GET("", func(c *gin.Context) { ListVacancies(c, db) })
+		vacancies.POST("", func(c *gin.Context) { CreateVacancy(c, db, skills, lintOpts, embeddings, employees) })
+		vacancies.GET("/:id", func(c *gin.Context) { GetVacancy(c, db, skills) })
+		vacancies.PATCH("/:id", func(c *gin.Context) { PatchVacancy(c, db, skills, lintOpts, embeddings, employees) })
+		vacancies.DELETE("/:id", func(c *gin.Context) { DeleteVacancy(c, db, embeddings) })
+		vacancies.POST("/:id/archive", func(c *gin.Context) { ArchiveVacancy(c, db, skills, employees) })
+		vacancies.GET("/:id/similar-resumes", func(c *gin.Context) { SimilarCandidates(c, db, embeddings) })
+		vacancies.GET("/:id/internal-shortlist", func(c *gin.Context) { InternalShortlist(c, db, access) })
+		vacancies.POST("/:id/internal-shortlist/refresh", func(c *gin.Context) { RefreshInternalShortlist(c, db, employees) })
+	}
+
+	staff := v1.Group("/employees", requireHR(access))
+	{
+		staff.GET("", func(c *gin.Context) { ListEmployees(c, db) })
+		staff.POST("/import", func(c *gin.Context) { ImportEmployees(c, db, skills, employees) })
+		staff.GET("/:id", func(c *gin.Context) { GetEmployee(c, db) })
+		staff.PATCH("/:id", func(c *gin.Context) { PatchEmployee(c, db, employees) })
+		staff.DELETE("/:id", func(c *gin.Context) { DeleteEmployee(c, db) })
+		staff.GET("/:id/vacancies", func(c *gin.Context) { EmployeeVacancies(c, db) })
+		staff.GET("/:id/gaps", func(c *gin.Context) { EmployeeGaps(c, db, skills) })
+	}
+
+	resumes := v1.Group("/resumes")
+	{
+		resumes.GET("", func(c *gin.Context) { ListResumes(c, db) })
+		resumes.GET("/search", func(c *gin.Context) { SearchResumes(c, db) })
+		resumes.GET("/query", func(c *gin.Context) { QueryResumes(c, db, skills) })
+		resumes.GET("/:id", func(c *gin.Context) { GetResume(c, db) })
+		resumes.PATCH("/:id", func(c *gin.Context) { PatchResume(c, db, skills) })
+		resumes.DELETE("/:id", func(c *gin.Context) { DeleteResume(c, db, embeddings) })
+		resumes.POST("/:id/archive", func(c *gin.Context) { ArchiveResume(c, db) })
+		resumes.GET("/:id/similar", func(c *gin.Context) { SimilarResumes(c, db, embeddings) })
+	}
+
+	analyses := v1.Group("/analyses")
+	{
+		analyses.GET("", func(c *gin.Context) { ListAnalyses(c, db) })
+		analyses.GET("/:id", func(c *gin.Context) { GetAnalysis(c, db) })
+		analyses.PATCH("/:id", func(c *gin.Context) { PatchAnalysis(c, db) })
+		analyses.DELETE("/:id", func(c *gin.Context) { DeleteAnalysis(c, db) })
+		analyses.POST("/:id/archive", func(c *gin.Context) { ArchiveAnalysis(c, db) })
+		analyses.GET("/:id/details", func(c *gin.Context) { AnalysisDetails(c, db) })
+		analyses.GET("/:id/gaps", func(c *gin.Context) { AnalysisGaps(c, db, skills) })
+	}
+
+	learning := v1.Group("/learning-resources")
+	{
+		learning.GET("", func(c *gin.Context) { ListLearningResources(c, db, skills) })
+		learning.POST("", func(c *gin.Context) { CreateLearningResource(c, db, skills) })
+		learning.GET("/:id", func(c *gin.Context) { GetLearningResource(c, db) })
+		learning.PATCH("/:id", func(c *gin.Context) { PatchLearningResource(c, db, skills) })
+		learning.DELETE("/:id", func(c *gin.Context) { DeleteLearningResource(c, db) })
+		learning.POST("/:id/archive", func(c *gin.Context) { ArchiveLearningResource(c, db) })
+	}
+
+	queries := v1.Group("/saved-queries")
+	{
+		queries.GET("", func(c *gin.Context) { ListSavedQueries(c, db) })
+		queries.POST("", func(c *gin.Context) { CreateSavedQuery(c, db) })
+		queries.GET("/:id", func(c *gin.Context) { GetSavedQuery(c, db) })
+		queries.PATCH("/:id", func(c *gin.Context) { PatchSavedQuery(c, db) })
+		queries.DELETE("/:id", func(c *gin.Context) { DeleteSavedQuery(c, db) })
+		queries.GET("/:id/resumes", func(c *gin.Context) { SavedQueryResumes(c, db, skills) })
+		queries.POST("/:id/alerts/check", func(c *gin.Context) { CheckSavedQuery(c, db, skills) })
+	}
+	v1.GET("/alerts", func(c *gin.Context) { ListAlerts(c, db, skills) })
+
+	interviews := v1.Group("/interviews")
+	{
+		interviews.GET("", func(c *gin.Context) { ListInterviewResults(c, db) })
+		interviews.POST("", func(c *gin.Context) { SaveInterviewResult(c, db) })
+	}
+
+	analytics := v1.Group("/analytics")
+	{
+		analytics.GET("/clusters", func(c *gin.Context) { LatestClusters(c, db) })
+		analytics.GET("/clusters/runs", func(c *gin.Context) { ListClusterRuns(c, db) })
+		analytics.POST("/clusters/runs", func(c *gin.Context) { StartClusterRun(c, db, skills) })
+		analytics.GET("/clusters/runs/:id", func(c *gin.Context) { GetClusterRun(c, db) })
+		analytics.GET("/clusters/:id/resumes", func(c *gin.Context) { ClusterResumes(c, db) })
+		analytics.GET("/skills/combinations", func(c *gin.Context) { SkillCombinations(c, db, skills) })
+		analytics.GET("/skills/supply-demand", func(c *gin.Context) { SkillSupplyDemand(c, db, skills) })
+	}
+}
+
+// setupMatchRoutes настраивает расчёты матрицы резюме × вакансии кампаний найма
+func setupMatchRoutes(runs *gin.RouterGroup, db *gorm.DB, nlpClient *nlp.Client, skills *taxonomy.Store, opts matching.Options) {
+	runs.GET("", func(c *gin.Context) { ListMatchRuns(c, db) })
+	runs.POST("", func(c *gin.Context) { StartMatchRun(c, db, nlpClient, skills, opts) })
+	runs.GET("/:id", func(c *gin.Context) { GetMatchRun(c, db) })
+	runs.DELETE("/:id", func(c *gin.Context) { DeleteMatchRun(c, db) })
+	runs.GET("/:id/matrix", func(c *gin.Context) { MatchRunMatrix(c, db) })
+	runs.GET("/:id/assignment", func(c *gin.Context) { MatchRunAssignment(c, db) })
+	runs.POST("/:id/assignment", func(c *gin.Context) { ReassignMatchRun(c, db) })
+}
+
+// setupSkillRoutes настраивает администрирование таксономии навыков
+func setupSkillRoutes(r *gin.Engine, skills *taxonomy.Store) {
+	admin := r.Group("/admin/skills")
+	{
+		admin.GET("", func(c *gin.Context) { ListSkills(c, skills) })
+		admin.POST("", func(c *gin.Context) { CreateSkill(c, skills) })
+		admin.POST("/normalize", func(c *gin.Context) { NormalizeSkills(c, skills) })
+		admin.GET("/:id", func(c *gin.Context) { GetSkill(c, skills) })
+		admin.PUT("/:id", func(c *gin.Context) { UpdateSkill(c, skills) })
+		admin.DELETE("/:id", func(c *gin.Context) { DeleteSkill(c, skills) })
+		admin.GET("/:id/related", func(c *gin.Context) { RelatedSkills(c, skills) })
+		admin.PUT("/:id/related/:related_id", func(c *gin.Context) { SetSkillRelation(c, skills) })
+		admin.DELETE("/:id/related/:related_id", func(c *gin.Context) { DeleteSkillRelation(c, skills) })
+		admin.POST("/relations/mine", func(c *gin.Context) { MineSkillRelations(c, skills) })
+	}
 }
 
 // HealthCheck проверяет статус сервиса
 func HealthCheck(c *gin.Context) {
 	c.JSON(200, gin.H{"status": "ok"})
 }
+
+// NLPStatus показывает состояние балансировщика и реплик NLP-сервиса
+func NLPStatus(c *gin.Context, nlpClient *nlp.Client) {
+	c.JSON(200, nlpClient.Status())
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// interviewSorts поля сортировки списка интервью
+var interviewSorts = map[string]sortField{
+	"completed_at": {"completed_at", kindTime},
+	"score":        {"score", kindFloat},
+}
+
+// interviewAnswer ответ кандидата на вопрос интервью
+type interviewAnswer struct {
+	Question string  `json:"question"`
+	Answer   string  `json:"answer"`
+	Score    float64 `json:"score"`
+}
+
+// interviewView итог интервью с ответами
+type interviewView struct {
+	models.InterviewResult
+	Answers json.RawMessage `json:"answers"`
+}
+
+// SaveInterviewResult сохраняет итог интервью, который присылает сервис
+// интервью по завершении. Повторная отправка той же сессии заменяет итог.
+func SaveInterviewResult(c *gin.Context, db *gorm.DB) {
+	var req struct {
+		SessionID   string            `json:"session_id"`
+		CandidateID uuid.UUID         `json:"candidate_id"`
+		VacancyID   uuid.UUID         `json:"vacancy_id"`
+		Answers     []interviewAnswer `json:"answers"`
+		Duration    float64           `json:"duration"`
+		StartedAt   time.Time         `json:"started_at"`
+		CompletedAt time.Time         `json:"completed_at"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
+		return
+	}
+	if req.SessionID == "" || req.CandidateID == uuid.Nil || req.VacancyID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Нужны session_id, candidate_id и vacancy_id"})
+		return
+	}
+	if req.Answers == nil {
+		req.Answers = []interviewAnswer{}
+	}
+	if req.CompletedAt.IsZero() {
+		req.CompletedAt = time.Now()
+	}
+
+	var score float64
+	for _, a := range req.Answers {
+		score += a.Score
+	}
+	if len(req.Answers) > 0 {
+		score /= float64(len(req.Answers))
+	}
+	answers, err := json.Marshal(req.Answers)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ответов"})
+		return
+	}
+
+	result := models.InterviewResult{
+		ID:          uuid.New(),
+		SessionID:   req.SessionID,
+		CandidateID: req.CandidateID,
+		VacancyID:   req.VacancyID,
+		Score:       score,
+		Questions:   len(req.Answers),
+		Answers:     string(answers),
+		Duration:    req.Duration,
+		StartedAt:   req.StartedAt,
+		CompletedAt: req.CompletedAt,
+	}
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "session_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"candidate_id", "vacancy_id", "score", "questions", "answers", "duration", "started_at", "completed_at"}),
+	}).Create(&result).Error
+	if err != nil {
+		log.WithError(err).Error("Ошибка сохранения результатов интервью")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения результатов интервью"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": interviewView{InterviewResult: result, Answers: answers}})
+}
+
+// ListInterviewResults возвращает страницу итогов интервью
+func ListInterviewResults(c *gin.Context, db *gorm.DB) {
+	q, err := parseListQuery(c, interviewSorts, "-completed_at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	f := newFilters(c, db.Model(&models.InterviewResult{}))
+	f.id("candidate_id", "candidate_id")
+	f.id("vacancy_id", "vacancy_id")
+	f.number("min_score", "score", ">=")
+	f.since("completed_from", "completed_at", ">=")
+	f.since("completed_to", "completed_at", "<")
+	if f.err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": f.err.Error()})
+		return
+	}
+
+	var results []models.InterviewResult
+	if err := q.apply(f.tx).Find(&results).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения результатов интервью")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения результатов интервью"})
+		return
+	}
+	results, next := page(q, results, func(r models.InterviewResult) (any, uuid.UUID) {
+		if q.sort.column == "score" {
+			return r.Score, r.ID
+		}
+		return r.CompletedAt, r.ID
+	})
+
+	views := make([]interviewView, len(results))
+	for i, r := range results {
+		views[i] = interviewView{InterviewResult: r, Answers: json.RawMessage(r.Answers)}
+	}
+	c.JSON(http.StatusOK, listResponse(views, next))
+}
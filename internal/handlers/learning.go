@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/gaps"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/search"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"github.com/moverq1337/VTBHack/internal/titles"
+	"gorm.io/gorm"
+)
+
+// maxGapResources предел материалов на один пробел
+const maxGapResources = 10
+
+// learningSorts поля сортировки каталога учебных материалов
+var learningSorts = map[string]sortField{
+	"created_at": {"created_at", kindTime},
+	"title":      {"title", kindString},
+	"hours":      {"hours", kindInt},
+}
+
+// learningFormats допустимые форматы материалов
+var learningFormats = map[string]bool{
+	models.LearningCourse:    true,
+	models.LearningBook:      true,
+	models.LearningVideo:     true,
+	models.LearningArticle:   true,
+	models.LearningMentoring: true,
+}
+
+// learningRequest тело создания и изменения учебного материала
+type learningRequest struct {
+	Title       *string `json:"title"`
+	Provider    *string `json:"provider"`
+	URL         *string `json:"url"`
+	Format      *string `json:"format"`
+	Level       *string `json:"level"`
+	Hours       *int    `json:"hours"`
+	Language    *string `json:"language"`
+	Free        *bool   `json:"free"`
+	Internal    *bool   `json:"internal"`
+	Skills      *string `json:"skills"`
+	Keywords    *string `json:"keywords"`
+	Description *string `json:"description"`
+}
+
+// AnalysisGaps возвращает незакрытые и слабо закрытые требования анализа
+// с серьёзностью и подходящими материалами каталога: план развития для
+// внутренних кандидатов и тех, кому немного не хватило до вакансии.
+// resources - число материалов на пробел.
+func AnalysisGaps(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	perGap, err := intParam(c, "resources", gaps.DefaultResources)
+	if err != nil || perGap < 1 || perGap > maxGapResources {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "resources: ожидается число от 1 до 10"})
+		return
+	}
+	analysis, explanation, ok := loadExplanation(c, db)
+	if !ok {
+		return
+	}
+
+	var catalog []models.LearningResource
+	if err := db.Where("archived_at IS NULL").Find(&catalog).Error; err != nil {
+		log.WithError(err).Error("Ошибка загрузки каталога обучения")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка загрузки каталога обучения"})
+		return
+	}
+
+	report := gaps.Analyze(explanation, skills.Current(), catalog, perGap)
+	c.JSON(http.StatusOK, gin.H{
+		"analysis_id": analysis.ID.String(),
+		"resume_id":   analysis.ResumeID.String(),
+		"vacancy_id":  analysis.VacancyID.String(),
+		"degraded":    analysis.Degraded,
+		"data":        report,
+	})
+}
+
+// ListLearningResources возвращает страницу каталога; skill - материалы по навыку
+func ListLearningResources(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	q, err := parseListQuery(c, learningSorts, "title")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	f := newFilters(c, db.Model(&models.LearningResource{}))
+	f.equal("format", "format")
+	f.equal("level", "level")
+	f.equal("language", "language")
+	f.boolean("free", "free")
+	f.boolean("internal", "internal")
+	f.archived("archived_at")
+	if f.err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": f.err.Error()})
+		return
+	}
+	tx := f.tx
+	if skill := c.Query("skill"); skill != "" {
+		if s, ok := skills.Current().Lookup(skill); ok {
+			skill = s.Name
+		}
+		tx = tx.Where(search.SkillCondition("skills"), skill)
+	}
+
+	var resources []models.LearningResource
+	if err := q.apply(tx).Find(&resources).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения каталога обучения")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения каталога обучения"})
+		return
+	}
+	resources, next := page(q, resources, func(r models.LearningResource) (any, uuid.UUID) {
+		switch q.sort.column {
+		case "title":
+			return r.Title, r.ID
+		case "hours":
+			return r.Hours, r.ID
+		}
+		return r.CreatedAt, r.ID
+	})
+	c.JSON(http.StatusOK, listResponse(resources, next))
+}
+
+// CreateLearningResource добавляет материал в каталог
+func CreateLearningResource(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	var req learningRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Title == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных: нужен title"})
+		return
+	}
+
+	resource := models.LearningResource{ID: uuid.New(), Format: models.LearningCourse}
+	if !applyLearningResource(c, &resource, req, skills.Current()) {
+		return
+	}
+	if err := db.Create(&resource).Error; err != nil {
+		log.WithError(err).Error("Ошибка сохранения учебного материала")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": resource})
+}
+
+// GetLearningResource возвращает материал каталога
+func GetLearningResource(c *gin.Context, db *gorm.DB) {
+	resource, ok := loadLearningResource(c, db)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resource})
+}
+
+// PatchLearningResource изменяет переданные поля материала
+func PatchLearningResource(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	resource, ok := loadLearningResource(c, db)
+	if !ok {
+		return
+	}
+	var req learningRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
+		return
+	}
+	if !applyLearningResource(c, &resource, req, skills.Current()) {
+		return
+	}
+	if err := db.Select("*").Omit("CreatedAt").Updates(&resource).Error; err != nil {
+		log.WithError(err).Error("Ошибка сохранения учебного материала")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resource})
+}
+
+// ArchiveLearningResource убирает материал из подбора, сохраняя его в каталоге
+func ArchiveLearningResource(c *gin.Context, db *gorm.DB) {
+	resource, ok := loadLearningResource(c, db)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	resource.ArchivedAt = &now
+	if err := db.Model(&resource).Update("archived_at", now).Error; err != nil {
+		log.WithError(err).Error("Ошибка архивации учебного материала")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resource})
+}
+
+// DeleteLearningResource удаляет материал из каталога
+func DeleteLearningResource(c *gin.Context, db *gorm.DB) {
+	resource, ok := loadLearningResource(c, db)
+	if !ok {
+		return
+	}
+	if err := db.Delete(&resource).Error; err != nil {
+		log.WithError(err).Error("Ошибка удаления учебного материала")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления учебного материала"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// applyLearningResource проверяет и переносит изменения в материал.
+// Навыки приводятся к каноническим названиям таксономии.
+func applyLearningResource(c *gin.Context, r *models.LearningResource, req learningRequest, t *taxonomy.Taxonomy) bool {
+	if req.Title != nil {
+		r.Title = strings.TrimSpace(*req.Title)
+	}
+	if r.Title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не указано название материала"})
+		return false
+	}
+	if req.Provider != nil {
+		r.Provider = strings.TrimSpace(*req.Provider)
+	}
+	if req.URL != nil {
+		r.URL = strings.TrimSpace(*req.URL)
+		if u, err := url.Parse(r.URL); r.URL != "" && (err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "url: ожидается ссылка http или https"})
+			return false
+		}
+	}
+	if req.Format != nil {
+		if !learningFormats[*req.Format] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "format: ожидается course, book, video, article или mentoring"})
+			return false
+		}
+		r.Format = *req.Format
+	}
+	if req.Level != nil {
+		if *req.Level != "" {
+			if _, ok := titles.ParseLevel(*req.Level); !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Неизвестный уровень: " + *req.Level})
+				return false
+			}
+		}
+		r.Level = *req.Level
+	}
+	if req.Hours != nil {
+		if *req.Hours < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Длительность не может быть отрицательной"})
+			return false
+		}
+		r.Hours = *req.Hours
+	}
+	if req.Language != nil {
+		if *req.Language != "" && *req.Language != "ru" && *req.Language != "en" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "language: ожидается ru, en или пустая строка"})
+			return false
+		}
+		r.Language = *req.Language
+	}
+	if req.Free != nil {
+		r.Free = *req.Free
+	}
+	if req.Internal != nil {
+		r.Internal = *req.Internal
+	}
+	if req.Skills != nil {
+		r.Skills = t.NormalizeList(*req.Skills)
+	}
+	if req.Keywords != nil {
+		r.Keywords = strings.TrimSpace(*req.Keywords)
+	}
+	if req.Description != nil {
+		r.Description = *req.Description
+	}
+	if r.Skills == "" && r.Keywords == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Укажите skills или keywords: по ним материал подбирается к пробелам"})
+		return false
+	}
+	return true
+}
+
+func loadLearningResource(c *gin.Context, db *gorm.DB) (models.LearningResource, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор материала"})
+		return models.LearningResource{}, false
+	}
+
+	var resource models.LearningResource
+	if err := db.First(&resource, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Материал не найден"})
+		return models.LearningResource{}, false
+	}
+	return resource, true
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/moverq1337/VTBHack/internal/contacts"
+	"github.com/moverq1337/VTBHack/internal/geo"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/segment"
+)
+
+// extractLocation определяет город кандидата и его пожелания. Пожелания
+// ищутся вне опыта работы и проектов: там "удалённо" относится к должности.
+func extractLocation(text string, sections []segment.Section, found contacts.Contacts) geo.Location {
+	var loc geo.Location
+	if city, ok := geo.Resolve(found.Value(contacts.KindCity)); ok {
+		loc.City, loc.Region = city.Name, city.Region
+	}
+
+	header := text
+	if len(sections) > 0 {
+		var parts []string
+		for _, s := range segment.Of(sections, segment.KindContacts, segment.KindSummary, segment.KindOther) {
+			parts = append(parts, s.Text)
+		}
+		header = strings.Join(parts, "\n")
+	}
+	loc.Preferences = geo.ParsePreferences(header)
+	return loc
+}
+
+// applyLocation сохраняет местоположение в резюме
+func applyLocation(resume *models.Resume, loc geo.Location) {
+	resume.City = loc.City
+	resume.Region = loc.Region
+	resume.Relocation = string(loc.Relocation)
+	resume.RelocateTo = strings.Join(loc.RelocationCities, ", ")
+	resume.Remote = loc.Remote
+}
+
+// resumeLocation местоположение из резюме. Для резюме, загруженных раньше,
+// оно извлекается из текста заново.
+func resumeLocation(resume models.Resume) geo.Location {
+	if resume.City == "" && resume.Relocation == "" && !resume.Remote {
+		sections := resumeSections(resume)
+		return extractLocation(resume.Text, sections, contacts.Extract(resume.Text, sections))
+	}
+
+	loc := geo.Location{City: resume.City, Region: resume.Region}
+	loc.Relocation = geo.Relocation(resume.Relocation)
+	loc.Remote = resume.Remote
+	if resume.RelocateTo != "" {
+		loc.RelocationCities = strings.Split(resume.RelocateTo, ", ")
+	}
+	return loc
+}
+
+// normalizeVacancyPlace приводит город и регион вакансии к названиям справочника.
+// Неизвестные названия сохраняются как есть.
+func normalizeVacancyPlace(vacancy *models.Vacancy) {
+	if city, ok := geo.LookupCity(vacancy.City); ok {
+		vacancy.City = city.Name
+		if vacancy.Region == "" {
+			vacancy.Region = city.Region
+		}
+	}
+	if region, ok := geo.LookupRegion(vacancy.Region); ok {
+		vacancy.Region = region.Name
+	}
+}
@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/search"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"gorm.io/gorm"
+)
+
+// savedQuerySorts поля сортировки списка сохранённых запросов
+var savedQuerySorts = map[string]sortField{
+	"created_at": {"created_at", kindTime},
+	"name":       {"name", kindString},
+}
+
+// savedQueryRequest тело создания и изменения сохранённого запроса
+type savedQueryRequest struct {
+	Name  *string `json:"name"`
+	Query *string `json:"query"`
+	Alert *bool   `json:"alert"`
+}
+
+// QueryResumes ищет резюме булевым запросом:
+// (go OR golang) AND kafka AND NOT стажер AND experience>=3 AND city:Москва
+func QueryResumes(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	q, err := parseListQuery(c, searchSorts, "-rank")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	respondQuery(c, db, skills, q, c.Query("q"))
+}
+
+// respondQuery разбирает запрос и отдаёт страницу подходящих резюме.
+// Ошибка в запросе возвращается с позицией: её можно подсветить в поле ввода.
+func respondQuery(c *gin.Context, db *gorm.DB, skills *taxonomy.Store, q listQuery, query string) {
+	node, err := search.Parse(query)
+	if err != nil {
+		queryError(c, err)
+		return
+	}
+
+	tx, err := search.Where(db.Model(&models.Resume{}), node, search.Env{Rates: loadRates(db), Skills: skills.Current()})
+	if err != nil {
+		log.WithError(err).Error("Ошибка построения поискового запроса")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка поиска"})
+		return
+	}
+	f := newFilters(c, tx)
+	f.archived("archived_at")
+	if f.err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": f.err.Error()})
+		return
+	}
+
+	hits, next, err := runSearch(db, q, f.tx, search.Terms(node))
+	if err != nil {
+		log.WithError(err).Error("Ошибка поиска резюме")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка поиска"})
+		return
+	}
+	c.JSON(http.StatusOK, listResponse(hits, next))
+}
+
+func queryError(c *gin.Context, err error) {
+	var syntax *search.SyntaxError
+	if errors.As(err, &syntax) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ошибка в запросе: " + syntax.Error(), "position": syntax.Pos})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// ListSavedQueries возвращает страницу сохранённых запросов; alert=true - только подписки
+func ListSavedQueries(c *gin.Context, db *gorm.DB) {
+	q, err := parseListQuery(c, savedQuerySorts, "name")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	f := newFilters(c, db.Model(&models.SavedQuery{}))
+	f.boolean("alert", "alert")
+	if f.err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": f.err.Error()})
+		return
+	}
+
+	var queries []models.SavedQuery
+	if err := q.apply(f.tx).Find(&queries).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения сохранённых запросов")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения запросов"})
+		return
+	}
+	queries, next := page(q, queries, func(s models.SavedQuery) (any, uuid.UUID) {
+		if q.sort.column == "name" {
+			return s.Name, s.ID
+		}
+		return s.CreatedAt, s.ID
+	})
+	c.JSON(http.StatusOK, listResponse(queries, next))
+}
+
+// CreateSavedQuery сохраняет запрос после проверки синтаксиса
+func CreateSavedQuery(c *gin.Context, db *gorm.DB) {
+	var req savedQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Query == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных: нужны name и query"})
+		return
+	}
+
+	saved := models.SavedQuery{ID: uuid.New(), CheckedAt: time.Now()}
+	if !applySavedQuery(c, &saved, req) {
+		return
+	}
+	if err := db.Create(&saved).Error; err != nil {
+		log.WithError(err).Error("Ошибка сохранения запроса")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": saved})
+}
+
+// GetSavedQuery возвращает сохранённый запрос
+func GetSavedQuery(c *gin.Context, db *gorm.DB) {
+	saved, ok := loadSavedQuery(c, db)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": saved})
+}
+
+// PatchSavedQuery изменяет название, запрос или подписку
+func PatchSavedQuery(c *gin.Context, db *gorm.DB) {
+	saved, ok := loadSavedQuery(c, db)
+	if !ok {
+		return
+	}
+	var req savedQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
+		return
+	}
+	if !applySavedQuery(c, &saved, req) {
+		return
+	}
+	if err := db.Select("*").Omit("CreatedAt").Updates(&saved).Error; err != nil {
+		log.WithError(err).Error("Ошибка сохранения запроса")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": saved})
+}
+
+// DeleteSavedQuery удаляет сохранённый запрос
+func DeleteSavedQuery(c *gin.Context, db *gorm.DB) {
+	saved, ok := loadSavedQuery(c, db)
+	if !ok {
+		return
+	}
+	if err := db.Delete(&saved).Error; err != nil {
+		log.WithError(err).Error("Ошибка удаления запроса")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления запроса"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SavedQueryResumes выполняет сохранённый запрос
+func SavedQueryResumes(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	saved, ok := loadSavedQuery(c, db)
+	if !ok {
+		return
+	}
+	q, err := parseListQuery(c, searchSorts, "-rank")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	respondQuery(c, db, skills, q, saved.Query)
+}
+
+// CheckSavedQuery возвращает резюме, загруженные после прошлой проверки
+// подписки, от старых к новым, и сдвигает отметку проверки на последнее
+// отданное резюме. Отметка - пара (created_at, id): резюме с тем же
+// временем загрузки не теряются. Если новых больше страницы, следующая
+// проверка вернёт остальные.
+func CheckSavedQuery(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	saved, ok := loadSavedQuery(c, db)
+	if !ok {
+		return
+	}
+	if !saved.Alert {
+		c.JSON(http.StatusConflict, gin.H{"error": "Запрос не является подпиской: включите alert"})
+		return
+	}
+
+	node, err := search.Parse(saved.Query)
+	if err != nil {
+		queryError(c, err)
+		return
+	}
+	tx, err := search.Where(db.Model(&models.Resume{}), node, search.Env{Rates: loadRates(db), Skills: skills.Current()})
+	if err != nil {
+		log.WithError(err).Error("Ошибка построения поискового запроса")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка поиска"})
+		return
+	}
+
+	tx = tx.Where("archived_at IS NULL AND (created_at, id) > (?, ?)", saved.CheckedAt, saved.CheckedID)
+	q := listQuery{sortKey: "created_at", sort: searchSorts["created_at"], limit: maxPageSize}
+	hits, next, err := runSearch(db, q, tx, search.Terms(node))
+	if err != nil {
+		log.WithError(err).Error("Ошибка проверки подписки")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка поиска"})
+		return
+	}
+
+	if len(hits) > 0 {
+		last := hits[len(hits)-1]
+		saved.CheckedAt, saved.CheckedID = last.CreatedAt, last.ID
+		if err := db.Model(&saved).Select("CheckedAt", "CheckedID").Updates(&saved).Error; err != nil {
+			log.WithError(err).Error("Ошибка сохранения отметки проверки")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": hits, "has_more": next != "", "checked_at": saved.CheckedAt})
+}
+
+// ListAlerts возвращает подписки с числом новых резюме, не сдвигая отметки
+// проверки. Новые резюме всех подписок считаются одним запросом: условие
+// каждой подписки применяется к её строке в соединении.
+func ListAlerts(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	var queries []models.SavedQuery
+	if err := db.Where("alert = ?", true).Order("name").Find(&queries).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения подписок")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения подписок"})
+		return
+	}
+
+	env := search.Env{Rates: loadRates(db), Skills: skills.Current()}
+	parseErrors := map[uuid.UUID]string{}
+	var conds []string
+	var args []any
+	for _, saved := range queries {
+		node, err := search.Parse(saved.Query)
+		if err != nil {
+			parseErrors[saved.ID] = err.Error()
+			continue
+		}
+		cond, condArgs := search.Compile(node, env)
+		conds = append(conds, "(saved_queries.id = ? AND "+cond+")")
+		args = append(append(args, saved.ID), condArgs...)
+	}
+
+	counts := map[uuid.UUID]int64{}
+	if len(conds) > 0 {
+		var rows []struct {
+			ID  uuid.UUID
+			New int64
+		}
+		err := db.Table("saved_queries").
+			Select("saved_queries.id AS id, COUNT(resumes.id) AS new").
+			Joins("JOIN resumes ON resumes.archived_at IS NULL AND (resumes.created_at, resumes.id) > (saved_queries.checked_at, saved_queries.checked_id) AND ("+strings.Join(conds, " OR ")+")", args...).
+			Group("saved_queries.id").
+			Scan(&rows).Error
+		if err != nil {
+			log.WithError(err).Error("Ошибка подсчёта новых резюме")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка поиска"})
+			return
+		}
+		for _, r := range rows {
+			counts[r.ID] = r.New
+		}
+	}
+
+	alerts := make([]gin.H, 0, len(queries))
+	for _, saved := range queries {
+		alert := gin.H{"saved_query": saved, "new": counts[saved.ID]}
+		if msg, ok := parseErrors[saved.ID]; ok {
+			alert["error"] = msg
+		}
+		alerts = append(alerts, alert)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": alerts})
+}
+
+// applySavedQuery переносит изменения в запрос. При включении подписки
+// новыми считаются резюме, загруженные после этого момента.
+func applySavedQuery(c *gin.Context, saved *models.SavedQuery, req savedQueryRequest) bool {
+	if req.Name != nil {
+		saved.Name = strings.TrimSpace(*req.Name)
+	}
+	if saved.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не указано название запроса"})
+		return false
+	}
+	if req.Query != nil {
+		if _, err := search.Parse(*req.Query); err != nil {
+			queryError(c, err)
+			return false
+		}
+		saved.Query = *req.Query
+	}
+	if req.Alert != nil {
+		if *req.Alert && !saved.Alert {
+			saved.CheckedAt, saved.CheckedID = time.Now(), uuid.Nil
+		}
+		saved.Alert = *req.Alert
+	}
+	return true
+}
+
+func loadSavedQuery(c *gin.Context, db *gorm.DB) (models.SavedQuery, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор запроса"})
+		return models.SavedQuery{}, false
+	}
+
+	var saved models.SavedQuery
+	if err := db.First(&saved, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Запрос не найден"})
+		return models.SavedQuery{}, false
+	}
+	return saved, true
+}
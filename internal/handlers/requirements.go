@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/lint"
+	"github.com/moverq1337/VTBHack/internal/mobility"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/requirements"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"gorm.io/gorm"
+)
+
+// VacancyRequirements возвращает пункты требований вакансии
+func VacancyRequirements(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	vacancy, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, requirementsResponse(vacancy, vacancyRequirements(vacancy, skills.Current())))
+}
+
+// UpdateVacancyRequirements заменяет пункты требований списком рекрутера.
+// После правки пункты не перезаписываются разбором текста вакансии.
+func UpdateVacancyRequirements(c *gin.Context, db *gorm.DB, skills *taxonomy.Store, lintOpts lint.Options, employees *mobility.Runner) {
+	vacancy, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Requirements []requirements.Item `json:"requirements"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
+		return
+	}
+
+	t := skills.Current()
+	items := make([]requirements.Item, 0, len(req.Requirements))
+	for i, item := range req.Requirements {
+		item, err := requirements.Validate(item, t)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Требование %d: %v", i+1, err)})
+			return
+		}
+		items = append(items, item)
+	}
+
+	result, err := saveRequirements(db, &vacancy, items, true, lintOpts)
+	if err != nil {
+		log.WithError(err).Error("Ошибка сохранения требований вакансии")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения требований"})
+		return
+	}
+	employees.Vacancy(vacancy.ID)
+	response := requirementsResponse(vacancy, items)
+	response["lint"] = result
+	c.JSON(http.StatusOK, response)
+}
+
+// ParseVacancyRequirements заново разбирает требования из текста вакансии,
+// отменяя правки рекрутера
+func ParseVacancyRequirements(c *gin.Context, db *gorm.DB, skills *taxonomy.Store, lintOpts lint.Options, employees *mobility.Runner) {
+	vacancy, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+
+	items := requirements.Parse(vacancy, skills.Current())
+	result, err := saveRequirements(db, &vacancy, items, false, lintOpts)
+	if err != nil {
+		log.WithError(err).Error("Ошибка сохранения требований вакансии")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения требований"})
+		return
+	}
+	employees.Vacancy(vacancy.ID)
+	response := requirementsResponse(vacancy, items)
+	response["lint"] = result
+	c.JSON(http.StatusOK, response)
+}
+
+func loadVacancy(c *gin.Context, db *gorm.DB) (models.Vacancy, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор вакансии"})
+		return models.Vacancy{}, false
+	}
+
+	var vacancy models.Vacancy
+	if err := db.First(&vacancy, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Вакансия не найдена"})
+		return models.Vacancy{}, false
+	}
+	return vacancy, true
+}
+
+func requirementsResponse(vacancy models.Vacancy, items []requirements.Item) gin.H {
+	return gin.H{
+		"vacancy_id":   vacancy.ID.String(),
+		"edited":       vacancy.ItemsEdited,
+		"requirements": items,
+	}
+}
+
+// vacancyRequirements возвращает сохранённые пункты требований. Для
+// вакансий, загруженных до появления пунктов, они разбираются из текста.
+func vacancyRequirements(vacancy models.Vacancy, t *taxonomy.Taxonomy) []requirements.Item {
+	items, err := requirements.Stored(vacancy, t)
+	if err != nil {
+		log.WithError(err).Error("Ошибка разбора требований вакансии")
+	}
+	if items == nil {
+		items = []requirements.Item{}
+	}
+	return items
+}
+
+// encodeRequirements сериализует пункты для хранения в вакансии
+func encodeRequirements(items []requirements.Item) string {
+	if len(items) == 0 {
+		return "[]"
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		log.WithError(err).Error("Ошибка сериализации требований вакансии")
+		return "[]"
+	}
+	return string(data)
+}
+
+// saveRequirements сохраняет пункты и заново проверяет вакансию: от числа
+// обязательных пунктов зависит результат проверки
+func saveRequirements(db *gorm.DB, vacancy *models.Vacancy, items []requirements.Item, edited bool, lintOpts lint.Options) (lint.Result, error) {
+	vacancy.RequirementItems = encodeRequirements(items)
+	vacancy.ItemsEdited = edited
+	result := applyLint(vacancy, items, lintOpts, vacancy.Status == models.VacancyDraft)
+	err := db.Model(vacancy).Select("RequirementItems", "ItemsEdited", "Status", "Lint", "LintErrors", "LintWarnings").Updates(vacancy).Error
+	return result, err
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/search"
+	"gorm.io/gorm"
+)
+
+// Размер страницы списков REST API
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// valueKind тип значения поля сортировки: по нему восстанавливается значение из курсора
+type valueKind int
+
+const (
+	kindTime valueKind = iota
+	kindInt
+	kindFloat
+	kindString
+)
+
+// sortField поле, по которому разрешена сортировка списка
+type sortField struct {
+	column string
+	kind   valueKind
+}
+
+// listQuery параметры списка: сортировка, размер страницы и позиция
+type listQuery struct {
+	sortKey string // Как в запросе: "-created_at"
+	sort    sortField
+	desc    bool
+	limit   int
+	after   *pageCursor
+}
+
+// pageCursor позиция последней записи страницы. Курсор привязан к сортировке:
+// с другой сортировкой он не имеет смысла.
+type pageCursor struct {
+	Sort  string    `json:"s"`
+	Value any       `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// parseListQuery разбирает sort, limit и cursor. Сортировка задаётся
+// именем поля, минус перед ним - по убыванию: sort=-created_at.
+// Равные значения упорядочиваются по ID, поэтому страницы не пересекаются.
+func parseListQuery(c *gin.Context, fields map[string]sortField, defaultSort string) (listQuery, error) {
+	q := listQuery{sortKey: c.DefaultQuery("sort", defaultSort), limit: defaultPageSize}
+
+	name := strings.TrimPrefix(q.sortKey, "-")
+	field, ok := fields[name]
+	if !ok {
+		return q, fmt.Errorf("Неизвестное поле сортировки: %s", name)
+	}
+	q.sort, q.desc = field, strings.HasPrefix(q.sortKey, "-")
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return q, errors.New("limit должен быть положительным числом")
+		}
+		q.limit = min(limit, maxPageSize)
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := decodeCursor(raw, field.kind)
+		if err != nil || cursor.Sort != q.sortKey {
+			return q, errors.New("Неверный курсор")
+		}
+		q.after = &cursor
+	}
+	return q, nil
+}
+
+// apply добавляет к запросу условие курсора, порядок и ограничение.
+// Запрашивается на одну запись больше: по ней видно, есть ли следующая страница.
+func (q listQuery) apply(tx *gorm.DB) *gorm.DB {
+	op, dir := ">", "ASC"
+	if q.desc {
+		op, dir = "<", "DESC"
+	}
+	col := q.sort.column
+	if q.after != nil {
+		tx = tx.Where(fmt.Sprintf("((%s %s ?) OR (%s = ? AND id %s ?))", col, op, col, op), q.after.Value, q.after.Value, q.after.ID)
+	}
+	return tx.Order(col + " " + dir).Order("id " + dir).Limit(q.limit + 1)
+}
+
+// page обрезает лишнюю запись и строит курсор следующей страницы;
+// key возвращает значение поля сортировки и ID записи
+func page[T any](q listQuery, rows []T, key func(T) (any, uuid.UUID)) ([]T, string) {
+	if len(rows) <= q.limit {
+		return rows, ""
+	}
+	rows = rows[:q.limit]
+	value, id := key(rows[len(rows)-1])
+	if t, ok := value.(time.Time); ok {
+		value = t.Format(time.RFC3339Nano)
+	}
+	data, err := json.Marshal(pageCursor{Sort: q.sortKey, Value: value, ID: id})
+	if err != nil {
+		log.WithError(err).Error("Ошибка сериализации курсора")
+		return rows, ""
+	}
+	return rows, base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(raw string, kind valueKind) (pageCursor, error) {
+	var cursor pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return cursor, err
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, err
+	}
+
+	// JSON не сохраняет тип: числа приходят как float64, время - строкой
+	switch v := cursor.Value.(type) {
+	case string:
+		if kind == kindTime {
+			cursor.Value, err = time.Parse(time.RFC3339Nano, v)
+		} else if kind != kindString {
+			err = errors.New("cursor value type")
+		}
+	case float64:
+		switch kind {
+		case kindInt:
+			cursor.Value = int64(v)
+		case kindFloat:
+		default:
+			err = errors.New("cursor value type")
+		}
+	default:
+		err = errors.New("cursor value type")
+	}
+	return cursor, err
+}
+
+// listResponse единый формат списков REST API
+func listResponse[T any](rows []T, next string) gin.H {
+	if rows == nil {
+		rows = []T{}
+	}
+	return gin.H{"data": rows, "next_cursor": next, "has_more": next != ""}
+}
+
+// filters накапливает условия списка из параметров запроса. Первая ошибка
+// разбора сохраняется, остальные условия после неё не добавляются.
+type filters struct {
+	c   *gin.Context
+	tx  *gorm.DB
+	err error
+}
+
+func newFilters(c *gin.Context, tx *gorm.DB) *filters {
+	return &filters{c: c, tx: tx}
+}
+
+// equal точное совпадение строки
+func (f *filters) equal(param, column string) {
+	if v := f.c.Query(param); v != "" && f.err == nil {
+		f.tx = f.tx.Where(column+" = ?", v)
+	}
+}
+
+// contains поиск подстроки без учёта регистра
+func (f *filters) contains(param, column string) {
+	if v := f.c.Query(param); v != "" && f.err == nil {
+		f.tx = f.tx.Where(column+" ILIKE ?", "%"+search.EscapeLike(v)+"%")
+	}
+}
+
+// listed элемент списка через запятую целиком, без учёта регистра
+func (f *filters) listed(param, column string) {
+	if v := f.c.Query(param); v != "" && f.err == nil {
+		f.tx = f.tx.Where(search.SkillCondition(column), strings.TrimSpace(v))
+	}
+}
+
+func (f *filters) id(param, column string) {
+	v := f.c.Query(param)
+	if v == "" || f.err != nil {
+		return
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		f.err = fmt.Errorf("Неверный идентификатор в %s", param)
+		return
+	}
+	f.tx = f.tx.Where(column+" = ?", id)
+}
+
+// number диапазон чисел: op - ">=" или "<="
+func (f *filters) number(param, column, op string) {
+	v := f.c.Query(param)
+	if v == "" || f.err != nil {
+		return
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		f.err = fmt.Errorf("%s должен быть числом", param)
+		return
+	}
+	f.tx = f.tx.Where(column+" "+op+" ?", n)
+}
+
+func (f *filters) boolean(param, column string) {
+	v := f.c.Query(param)
+	if v == "" || f.err != nil {
+		return
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		f.err = fmt.Errorf("%s должен быть true или false", param)
+		return
+	}
+	f.tx = f.tx.Where(column+" = ?", b)
+}
+
+// since диапазон дат в формате RFC 3339 или YYYY-MM-DD; op - ">=" или "<"
+func (f *filters) since(param, column, op string) {
+	v := f.c.Query(param)
+	if v == "" || f.err != nil {
+		return
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, v)
+	}
+	if err != nil {
+		f.err = fmt.Errorf("%s: ожидается дата YYYY-MM-DD", param)
+		return
+	}
+	f.tx = f.tx.Where(column+" "+op+" ?", t)
+}
+
+// archived по умолчанию скрывает архивные записи; archived=true - только архивные, all - все
+func (f *filters) archived(column string) {
+	if f.err != nil {
+		return
+	}
+	switch f.c.DefaultQuery("archived", "false") {
+	case "false":
+		f.tx = f.tx.Where(column + " IS NULL")
+	case "true":
+		f.tx = f.tx.Where(column + " IS NOT NULL")
+	case "all":
+	default:
+		f.err = errors.New("archived: ожидается true, false или all")
+	}
+}
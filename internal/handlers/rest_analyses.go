@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"gorm.io/gorm"
+)
+
+// analysisSorts поля сортировки списка анализов
+var analysisSorts = map[string]sortField{
+	"created_at":  {"created_at", kindTime},
+	"match_score": {"match_score", kindFloat},
+	"score":       {"score", kindFloat},
+}
+
+// analysisView анализ с обоснованием оценки
+type analysisView struct {
+	models.AnalysisResult
+	Details     json.RawMessage       `json:"details"`
+	Explanation *matching.Explanation `json:"explanation"`
+}
+
+func newAnalysisView(analysis models.AnalysisResult) analysisView {
+	view := analysisView{AnalysisResult: analysis, Details: json.RawMessage(analysis.Details)}
+	if !json.Valid(view.Details) {
+		view.Details = json.RawMessage("{}")
+	}
+	var explanation matching.Explanation
+	if err := json.Unmarshal([]byte(analysis.Explanation), &explanation); err == nil && explanation.Criteria != nil {
+		view.Explanation = &explanation
+	}
+	return view
+}
+
+// detailView критерий анализа с фрагментами резюме
+type detailView struct {
+	models.AnalysisDetail
+	Evidence json.RawMessage `json:"evidence"`
+}
+
+// analysisPatch решение рекрутера и заметка
+type analysisPatch struct {
+	Decision *string `json:"decision"`
+	Note     *string `json:"note"`
+}
+
+// ListAnalyses возвращает страницу анализов
+func ListAnalyses(c *gin.Context, db *gorm.DB) {
+	q, err := parseListQuery(c, analysisSorts, "-created_at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	f := newFilters(c, db.Model(&models.AnalysisResult{}))
+	f.id("resume_id", "resume_id")
+	f.id("vacancy_id", "vacancy_id")
+	f.number("min_score", "score", ">=")
+	f.number("max_score", "score", "<=")
+	f.boolean("degraded", "degraded")
+	f.equal("decision", "decision")
+	f.since("created_from", "created_at", ">=")
+	f.since("created_to", "created_at", "<")
+	f.archived("archived_at")
+	if f.err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": f.err.Error()})
+		return
+	}
+
+	var analyses []models.AnalysisResult
+	if err := q.apply(f.tx).Find(&analyses).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения списка анализов")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения анализов"})
+		return
+	}
+
+	analyses, next := page(q, analyses, func(a models.AnalysisResult) (any, uuid.UUID) {
+		switch q.sort.column {
+		case "match_score":
+			return a.MatchScore, a.ID
+		case "score":
+			return a.Score, a.ID
+		}
+		return a.CreatedAt, a.ID
+	})
+	c.JSON(http.StatusOK, listResponse(analyses, next))
+}
+
+// GetAnalysis возвращает анализ с обоснованием оценки
+func GetAnalysis(c *gin.Context, db *gorm.DB) {
+	analysis, ok := loadAnalysis(c, db)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": newAnalysisView(analysis)})
+}
+
+// PatchAnalysis сохраняет решение рекрутера и заметку
+func PatchAnalysis(c *gin.Context, db *gorm.DB) {
+	analysis, ok := loadAnalysis(c, db)
+	if !ok {
+		return
+	}
+
+	var patch analysisPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
+		return
+	}
+	if patch.Decision != nil {
+		switch *patch.Decision {
+		case "", models.DecisionShortlisted, models.DecisionRejected:
+			analysis.Decision = *patch.Decision
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "decision: ожидается shortlisted, rejected или пустая строка"})
+			return
+		}
+	}
+	if patch.Note != nil {
+		analysis.Note = *patch.Note
+	}
+
+	if err := db.Model(&analysis).Select("Decision", "Note").Updates(&analysis).Error; err != nil {
+		log.WithError(err).Error("Ошибка сохранения анализа")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": newAnalysisView(analysis)})
+}
+
+// ArchiveAnalysis переносит анализ в архив
+func ArchiveAnalysis(c *gin.Context, db *gorm.DB) {
+	analysis, ok := loadAnalysis(c, db)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	analysis.ArchivedAt = &now
+	if err := db.Model(&analysis).Update("archived_at", now).Error; err != nil {
+		log.WithError(err).Error("Ошибка архивации анализа")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": newAnalysisView(analysis)})
+}
+
+// DeleteAnalysis удаляет анализ и его критерии
+func DeleteAnalysis(c *gin.Context, db *gorm.DB) {
+	analysis, ok := loadAnalysis(c, db)
+	if !ok {
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("analysis_result_id = ?", analysis.ID).Delete(&models.AnalysisDetail{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&analysis).Error
+	})
+	if err != nil {
+		log.WithError(err).Error("Ошибка удаления анализа")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления анализа"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AnalysisDetails возвращает критерии анализа; category отбирает одну категорию
+func AnalysisDetails(c *gin.Context, db *gorm.DB) {
+	analysis, ok := loadAnalysis(c, db)
+	if !ok {
+		return
+	}
+
+	tx := db.Where("analysis_result_id = ?", analysis.ID)
+	if category := c.Query("category"); category != "" {
+		tx = tx.Where("category = ?", category)
+	}
+	var details []models.AnalysisDetail
+	if err := tx.Order("weight DESC").Order("id").Find(&details).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения критериев анализа")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения критериев"})
+		return
+	}
+
+	views := make([]detailView, len(details))
+	for i, d := range details {
+		views[i] = detailView{AnalysisDetail: d, Evidence: json.RawMessage(d.Evidence)}
+		if !json.Valid(views[i].Evidence) {
+			views[i].Evidence = json.RawMessage("[]")
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": views})
+}
+
+func loadAnalysis(c *gin.Context, db *gorm.DB) (models.AnalysisResult, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор анализа"})
+		return models.AnalysisResult{}, false
+	}
+
+	var analysis models.AnalysisResult
+	if err := db.First(&analysis, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Анализ не найден"})
+		return models.AnalysisResult{}, false
+	}
+	return analysis, true
+}
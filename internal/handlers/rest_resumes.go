@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/geo"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/segment"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"github.com/moverq1337/VTBHack/internal/vectors"
+	"gorm.io/gorm"
+)
+
+// previewLength длина фрагмента текста резюме в списке, в символах
+const previewLength = 300
+
+// resumeSorts поля сортировки списка резюме
+var resumeSorts = map[string]sortField{
+	"created_at":    {"created_at", kindTime},
+	"updated_at":    {"updated_at", kindTime},
+	"experience":    {"experience", kindInt},
+	"salary_expect": {"salary_expect", kindInt},
+}
+
+// resumeItem резюме в списке: вместо полного текста - его начало
+type resumeItem struct {
+	models.Resume
+	Text string `json:"text"` // Закрывает полный текст из models.Resume
+}
+
+// resumeView резюме с разделами и данными разбора
+type resumeView struct {
+	models.Resume
+	Sections   []segment.Section `json:"sections"`
+	ParsedData json.RawMessage   `json:"parsed_data"`
+}
+
+func newResumeView(resume models.Resume) resumeView {
+	parsed := json.RawMessage(resume.ParsedData)
+	if !json.Valid(parsed) {
+		parsed = json.RawMessage("{}")
+	}
+	return resumeView{Resume: resume, Sections: resumeSections(resume), ParsedData: parsed}
+}
+
+// resumePatch частичное изменение резюме. Поля, извлечённые из текста,
+// можно поправить вручную; сам текст не меняется.
+type resumePatch struct {
+	Skills         *string `json:"skills"`
+	Education      *string `json:"education"`
+	Languages      *string `json:"languages"`
+	Experience     *int    `json:"experience"`
+	City           *string `json:"city"`
+	Relocation     *string `json:"relocation"`
+	RelocateTo     *string `json:"relocate_to"`
+	Remote         *bool   `json:"remote"`
+	SalaryExpect   *int    `json:"salary_expect"`
+	SalaryCurrency *string `json:"salary_currency"`
+	SalaryBasis    *string `json:"salary_basis"`
+}
+
+// ListResumes возвращает страницу резюме
+func ListResumes(c *gin.Context, db *gorm.DB) {
+	q, err := parseListQuery(c, resumeSorts, "-created_at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	f := newFilters(c, db.Model(&models.Resume{}))
+	f.id("candidate_id", "candidate_id")
+	f.equal("city", "city")
+	f.equal("relocation", "relocation")
+	f.boolean("remote", "remote")
+	f.listed("skill", "skills")
+	f.number("experience_min", "experience", ">=")
+	f.number("experience_max", "experience", "<=")
+	f.since("created_from", "created_at", ">=")
+	f.since("created_to", "created_at", "<")
+	f.archived("archived_at")
+	if f.err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": f.err.Error()})
+		return
+	}
+
+	var resumes []models.Resume
+	if err := q.apply(f.tx).Find(&resumes).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения списка резюме")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения резюме"})
+		return
+	}
+
+	resumes, next := page(q, resumes, func(r models.Resume) (any, uuid.UUID) {
+		switch q.sort.column {
+		case "updated_at":
+			return r.UpdatedAt, r.ID
+		case "experience":
+			return r.Experience, r.ID
+		case "salary_expect":
+			return r.SalaryExpect, r.ID
+		}
+		return r.CreatedAt, r.ID
+	})
+
+	items := make([]resumeItem, len(resumes))
+	for i, r := range resumes {
+		items[i] = resumeItem{Resume: r, Text: preview(r.Text, previewLength)}
+	}
+	c.JSON(http.StatusOK, listResponse(items, next))
+}
+
+// GetResume возвращает резюме с разделами и данными разбора
+func GetResume(c *gin.Context, db *gorm.DB) {
+	resume, ok := loadResume(c, db)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": newResumeView(resume)})
+}
+
+// PatchResume изменяет переданные поля резюме. Навыки приводятся к
+// таксономии, город - к справочнику, валюта проверяется по таблице курсов.
+func PatchResume(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	resume, ok := loadResume(c, db)
+	if !ok {
+		return
+	}
+
+	var patch resumePatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
+		return
+	}
+
+	if patch.Skills != nil {
+		resume.Skills = skills.Current().NormalizeList(*patch.Skills)
+	}
+	if patch.Education != nil {
+		resume.Education = *patch.Education
+	}
+	if patch.Languages != nil {
+		resume.Languages = *patch.Languages
+	}
+	if patch.Experience != nil {
+		if *patch.Experience < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Опыт не может быть отрицательным"})
+			return
+		}
+		resume.Experience = *patch.Experience
+	}
+	if patch.City != nil {
+		resume.City, resume.Region = strings.TrimSpace(*patch.City), ""
+		if city, ok := geo.LookupCity(resume.City); ok {
+			resume.City, resume.Region = city.Name, city.Region
+		}
+	}
+	if patch.Relocation != nil {
+		switch r := geo.Relocation(*patch.Relocation); r {
+		case geo.RelocationUnknown, geo.RelocationReady, geo.RelocationNotReady:
+			resume.Relocation = string(r)
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "relocation: ожидается ready, not_ready или пустая строка"})
+			return
+		}
+	}
+	if patch.RelocateTo != nil {
+		resume.RelocateTo = *patch.RelocateTo
+	}
+	if patch.Remote != nil {
+		resume.Remote = *patch.Remote
+	}
+	if patch.SalaryExpect != nil {
+		if *patch.SalaryExpect < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Зарплата не может быть отрицательной"})
+			return
+		}
+		resume.SalaryExpect = *patch.SalaryExpect
+	}
+	if patch.SalaryCurrency != nil || patch.SalaryBasis != nil {
+		currency, basis := resume.SalaryCurrency, resume.SalaryBasis
+		if patch.SalaryCurrency != nil {
+			currency = *patch.SalaryCurrency
+		}
+		if patch.SalaryBasis != nil {
+			basis = *patch.SalaryBasis
+		}
+		cur, b, err := vacancySalary(loadRates(db), currency, basis)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		resume.SalaryCurrency, resume.SalaryBasis = cur, string(b)
+	}
+
+	if err := db.Select("*").Omit("CreatedAt").Updates(&resume).Error; err != nil {
+		log.WithError(err).Error("Ошибка сохранения резюме")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": newResumeView(resume)})
+}
+
+// ArchiveResume переносит резюме в архив: оно скрывается из списков по умолчанию
+func ArchiveResume(c *gin.Context, db *gorm.DB) {
+	resume, ok := loadResume(c, db)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	resume.ArchivedAt = &now
+	if err := db.Model(&resume).Update("archived_at", now).Error; err != nil {
+		log.WithError(err).Error("Ошибка архивации резюме")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": newResumeView(resume)})
+}
+
+// DeleteResume удаляет резюме, его историю работы и анализы
+func DeleteResume(c *gin.Context, db *gorm.DB, embeddings *vectors.Store) {
+	resume, ok := loadResume(c, db)
+	if !ok {
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		analyses := tx.Model(&models.AnalysisResult{}).Select("id").Where("resume_id = ?", resume.ID)
+		if err := tx.Where("analysis_result_id IN (?)", analyses).Delete(&models.AnalysisDetail{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("resume_id = ?", resume.ID).Delete(&models.AnalysisResult{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("resume_id = ?", resume.ID).Delete(&models.WorkExperience{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&resume).Error
+	})
+	if err != nil {
+		log.WithError(err).Error("Ошибка удаления резюме")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления резюме"})
+		return
+	}
+	embeddings.Remove(vectors.KindResume, resume.ID)
+	c.Status(http.StatusNoContent)
+}
+
+func loadResume(c *gin.Context, db *gorm.DB) (models.Resume, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор резюме"})
+		return models.Resume{}, false
+	}
+
+	var resume models.Resume
+	if err := db.First(&resume, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Резюме не найдено"})
+		return models.Resume{}, false
+	}
+	return resume, true
+}
+
+// preview начало текста не длиннее n символов
+func preview(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return strings.TrimRightFunc(string(runes[:n]), func(r rune) bool { return r == ' ' || r == '\n' }) + "..."
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestFiltersListed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/resumes?skill=+go+", nil)
+
+	f := newFilters(c, db.Model(&models.Resume{}))
+	f.listed("skill", "skills")
+	stmt := f.tx.Find(&[]models.Resume{}).Statement
+
+	// Навык ищется среди элементов списка, а не подстрокой: skill=go не находит MongoDB
+	want := `SELECT * FROM "resumes" WHERE EXISTS (SELECT 1 FROM unnest(string_to_array(skills, ',')) AS s WHERE lower(trim(s)) = lower($1))`
+	if got := stmt.SQL.String(); got != want {
+		t.Errorf("SQL =\n%s\nwant\n%s", got, want)
+	}
+	if !reflect.DeepEqual(stmt.Vars, []any{"go"}) {
+		t.Errorf("Vars = %v", stmt.Vars)
+	}
+}
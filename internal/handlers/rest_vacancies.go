@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/lint"
+	"github.com/moverq1337/VTBHack/internal/mobility"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/requirements"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"github.com/moverq1337/VTBHack/internal/vectors"
+	"gorm.io/gorm"
+)
+
+// vacancySorts поля сортировки списка вакансий
+var vacancySorts = map[string]sortField{
+	"created_at": {"created_at", kindTime},
+	"updated_at": {"updated_at", kindTime},
+	"title":      {"title", kindString},
+	"salary_min": {"salary_min", kindInt},
+	"salary_max": {"salary_max", kindInt},
+}
+
+// vacancyView вакансия с разобранными требованиями и результатом проверки
+type vacancyView struct {
+	models.Vacancy
+	RequirementItems []requirements.Item `json:"requirement_items"`
+	Lint             lint.Result         `json:"lint"`
+}
+
+func newVacancyView(vacancy models.Vacancy, t *taxonomy.Taxonomy) vacancyView {
+	var result lint.Result
+	if err := json.Unmarshal([]byte(vacancy.Lint), &result); err != nil || result.Issues == nil {
+		result.Issues = []lint.Issue{}
+	}
+	return vacancyView{Vacancy: vacancy, RequirementItems: vacancyRequirements(vacancy, t), Lint: result}
+}
+
+// vacancyPatch частичное изменение вакансии: заданы только изменяемые поля
+type vacancyPatch struct {
+	Title            *string `json:"title"`
+	Requirements     *string `json:"requirements"`
+	Responsibilities *string `json:"responsibilities"`
+	Region           *string `json:"region"`
+	City             *string `json:"city"`
+	EmploymentType   *string `json:"employment_type"`
+	WorkSchedule     *string `json:"work_schedule"`
+	Experience       *string `json:"experience"`
+	Education        *string `json:"education"`
+	SalaryMin        *int    `json:"salary_min"`
+	SalaryMax        *int    `json:"salary_max"`
+	SalaryCurrency   *string `json:"salary_currency"`
+	SalaryBasis      *string `json:"salary_basis"`
+	Languages        *string `json:"languages"`
+	Skills           *string `json:"skills"`
+	Seniority        *string `json:"seniority"`
+	Draft            *bool   `json:"draft"`
+}
+
+// apply накладывает изменения на текущую вакансию. При смене названия без
+// явного уровня уровень определяется по новому названию.
+func (p vacancyPatch) apply(v models.Vacancy) vacancyRequest {
+	req := vacancyRequest{
+		Title:            v.Title,
+		Requirements:     v.Requirements,
+		Responsibilities: v.Responsibilities,
+		Region:           v.Region,
+		City:             v.City,
+		EmploymentType:   v.EmploymentType,
+		WorkSchedule:     v.WorkSchedule,
+		Experience:       v.Experience,
+		Education:        v.Education,
+		SalaryMin:        v.SalaryMin,
+		SalaryMax:        v.SalaryMax,
+		SalaryCurrency:   v.SalaryCurrency,
+		SalaryBasis:      v.SalaryBasis,
+		Languages:        v.Languages,
+		Skills:           v.Skills,
+		Seniority:        v.Seniority,
+	}
+	if p.Title != nil && p.Seniority == nil {
+		req.Seniority = ""
+	}
+
+	set := func(dst *string, src *string) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	set(&req.Title, p.Title)
+	set(&req.Requirements, p.Requirements)
+	set(&req.Responsibilities, p.Responsibilities)
+	set(&req.Region, p.Region)
+	set(&req.City, p.City)
+	set(&req.EmploymentType, p.EmploymentType)
+	set(&req.WorkSchedule, p.WorkSchedule)
+	set(&req.Experience, p.Experience)
+	set(&req.Education, p.Education)
+	set(&req.SalaryCurrency, p.SalaryCurrency)
+	set(&req.SalaryBasis, p.SalaryBasis)
+	set(&req.Languages, p.Languages)
+	set(&req.Skills, p.Skills)
+	set(&req.Seniority, p.Seniority)
+	if p.SalaryMin != nil {
+		req.SalaryMin = *p.SalaryMin
+	}
+	if p.SalaryMax != nil {
+		req.SalaryMax = *p.SalaryMax
+	}
+	if p.Draft != nil {
+		req.Draft = *p.Draft
+	}
+	return req
+}
+
+// ListVacancies возвращает страницу вакансий. Архивные вакансии
+// показываются только при archived=true или archived=all.
+func ListVacancies(c *gin.Context, db *gorm.DB) {
+	q, err := parseListQuery(c, vacancySorts, "-created_at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	f := newFilters(c, db.Model(&models.Vacancy{}))
+	if status := c.Query("status"); status != "all" {
+		f.equal("status", "status")
+	}
+	f.archived("archived_at")
+	f.equal("city", "city")
+	f.equal("region", "region")
+	f.equal("role_family", "role_family")
+	f.equal("seniority", "seniority")
+	f.contains("q", "title")
+	f.number("salary_from", "GREATEST(salary_min, salary_max)", ">=")
+	f.number("salary_to", "salary_min", "<=")
+	f.since("created_from", "created_at", ">=")
+	f.since("created_to", "created_at", "<")
+	if f.err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": f.err.Error()})
+		return
+	}
+
+	var vacancies []models.Vacancy
+	if err := q.apply(f.tx).Find(&vacancies).Error; err != nil {
+		log.WithError(err).Error("Ошибка получения списка вакансий")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения вакансий"})
+		return
+	}
+
+	vacancies, next := page(q, vacancies, func(v models.Vacancy) (any, uuid.UUID) {
+		switch q.sort.column {
+		case "updated_at":
+			return v.UpdatedAt, v.ID
+		case "title":
+			return v.Title, v.ID
+		case "salary_min":
+			return v.SalaryMin, v.ID
+		case "salary_max":
+			return v.SalaryMax, v.ID
+		}
+		return v.CreatedAt, v.ID
+	})
+	c.JSON(http.StatusOK, listResponse(vacancies, next))
+}
+
+// GetVacancy возвращает вакансию с пунктами требований и результатом проверки
+func GetVacancy(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	vacancy, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": newVacancyView(vacancy, skills.Current())})
+}
+
+// CreateVacancy создаёт вакансию так же, как загрузка, и возвращает её целиком
+func CreateVacancy(c *gin.Context, db *gorm.DB, skills *taxonomy.Store, lintOpts lint.Options, embeddings *vectors.Store, employees *mobility.Runner) {
+	var req vacancyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
+		return
+	}
+
+	t := skills.Current()
+	vacancy, err := buildVacancy(req, t, loadRates(db))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	vacancy.ID = uuid.New()
+	items := requirements.Parse(vacancy, t)
+	vacancy.RequirementItems = encodeRequirements(items)
+	applyLint(&vacancy, items, lintOpts, req.Draft)
+
+	if err := db.Create(&vacancy).Error; err != nil {
+		log.WithError(err).Error("Ошибка сохранения вакансии")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	embeddings.Refresh(vectors.KindVacancy, vacancy.ID, vectors.VacancyText(vacancy))
+	employees.Vacancy(vacancy.ID)
+	c.JSON(http.StatusCreated, gin.H{"data": newVacancyView(vacancy, t)})
+}
+
+// PatchVacancy изменяет только переданные поля вакансии и проверяет её заново
+func PatchVacancy(c *gin.Context, db *gorm.DB, skills *taxonomy.Store, lintOpts lint.Options, embeddings *vectors.Store, employees *mobility.Runner) {
+	current, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+
+	var patch vacancyPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
+		return
+	}
+
+	t := skills.Current()
+	req := patch.apply(current)
+	vacancy, err := buildVacancy(req, t, loadRates(db))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	reviseVacancy(&vacancy, current, t, lintOpts, req.Draft)
+	if err := saveVacancy(db, &vacancy); err != nil {
+		log.WithError(err).Error("Ошибка сохранения вакансии")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	if vectors.VacancyText(vacancy) != vectors.VacancyText(current) {
+		embeddings.Refresh(vectors.KindVacancy, vacancy.ID, vectors.VacancyText(vacancy))
+	}
+	employees.Vacancy(vacancy.ID)
+
+	if err := db.First(&vacancy, "id = ?", vacancy.ID).Error; err != nil {
+		log.WithError(err).Error("Ошибка чтения вакансии")
+	}
+	c.JSON(http.StatusOK, gin.H{"data": newVacancyView(vacancy, t)})
+}
+
+// ArchiveVacancy переносит вакансию в архив и снимает с публикации.
+// Вернуть её можно публикацией.
+func ArchiveVacancy(c *gin.Context, db *gorm.DB, skills *taxonomy.Store, employees *mobility.Runner) {
+	vacancy, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	vacancy.Status, vacancy.ArchivedAt = models.VacancyDraft, &now
+	if err := db.Model(&vacancy).Select("Status", "ArchivedAt").Updates(&vacancy).Error; err != nil {
+		log.WithError(err).Error("Ошибка архивации вакансии")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	employees.Vacancy(vacancy.ID)
+	c.JSON(http.StatusOK, gin.H{"data": newVacancyView(vacancy, skills.Current())})
+}
+
+// DeleteVacancy удаляет вакансию вместе с её анализами и оценками сотрудников
+func DeleteVacancy(c *gin.Context, db *gorm.DB, embeddings *vectors.Store) {
+	vacancy, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		analyses := tx.Model(&models.AnalysisResult{}).Select("id").Where("vacancy_id = ?", vacancy.ID)
+		if err := tx.Where("analysis_result_id IN (?)", analyses).Delete(&models.AnalysisDetail{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("vacancy_id = ?", vacancy.ID).Delete(&models.AnalysisResult{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("vacancy_id = ?", vacancy.ID).Delete(&models.EmployeeMatch{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&vacancy).Error
+	})
+	if err != nil {
+		log.WithError(err).Error("Ошибка удаления вакансии")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления вакансии"})
+		return
+	}
+	embeddings.Remove(vectors.KindVacancy, vacancy.ID)
+	c.Status(http.StatusNoContent)
+}
@@ -1,31 +1,41 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/unidoc/unioffice/common/license"
-	"google.golang.org/grpc/credentials/insecure"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/contacts"
+	"github.com/moverq1337/VTBHack/internal/lint"
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/moverq1337/VTBHack/internal/mobility"
 	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/nlp"
 	"github.com/moverq1337/VTBHack/internal/pb"
+	"github.com/moverq1337/VTBHack/internal/requirements"
+	"github.com/moverq1337/VTBHack/internal/salary"
+	"github.com/moverq1337/VTBHack/internal/segment"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
 	"github.com/moverq1337/VTBHack/internal/utils"
+	"github.com/moverq1337/VTBHack/internal/vectors"
 	"github.com/sirupsen/logrus"
 	"github.com/unidoc/unioffice/document"
-	"google.golang.org/grpc"
 	"gorm.io/gorm"
 )
 
 var log = logrus.New()
 
 // UploadResume обрабатывает загрузку резюме в формате DOCX
-func UploadResume(c *gin.Context, db *gorm.DB) {
+func UploadResume(c *gin.Context, db *gorm.DB, nlpClient *nlp.Client, skills *taxonomy.Store, embeddings *vectors.Store) {
 	log.Info("Начало загрузки резюме DOCX")
 
 	file, err := c.FormFile("resume")
@@ -76,52 +86,99 @@ func UploadResume(c *gin.Context, db *gorm.DB) {
 		Text:        text,
 		ParsedData:  "{}",
 		FileURL:     diskURL,
+		Skills:      strings.Join(skills.Current().Extract(text), ", "), // Канонические навыки таксономии
 	}
 
+	// Разделы резюме: опыт, образование, курсы и т.д.
+	sections := segment.Segment(text)
+	resume.Sections = encodeSections(sections)
+
 	// Вызов парсинга резюме
-	grpchost := "scoring-service:50051"
-	conn, err := grpc.NewClient(grpchost, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	parseResp, _, err := nlpClient.Parse(c.Request.Context(), parseRequest(text, sections))
 	if err != nil {
-		log.WithError(err).Error("Ошибка gRPC-соединения для парсинга")
+		log.WithError(err).Error("Ошибка парсинга резюме")
 	} else {
-		defer conn.Close()
+		// Сохраняем результаты парсинга
+		resume.ParsedData = parseResp.ParsedData
+	}
 
-		client := pb.NewNLPServiceClient(conn)
-		parseResp, err := client.ParseResume(context.Background(), &pb.ParseRequest{
-			Text: text,
-		})
+	// Контакты кандидата; повторное резюме того же человека привязывается к прежней записи
+	found := contacts.Extract(text, sections)
 
-		if err != nil {
-			log.WithError(err).Error("Ошибка парсинга резюме")
-		} else {
-			// Сохраняем результаты парсинга
-			resume.ParsedData = parseResp.ParsedData
-		}
+	// Город, готовность к переезду и удалённой работе
+	location := extractLocation(text, sections, found)
+	applyLocation(&resume, location)
+
+	// Ожидаемая зарплата с валютой и признаком "на руки"
+	expectation, hasSalary := salary.Extract(text, sections)
+	if hasSalary {
+		applySalary(&resume, expectation)
 	}
 
-	if err := db.Create(&resume).Error; err != nil {
+	// Иностранные языки с уровнем: по ним фильтруется поиск
+	resume.Languages = strings.Join(requirements.Languages(text), ", ")
+
+	// История работы: стаж без двойного учёта пересекающихся периодов,
+	// только по разделам с опытом работы
+	history := extractTimeline(text, sections, skills.Current())
+	resume.Experience = int(history.Years())
+
+	// Кандидат, резюме и история работы сохраняются вместе: при ошибке
+	// не остаётся кандидата без резюме
+	var duplicate bool
+	err = db.Transaction(func(tx *gorm.DB) error {
+		candidate, dup, err := upsertCandidate(tx, candidateID, found)
+		if err != nil {
+			return err
+		}
+		resume.CandidateID, duplicate = candidate.ID, dup
+		if err := tx.Create(&resume).Error; err != nil {
+			return err
+		}
+		return saveWorkHistory(tx, resume.ID, history)
+	})
+	if err != nil {
+		log.WithError(err).Error("Ошибка сохранения резюме")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
 		return
 	}
+	embeddings.Refresh(vectors.KindResume, resume.ID, vectors.ResumeText(resume))
 
 	c.JSON(http.StatusOK, gin.H{
-		"candidate_id": candidateID.String(),
+		"candidate_id": resume.CandidateID.String(),
+		"duplicate":    duplicate,
 		"file_url":     diskURL,
 		"resume_id":    resume.ID.String(),
 		"text_preview": truncateText(text, 200), // Первые 200 символов для предпросмотра
+		"experience":   history.Years(),
+		"sections":     sectionKinds(sections),
+		"location":     location,
+		"salary":       salaryResponse(expectation, hasSalary),
 	})
 }
 
+var (
+	unidocOnce sync.Once
+	unidocErr  error
+)
+
+// unidocLicense один раз устанавливает ключ UniDoc: без него unioffice не
+// читает и не сохраняет документы
+func unidocLicense() error {
+	unidocOnce.Do(func() {
+		apiKey := os.Getenv("UNIDOC_LICENSE_API_KEY")
+		if apiKey == "" {
+			unidocErr = errors.New("UNIDOC_LICENSE_API_KEY environment variable not set")
+			return
+		}
+		unidocErr = license.SetMeteredKey(apiKey)
+	})
+	return unidocErr
+}
+
 // extractTextFromDOCX извлекает текст из DOCX файла
 func extractTextFromDOCX(filePath string) (string, error) {
-	apiKey := os.Getenv("UNIDOC_LICENSE_API_KEY")
-	if apiKey == "" {
-		log.Fatal("UNIDOC_LICENSE_API_KEY environment variable not set")
-	}
-
-	// Установка API ключа для UniDoc
-	err := license.SetMeteredKey(apiKey)
-	if err != nil {
+	if err := unidocLicense(); err != nil {
 		log.Fatalf("Ошибка инициализации UniDoc license: %v", err)
 	}
 	doc, err := document.Open(filePath)
@@ -163,58 +220,58 @@ func truncateText(text string, maxLength int) string {
 }
 
 // UploadVacancy обрабатывает загрузку вакансии
-func UploadVacancy(c *gin.Context, db *gorm.DB) {
-	type VacancyRequest struct {
-		Title            string `json:"title"`
-		Requirements     string `json:"requirements"`
-		Responsibilities string `json:"responsibilities"`
-		Region           string `json:"region"`
-		City             string `json:"city"`
-		EmploymentType   string `json:"employment_type"`
-		WorkSchedule     string `json:"work_schedule"`
-		Experience       string `json:"experience"`
-		Education        string `json:"education"`
-		SalaryMin        int    `json:"salary_min"`
-		SalaryMax        int    `json:"salary_max"`
-		Languages        string `json:"languages"`
-		Skills           string `json:"skills"`
-	}
-
-	var req VacancyRequest
+func UploadVacancy(c *gin.Context, db *gorm.DB, skills *taxonomy.Store, lintOpts lint.Options, embeddings *vectors.Store, employees *mobility.Runner) {
+	var req vacancyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
 		return
 	}
 
-	vacancy := models.Vacancy{
-		ID:               uuid.New(),
-		Title:            req.Title,
-		Requirements:     req.Requirements,
-		Responsibilities: req.Responsibilities,
-		Region:           req.Region,
-		City:             req.City,
-		EmploymentType:   req.EmploymentType,
-		WorkSchedule:     req.WorkSchedule,
-		Experience:       req.Experience,
-		Education:        req.Education,
-		SalaryMin:        req.SalaryMin,
-		SalaryMax:        req.SalaryMax,
-		Languages:        req.Languages,
-		Skills:           req.Skills,
+	vacancy, err := buildVacancy(req, skills.Current(), loadRates(db))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
+	vacancy.ID = uuid.New()
+	items := requirements.Parse(vacancy, skills.Current())
+	vacancy.RequirementItems = encodeRequirements(items)
+
+	// Проверка вакансии; в строгом режиме вакансия с ошибками остаётся черновиком
+	result := applyLint(&vacancy, items, lintOpts, req.Draft)
 	if err := db.Create(&vacancy).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
 		return
 	}
+	embeddings.Refresh(vectors.KindVacancy, vacancy.ID, vectors.VacancyText(vacancy))
+	employees.Vacancy(vacancy.ID)
 
 	c.JSON(http.StatusOK, gin.H{
-		"vacancy_id": vacancy.ID.String(),
-		"title":      vacancy.Title,
+		"vacancy_id":   vacancy.ID.String(),
+		"title":        vacancy.Title,
+		"skills":       vacancy.Skills,
+		"role_family":  vacancy.RoleFamily,
+		"seniority":    vacancy.Seniority,
+		"city":         vacancy.City,
+		"region":       vacancy.Region,
+		"requirements": items,
+		"salary": gin.H{
+			"min":      vacancy.SalaryMin,
+			"max":      vacancy.SalaryMax,
+			"currency": vacancy.SalaryCurrency,
+			"basis":    vacancy.SalaryBasis,
+		},
+		"status": vacancy.Status,
+		"lint":   result,
 	})
 }
 
+// vacancyText собирает текст вакансии для сопоставления
+func vacancyText(vacancy models.Vacancy) string {
+	return fmt.Sprintf("%s %s %s %s", vacancy.Title, vacancy.Requirements, vacancy.Responsibilities, vacancy.Skills)
+}
+
 // AnalyzeResume обрабатывает анализ резюме
-func AnalyzeResume(c *gin.Context, db *gorm.DB) {
+func AnalyzeResume(c *gin.Context, db *gorm.DB, nlpClient *nlp.Client, skills *taxonomy.Store, opts matching.Options) {
 	type AnalyzeRequest struct {
 		ResumeID  uuid.UUID `json:"resume_id"`
 		VacancyID uuid.UUID `json:"vacancy_id"`
@@ -238,51 +295,78 @@ func AnalyzeResume(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
-	grpchost := "scoring-service:50051"
-
-	conn, err := grpc.NewClient(grpchost, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		log.WithError(err).Error("Ошибка gRPC-соединения")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка подключения к сервису анализа"})
-		return
-	}
-	defer conn.Close()
-
-	client := pb.NewNLPServiceClient(conn)
-
 	// Сопоставление с вакансией
-	matchResp, err := client.MatchResumeVacancy(context.Background(), &pb.MatchRequest{
+	matchResp, degraded, err := nlpClient.Match(c.Request.Context(), &pb.MatchRequest{
 		ResumeText:  resume.Text,
-		VacancyText: fmt.Sprintf("%s %s %s %s", vacancy.Title, vacancy.Requirements, vacancy.Responsibilities, vacancy.Skills),
+		VacancyText: vacancyText(vacancy),
 	})
 	if err != nil {
 		log.WithError(err).Error("Ошибка сопоставления")
+		if nlp.IsUnavailable(err) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Сервис анализа недоступен"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сопоставления с вакансией"})
 		return
 	}
 
 	// Парсим резюме для получения деталей
-	parseResp, err := client.ParseResume(context.Background(), &pb.ParseRequest{
-		Text: resume.Text,
-	})
+	parsedJSON := "{}"
+	parseResp, parseDegraded, err := nlpClient.Parse(c.Request.Context(), parseRequest(resume.Text, resumeSections(resume)))
 	if err != nil {
 		log.WithError(err).Error("Ошибка парсинга резюме")
+	} else {
+		parsedJSON = parseResp.ParsedData
+		degraded = degraded || parseDegraded
 	}
 
 	var parsedData map[string]interface{}
-	if err := json.Unmarshal([]byte(parseResp.ParsedData), &parsedData); err != nil {
+	if err := json.Unmarshal([]byte(parsedJSON), &parsedData); err != nil {
 		log.WithError(err).Error("Ошибка разбора JSON данных парсинга")
 		parsedData = make(map[string]interface{})
+		parsedJSON = "{}"
+	}
+
+	// Стаж берём из истории работы; если периоды не найдены - из данных парсинга
+	resumeYears := experienceYears(parsedData)
+	history, err := resumeTimeline(db, resume, skills.Current())
+	if err != nil {
+		log.WithError(err).Error("Ошибка загрузки истории работы")
+	} else if history.TotalMonths > 0 {
+		resumeYears = history.Years()
+	}
+
+	// Разбираем оценку по требованиям вакансии
+	expectation, _ := resumeSalary(resume, resumeSections(resume))
+	matcher := matching.NewEngine(skills.Current(), opts)
+	explanation := matcher.Evaluate(matching.Input{
+		ResumeText:    resume.Text,
+		ResumeYears:   resumeYears,
+		Vacancy:       vacancy,
+		Requirements:  vacancyRequirements(vacancy, skills.Current()),
+		SemanticScore: float64(matchResp.Score),
+		History:       history,
+		Location:      resumeLocation(resume),
+		Salary:        expectation,
+		Rates:         loadRates(db),
+	})
+	explanationJSON, err := json.Marshal(explanation)
+	if err != nil {
+		log.WithError(err).Error("Ошибка сериализации обоснования оценки")
+		explanationJSON = []byte("{}")
 	}
 
 	// Сохраняем результаты анализа
 	analysisResult := models.AnalysisResult{
-		ID:         uuid.New(),
-		ResumeID:   resume.ID,
-		VacancyID:  vacancy.ID,
-		MatchScore: float64(matchResp.Score),
-		Details:    parseResp.ParsedData, // Сохраняем полные данные парсинга
-		CreatedAt:  time.Now(),
+		ID:          uuid.New(),
+		ResumeID:    resume.ID,
+		VacancyID:   vacancy.ID,
+		MatchScore:  float64(matchResp.Score),
+		Score:       explanation.Score,
+		Details:     parsedJSON, // Сохраняем полные данные парсинга
+		Degraded:    degraded,
+		Explanation: string(explanationJSON),
+		CreatedAt:   time.Now(),
 	}
 
 	if err := db.Create(&analysisResult).Error; err != nil {
@@ -291,27 +375,26 @@ func AnalyzeResume(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
-	// Сохраняем детали анализа
-	if skills, ok := parsedData["skills"].(map[string]interface{}); ok {
-		for category, skillList := range skills {
-			if skillsArr, ok := skillList.([]interface{}); ok {
-				for _, skill := range skillsArr {
-					analysisDetail := models.AnalysisDetail{
-						ID:               uuid.New(),
-						AnalysisResultID: analysisResult.ID,
-						Category:         "skills",
-						Criteria:         category,
-						ResumeValue:      fmt.Sprintf("%v", skill),
-						VacancyValue:     "",  // Можно добавить проверку наличия в вакансии
-						MatchScore:       0.8, // Заглушка
-						Weight:           0.3, // Заглушка
-						CreatedAt:        time.Now(),
-					}
-					if err := db.Create(&analysisDetail).Error; err != nil {
-						log.WithError(err).Error("Ошибка сохранения деталей анализа")
-					}
-				}
-			}
+	// Сохраняем детали анализа: по строке на критерий
+	for _, criterion := range explanation.Criteria {
+		evidence, _ := json.Marshal(criterion.Evidence)
+		if criterion.Evidence == nil {
+			evidence = []byte("[]")
+		}
+		analysisDetail := models.AnalysisDetail{
+			ID:               uuid.New(),
+			AnalysisResultID: analysisResult.ID,
+			Category:         criterion.Category,
+			Criteria:         criterion.Requirement,
+			ResumeValue:      criterion.ResumeValue,
+			VacancyValue:     criterion.Requirement,
+			MatchScore:       criterion.Score,
+			Weight:           criterion.Weight,
+			Evidence:         string(evidence),
+			CreatedAt:        time.Now(),
+		}
+		if err := db.Create(&analysisDetail).Error; err != nil {
+			log.WithError(err).Error("Ошибка сохранения деталей анализа")
 		}
 	}
 
@@ -321,8 +404,11 @@ func AnalyzeResume(c *gin.Context, db *gorm.DB) {
 		"resume_id":    resume.ID.String(),
 		"vacancy_id":   vacancy.ID.String(),
 		"match_score":  fmt.Sprintf("%.2f%%", matchResp.Score*100),
+		"score":        fmt.Sprintf("%.2f%%", explanation.Score*100),
 		"candidate_id": resume.CandidateID.String(),
 		"created_at":   analysisResult.CreatedAt,
+		"degraded":     analysisResult.Degraded,
 		"details":      parsedData, // Добавляем детали в ответ
+		"explanation":  explanation,
 	})
 }
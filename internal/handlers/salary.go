@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/salary"
+	"github.com/moverq1337/VTBHack/internal/segment"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// loadRates курсы валют: таблица currency_rates поверх курсов по умолчанию
+func loadRates(db *gorm.DB) salary.Rates {
+	rates := make(salary.Rates, len(salary.DefaultRates))
+	for currency, rate := range salary.DefaultRates {
+		rates[currency] = rate
+	}
+
+	var stored []models.CurrencyRate
+	if err := db.Find(&stored).Error; err != nil {
+		log.WithError(err).Error("Ошибка загрузки курсов валют")
+		return rates
+	}
+	for _, r := range stored {
+		rates[r.Currency] = r.Rate
+	}
+	return rates
+}
+
+// ListRates возвращает действующие курсы валют
+func ListRates(c *gin.Context, db *gorm.DB) {
+	c.JSON(http.StatusOK, gin.H{"base": salary.RUB, "rates": loadRates(db)})
+}
+
+// SetRate задаёт курс валюты к рублю
+func SetRate(c *gin.Context, db *gorm.DB) {
+	var req struct {
+		Rate float64 `json:"rate"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Rate <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Курс должен быть положительным числом"})
+		return
+	}
+
+	currency := strings.ToUpper(c.Param("currency"))
+	if len(currency) != 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный код валюты: " + c.Param("currency")})
+		return
+	}
+	if currency == salary.RUB {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Курс рубля всегда равен 1"})
+		return
+	}
+
+	rate := models.CurrencyRate{Currency: currency, Rate: req.Rate, UpdatedAt: time.Now()}
+	err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&rate).Error
+	if err != nil {
+		log.WithError(err).Error("Ошибка сохранения курса валюты")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения курса"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rate": rate})
+}
+
+// applySalary сохраняет ожидаемую зарплату в резюме
+func applySalary(resume *models.Resume, a salary.Amount) {
+	resume.SalaryExpect = a.Value
+	resume.SalaryCurrency = a.Currency
+	resume.SalaryBasis = string(a.Basis)
+}
+
+// resumeSalary ожидаемая зарплата из резюме. Для резюме, загруженных
+// раньше, она извлекается из текста заново.
+func resumeSalary(resume models.Resume, sections []segment.Section) (salary.Amount, bool) {
+	if resume.SalaryExpect > 0 {
+		return salary.Amount{
+			Value:    resume.SalaryExpect,
+			Currency: resume.SalaryCurrency,
+			Basis:    salary.Basis(resume.SalaryBasis),
+		}, true
+	}
+	return salary.Extract(resume.Text, sections)
+}
+
+// vacancySalary проверяет валюту и налоговую базу вилки вакансии.
+// Валюта должна быть в таблице курсов, иначе вилку не с чем сравнить.
+func vacancySalary(rates salary.Rates, currency, basis string) (string, salary.Basis, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		currency = salary.RUB
+	}
+	if _, ok := rates.Rate(currency); !ok {
+		return "", "", errors.New("Неизвестная валюта: " + currency)
+	}
+	b, ok := salary.ParseBasis(strings.TrimSpace(basis))
+	if !ok {
+		return "", "", errors.New("Неизвестная налоговая база: " + basis + " (gross или net)")
+	}
+	return currency, b, nil
+}
+
+// salaryResponse ожидаемая зарплата для ответа загрузки; nil, если не указана
+func salaryResponse(a salary.Amount, ok bool) *salary.Amount {
+	if !ok {
+		return nil
+	}
+	return &a
+}
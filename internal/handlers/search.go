@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/geo"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/requirements"
+	"github.com/moverq1337/VTBHack/internal/salary"
+	"github.com/moverq1337/VTBHack/internal/search"
+	"gorm.io/gorm"
+)
+
+// searchSorts поля сортировки результатов поиска; по умолчанию - релевантность
+var searchSorts = map[string]sortField{
+	"rank":          {"rank", kindFloat},
+	"created_at":    {"created_at", kindTime},
+	"experience":    {"experience", kindInt},
+	"salary_expect": {"salary_expect", kindInt},
+}
+
+// rankedResume резюме с релевантностью запросу
+type rankedResume struct {
+	models.Resume
+	Rank float64
+}
+
+// searchHit результат поиска: резюме, релевантность и фрагменты с совпадениями
+type searchHit struct {
+	resumeItem
+	Rank     float64          `json:"rank"`
+	Snippets []search.Snippet `json:"snippets"`
+}
+
+// SearchResumes ищет резюме по тексту и навыкам. Запрос q - слова через
+// пробел ("kafka go банк"), "точная фраза", or, -исключение. Результаты
+// отбираются по опыту, языкам, городу и зарплатным ожиданиям.
+func SearchResumes(c *gin.Context, db *gorm.DB) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не указан поисковый запрос q"})
+		return
+	}
+	q, err := parseListQuery(c, searchSorts, "-rank")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	matched, err := search.Match(db.Model(&models.Resume{}), query)
+	if err != nil {
+		log.WithError(err).Error("Ошибка построения поискового запроса")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка поиска"})
+		return
+	}
+	tx, ok := filterResumes(c, db, matched)
+	if !ok {
+		return
+	}
+
+	hits, next, err := runSearch(db, q, tx, query)
+	if err != nil {
+		log.WithError(err).Error("Ошибка поиска резюме")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка поиска"})
+		return
+	}
+	c.JSON(http.StatusOK, listResponse(hits, next))
+}
+
+// filterResumes отбирает резюме по опыту, языкам, городу, готовности к
+// переезду и удалённой работе и зарплатным ожиданиям из параметров запроса.
+// При неверном параметре отвечает 400 и возвращает false.
+func filterResumes(c *gin.Context, db *gorm.DB, tx *gorm.DB) (*gorm.DB, bool) {
+	f := newFilters(c, tx)
+	f.number("experience_min", "experience", ">=")
+	f.number("experience_max", "experience", "<=")
+	f.equal("relocation", "relocation")
+	f.boolean("remote", "remote")
+	f.archived("archived_at")
+	if f.err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": f.err.Error()})
+		return nil, false
+	}
+	tx = f.tx
+
+	if city := strings.TrimSpace(c.Query("city")); city != "" {
+		if found, ok := geo.LookupCity(city); ok {
+			city = found.Name
+		}
+		tx = tx.Where("city = ?", city)
+	}
+
+	// Все перечисленные языки: language=английский,немецкий
+	for _, lang := range strings.Split(c.Query("language"), ",") {
+		if lang = strings.TrimSpace(lang); lang == "" {
+			continue
+		}
+		name, ok := requirements.LanguageName(lang)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неизвестный язык: " + lang})
+			return nil, false
+		}
+		tx = tx.Where("languages ILIKE ?", "%"+name+"%")
+	}
+
+	// Вилка в валюте salary_currency до вычета налогов
+	rates := loadRates(db)
+	currency, _, err := vacancySalary(rates, c.Query("salary_currency"), "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	for _, bound := range []struct {
+		param string
+		apply func(*gorm.DB, salary.Rates, float64) *gorm.DB
+	}{
+		{"salary_min", search.SalaryFrom},
+		{"salary_max", search.SalaryUpTo},
+	} {
+		raw := c.Query(bound.param)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": bound.param + " должен быть неотрицательным числом"})
+			return nil, false
+		}
+		rub, _ := rates.GrossRUB(salary.Amount{Value: value, Currency: currency})
+		tx = bound.apply(tx, rates, rub)
+	}
+	return tx, true
+}
+
+// runSearch выполняет отобранный поиск постранично и добавляет к
+// результатам фрагменты с совпадениями terms
+func runSearch(db *gorm.DB, q listQuery, tx *gorm.DB, terms string) ([]searchHit, string, error) {
+	var rows []rankedResume
+	if err := q.apply(db.Table("(?) AS r", tx)).Find(&rows).Error; err != nil {
+		return nil, "", err
+	}
+	rows, next := page(q, rows, func(r rankedResume) (any, uuid.UUID) {
+		switch q.sort.column {
+		case "created_at":
+			return r.CreatedAt, r.ID
+		case "experience":
+			return r.Experience, r.ID
+		case "salary_expect":
+			return r.SalaryExpect, r.ID
+		}
+		return r.Rank, r.ID
+	})
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, r := range rows {
+		ids[i] = r.ID
+	}
+	snippets := map[uuid.UUID][]search.Snippet{}
+	if terms != "" {
+		found, err := search.Snippets(db, ids, terms)
+		if err != nil {
+			// Результаты без фрагментов лучше, чем ошибка поиска
+			log.WithError(err).Error("Ошибка построения фрагментов резюме")
+		} else {
+			snippets = found
+		}
+	}
+
+	hits := make([]searchHit, len(rows))
+	for i, r := range rows {
+		hits[i] = searchHit{
+			resumeItem: resumeItem{Resume: r.Resume, Text: preview(r.Text, previewLength)},
+			Rank:       r.Rank,
+			Snippets:   snippets[r.ID],
+		}
+		if hits[i].Snippets == nil {
+			hits[i].Snippets = []search.Snippet{}
+		}
+	}
+	return hits, next, nil
+}
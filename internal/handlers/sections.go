@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/pb"
+	"github.com/moverq1337/VTBHack/internal/segment"
+	"gorm.io/gorm"
+)
+
+// ResumeSections возвращает разделы резюме с границами в тексте
+func ResumeSections(c *gin.Context, db *gorm.DB) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор резюме"})
+		return
+	}
+
+	var resume models.Resume
+	if err := db.First(&resume, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Резюме не найдено"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"resume_id": resume.ID.String(),
+		"sections":  resumeSections(resume),
+	})
+}
+
+// resumeSections возвращает сохранённые разделы резюме. Для резюме,
+// загруженных до появления разделов, текст размечается заново.
+func resumeSections(resume models.Resume) []segment.Section {
+	var sections []segment.Section
+	if resume.Sections != "" {
+		if err := json.Unmarshal([]byte(resume.Sections), &sections); err != nil {
+			log.WithError(err).Error("Ошибка разбора разделов резюме")
+		}
+	}
+	if len(sections) == 0 {
+		sections = segment.Segment(resume.Text)
+	}
+	if sections == nil {
+		sections = []segment.Section{}
+	}
+	return sections
+}
+
+// encodeSections сериализует разделы для хранения в резюме
+func encodeSections(sections []segment.Section) string {
+	if len(sections) == 0 {
+		return "[]"
+	}
+	data, err := json.Marshal(sections)
+	if err != nil {
+		log.WithError(err).Error("Ошибка сериализации разделов резюме")
+		return "[]"
+	}
+	return string(data)
+}
+
+// parseRequest собирает запрос разбора резюме с разделами
+func parseRequest(text string, sections []segment.Section) *pb.ParseRequest {
+	req := &pb.ParseRequest{Text: text}
+	for _, s := range sections {
+		req.Sections = append(req.Sections, &pb.Section{
+			Kind:    string(s.Kind),
+			Heading: s.Heading,
+			Text:    s.Text,
+			Start:   int32(s.Start),
+			End:     int32(s.End),
+		})
+	}
+	return req
+}
+
+// sectionKinds перечисляет найденные типы разделов без повторов
+func sectionKinds(sections []segment.Section) []segment.Kind {
+	kinds := []segment.Kind{}
+	seen := make(map[segment.Kind]bool)
+	for _, s := range sections {
+		if !seen[s.Kind] {
+			seen[s.Kind] = true
+			kinds = append(kinds, s.Kind)
+		}
+	}
+	return kinds
+}
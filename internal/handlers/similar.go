@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/vectors"
+	"gorm.io/gorm"
+)
+
+// similarOversample во столько раз больше соседей берётся из индекса, чем
+// нужно: часть отсеют фильтры. Если не хватило, выборка растёт так же.
+const similarOversample = 4
+
+// similarHit похожее резюме и косинусная близость к образцу
+type similarHit struct {
+	resumeItem
+	Similarity float64 `json:"similarity"`
+}
+
+// SimilarResumes ищет резюме, близкие по смыслу к данному: "ещё такие же
+// кандидаты". Другие резюме того же кандидата не показываются. Фильтры
+// те же, что у поиска, и min_similarity - нижняя граница близости.
+func SimilarResumes(c *gin.Context, db *gorm.DB, embeddings *vectors.Store) {
+	resume, ok := loadResume(c, db)
+	if !ok {
+		return
+	}
+	vec, ok := documentVector(c, embeddings, vectors.KindResume, resume.ID, vectors.ResumeText(resume))
+	if !ok {
+		return
+	}
+
+	base := db.Model(&models.Resume{}).Where("id <> ?", resume.ID)
+	if resume.CandidateID != uuid.Nil {
+		base = base.Where("candidate_id IS NULL OR candidate_id <> ?", resume.CandidateID)
+	}
+	respondSimilar(c, db, embeddings, vec, base)
+}
+
+// SimilarCandidates ищет резюме, близкие по смыслу к тексту вакансии.
+// В отличие от анализа, не требует оценки каждого резюме NLP-сервисом.
+func SimilarCandidates(c *gin.Context, db *gorm.DB, embeddings *vectors.Store) {
+	vacancy, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+	vec, ok := documentVector(c, embeddings, vectors.KindVacancy, vacancy.ID, vectors.VacancyText(vacancy))
+	if !ok {
+		return
+	}
+	respondSimilar(c, db, embeddings, vec, db.Model(&models.Resume{}))
+}
+
+// documentVector берёт вектор документа из индекса. Если его ещё нет,
+// вектор считается сразу; без NLP-сервиса ответ 503.
+func documentVector(c *gin.Context, embeddings *vectors.Store, kind string, id uuid.UUID, text string) ([]float32, bool) {
+	if vec, ok := embeddings.Vector(kind, id); ok {
+		return vec, true
+	}
+	if err := embeddings.Put(c.Request.Context(), kind, id, text); err != nil {
+		log.WithError(err).Warnf("Не удалось вычислить вектор %s %s", kind, id)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Вектор документа ещё не вычислен: NLP-сервис недоступен"})
+		return nil, false
+	}
+	if vec, ok := embeddings.Vector(kind, id); ok {
+		return vec, true
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": "У документа нет текста для поиска похожих"})
+	return nil, false
+}
+
+// respondSimilar отдаёт до limit резюме из base, ближайших к вектору,
+// от самых близких. Соседи берутся из индекса с запасом и проверяются
+// фильтрами в БД; если после фильтров их не хватает, выборка растёт.
+func respondSimilar(c *gin.Context, db *gorm.DB, embeddings *vectors.Store, vec []float32, base *gorm.DB) {
+	limit := defaultPageSize
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit должен быть положительным числом"})
+			return
+		}
+		limit = min(n, maxPageSize)
+	}
+	minSimilarity := -1.0
+	if raw := c.Query("min_similarity"); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v < -1 || v > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "min_similarity должен быть числом от -1 до 1"})
+			return
+		}
+		minSimilarity = v
+	}
+
+	allowed, ok := filterResumes(c, db, base.Select("id"))
+	if !ok {
+		return
+	}
+
+	total := embeddings.Len(vectors.KindResume)
+	hits := []similarHit{}
+	for n := limit * similarOversample; ; n *= similarOversample {
+		n = min(n, total)
+		results := embeddings.Search(vectors.KindResume, vec, n, n)
+
+		ids := make([]uuid.UUID, 0, len(results))
+		exhausted := len(results) < n || n == total
+		for _, r := range results {
+			if r.Similarity < minSimilarity {
+				exhausted = true
+				break
+			}
+			ids = append(ids, r.ID)
+		}
+
+		var rows []models.Resume
+		if len(ids) > 0 {
+			if err := db.Where("id IN ? AND id IN (?)", ids, allowed).Find(&rows).Error; err != nil {
+				log.WithError(err).Error("Ошибка поиска похожих резюме")
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка поиска"})
+				return
+			}
+		}
+		byID := make(map[uuid.UUID]models.Resume, len(rows))
+		for _, r := range rows {
+			byID[r.ID] = r
+		}
+
+		hits = hits[:0]
+		for _, r := range results[:len(ids)] {
+			resume, ok := byID[r.ID]
+			if !ok {
+				continue
+			}
+			hits = append(hits, similarHit{
+				resumeItem: resumeItem{Resume: resume, Text: preview(resume.Text, previewLength)},
+				Similarity: r.Similarity,
+			})
+			if len(hits) == limit {
+				break
+			}
+		}
+		if len(hits) == limit || exhausted {
+			break
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": hits})
+}
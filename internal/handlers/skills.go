@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+)
+
+// skillRequest тело создания и изменения навыка
+type skillRequest struct {
+	Slug     *string  `json:"slug"`
+	Name     *string  `json:"name"`
+	Category *string  `json:"category"`
+	ParentID *string  `json:"parent_id"` // Пустая строка снимает родителя
+	Aliases  []string `json:"aliases"`
+}
+
+// ListSkills возвращает навыки таксономии с фильтром по категории и написанию
+func ListSkills(c *gin.Context, skills *taxonomy.Store) {
+	category := c.Query("category")
+	query := taxonomy.NormalizeAlias(c.Query("q"))
+
+	out := []models.Skill{}
+	for _, s := range skills.Current().Skills() {
+		if category != "" && s.Category != category {
+			continue
+		}
+		if query != "" && !skillMatchesQuery(s, query) {
+			continue
+		}
+		out = append(out, s)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"skills": out, "total": len(out)})
+}
+
+// GetSkill возвращает навык с синонимами и дочерними навыками
+func GetSkill(c *gin.Context, skills *taxonomy.Store) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор навыка"})
+		return
+	}
+
+	t := skills.Current()
+	skill, ok := t.Get(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Навык не найден"})
+		return
+	}
+
+	children := []models.Skill{}
+	for _, childID := range t.Descendants(id) {
+		if child, ok := t.Get(childID); ok && child.ParentID != nil && *child.ParentID == id {
+			children = append(children, child)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"skill": skill, "children": children})
+}
+
+// CreateSkill добавляет навык в таксономию
+func CreateSkill(c *gin.Context, skills *taxonomy.Store) {
+	in, ok := bindSkillInput(c)
+	if !ok {
+		return
+	}
+
+	skill, err := skills.Create(in)
+	if err != nil {
+		skillError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"skill": skill})
+}
+
+// UpdateSkill изменяет навык; переданные синонимы заменяют прежние
+func UpdateSkill(c *gin.Context, skills *taxonomy.Store) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор навыка"})
+		return
+	}
+	in, ok := bindSkillInput(c)
+	if !ok {
+		return
+	}
+
+	skill, err := skills.Update(id, in)
+	if err != nil {
+		skillError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"skill": skill})
+}
+
+// DeleteSkill удаляет навык из таксономии
+func DeleteSkill(c *gin.Context, skills *taxonomy.Store) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор навыка"})
+		return
+	}
+
+	if err := skills.Delete(id); err != nil {
+		skillError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deleted": id.String()})
+}
+
+// NormalizeSkills приводит перечень навыков или текст к каноническим навыкам таксономии
+func NormalizeSkills(c *gin.Context, skills *taxonomy.Store) {
+	type NormalizeRequest struct {
+		Skills []string `json:"skills"`
+		Text   string   `json:"text"`
+	}
+
+	var req NormalizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	t := skills.Current()
+	names := append(req.Skills, t.Extract(req.Text)...)
+	known, unknown := t.Normalize(names)
+	if known == nil {
+		known = []models.Skill{}
+	}
+	if unknown == nil {
+		unknown = []string{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"skills": known, "unknown": unknown})
+}
+
+func bindSkillInput(c *gin.Context) (taxonomy.SkillInput, bool) {
+	var req skillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return taxonomy.SkillInput{}, false
+	}
+
+	in := taxonomy.SkillInput{Slug: req.Slug, Name: req.Name, Category: req.Category, Aliases: req.Aliases}
+	if req.ParentID != nil {
+		if *req.ParentID == "" {
+			in.ClearParent = true
+		} else {
+			parentID, err := uuid.Parse(*req.ParentID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор родительского навыка"})
+				return taxonomy.SkillInput{}, false
+			}
+			in.ParentID = &parentID
+		}
+	}
+	return in, true
+}
+
+func skillError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, taxonomy.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, taxonomy.ErrInvalid), errors.Is(err, taxonomy.ErrInvalidWeight), errors.Is(err, taxonomy.ErrSelfRelation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, taxonomy.ErrSlugTaken), errors.Is(err, taxonomy.ErrAliasTaken), errors.Is(err, taxonomy.ErrParentCycle):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.WithError(err).Error("Ошибка изменения таксономии навыков")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения навыка"})
+	}
+}
+
+func skillMatchesQuery(s models.Skill, query string) bool {
+	if strings.Contains(taxonomy.NormalizeAlias(s.Name), query) || strings.Contains(s.Slug, query) {
+		return true
+	}
+	for _, a := range s.Aliases {
+		if strings.Contains(a.Alias, query) {
+			return true
+		}
+	}
+	return false
+}
+
+// RelatedSkills возвращает смежные навыки с весами связей
+func RelatedSkills(c *gin.Context, skills *taxonomy.Store) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор навыка"})
+		return
+	}
+
+	t := skills.Current()
+	skill, ok := t.Get(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Навык не найден"})
+		return
+	}
+
+	related := []gin.H{}
+	for _, r := range t.Related(skill.Slug) {
+		related = append(related, gin.H{
+			"skill":  r.Skill,
+			"weight": r.Weight,
+			"source": r.Source,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"skill": skill, "related": related})
+}
+
+// SetSkillRelation создаёт или меняет курируемую связь навыков
+func SetSkillRelation(c *gin.Context, skills *taxonomy.Store) {
+	type RelationRequest struct {
+		Weight float64 `json:"weight"`
+	}
+
+	id, relatedID, ok := relationIDs(c)
+	if !ok {
+		return
+	}
+	var req RelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	relation, err := skills.SetRelation(id, relatedID, req.Weight)
+	if err != nil {
+		skillError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"relation": relation})
+}
+
+// DeleteSkillRelation удаляет курируемую связь навыков
+func DeleteSkillRelation(c *gin.Context, skills *taxonomy.Store) {
+	id, relatedID, ok := relationIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := skills.DeleteRelation(id, relatedID); err != nil {
+		skillError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deleted": true})
+}
+
+// MineSkillRelations пересчитывает связи по совместной встречаемости навыков
+func MineSkillRelations(c *gin.Context, skills *taxonomy.Store) {
+	count, err := skills.MineRelations()
+	if err != nil {
+		log.WithError(err).Error("Ошибка пересчёта связей навыков")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка пересчёта связей навыков"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"relations": count})
+}
+
+func relationIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор навыка"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	relatedID, err := uuid.Parse(c.Param("related_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор смежного навыка"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, relatedID, true
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/segment"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"github.com/moverq1337/VTBHack/internal/timeline"
+	"github.com/moverq1337/VTBHack/internal/titles"
+	"gorm.io/gorm"
+)
+
+// ResumeTimeline возвращает историю работы кандидата: периоды, перерывы и стаж по навыкам
+func ResumeTimeline(c *gin.Context, db *gorm.DB, skills *taxonomy.Store) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор резюме"})
+		return
+	}
+
+	var resume models.Resume
+	if err := db.First(&resume, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Резюме не найдено"})
+		return
+	}
+
+	tl, err := resumeTimeline(db, resume, skills.Current())
+	if err != nil {
+		log.WithError(err).Error("Ошибка загрузки истории работы")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка загрузки истории работы"})
+		return
+	}
+
+	periods := make([]gin.H, 0, len(tl.Periods))
+	for _, p := range tl.Periods {
+		title := titles.ForPeriod(p)
+		periods = append(periods, gin.H{
+			"company":     p.Company,
+			"title":       p.Title,
+			"role_family": title.Family,
+			"seniority":   title.Seniority,
+			"start":       p.Start.Format("2006-01"),
+			"end":         p.End.Format("2006-01"),
+			"current":     p.Current,
+			"months":      p.Months(),
+			"skills":      nonNil(p.Skills),
+			"span_start":  p.SpanStart,
+			"span_end":    p.SpanEnd,
+		})
+	}
+
+	gaps := make([]gin.H, 0, len(tl.Gaps))
+	for _, g := range tl.Gaps {
+		gaps = append(gaps, gin.H{"from": g.From.Format("2006-01"), "to": g.To.Format("2006-01"), "months": g.Months})
+	}
+
+	skillExperience := make([]gin.H, 0, len(tl.SkillMonths))
+	for _, name := range sortedSkills(tl.SkillMonths) {
+		lastUsed, years, _ := tl.SkillUsed(name)
+		skillExperience = append(skillExperience, gin.H{
+			"skill":     name,
+			"months":    tl.SkillMonths[name],
+			"years":     years,
+			"last_used": lastUsed.Year(),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"resume_id":    resume.ID.String(),
+		"periods":      periods,
+		"gaps":         gaps,
+		"total_months": tl.TotalMonths,
+		"total_years":  tl.Years(),
+		"skills":       skillExperience,
+	})
+}
+
+// extractTimeline строит историю работы по разделам с опытом работы,
+// чтобы навыки из курсов и учёбы не попадали в стаж. Смещения периодов
+// указываются относительно всего текста резюме.
+func extractTimeline(text string, sections []segment.Section, t *taxonomy.Taxonomy) timeline.Timeline {
+	work := segment.Work(sections)
+	if len(work) == 0 {
+		return timeline.Extract(text, time.Now(), t.Extract)
+	}
+
+	var periods []timeline.Period
+	for _, s := range work {
+		for _, p := range timeline.Extract(s.Text, time.Now(), t.Extract).Periods {
+			p.SpanStart += s.Start
+			p.SpanEnd += s.Start
+			periods = append(periods, p)
+		}
+	}
+	return timeline.Build(periods)
+}
+
+// saveWorkHistory сохраняет периоды работы резюме, заменяя прежние
+func saveWorkHistory(db *gorm.DB, resumeID uuid.UUID, tl timeline.Timeline) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("resume_id = ?", resumeID).Delete(&models.WorkExperience{}).Error; err != nil {
+			return err
+		}
+		if len(tl.Periods) == 0 {
+			return nil
+		}
+
+		rows := make([]models.WorkExperience, len(tl.Periods))
+		for i, p := range tl.Periods {
+			title := titles.ForPeriod(p)
+			rows[i] = models.WorkExperience{
+				ID:          uuid.New(),
+				ResumeID:    resumeID,
+				Company:     p.Company,
+				Title:       p.Title,
+				RoleFamily:  title.Family,
+				Seniority:   string(title.Seniority),
+				StartDate:   p.Start,
+				EndDate:     p.End,
+				Current:     p.Current,
+				Months:      p.Months(),
+				Skills:      strings.Join(p.Skills, ", "),
+				Description: p.Description,
+				SpanStart:   p.SpanStart,
+				SpanEnd:     p.SpanEnd,
+				CreatedAt:   time.Now(),
+			}
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// resumeTimeline собирает историю из сохранённых периодов. Текущая работа
+// продлевается до текущего месяца. Периоды сохраняются при загрузке резюме;
+// для резюме, загруженных раньше, история извлекается из текста без записи.
+func resumeTimeline(db *gorm.DB, resume models.Resume, t *taxonomy.Taxonomy) (timeline.Timeline, error) {
+	var rows []models.WorkExperience
+	if err := db.Where("resume_id = ?", resume.ID).Order("start_date").Find(&rows).Error; err != nil {
+		return timeline.Timeline{}, err
+	}
+
+	if len(rows) == 0 {
+		return extractTimeline(resume.Text, resumeSections(resume), t), nil
+	}
+
+	now := time.Now()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periods := make([]timeline.Period, len(rows))
+	for i, r := range rows {
+		p := timeline.Period{
+			Start:       r.StartDate.UTC(),
+			End:         r.EndDate.UTC(),
+			Current:     r.Current,
+			Company:     r.Company,
+			Title:       r.Title,
+			Description: r.Description,
+			SpanStart:   r.SpanStart,
+			SpanEnd:     r.SpanEnd,
+		}
+		if r.Current {
+			p.End = current
+		}
+		if r.Skills != "" {
+			p.Skills = strings.Split(r.Skills, ", ")
+		}
+		periods[i] = p
+	}
+	return timeline.Build(periods), nil
+}
+
+func sortedSkills(months map[string]int) []string {
+	names := make([]string, 0, len(months))
+	for name := range months {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if months[names[i]] != months[names[j]] {
+			return months[names[i]] > months[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moverq1337/VTBHack/internal/lint"
+	"github.com/moverq1337/VTBHack/internal/mobility"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/requirements"
+	"github.com/moverq1337/VTBHack/internal/salary"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"github.com/moverq1337/VTBHack/internal/titles"
+	"github.com/moverq1337/VTBHack/internal/vectors"
+	"gorm.io/gorm"
+)
+
+// vacancyRequest тело загрузки и изменения вакансии
+type vacancyRequest struct {
+	Title            string `json:"title"`
+	Requirements     string `json:"requirements"`
+	Responsibilities string `json:"responsibilities"`
+	Region           string `json:"region"`
+	City             string `json:"city"`
+	EmploymentType   string `json:"employment_type"`
+	WorkSchedule     string `json:"work_schedule"`
+	Experience       string `json:"experience"`
+	Education        string `json:"education"`
+	SalaryMin        int    `json:"salary_min"`
+	SalaryMax        int    `json:"salary_max"`
+	SalaryCurrency   string `json:"salary_currency"` // По умолчанию RUB
+	SalaryBasis      string `json:"salary_basis"`    // gross или net
+	Languages        string `json:"languages"`
+	Skills           string `json:"skills"`
+	Seniority        string `json:"seniority"` // Необязательно; по умолчанию определяется по названию
+	Draft            bool   `json:"draft"`     // Сохранить без публикации
+}
+
+// buildVacancy собирает вакансию из запроса: нормализует навыки, место
+// работы, направление и уровень, проверяет валюту вилки
+func buildVacancy(req vacancyRequest, t *taxonomy.Taxonomy, rates salary.Rates) (models.Vacancy, error) {
+	title := titles.Normalize(req.Title)
+	if req.Seniority != "" {
+		level, ok := titles.ParseLevel(req.Seniority)
+		if !ok {
+			return models.Vacancy{}, errors.New("Неизвестный уровень: " + req.Seniority)
+		}
+		title.Seniority = level
+	}
+
+	currency, basis, err := vacancySalary(rates, req.SalaryCurrency, req.SalaryBasis)
+	if err != nil {
+		return models.Vacancy{}, err
+	}
+
+	vacancy := models.Vacancy{
+		Title:            req.Title,
+		Requirements:     req.Requirements,
+		Responsibilities: req.Responsibilities,
+		Region:           req.Region,
+		City:             req.City,
+		EmploymentType:   req.EmploymentType,
+		WorkSchedule:     req.WorkSchedule,
+		Experience:       req.Experience,
+		Education:        req.Education,
+		SalaryMin:        req.SalaryMin,
+		SalaryMax:        req.SalaryMax,
+		SalaryCurrency:   currency,
+		SalaryBasis:      string(basis),
+		Languages:        req.Languages,
+		Skills:           t.NormalizeList(req.Skills),
+		RoleFamily:       title.Family,
+		Seniority:        string(title.Seniority),
+	}
+	normalizeVacancyPlace(&vacancy)
+	return vacancy, nil
+}
+
+// UpdateVacancy заменяет поля вакансии и проверяет её заново. Пункты
+// требований, исправленные рекрутером, сохраняются; остальные разбираются
+// из нового текста.
+func UpdateVacancy(c *gin.Context, db *gorm.DB, skills *taxonomy.Store, lintOpts lint.Options, embeddings *vectors.Store, employees *mobility.Runner) {
+	current, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+
+	var req vacancyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
+		return
+	}
+	vacancy, err := buildVacancy(req, skills.Current(), loadRates(db))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	items, result := reviseVacancy(&vacancy, current, skills.Current(), lintOpts, req.Draft)
+	if err := saveVacancy(db, &vacancy); err != nil {
+		log.WithError(err).Error("Ошибка сохранения вакансии")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+	if vectors.VacancyText(vacancy) != vectors.VacancyText(current) {
+		embeddings.Refresh(vectors.KindVacancy, vacancy.ID, vectors.VacancyText(vacancy))
+	}
+	employees.Vacancy(vacancy.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"vacancy_id":   vacancy.ID.String(),
+		"status":       vacancy.Status,
+		"requirements": items,
+		"lint":         result,
+	})
+}
+
+// PublishVacancy публикует черновик. В строгом режиме вакансия с ошибками
+// проверки не публикуется: ответ 422 со списком замечаний.
+func PublishVacancy(c *gin.Context, db *gorm.DB, skills *taxonomy.Store, lintOpts lint.Options, employees *mobility.Runner) {
+	vacancy, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+
+	items := vacancyRequirements(vacancy, skills.Current())
+	archivedAt := vacancy.ArchivedAt
+	vacancy.ArchivedAt = nil
+	result := applyLint(&vacancy, items, lintOpts, false)
+	if vacancy.Status != models.VacancyPublished {
+		vacancy.ArchivedAt = archivedAt
+	}
+	if err := db.Model(&vacancy).Select("Status", "ArchivedAt", "Lint", "LintErrors", "LintWarnings").Updates(&vacancy).Error; err != nil {
+		log.WithError(err).Error("Ошибка сохранения вакансии")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения в БД"})
+		return
+	}
+
+	if vacancy.Status != models.VacancyPublished {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Вакансия не опубликована: исправьте ошибки проверки",
+			"lint":  result,
+		})
+		return
+	}
+	employees.Vacancy(vacancy.ID)
+	c.JSON(http.StatusOK, gin.H{"vacancy_id": vacancy.ID.String(), "status": vacancy.Status, "lint": result})
+}
+
+// VacancyLint возвращает результат последней проверки вакансии
+func VacancyLint(c *gin.Context, db *gorm.DB) {
+	vacancy, ok := loadVacancy(c, db)
+	if !ok {
+		return
+	}
+
+	var result lint.Result
+	if err := json.Unmarshal([]byte(vacancy.Lint), &result); err != nil || result.Issues == nil {
+		result.Issues = []lint.Issue{}
+	}
+	c.JSON(http.StatusOK, gin.H{"vacancy_id": vacancy.ID.String(), "status": vacancy.Status, "lint": result})
+}
+
+// reviseVacancy переносит в новую версию вакансии идентификатор и статус,
+// разбирает требования и проверяет её. Пункты требований, исправленные
+// рекрутером, сохраняются; архивная вакансия остаётся в архиве.
+func reviseVacancy(vacancy *models.Vacancy, current models.Vacancy, t *taxonomy.Taxonomy, lintOpts lint.Options, draft bool) ([]requirements.Item, lint.Result) {
+	vacancy.ID, vacancy.CreatedAt, vacancy.ArchivedAt = current.ID, current.CreatedAt, current.ArchivedAt
+
+	items := requirements.Parse(*vacancy, t)
+	if current.ItemsEdited {
+		items = vacancyRequirements(current, t)
+		vacancy.ItemsEdited = true
+	}
+	vacancy.RequirementItems = encodeRequirements(items)
+
+	result := applyLint(vacancy, items, lintOpts, draft || current.Status == models.VacancyDraft)
+	return items, result
+}
+
+// saveVacancy сохраняет все поля вакансии, включая пустые
+func saveVacancy(db *gorm.DB, vacancy *models.Vacancy) error {
+	return db.Select("*").Omit("CreatedAt").Updates(vacancy).Error
+}
+
+// applyLint проверяет вакансию, сохраняет результат в неё и выставляет
+// статус: черновик по запросу, для архивной вакансии или если строгий режим
+// запрещает публикацию
+func applyLint(vacancy *models.Vacancy, items []requirements.Item, opts lint.Options, draft bool) lint.Result {
+	result := lint.Check(*vacancy, items)
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.WithError(err).Error("Ошибка сериализации результата проверки вакансии")
+		data = []byte("{}")
+	}
+	vacancy.Lint = string(data)
+	vacancy.LintErrors, vacancy.LintWarnings = result.Errors, result.Warnings
+
+	vacancy.Status = models.VacancyPublished
+	if draft || vacancy.ArchivedAt != nil || opts.Blocks(result) {
+		vacancy.Status = models.VacancyDraft
+	}
+	return result
+}
@@ -0,0 +1,179 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moverq1337/VTBHack/internal/config"
+	"github.com/moverq1337/VTBHack/internal/geo"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/requirements"
+	"github.com/moverq1337/VTBHack/internal/titles"
+)
+
+// Severity серьёзность замечания
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // В строгом режиме блокирует публикацию
+	SeverityWarning Severity = "warning" // Рекомендация
+)
+
+// Issue замечание к вакансии. Rule - стабильный идентификатор правила,
+// по нему замечания можно фильтровать и отключать на стороне клиента.
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Field    string   `json:"field"`
+	Message  string   `json:"message"`
+	Fragment string   `json:"fragment,omitempty"` // Найденная формулировка
+	Start    int      `json:"start,omitempty"`    // Смещение в поле, в рунах
+	End      int      `json:"end,omitempty"`
+}
+
+// Result результат проверки вакансии
+type Result struct {
+	Issues   []Issue `json:"issues"`
+	Errors   int     `json:"errors"`
+	Warnings int     `json:"warnings"`
+}
+
+// Options настройки проверки
+type Options struct {
+	Strict bool // Вакансию с ошибками нельзя опубликовать
+}
+
+// OptionsFrom собирает настройки проверки из конфигурации сервиса
+func OptionsFrom(cfg *config.Config) Options {
+	return Options{Strict: cfg.VacancyLintStrict}
+}
+
+// Blocks сообщает, что результат не позволяет опубликовать вакансию
+func (o Options) Blocks(r Result) bool {
+	return o.Strict && r.Errors > 0
+}
+
+// Check проверяет вакансию: обязательные поля, вилку зарплаты,
+// дискриминационные формулировки и реалистичность требований
+func Check(v models.Vacancy, items []requirements.Item) Result {
+	var issues []Issue
+	issues = append(issues, missingFields(v)...)
+	issues = append(issues, salaryIssues(v)...)
+	issues = append(issues, phraseIssues(v)...)
+	issues = append(issues, requirementIssues(v, items)...)
+
+	r := Result{Issues: issues}
+	if r.Issues == nil {
+		r.Issues = []Issue{}
+	}
+	for _, i := range r.Issues {
+		if i.Severity == SeverityError {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+	}
+	return r
+}
+
+func missingFields(v models.Vacancy) []Issue {
+	var out []Issue
+	missing := func(field, value string, severity Severity, message string) {
+		if strings.TrimSpace(value) == "" {
+			out = append(out, Issue{Rule: "missing-" + strings.ReplaceAll(field, "_", "-"), Severity: severity, Field: field, Message: message})
+		}
+	}
+	missing("title", v.Title, SeverityError, "Не указано название вакансии")
+	missing("requirements", v.Requirements+v.Skills, SeverityError, "Не указаны требования и ключевые навыки")
+	missing("responsibilities", v.Responsibilities, SeverityWarning, "Не указаны обязанности")
+	missing("employment_type", v.EmploymentType, SeverityWarning, "Не указан тип занятости")
+	missing("experience", v.Experience, SeverityWarning, "Не указан требуемый опыт")
+	if !geo.IsRemote(v.EmploymentType + " " + v.WorkSchedule) {
+		missing("city", v.City+v.Region, SeverityWarning, "Не указан город или регион работы")
+	}
+	return out
+}
+
+func salaryIssues(v models.Vacancy) []Issue {
+	switch {
+	case v.SalaryMin < 0 || v.SalaryMax < 0:
+		return []Issue{{Rule: "salary-negative", Severity: SeverityError, Field: "salary_min", Message: "Зарплата не может быть отрицательной"}}
+	case v.SalaryMin == 0 && v.SalaryMax == 0:
+		return []Issue{{Rule: "missing-salary", Severity: SeverityWarning, Field: "salary_min", Message: "Не указана зарплата: вакансии без вилки получают меньше откликов"}}
+	case v.SalaryMax > 0 && v.SalaryMin > v.SalaryMax:
+		return []Issue{{Rule: "salary-range", Severity: SeverityError, Field: "salary_min",
+			Message: fmt.Sprintf("Нижняя граница зарплаты %d больше верхней %d", v.SalaryMin, v.SalaryMax)}}
+	}
+	return nil
+}
+
+// phraseIssues ищет запрещённые формулировки во всех текстовых полях
+func phraseIssues(v models.Vacancy) []Issue {
+	fields := []struct{ name, text string }{
+		{"title", v.Title},
+		{"requirements", v.Requirements},
+		{"responsibilities", v.Responsibilities},
+		{"education", v.Education},
+		{"work_schedule", v.WorkSchedule},
+		{"languages", v.Languages},
+	}
+
+	var out []Issue
+	for _, f := range fields {
+		var found []Issue
+		for _, rule := range phraseRules {
+			for _, m := range rule.pattern.FindAllStringSubmatchIndex(f.text, -1) {
+				found = append(found, Issue{
+					Rule:     rule.rule,
+					Severity: rule.severity,
+					Field:    f.name,
+					Message:  rule.message,
+					Fragment: f.text[m[2]:m[3]],
+					Start:    len([]rune(f.text[:m[2]])),
+					End:      len([]rune(f.text[:m[3]])),
+				})
+			}
+		}
+		sort.SliceStable(found, func(i, j int) bool { return found[i].Start < found[j].Start })
+		out = append(out, found...)
+	}
+	return out
+}
+
+// requirementIssues проверяет число требований и соответствие опыта уровню
+func requirementIssues(v models.Vacancy, items []requirements.Item) []Issue {
+	var out []Issue
+	must := 0
+	for _, item := range items {
+		if item.Priority == requirements.PriorityMust {
+			must++
+		}
+	}
+	switch {
+	case must > maxMustError:
+		out = append(out, Issue{Rule: "too-many-requirements", Severity: SeverityError, Field: "requirements",
+			Message: fmt.Sprintf("Обязательных требований %d: такому описанию не соответствует почти никто", must)})
+	case must > maxMustWarning:
+		out = append(out, Issue{Rule: "too-many-requirements", Severity: SeverityWarning, Field: "requirements",
+			Message: fmt.Sprintf("Обязательных требований %d, рекомендуется не больше %d: часть стоит перенести в желательные", must, maxMustWarning)})
+	}
+
+	if n := len(strings.FieldsFunc(v.Skills, func(r rune) bool { return r == ',' || r == ';' || r == '\n' })); n > maxSkills {
+		out = append(out, Issue{Rule: "too-many-skills", Severity: SeverityWarning, Field: "skills",
+			Message: fmt.Sprintf("Ключевых навыков %d, рекомендуется не больше %d", n, maxSkills)})
+	}
+
+	level, ok := titles.ParseLevel(v.Seniority)
+	if !ok || level.Rank() > titles.LevelJunior.Rank() {
+		return out
+	}
+	for _, item := range items {
+		if item.Kind == requirements.KindExperience && item.Priority == requirements.PriorityMust && item.Years >= 3 {
+			out = append(out, Issue{Rule: "seniority-experience", Severity: SeverityWarning, Field: "experience",
+				Message: fmt.Sprintf("Для уровня %s требуется опыт от %.0f лет", level, item.Years), Fragment: item.Text})
+			break
+		}
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package lint
+
+import "regexp"
+
+// Пороги числа требований, после которых вакансия выглядит нереалистичной
+const (
+	maxMustWarning = 15 // Обязательных пунктов
+	maxMustError   = 30
+	maxSkills      = 20 // Ключевых навыков
+)
+
+// phraseRules формулировки, запрещённые или нежелательные в тексте вакансии.
+// Статья 3 и 64 ТК РФ запрещают ограничения по полу, возрасту, национальности,
+// семейному положению, месту жительства и другим обстоятельствам, не связанным
+// с деловыми качествами; статья 25 закона "О занятости населения" запрещает
+// такие требования в объявлениях о вакансиях.
+var phraseRules = []struct {
+	rule     string
+	severity Severity
+	message  string
+	pattern  *regexp.Regexp
+}{
+	{"bias-gender", SeverityError, "Требование к полу кандидата запрещено (ст. 3, 64 ТК РФ)",
+		words(`девушк\pL*`, `юнош\pL*`, `парн(?:я|ей|ем)`, `мужчин\pL*`, `женщин\pL*`, `только\s+муж\pL*`, `только\s+жен\pL*`,
+			`(?:мужской|женский)\s+пол`, `male\s+only`, `female\s+only`)},
+	{"bias-age", SeverityError, "Ограничение по возрасту запрещено (ст. 3, 64 ТК РФ)",
+		words(`возраст\pL*\s*(?:от|до|не\s+старше|не\s+более)?\s*\d+`, `не\s+старше\s+\d+`,
+			`(?:девушк|юнош|мужчин|женщин|кандидат|сотрудник|специалист|соискател|выпускник|лиц)\pL*\s+(?:в\s+возрасте\s+)?(?:от\s+\d+\s+)?до\s+\d+\s*(?:-?ти\s+)?лет`,
+			`under\s+\d+\s+years\s+old`, `aged?\s+(?:under|below)\s+\d+`)},
+	{"bias-age-wording", SeverityWarning, "Формулировка может восприниматься как ограничение по возрасту",
+		words(`молод(?:ой|ая|ые|ых|ого)`, `young`)},
+	{"bias-nationality", SeverityError, "Требование к национальности или внешности запрещено (ст. 3, 64 ТК РФ)",
+		words(`славянск\pL*`, `национальност\pL*`, `русск\pL*\s+(?:по\s+)?(?:происхождени|национальност)\pL*`, `внешност\pL*`)},
+	{"bias-family", SeverityError, "Требование к семейному положению и наличию детей запрещено (ст. 3, 64 ТК РФ)",
+		words(`без\s+детей`, `нет\s+детей`, `не\s+замуж\pL*`, `незамуж\pL*`, `холост\pL*`, `семейн\pL*\s+положени\pL*`, `планирующ\pL*\s+(?:декрет|беременност)\pL*`)},
+	{"bias-residence", SeverityError, "Требование к месту жительства или регистрации запрещено (ст. 3, 64 ТК РФ)",
+		words(`прописк\pL*`, `(?:постоянн\pL*\s+)?регистраци\pL*\s+(?:в\s+(?:москв|спб|санкт|г\.|город|регион|област)|по\s+месту)\pL*`, `местн\pL*\s+жител\pL*`)},
+	{"bias-religion", SeverityError, "Требование к вероисповеданию запрещено (ст. 3, 64 ТК РФ)",
+		words(`вероисповедани\pL*`, `религи\pL*`)},
+	{"bias-citizenship", SeverityWarning, "Требование гражданства допустимо только если его прямо требует закон",
+		words(`гражданств\pL*\s+(?:рф|россии|российской)`, `только\s+граждан\pL*`)},
+}
+
+// words собирает шаблон с ручными границами слов: \b в Go не учитывает кириллицу
+func words(alts ...string) *regexp.Regexp {
+	pattern := `(?i)(?:^|[^\pL\pN])(`
+	for i, a := range alts {
+		if i > 0 {
+			pattern += "|"
+		}
+		pattern += a
+	}
+	return regexp.MustCompile(pattern + `)`)
+}
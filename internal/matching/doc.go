@@ -0,0 +1,81 @@
+package matching
+
+import (
+	"sort"
+	"time"
+
+	"github.com/moverq1337/VTBHack/internal/textproc"
+	"github.com/moverq1337/VTBHack/internal/timeline"
+)
+
+// resumeDoc текст резюме, разбитый на предложения с основами слов
+type resumeDoc struct {
+	text      string
+	runes     []rune
+	sentences []docSentence
+	history   timeline.Timeline
+	now       time.Time
+}
+
+type docSentence struct {
+	textproc.Sentence
+	stems map[string]bool
+}
+
+func newResumeDoc(text string) *resumeDoc {
+	doc := &resumeDoc{text: text, runes: []rune(text)}
+	for _, s := range textproc.Sentences(text) {
+		stems := make(map[string]bool)
+		for _, stem := range textproc.Stems(s.Text) {
+			stems[stem] = true
+		}
+		doc.sentences = append(doc.sentences, docSentence{Sentence: s, stems: stems})
+	}
+	return doc
+}
+
+// findSkill ищет первое упоминание навыка в резюме
+func (d *resumeDoc) findSkill(entry textproc.SkillEntry) (textproc.SkillMatch, bool) {
+	found := textproc.FindSkills(d.text, []textproc.SkillEntry{entry})
+	if len(found) == 0 {
+		return textproc.SkillMatch{}, false
+	}
+	return found[0], true
+}
+
+// slice возвращает фрагмент исходного текста по смещениям в рунах
+func (d *resumeDoc) slice(start, end int) string {
+	start = max(0, min(start, len(d.runes)))
+	end = max(start, min(end, len(d.runes)))
+	return string(d.runes[start:end])
+}
+
+// sentenceIndex номер предложения, содержащего позицию; -1 если позиция вне предложений
+func (d *resumeDoc) sentenceIndex(pos int) int {
+	i := sort.Search(len(d.sentences), func(i int) bool { return d.sentences[i].End > pos })
+	if i < len(d.sentences) && d.sentences[i].Start <= pos {
+		return i
+	}
+	return -1
+}
+
+// sentenceAt возвращает предложение с позицией; вне предложений - саму позицию
+func (d *resumeDoc) sentenceAt(pos int) Span {
+	if i := d.sentenceIndex(pos); i >= 0 {
+		s := d.sentences[i]
+		return Span{Start: s.Start, End: s.End, Text: s.Text}
+	}
+	return Span{Start: pos, End: pos}
+}
+
+// spans переводит номера предложений в фрагменты в порядке следования в тексте
+func (d *resumeDoc) spans(indices map[int]bool) []Span {
+	var out []Span
+	for i := range d.sentences {
+		if indices[i] {
+			s := d.sentences[i]
+			out = append(out, Span{Start: s.Start, End: s.End, Text: s.Text})
+		}
+	}
+	return out
+}
@@ -0,0 +1,436 @@
+package matching
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moverq1337/VTBHack/internal/geo"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/requirements"
+	"github.com/moverq1337/VTBHack/internal/salary"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"github.com/moverq1337/VTBHack/internal/textproc"
+	"github.com/moverq1337/VTBHack/internal/timeline"
+)
+
+// Категории критериев
+const (
+	CategorySemantic    = "semantic"
+	CategorySkills      = "skills"
+	CategoryRequirement = "requirements"
+	CategoryExperience  = "experience"
+	CategorySeniority   = "seniority"
+	CategoryLocation    = "location"
+	CategorySalary      = "salary"
+)
+
+// categoryWeights доли категорий в итоговой оценке. Вес категории делится
+// поровну между её критериями; вес пустой категории перераспределяется.
+var categoryWeights = map[string]float64{
+	CategorySemantic:    0.3,
+	CategorySkills:      0.4,
+	CategoryRequirement: 0.2,
+	CategoryExperience:  0.1,
+	CategorySeniority:   0.1,
+	CategoryLocation:    0.1,
+	CategorySalary:      0.1,
+}
+
+// Пороги сопоставления требований с предложениями резюме
+const (
+	matchThreshold    = 0.5
+	evidenceThreshold = 0.3
+	maxEvidence       = 3
+)
+
+// Span фрагмент Resume.Text. Смещения указаны в символах (рунах), End не включается.
+type Span struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+}
+
+// Criterion результат проверки одного требования вакансии
+type Criterion struct {
+	Category     string  `json:"category"`
+	Requirement  string  `json:"requirement"`
+	Priority     string  `json:"priority,omitempty"` // must или nice для пунктов требований
+	Matched      bool    `json:"matched"`
+	Score        float64 `json:"score"`        // Степень соответствия 0..1
+	Weight       float64 `json:"weight"`       // Доля критерия в итоговой оценке
+	Contribution float64 `json:"contribution"` // Score * Weight, вклад в итоговую оценку
+	ResumeValue  string  `json:"resume_value,omitempty"`
+	Via          string  `json:"via,omitempty"`       // Смежный навык, через который засчитано требование
+	LastUsed     int     `json:"last_used,omitempty"` // Год последнего использования навыка по истории работы
+	SkillYears   float64 `json:"skill_years,omitempty"`
+	Recency      float64 `json:"recency,omitempty"` // Доля зачёта с учётом давности использования
+	Note         string  `json:"note,omitempty"`    // Пояснение к оценке
+	Evidence     []Span  `json:"evidence,omitempty"`
+}
+
+// Explanation обоснование оценки: итог равен сумме вкладов критериев
+type Explanation struct {
+	Score    float64     `json:"score"`
+	Criteria []Criterion `json:"criteria"`
+	Missing  []string    `json:"missing"`
+}
+
+// Input данные для оценки пары резюме-вакансия
+type Input struct {
+	ResumeText    string
+	ResumeYears   float64 // Опыт кандидата в годах
+	Vacancy       models.Vacancy
+	SemanticScore float64             // Оценка NLP-сервиса 0..1
+	History       timeline.Timeline   // История работы: стаж и давность использования навыков
+	Requirements  []requirements.Item // Пункты требований; nil - разобрать из текста вакансии
+	Location      geo.Location        // Город кандидата и готовность к переезду
+	Salary        salary.Amount       // Ожидаемая зарплата; нулевая - не указана
+	Rates         salary.Rates        // Курсы валют; по умолчанию salary.DefaultRates
+	Now           time.Time           // Момент оценки; по умолчанию текущее время
+}
+
+// Engine сопоставляет требования вакансии с резюме и объясняет оценку
+type Engine struct {
+	skills   []textproc.SkillEntry
+	taxonomy *taxonomy.Taxonomy
+	opts     Options
+}
+
+// NewEngine создаёт движок по снимку таксономии навыков
+func NewEngine(t *taxonomy.Taxonomy, opts Options) *Engine {
+	return &Engine{skills: t.Entries(), taxonomy: t, opts: opts}
+}
+
+// Evaluate проверяет каждое требование вакансии и собирает обоснование
+func (e *Engine) Evaluate(in Input) Explanation {
+	doc := newResumeDoc(in.ResumeText)
+	doc.history, doc.now = in.History, in.Now
+	if doc.now.IsZero() {
+		doc.now = time.Now()
+	}
+
+	criteria := []Criterion{{
+		Category:    CategorySemantic,
+		Requirement: "Смысловое сходство резюме и вакансии",
+		Score:       clamp(in.SemanticScore),
+		Matched:     in.SemanticScore >= matchThreshold,
+	}}
+
+	items := in.Requirements
+	if items == nil {
+		items = requirements.Parse(in.Vacancy, e.taxonomy)
+	}
+	for _, item := range items {
+		c := e.itemCriterion(doc, in, item)
+		c.Priority = string(item.Priority)
+		criteria = append(criteria, c)
+	}
+	if c, ok := seniorityCriterion(doc, in); ok {
+		criteria = append(criteria, c)
+	}
+	if c, ok := locationCriterion(in); ok {
+		criteria = append(criteria, c)
+	}
+	if c, ok := salaryCriterion(in); ok {
+		criteria = append(criteria, c)
+	}
+
+	return summarize(criteria)
+}
+
+// skillCriterion ищет ключевой навык вакансии в резюме. Если навыка нет,
+// частично засчитывается самый близкий из найденных смежных навыков.
+func (e *Engine) skillCriterion(doc *resumeDoc, skill string) Criterion {
+	c := Criterion{Category: CategorySkills, Requirement: skill}
+
+	entry := e.lookupSkill(skill)
+	m, ok := doc.findSkill(entry)
+	if ok {
+		c.Score = 1
+	} else if rel, rm, relOK := e.findRelated(doc, entry.Name); relOK {
+		m, ok = rm, true
+		c.Score = rel.Weight
+		c.Via = rel.Skill.Name
+		c.Note = fmt.Sprintf("Совпадение через смежный навык %s (зачёт %.0f%%)", rel.Skill.Name, rel.Weight*100)
+	}
+	if !ok {
+		return c
+	}
+
+	c.ResumeValue = doc.slice(m.Start, m.End)
+	c.Evidence = []Span{doc.sentenceAt(m.Start)}
+	u := e.usage(doc, m)
+	c.Recency = u.factor
+	if u.known {
+		c.LastUsed, c.SkillYears = u.lastUsed.Year(), u.years
+	}
+	if u.factor < 1 {
+		c.Score *= u.factor
+		if u.known {
+			c.Note = joinNotes(c.Note, fmt.Sprintf("Последний раз использовался в %d г., зачёт %.0f%%", u.lastUsed.Year(), u.factor*100))
+		} else {
+			c.Note = joinNotes(c.Note, fmt.Sprintf("Нет в опыте работы, давность неизвестна, зачёт %.0f%%", u.factor*100))
+		}
+	}
+	c.Matched = c.Score >= matchThreshold
+	return c
+}
+
+type skillUsage struct {
+	lastUsed time.Time
+	years    float64
+	factor   float64
+	known    bool // Навык есть в периодах работы
+}
+
+// usage находит найденный навык в истории работы и считает затухание зачёта.
+// Навык без периодов работы (например, только в перечне навыков) получает
+// долю зачёта Decay.Unknown.
+func (e *Engine) usage(doc *resumeDoc, m textproc.SkillMatch) skillUsage {
+	name := e.lookupSkill(m.Alias).Name
+	last, years, ok := doc.history.SkillUsed(name)
+	if !ok {
+		return skillUsage{factor: e.opts.Decay.UnknownFactor()}
+	}
+	return skillUsage{lastUsed: last, years: years, factor: e.opts.Decay.Factor(yearsSince(last, doc.now)), known: true}
+}
+
+func joinNotes(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "; " + b
+}
+
+// findRelated ищет в резюме смежные навыки по убыванию веса связи
+func (e *Engine) findRelated(doc *resumeDoc, name string) (taxonomy.Related, textproc.SkillMatch, bool) {
+	for _, rel := range e.taxonomy.Related(name) {
+		if m, ok := doc.findSkill(rel.Entry); ok {
+			return rel, m, true
+		}
+	}
+	return taxonomy.Related{}, textproc.SkillMatch{}, false
+}
+
+// requirementCriterion сопоставляет пункт требований с предложениями резюме
+// по пересечению основ содержательных слов; упомянутые в пункте навыки
+// проверяются отдельно по всему резюме
+func (e *Engine) requirementCriterion(doc *resumeDoc, requirement string) Criterion {
+	c := Criterion{Category: CategoryRequirement, Requirement: requirement}
+
+	reqStems := uniqueStems(requirement)
+	if len(reqStems) == 0 {
+		return c
+	}
+
+	type scored struct {
+		idx     int
+		overlap float64
+	}
+	var ranked []scored
+	for i, sent := range doc.sentences {
+		hits := 0
+		for stem := range reqStems {
+			if sent.stems[stem] {
+				hits++
+			}
+		}
+		if hits > 0 {
+			ranked = append(ranked, scored{i, float64(hits) / float64(len(reqStems))})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].overlap > ranked[j].overlap })
+
+	best := 0.0
+	if len(ranked) > 0 {
+		best = ranked[0].overlap
+	}
+
+	evidence := make(map[int]bool)
+	for _, r := range ranked {
+		if r.overlap < evidenceThreshold || len(evidence) >= maxEvidence {
+			break
+		}
+		evidence[r.idx] = true
+	}
+
+	score := best
+	if skills := textproc.FindSkills(requirement, e.skills); len(skills) > 0 {
+		found, total := 0.0, 0
+		var via []string
+		for _, s := range skills {
+			// Общий навык, найденный по написанию дочернего, не считается отдельно
+			if e.lookupSkill(s.Alias).Name != s.Name {
+				continue
+			}
+			total++
+			credit := 0.0
+			m, ok := doc.findSkill(e.lookupSkill(s.Name))
+			if ok {
+				credit = 1
+			} else if rel, rm, relOK := e.findRelated(doc, s.Name); relOK {
+				m, ok = rm, true
+				credit = rel.Weight
+				via = append(via, fmt.Sprintf("%s через %s", s.Name, rel.Skill.Name))
+			}
+			if !ok {
+				continue
+			}
+			credit *= e.usage(doc, m).factor
+			found += credit
+			if len(evidence) < maxEvidence {
+				evidence[doc.sentenceIndex(m.Start)] = true
+			}
+		}
+		if len(via) > 0 {
+			c.Note = "Совпадение через смежный навык: " + strings.Join(via, ", ")
+		}
+		if total > 0 {
+			coverage := found / float64(total)
+			score = 0.6*coverage + 0.4*best
+		}
+	}
+
+	c.Score = clamp(score)
+	c.Matched = c.Score >= matchThreshold
+	c.Evidence = doc.spans(evidence)
+	if len(c.Evidence) > 0 {
+		c.ResumeValue = c.Evidence[0].Text
+	}
+	return c
+}
+
+// experienceCriterion сравнивает опыт кандидата с требуемым
+func experienceCriterion(have, need float64, requirement string) Criterion {
+	score := 1.0
+	if have < need {
+		score = have / need
+	}
+	return Criterion{
+		Category:    CategoryExperience,
+		Requirement: requirement,
+		Matched:     have >= need,
+		Score:       clamp(score),
+		ResumeValue: fmt.Sprintf("%.1f лет", have),
+	}
+}
+
+// lookupSkill находит навык словаря по названию или синониму. Если синоним
+// встречается у нескольких навыков (общий навык включает написания дочерних),
+// выбирается самый узкий. Неизвестный навык ищется по собственному написанию.
+func (e *Engine) lookupSkill(name string) textproc.SkillEntry {
+	key := textproc.Normalize(strings.TrimSpace(name))
+	best := -1
+	for i, entry := range e.skills {
+		if textproc.Normalize(entry.Name) == key {
+			return entry
+		}
+		for _, alias := range entry.Aliases {
+			if textproc.Normalize(alias) == key && (best < 0 || len(entry.Aliases) < len(e.skills[best].Aliases)) {
+				best = i
+				break
+			}
+		}
+	}
+	if best >= 0 {
+		return e.skills[best]
+	}
+	return textproc.SkillEntry{Name: name, Aliases: []string{key}}
+}
+
+// niceShare доля желательного пункта относительно обязательного
+const niceShare = 0.5
+
+// summarize распределяет веса по критериям и считает вклады. Внутри
+// категории желательный пункт весит вдвое меньше обязательного; категория
+// только из желательных пунктов получает половину своего веса.
+func summarize(criteria []Criterion) Explanation {
+	shares := make(map[string]float64)
+	top := make(map[string]float64)
+	for _, c := range criteria {
+		shares[c.Category] += c.share()
+		top[c.Category] = math.Max(top[c.Category], c.share())
+	}
+
+	total := 0.0
+	for category := range shares {
+		total += categoryWeights[category] * top[category]
+	}
+
+	exp := Explanation{Criteria: criteria, Missing: []string{}}
+	for i := range exp.Criteria {
+		c := &exp.Criteria[i]
+		if total > 0 {
+			c.Weight = categoryWeights[c.Category] * top[c.Category] / total * c.share() / shares[c.Category]
+		}
+		c.Contribution = c.Score * c.Weight
+		exp.Score += c.Contribution
+		if !c.Matched && c.Category != CategorySemantic && c.Priority != string(requirements.PriorityNice) {
+			exp.Missing = append(exp.Missing, c.Requirement)
+		}
+	}
+	exp.Score = clamp(exp.Score)
+	return exp
+}
+
+// share доля критерия в весе категории
+func (c Criterion) share() float64 {
+	if c.Priority == string(requirements.PriorityNice) {
+		return niceShare
+	}
+	return 1
+}
+
+// Highlights возвращает фрагменты-доказательства всех критериев, упорядоченные по началу
+func (exp Explanation) Highlights() []Highlight {
+	var out []Highlight
+	for _, c := range exp.Criteria {
+		for _, s := range c.Evidence {
+			out = append(out, Highlight{Start: s.Start, End: s.End, Category: c.Category, Requirement: c.Requirement, Score: c.Score})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Start < out[j].Start })
+	return out
+}
+
+// Highlight диапазон подсветки в тексте резюме
+type Highlight struct {
+	Start       int     `json:"start"`
+	End         int     `json:"end"`
+	Category    string  `json:"category"`
+	Requirement string  `json:"requirement"`
+	Score       float64 `json:"score"`
+}
+
+func clamp(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return math.Max(0, math.Min(1, v))
+}
+
+// requirementFiller общие слова формулировок требований, не несущие содержания
+var requirementFiller = map[string]bool{}
+
+func init() {
+	for _, w := range []string{
+		"опыт", "знание", "умение", "навык", "понимание", "владение", "наличие", "желательно",
+		"хороший", "уверенный", "отличный", "базовый", "работа", "год", "лет",
+		"experience", "knowledge", "understanding", "skills", "ability", "good", "strong", "years",
+	} {
+		requirementFiller[textproc.Stem(w)] = true
+	}
+}
+
+func uniqueStems(text string) map[string]bool {
+	stems := make(map[string]bool)
+	for _, s := range textproc.Stems(text) {
+		if len([]rune(s)) >= 2 && !requirementFiller[s] {
+			stems[s] = true
+		}
+	}
+	return stems
+}
@@ -0,0 +1,107 @@
+package matching
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/moverq1337/VTBHack/internal/requirements"
+)
+
+func TestSummarize(t *testing.T) {
+	const nice = string(requirements.PriorityNice)
+	tests := []struct {
+		name     string
+		criteria []Criterion
+		weights  []float64
+		score    float64
+		missing  []string
+	}{
+		{
+			name:     "только смысловое сходство",
+			criteria: []Criterion{{Category: CategorySemantic, Score: 0.7}},
+			weights:  []float64{1},
+			score:    0.7,
+			missing:  []string{},
+		},
+		{
+			// Вес пустых категорий перераспределяется, вес навыков делится поровну,
+			// категория из одних желательных пунктов весит вдвое меньше
+			name: "перераспределение",
+			criteria: []Criterion{
+				{Category: CategorySemantic, Score: 0.8, Matched: true},
+				{Category: CategorySkills, Requirement: "Go", Score: 1, Matched: true},
+				{Category: CategorySkills, Requirement: "Kafka"},
+				{Category: CategoryRequirement, Requirement: "Redis", Priority: nice, Score: 1, Matched: true},
+			},
+			weights: []float64{0.375, 0.25, 0.25, 0.125},
+			score:   0.675,
+			missing: []string{"Kafka"},
+		},
+		{
+			// Желательный пункт получает половину доли обязательного и не попадает в недостающие
+			name: "желательные пункты",
+			criteria: []Criterion{
+				{Category: CategorySemantic, Requirement: "Смысловое сходство"},
+				{Category: CategoryRequirement, Requirement: "Go", Priority: string(requirements.PriorityMust), Score: 1, Matched: true},
+				{Category: CategoryRequirement, Requirement: "Kafka", Priority: nice},
+			},
+			weights: []float64{0.6, 0.4 / 1.5, 0.2 / 1.5},
+			score:   0.4 / 1.5,
+			missing: []string{},
+		},
+		{
+			name:    "нет критериев",
+			missing: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := summarize(tt.criteria)
+			sum := 0.0
+			for i, c := range exp.Criteria {
+				sum += c.Weight
+				if math.Abs(c.Weight-tt.weights[i]) > 1e-9 {
+					t.Errorf("%s: вес %v, want %v", c.Requirement, c.Weight, tt.weights[i])
+				}
+				if c.Contribution != c.Score*c.Weight {
+					t.Errorf("%s: вклад %v не равен оценке на вес", c.Requirement, c.Contribution)
+				}
+			}
+			if len(exp.Criteria) > 0 && math.Abs(sum-1) > 1e-9 {
+				t.Errorf("сумма весов %v, want 1", sum)
+			}
+			if math.Abs(exp.Score-tt.score) > 1e-9 {
+				t.Errorf("оценка %v, want %v", exp.Score, tt.score)
+			}
+			if !reflect.DeepEqual(exp.Missing, tt.missing) {
+				t.Errorf("недостающие %q, want %q", exp.Missing, tt.missing)
+			}
+		})
+	}
+}
+
+func TestDecayFactor(t *testing.T) {
+	d := DefaultOptions.Decay
+	tests := []struct {
+		yearsAgo float64
+		want     float64
+	}{
+		{0, 1},
+		{2, 1},   // В пределах Grace
+		{6, 0.5}, // Grace + HalfLife
+		{10, 0.3},
+		{30, 0.3}, // Не ниже Floor
+	}
+	for _, tt := range tests {
+		if got := d.Factor(tt.yearsAgo); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Factor(%v) = %v, want %v", tt.yearsAgo, got, tt.want)
+		}
+	}
+	if got := (Decay{}).Factor(30); got != 1 {
+		t.Errorf("без затухания Factor = %v, want 1", got)
+	}
+	if got := d.UnknownFactor(); got != 0.8 {
+		t.Errorf("UnknownFactor = %v, want 0.8", got)
+	}
+}
@@ -0,0 +1,89 @@
+package matching
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/moverq1337/VTBHack/internal/geo"
+)
+
+// Расстояния, в пределах которых переезд не нужен
+const (
+	sameCityKm = 50  // Агломерация: Москва - Химки
+	commuteKm  = 150 // Ежедневная дорога возможна, но тяжела
+)
+
+// remoteMismatch зачёт кандидата, который ищет удалённую работу, для
+// офисной вакансии в другом городе: формат не совпадает, но об удалёнке
+// иногда можно договориться
+const remoteMismatch = 0.4
+
+// locationCriterion оценивает, сможет ли кандидат работать в месте работы
+// вакансии: по расстоянию, удалённому формату и готовности к переезду.
+// Кандидата, который ищет удалённую работу и не готов к переезду, за
+// расстояние не штрафуют: для офисной вакансии важно несовпадение формата.
+// Критерий не строится, если город вакансии неизвестен, или город
+// кандидата неизвестен и удалённую работу он не ищет.
+func locationCriterion(in Input) (Criterion, bool) {
+	v := in.Vacancy
+	if geo.IsRemote(v.EmploymentType + " " + v.WorkSchedule) {
+		return Criterion{
+			Category:    CategoryLocation,
+			Requirement: "Удалённая работа",
+			Matched:     true,
+			Score:       1,
+			ResumeValue: in.Location.City,
+			Note:        "Вакансия допускает удалённую работу, город кандидата не важен",
+		}, true
+	}
+
+	place, ok := geo.Resolve(v.City)
+	if !ok {
+		place, ok = geo.Resolve(v.Region)
+	}
+	if !ok {
+		return Criterion{}, false
+	}
+	c := Criterion{Category: CategoryLocation, Requirement: "Место работы: " + place.Name}
+	home, ok := geo.Resolve(in.Location.City)
+	if !ok {
+		if !in.Location.Remote {
+			return Criterion{}, false
+		}
+		c.Score, c.Note = remoteMismatch, "Ищет удалённую работу, вакансия в офисе, город кандидата неизвестен"
+		return c, true
+	}
+
+	c.ResumeValue = home.Name
+	km := geo.Distance(home, place)
+	switch {
+	case km <= sameCityKm:
+		c.Score = 1
+	case km <= commuteKm:
+		c.Score = 0.8
+		c.Note = fmt.Sprintf("Живёт в %.0f км от места работы", km)
+	case in.Location.Remote && in.Location.Relocation != geo.RelocationReady:
+		c.Score = remoteMismatch
+		c.Note = fmt.Sprintf("Ищет удалённую работу, вакансия в офисе в %.0f км", km)
+	default:
+		c.Score, c.Note = relocationFit(in.Location, place, km)
+	}
+	c.Matched = c.Score >= matchThreshold
+	return c, true
+}
+
+// relocationFit оценка для кандидата из другого города
+func relocationFit(loc geo.Location, place geo.City, km float64) (float64, string) {
+	distance := fmt.Sprintf("%.0f км от места работы", km)
+	switch loc.Relocation {
+	case geo.RelocationReady:
+		if len(loc.RelocationCities) == 0 || slices.Contains(loc.RelocationCities, place.Name) {
+			return 0.9, "Готов к переезду, " + distance
+		}
+		return 0.5, "Готов к переезду в другие города, " + distance
+	case geo.RelocationNotReady:
+		return 0.1, "Не готов к переезду, " + distance
+	default:
+		return 0.3, "Готовность к переезду не указана, " + distance
+	}
+}
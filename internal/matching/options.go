@@ -0,0 +1,63 @@
+package matching
+
+import (
+	"math"
+	"time"
+
+	"github.com/moverq1337/VTBHack/internal/config"
+)
+
+// Options настройки оценки
+type Options struct {
+	Decay Decay
+}
+
+// Decay затухание зачёта навыка с давности последнего использования.
+// Зачёт = max(Floor, 0.5^((давность - Grace) / HalfLife)).
+type Decay struct {
+	HalfLife float64 // Лет, за которые зачёт падает вдвое; 0 отключает затухание
+	Grace    float64 // Лет после последнего использования без затухания
+	Floor    float64 // Минимальная доля зачёта
+	Unknown  float64 // Доля зачёта навыка без периодов работы; 0 - без затухания
+}
+
+// DefaultOptions настройки по умолчанию
+var DefaultOptions = Options{
+	Decay: Decay{HalfLife: 4, Grace: 2, Floor: 0.3, Unknown: 0.8},
+}
+
+// OptionsFrom собирает настройки оценки из конфигурации сервиса
+func OptionsFrom(cfg *config.Config) Options {
+	return Options{
+		Decay: Decay{
+			HalfLife: cfg.RecencyHalfLife,
+			Grace:    cfg.RecencyGrace,
+			Floor:    cfg.RecencyFloor,
+			Unknown:  cfg.RecencyUnknown,
+		},
+	}
+}
+
+// Factor возвращает долю зачёта навыка, который последний раз использовался yearsAgo лет назад
+func (d Decay) Factor(yearsAgo float64) float64 {
+	if d.HalfLife <= 0 || yearsAgo <= d.Grace {
+		return 1
+	}
+	return math.Max(d.Floor, math.Pow(0.5, (yearsAgo-d.Grace)/d.HalfLife))
+}
+
+// UnknownFactor возвращает долю зачёта навыка, который не встречается в
+// периодах работы (например, только в перечне навыков): когда он
+// использовался, неизвестно
+func (d Decay) UnknownFactor() float64 {
+	if d.HalfLife <= 0 || d.Unknown <= 0 {
+		return 1
+	}
+	return math.Min(1, math.Max(d.Floor, d.Unknown))
+}
+
+// yearsSince давность в годах от месяца last до текущего месяца
+func yearsSince(last, now time.Time) float64 {
+	months := (now.Year()-last.Year())*12 + int(now.Month()) - int(last.Month())
+	return math.Max(0, float64(months)/12)
+}
@@ -0,0 +1,107 @@
+package matching
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/moverq1337/VTBHack/internal/requirements"
+)
+
+// skillLevelYears стаж использования навыка, ожидаемый для уровня владения
+var skillLevelYears = map[string]float64{
+	requirements.SkillConfident: 1,
+	requirements.SkillExpert:    3,
+}
+
+// itemCriterion оценивает пункт требований в зависимости от его типа
+func (e *Engine) itemCriterion(doc *resumeDoc, in Input, item requirements.Item) Criterion {
+	switch item.Kind {
+	case requirements.KindSkill:
+		c := e.skillCriterion(doc, item.Name)
+		applySkillLevel(&c, item.Level)
+		return c
+	case requirements.KindExperience:
+		return experienceItem(doc, in, item)
+	case requirements.KindEducation:
+		if item.Rank() > 0 {
+			return educationCriterion(doc, item)
+		}
+	case requirements.KindLanguage:
+		return languageCriterion(doc, item)
+	}
+	return e.requirementCriterion(doc, item.Text)
+}
+
+// applySkillLevel снижает зачёт навыка, если по истории работы он
+// использовался меньше, чем ожидается для требуемого уровня
+func applySkillLevel(c *Criterion, level string) {
+	need := skillLevelYears[level]
+	if need == 0 || c.Score == 0 || c.SkillYears == 0 || c.SkillYears >= need {
+		return
+	}
+	factor := math.Max(0.5, c.SkillYears/need)
+	c.Score *= factor
+	c.Matched = c.Score >= matchThreshold
+	c.Note = joinNotes(c.Note, fmt.Sprintf("Для уровня %s ожидается от %.0f лет использования, в истории работы %.1f", level, need, c.SkillYears))
+}
+
+// experienceItem сравнивает стаж с требуемым. Если в пункте назван навык
+// ("Опыт разработки на Go от 3 лет"), берётся стаж использования навыка.
+func experienceItem(doc *resumeDoc, in Input, item requirements.Item) Criterion {
+	if item.Name != "" {
+		if _, years, ok := doc.history.SkillUsed(item.Name); ok {
+			c := experienceCriterion(years, item.Years, item.Text)
+			c.ResumeValue = fmt.Sprintf("%.1f лет с %s", years, item.Name)
+			return c
+		}
+	}
+	return experienceCriterion(in.ResumeYears, item.Years, item.Text)
+}
+
+// educationCriterion сравнивает высший уровень образования в резюме с требуемым
+func educationCriterion(doc *resumeDoc, item requirements.Item) Criterion {
+	c := Criterion{Category: CategoryRequirement, Requirement: item.Text}
+	have := requirements.Education(doc.text)
+	c.ResumeValue = have
+
+	gap := item.Rank() - requirements.EducationRank(have)
+	switch {
+	case have == "":
+		c.Note = "Образование в резюме не найдено"
+	case gap <= 0:
+		c.Score = 1
+	case gap == 1:
+		c.Score = 0.6
+	default:
+		c.Score = 0.2
+	}
+	c.Matched = c.Score >= matchThreshold
+	return c
+}
+
+// languageCriterion ищет язык в резюме и сравнивает уровень по шкале CEFR
+func languageCriterion(doc *resumeDoc, item requirements.Item) Criterion {
+	c := Criterion{Category: CategoryRequirement, Requirement: item.Text}
+	level, found := requirements.Language(doc.text, item.Name)
+	if !found {
+		c.Note = "Язык в резюме не упомянут"
+		return c
+	}
+
+	c.ResumeValue = item.Name
+	if level != "" {
+		c.ResumeValue += " " + level
+	}
+	need, have := item.Rank(), requirements.LanguageRank(level)
+	switch {
+	case need == 0 || have >= need:
+		c.Score = 1
+	case have == 0:
+		c.Score = 0.7
+		c.Note = "Уровень языка в резюме не указан"
+	default:
+		c.Score = clamp(1 - 0.25*float64(need-have))
+	}
+	c.Matched = c.Score >= matchThreshold
+	return c
+}
@@ -0,0 +1,84 @@
+package matching
+
+import (
+	"fmt"
+
+	"github.com/moverq1337/VTBHack/internal/salary"
+)
+
+// openRange запас над нижней границей вилки, если верхняя не указана: "от 200 000"
+const openRange = 1.2
+
+// salaryCriterion сравнивает ожидания кандидата с вилкой вакансии. Обе суммы
+// пересчитываются в рубли до вычета налогов по таблице курсов. Ожидания
+// в пределах вилки и ниже засчитываются полностью, выше - с убыванием:
+// на 50% выше верхней границы - ноль. Критерий не строится, если ожидания
+// или вилка не указаны.
+func salaryCriterion(in Input) (Criterion, bool) {
+	v := in.Vacancy
+	if in.Salary.Value <= 0 || (v.SalaryMin <= 0 && v.SalaryMax <= 0) {
+		return Criterion{}, false
+	}
+	rates := in.Rates
+	if rates == nil {
+		rates = salary.DefaultRates
+	}
+
+	expect, ok := rates.GrossRUB(in.Salary)
+	if !ok {
+		return Criterion{}, false
+	}
+	if _, ok := rates.Rate(currencyOr(v.SalaryCurrency)); !ok {
+		return Criterion{}, false
+	}
+	bound := func(value int) float64 {
+		rub, _ := rates.GrossRUB(salary.Amount{Value: value, Currency: v.SalaryCurrency, Basis: salary.Basis(v.SalaryBasis)})
+		return rub
+	}
+	lower, upper := bound(v.SalaryMin), bound(v.SalaryMax)
+	if upper <= 0 {
+		upper = lower * openRange
+	}
+
+	c := Criterion{
+		Category:    CategorySalary,
+		Requirement: "Зарплата: " + salaryRange(v.SalaryMin, v.SalaryMax, currencyOr(v.SalaryCurrency)),
+		ResumeValue: fmt.Sprintf("%d %s", in.Salary.Value, currencyOr(in.Salary.Currency)),
+		Score:       1,
+	}
+	if in.Salary.Basis == salary.BasisNet {
+		c.ResumeValue += " на руки"
+	}
+	if currencyOr(in.Salary.Currency) != currencyOr(v.SalaryCurrency) {
+		c.Note = fmt.Sprintf("Ожидания в пересчёте: %.0f RUB до вычета налогов", expect)
+	}
+
+	switch over := expect/upper - 1; {
+	case over > 0:
+		c.Score = clamp(1 - 2*over)
+		c.Note = joinNotes(c.Note, fmt.Sprintf("Ожидания выше вилки на %.0f%%", over*100))
+	case lower > 0 && expect < lower:
+		c.Note = joinNotes(c.Note, "Ожидания ниже вилки")
+	}
+	c.Matched = c.Score >= matchThreshold
+	return c, true
+}
+
+func currencyOr(currency string) string {
+	if currency == "" {
+		return salary.RUB
+	}
+	return currency
+}
+
+// salaryRange вилка в виде "от 200000 до 250000 RUB"
+func salaryRange(min, max int, currency string) string {
+	switch {
+	case min > 0 && max > 0:
+		return fmt.Sprintf("от %d до %d %s", min, max, currency)
+	case min > 0:
+		return fmt.Sprintf("от %d %s", min, currency)
+	default:
+		return fmt.Sprintf("до %d %s", max, currency)
+	}
+}
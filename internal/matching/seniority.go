@@ -0,0 +1,105 @@
+package matching
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/moverq1337/VTBHack/internal/timeline"
+	"github.com/moverq1337/VTBHack/internal/titles"
+)
+
+// seniorityCriterion сравнивает уровень кандидата с уровнем вакансии.
+// Уровень кандидата берётся из последней должности, а если в ней он не
+// указан - оценивается по стажу. Критерий не строится, если уровень
+// вакансии или кандидата определить не удалось.
+func seniorityCriterion(doc *resumeDoc, in Input) (Criterion, bool) {
+	vacancy := titles.Normalize(in.Vacancy.Title)
+	if level, ok := titles.ParseLevel(in.Vacancy.Seniority); ok {
+		vacancy.Seniority = level
+	}
+	if in.Vacancy.RoleFamily != "" {
+		vacancy.Family = in.Vacancy.RoleFamily
+	}
+	if vacancy.Seniority == "" {
+		return Criterion{}, false
+	}
+
+	c := Criterion{Category: CategorySeniority, Requirement: "Уровень: " + string(vacancy.Seniority)}
+
+	var candidate titles.Title
+	if p, ok := latestPeriod(in.History); ok {
+		candidate = titles.ForPeriod(p)
+		if candidate.Seniority != "" {
+			c.ResumeValue = fmt.Sprintf("%s (%s)", candidate.Seniority, positionName(p))
+			if span, ok := titleSpan(doc, p); ok {
+				c.Evidence = []Span{span}
+			}
+		}
+	}
+	if candidate.Seniority == "" {
+		if in.ResumeYears <= 0 {
+			return Criterion{}, false
+		}
+		candidate.Seniority = titles.LevelForYears(in.ResumeYears)
+		c.ResumeValue = string(candidate.Seniority)
+		c.Note = fmt.Sprintf("Уровень оценён по стажу %.1f лет", in.ResumeYears)
+	}
+
+	c.Score = seniorityFit(candidate.Seniority, vacancy.Seniority)
+	c.Matched = c.Score >= matchThreshold
+	if vacancy.Family != "" && candidate.Family != "" && vacancy.Family != candidate.Family {
+		c.Note = joinNotes(c.Note, fmt.Sprintf("Направление кандидата (%s) отличается от вакансии (%s)",
+			titles.FamilyName(candidate.Family), titles.FamilyName(vacancy.Family)))
+	}
+	return c, true
+}
+
+// seniorityFit оценивает соответствие уровней. Недостающий уровень
+// штрафуется сильнее, чем избыточный: senior на позиции middle
+// справится, но может быстро уйти.
+func seniorityFit(have, need titles.Level) float64 {
+	diff := float64(have.Rank() - need.Rank())
+	switch {
+	case diff == 0:
+		return 1
+	case diff > 0:
+		return math.Max(0.4, 1-0.3*diff)
+	default:
+		return clamp(1 + 0.5*diff)
+	}
+}
+
+// latestPeriod последний период работы: текущий или с самым поздним окончанием
+func latestPeriod(t timeline.Timeline) (timeline.Period, bool) {
+	var latest timeline.Period
+	found := false
+	for _, p := range t.Periods {
+		if !found || p.End.After(latest.End) || (p.End.Equal(latest.End) && p.Start.After(latest.Start)) {
+			latest, found = p, true
+		}
+	}
+	return latest, found
+}
+
+func positionName(p timeline.Period) string {
+	if titles.Normalize(p.Title).Known() || p.Company == "" {
+		return p.Title
+	}
+	return p.Company
+}
+
+// titleSpan находит строку с должностью в блоке периода
+func titleSpan(doc *resumeDoc, p timeline.Period) (Span, bool) {
+	name := positionName(p)
+	if name == "" {
+		return Span{}, false
+	}
+	block := doc.slice(p.SpanStart, p.SpanEnd)
+	i := strings.Index(block, name)
+	if i < 0 {
+		return Span{}, false
+	}
+	start := p.SpanStart + len([]rune(block[:i]))
+	return Span{Start: start, End: start + len([]rune(name)), Text: name}, true
+}
@@ -0,0 +1,402 @@
+// Package mobility хранит профили сотрудников из HR-системы, оценивает их
+// для открытых вакансий тем же движком, что и резюме, и собирает
+// внутренний шорт-лист с учётом согласий сотрудников.
+package mobility
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/geo"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"github.com/moverq1337/VTBHack/internal/titles"
+	"gorm.io/gorm"
+)
+
+// MaxRows предел строк одной выгрузки
+const MaxRows = 10000
+
+// ErrTooManyRows выгрузка больше MaxRows строк
+var ErrTooManyRows = fmt.Errorf("в выгрузке больше %d строк", MaxRows)
+
+// Row строка выгрузки HR-системы. Поля CSV называются так же, как ключи JSON.
+// Согласия, не указанные в выгрузке, у существующих сотрудников не меняются,
+// у новых считаются не данными.
+type Row struct {
+	ExternalID        string  `json:"external_id"`
+	FullName          string  `json:"full_name"`
+	Email             string  `json:"email"`
+	Department        string  `json:"department"`
+	Title             string  `json:"title"`
+	City              string  `json:"city"`
+	Relocation        string  `json:"relocation"`
+	Skills            string  `json:"skills"`
+	Experience        float64 `json:"experience_years"`
+	HiredAt           string  `json:"hired_at"` // 2006-01-02
+	Summary           string  `json:"summary"`
+	OpenToMove        *bool   `json:"open_to_move"`
+	ShareWithManagers *bool   `json:"share_with_managers"`
+	Active            *bool   `json:"active"` // По умолчанию true
+}
+
+// RowError ошибка в строке выгрузки; Line считается с 1 без заголовка
+type RowError struct {
+	Line       int    `json:"line"`
+	ExternalID string `json:"external_id,omitempty"`
+	Error      string `json:"error"`
+}
+
+// Result итог импорта
+type Result struct {
+	Created int         `json:"created"`
+	Updated int         `json:"updated"`
+	Skipped int         `json:"skipped"`
+	Errors  []RowError  `json:"errors"`
+	IDs     []uuid.UUID `json:"-"` // Созданные и изменённые сотрудники
+}
+
+// ParseJSON читает выгрузку: массив строк или {"employees": [...]}.
+// Элемент с неверными типами значений не срывает всю выгрузку: он
+// возвращается пустой строкой с RowError, как в ParseCSV.
+func ParseJSON(r io.Reader) ([]Row, []RowError, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		var wrapped struct {
+			Employees []json.RawMessage `json:"employees"`
+		}
+		if json.Unmarshal(data, &wrapped) != nil {
+			return nil, nil, fmt.Errorf("неверный JSON: %w", err)
+		}
+		elements = wrapped.Employees
+	}
+	if len(elements) > MaxRows {
+		return nil, nil, ErrTooManyRows
+	}
+
+	rows := make([]Row, 0, len(elements))
+	var rowErrors []RowError
+	for i, element := range elements {
+		var row Row
+		if err := json.Unmarshal(element, &row); err != nil {
+			rowErrors = append(rowErrors, RowError{Line: i + 1, ExternalID: externalID(element), Error: jsonProblem(err)})
+			rows = append(rows, Row{})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrors, nil
+}
+
+// externalID достаёт табельный номер из элемента, который не разобрался целиком
+func externalID(element json.RawMessage) string {
+	var probe struct {
+		ExternalID json.RawMessage `json:"external_id"`
+	}
+	if json.Unmarshal(element, &probe) != nil || len(probe.ExternalID) == 0 {
+		return ""
+	}
+	var id string
+	if json.Unmarshal(probe.ExternalID, &id) == nil {
+		return strings.TrimSpace(id)
+	}
+	return strings.Trim(string(probe.ExternalID), `"`)
+}
+
+// jsonProblem описывает ошибку разбора элемента выгрузки
+func jsonProblem(err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field == "" {
+			return "ожидается объект сотрудника"
+		}
+		return typeErr.Field + ": неверный тип значения"
+	}
+	return err.Error()
+}
+
+// ParseCSV читает выгрузку с заголовком. Разделитель - запятая или точка с
+// запятой, как в выгрузках Excel. Неизвестные столбцы пропускаются. Строки
+// с ошибками возвращаются пустыми, чтобы номера строк совпадали с RowError.
+func ParseCSV(r io.Reader) ([]Row, []RowError, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	header, _, _ := strings.Cut(text, "\n")
+
+	reader := csv.NewReader(strings.NewReader(text))
+	if strings.Count(header, ";") > strings.Count(header, ",") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns, err := reader.Read()
+	if err != nil {
+		return nil, nil, errors.New("пустая выгрузка")
+	}
+	index := map[string]int{}
+	for i, name := range columns {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := index["external_id"]; !ok {
+		return nil, nil, errors.New("нет столбца external_id")
+	}
+
+	var rows []Row
+	var rowErrors []RowError
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("строка %d: %w", line, err)
+		}
+		if len(rows) >= MaxRows {
+			return nil, nil, ErrTooManyRows
+		}
+		get := func(name string) string {
+			if i, ok := index[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := Row{
+			ExternalID: get("external_id"),
+			FullName:   get("full_name"),
+			Email:      get("email"),
+			Department: get("department"),
+			Title:      get("title"),
+			City:       get("city"),
+			Relocation: get("relocation"),
+			Skills:     get("skills"),
+			HiredAt:    get("hired_at"),
+			Summary:    get("summary"),
+		}
+		var problems []string
+		if v := get("experience_years"); v != "" {
+			years, err := strconv.ParseFloat(strings.Replace(v, ",", ".", 1), 64)
+			if err != nil {
+				problems = append(problems, "experience_years: ожидается число")
+			}
+			row.Experience = years
+		}
+		for _, flag := range []struct {
+			name string
+			dst  **bool
+		}{
+			{"open_to_move", &row.OpenToMove},
+			{"share_with_managers", &row.ShareWithManagers},
+			{"active", &row.Active},
+		} {
+			v, ok, err := parseFlag(get(flag.name))
+			if err != nil {
+				problems = append(problems, flag.name+": "+err.Error())
+			} else if ok {
+				*flag.dst = &v
+			}
+		}
+		if len(problems) > 0 {
+			rowErrors = append(rowErrors, RowError{Line: line, ExternalID: row.ExternalID, Error: strings.Join(problems, "; ")})
+			rows = append(rows, Row{})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrors, nil
+}
+
+// parseFlag разбирает согласие: пустое значение - не указано
+func parseFlag(v string) (value, ok bool, err error) {
+	switch strings.ToLower(v) {
+	case "":
+		return false, false, nil
+	case "true", "1", "yes", "y", "да":
+		return true, true, nil
+	case "false", "0", "no", "n", "нет":
+		return false, true, nil
+	}
+	return false, false, errors.New("ожидается да или нет")
+}
+
+// Import сохраняет сотрудников по табельному номеру: новые создаются,
+// существующие обновляются. Навыки приводятся к таксономии, должность - к
+// направлению и уровню, город - к справочнику. Сотрудник связывается с
+// кандидатом с той же почтой. Строки с ошибками пропускаются.
+// Номера строк в preErrors (ошибки разбора) учитываются как пропущенные.
+func Import(db *gorm.DB, t *taxonomy.Taxonomy, rows []Row, preErrors []RowError) (Result, error) {
+	valid, result := check(rows, preErrors)
+	if len(valid) == 0 {
+		return result, nil
+	}
+	now := time.Now()
+
+	ids := make([]string, len(valid))
+	emails := []string{}
+	for i, row := range valid {
+		ids[i] = row.ExternalID
+		if email := strings.ToLower(strings.TrimSpace(row.Email)); email != "" {
+			emails = append(emails, email)
+		}
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var existing []models.Employee
+		if err := tx.Where("external_id IN ?", ids).Find(&existing).Error; err != nil {
+			return err
+		}
+		byExternal := make(map[string]models.Employee, len(existing))
+		for _, e := range existing {
+			byExternal[e.ExternalID] = e
+		}
+
+		candidates := map[string]uuid.UUID{}
+		if len(emails) > 0 {
+			var found []models.Candidate
+			if err := tx.Select("id", "email").Where("LOWER(email) IN ?", emails).Find(&found).Error; err != nil {
+				return err
+			}
+			for _, c := range found {
+				candidates[strings.ToLower(c.Email)] = c.ID
+			}
+		}
+
+		var created []models.Employee
+		for _, row := range valid {
+			e, ok := byExternal[row.ExternalID]
+			if !ok {
+				e = models.Employee{ID: uuid.New(), ExternalID: row.ExternalID, Active: true}
+			}
+			apply(&e, row, t)
+			e.ImportedAt = now
+			e.CandidateID = nil
+			if id, ok := candidates[strings.ToLower(e.Email)]; ok {
+				e.CandidateID = &id
+			}
+			result.IDs = append(result.IDs, e.ID)
+			if !ok {
+				created = append(created, e)
+				continue
+			}
+			if err := tx.Select("*").Omit("CreatedAt").Updates(&e).Error; err != nil {
+				return err
+			}
+			result.Updated++
+		}
+		if len(created) > 0 {
+			if err := tx.CreateInBatches(created, 500).Error; err != nil {
+				return err
+			}
+		}
+		result.Created = len(created)
+		return nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
+
+// check отбирает строки для сохранения: без ошибок разбора, прошедшие
+// validate и с табельным номером, не встречавшимся выше. Остальные
+// записываются в ошибки результата.
+func check(rows []Row, preErrors []RowError) ([]Row, Result) {
+	result := Result{Errors: []RowError{}}
+	failed := map[int]bool{}
+	for _, e := range preErrors {
+		failed[e.Line] = true
+		result.Errors = append(result.Errors, e)
+	}
+
+	seen := map[string]int{}
+	var valid []Row
+	for i, row := range rows {
+		line := i + 1
+		if failed[line] {
+			continue
+		}
+		row.ExternalID = strings.TrimSpace(row.ExternalID)
+		if err := validate(&row); err != nil {
+			result.Errors = append(result.Errors, RowError{Line: line, ExternalID: row.ExternalID, Error: err.Error()})
+			continue
+		}
+		if prev, ok := seen[row.ExternalID]; ok {
+			result.Errors = append(result.Errors, RowError{Line: line, ExternalID: row.ExternalID, Error: fmt.Sprintf("повтор строки %d", prev)})
+			continue
+		}
+		seen[row.ExternalID] = line
+		valid = append(valid, row)
+	}
+	result.Skipped = len(rows) - len(valid)
+	return valid, result
+}
+
+// validate проверяет обязательные поля и форматы строки
+func validate(row *Row) error {
+	if row.ExternalID == "" {
+		return errors.New("не указан external_id")
+	}
+	if strings.TrimSpace(row.FullName) == "" {
+		return errors.New("не указано full_name")
+	}
+	if row.Experience < 0 {
+		return errors.New("experience_years не может быть отрицательным")
+	}
+	if row.HiredAt != "" {
+		if _, err := time.Parse("2006-01-02", row.HiredAt); err != nil {
+			return errors.New("hired_at: ожидается дата ГГГГ-ММ-ДД")
+		}
+	}
+	switch geo.Relocation(row.Relocation) {
+	case geo.RelocationUnknown, geo.RelocationReady, geo.RelocationNotReady:
+	default:
+		return errors.New("relocation: ожидается ready, not_ready или пустое значение")
+	}
+	return nil
+}
+
+// apply переносит строку выгрузки в профиль сотрудника
+func apply(e *models.Employee, row Row, t *taxonomy.Taxonomy) {
+	e.FullName = strings.TrimSpace(row.FullName)
+	e.Email = strings.TrimSpace(row.Email)
+	e.Department = strings.TrimSpace(row.Department)
+	e.Title = strings.TrimSpace(row.Title)
+	title := titles.Normalize(e.Title)
+	e.RoleFamily, e.Seniority = title.Family, string(title.Seniority)
+	e.City, e.Region = strings.TrimSpace(row.City), ""
+	if city, ok := geo.LookupCity(e.City); ok {
+		e.City, e.Region = city.Name, city.Region
+	}
+	e.Relocation = row.Relocation
+	e.Skills = t.NormalizeList(row.Skills)
+	e.Experience = row.Experience
+	e.HiredAt = nil
+	if hired, err := time.Parse("2006-01-02", row.HiredAt); err == nil {
+		e.HiredAt = &hired
+	}
+	e.Summary = strings.TrimSpace(row.Summary)
+	if row.OpenToMove != nil {
+		e.OpenToMove = *row.OpenToMove
+	}
+	if row.ShareWithManagers != nil {
+		e.ShareWithManagers = *row.ShareWithManagers
+	}
+	if row.Active != nil {
+		e.Active = *row.Active
+	}
+}
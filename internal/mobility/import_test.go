@@ -0,0 +1,184 @@
+package mobility
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"запятая", "external_id,full_name,experience_years,open_to_move\nE1,Иван Петров,\"3,5\",да\n"},
+		{"точка с запятой и BOM", "\ufeffExternal_ID; Full_Name; experience_years; open_to_move; unknown\nE1; Иван Петров; 3.5; yes; x\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, rowErrors, err := ParseCSV(strings.NewReader(tt.input))
+			if err != nil || len(rowErrors) != 0 {
+				t.Fatalf("ParseCSV: %v, %v", err, rowErrors)
+			}
+			if len(rows) != 1 {
+				t.Fatalf("строк %d, want 1", len(rows))
+			}
+			row := rows[0]
+			if row.ExternalID != "E1" || row.FullName != "Иван Петров" || row.Experience != 3.5 {
+				t.Errorf("row = %+v", row)
+			}
+			if row.OpenToMove == nil || !*row.OpenToMove || row.ShareWithManagers != nil || row.Active != nil {
+				t.Errorf("согласия = %v, %v, %v", row.OpenToMove, row.ShareWithManagers, row.Active)
+			}
+		})
+	}
+}
+
+func TestParseCSVRowErrors(t *testing.T) {
+	input := "external_id,full_name,experience_years,share_with_managers,active\n" +
+		"E1,Анна,много,,\n" +
+		"E2,Борис,2,нет,0\n" +
+		"E3,Вера,,может быть,\n"
+	rows, rowErrors, err := ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0] != (Row{}) || rows[2] != (Row{}) {
+		t.Fatalf("строки с ошибками должны остаться пустыми на своих местах: %+v", rows)
+	}
+	if rows[1].ShareWithManagers == nil || *rows[1].ShareWithManagers || rows[1].Active == nil || *rows[1].Active {
+		t.Errorf("согласия E2 = %v, %v", rows[1].ShareWithManagers, rows[1].Active)
+	}
+	want := []RowError{
+		{Line: 1, ExternalID: "E1", Error: "experience_years: ожидается число"},
+		{Line: 3, ExternalID: "E3", Error: "share_with_managers: ожидается да или нет"},
+	}
+	if !reflect.DeepEqual(rowErrors, want) {
+		t.Errorf("ошибки = %+v, want %+v", rowErrors, want)
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	for name, input := range map[string]string{
+		"пусто":              "",
+		"нет external_id":    "full_name\nИван\n",
+		"незакрытая кавычка": "external_id,full_name\nE1,\"Иван\n",
+	} {
+		if _, _, err := ParseCSV(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: нет ошибки", name)
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	inputs := map[string]string{
+		"массив":  `[{"external_id": "E1", "full_name": "Анна", "open_to_move": true}, {"external_id": "E2", "full_name": "Борис"}]`,
+		"обёртка": `{"employees": [{"external_id": "E1", "full_name": "Анна", "open_to_move": true}, {"external_id": "E2", "full_name": "Борис"}]}`,
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			rows, rowErrors, err := ParseJSON(strings.NewReader(input))
+			if err != nil || len(rowErrors) != 0 {
+				t.Fatalf("ParseJSON: %v, %v", err, rowErrors)
+			}
+			if len(rows) != 2 || rows[0].ExternalID != "E1" || rows[0].OpenToMove == nil || !*rows[0].OpenToMove || rows[1].FullName != "Борис" {
+				t.Errorf("rows = %+v", rows)
+			}
+		})
+	}
+}
+
+func TestParseJSONRowErrors(t *testing.T) {
+	input := `[
+		{"external_id": "E1", "full_name": "Анна", "experience_years": "пять"},
+		{"external_id": "E2", "full_name": "Борис"},
+		{"external_id": 3, "full_name": "Вера"},
+		{"external_id": "E4", "full_name": "Глеб", "open_to_move": "да"},
+		"E5"
+	]`
+	rows, rowErrors, err := ParseJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 || rows[1].ExternalID != "E2" {
+		t.Fatalf("rows = %+v", rows)
+	}
+	for _, i := range []int{0, 2, 3, 4} {
+		if rows[i] != (Row{}) {
+			t.Errorf("строка %d с ошибкой не пустая: %+v", i+1, rows[i])
+		}
+	}
+	want := []RowError{
+		{Line: 1, ExternalID: "E1", Error: "experience_years: неверный тип значения"},
+		{Line: 3, ExternalID: "3", Error: "external_id: неверный тип значения"},
+		{Line: 4, ExternalID: "E4", Error: "open_to_move: неверный тип значения"},
+		{Line: 5, Error: "ожидается объект сотрудника"},
+	}
+	if !reflect.DeepEqual(rowErrors, want) {
+		t.Errorf("ошибки =\n%+v\nwant\n%+v", rowErrors, want)
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	for _, input := range []string{"", "{", `{"employees": 5}`, `"строка"`} {
+		if _, _, err := ParseJSON(strings.NewReader(input)); err == nil {
+			t.Errorf("%q: нет ошибки", input)
+		}
+	}
+
+	many := "[" + strings.Repeat("{},", MaxRows) + "{}]"
+	if _, _, err := ParseJSON(strings.NewReader(many)); !errors.Is(err, ErrTooManyRows) {
+		t.Errorf("больше MaxRows: %v", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rows := []Row{
+		{},
+		{ExternalID: " E1 ", FullName: "Анна", HiredAt: "01.02.2020"},
+		{FullName: "Без номера"},
+		{ExternalID: "E2"},
+		{ExternalID: "E3", FullName: "Вера", Experience: -1},
+		{ExternalID: "E4", FullName: "Глеб", Relocation: "maybe"},
+		{ExternalID: " E5", FullName: "Дина", HiredAt: "2020-02-01", Relocation: "ready"},
+		{ExternalID: "E5 ", FullName: "Дина Иванова"},
+		{ExternalID: "E6", FullName: "Егор"},
+	}
+	preErrors := []RowError{{Line: 1, ExternalID: "E0", Error: "experience_years: ожидается число"}}
+
+	valid, result := check(rows, preErrors)
+	var ids []string
+	for _, row := range valid {
+		ids = append(ids, row.ExternalID)
+	}
+	if !reflect.DeepEqual(ids, []string{"E5", "E6"}) || valid[0].FullName != "Дина" {
+		t.Errorf("valid = %+v", valid)
+	}
+	if result.Skipped != 7 {
+		t.Errorf("Skipped = %d, want 7", result.Skipped)
+	}
+	want := []RowError{
+		preErrors[0],
+		{Line: 2, ExternalID: "E1", Error: "hired_at: ожидается дата ГГГГ-ММ-ДД"},
+		{Line: 3, Error: "не указан external_id"},
+		{Line: 4, ExternalID: "E2", Error: "не указано full_name"},
+		{Line: 5, ExternalID: "E3", Error: "experience_years не может быть отрицательным"},
+		{Line: 6, ExternalID: "E4", Error: "relocation: ожидается ready, not_ready или пустое значение"},
+		{Line: 8, ExternalID: "E5", Error: "повтор строки 7"},
+	}
+	if !reflect.DeepEqual(result.Errors, want) {
+		t.Errorf("ошибки =\n%+v\nwant\n%+v", result.Errors, want)
+	}
+}
+
+// TestImportNothingValid без строк для сохранения импорт не обращается к БД
+func TestImportNothingValid(t *testing.T) {
+	result, err := Import(nil, nil, []Row{{ExternalID: "E1"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Skipped != 1 || len(result.Errors) != 1 || result.Created != 0 || len(result.IDs) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+}
@@ -0,0 +1,266 @@
+package mobility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/matching"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/timeline"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var log = logrus.New()
+
+// batchSize сотрудников в одном запросе к NLP-сервису
+const batchSize = 200
+
+// jobTimeout ограничивает один проход оценки
+const jobTimeout = time.Hour
+
+// Score оценка сотрудника для вакансии; Err - оценить не удалось
+type Score struct {
+	Explanation matching.Explanation
+	Degraded    bool
+	Err         error
+}
+
+// Scorer оценивает сотрудников для вакансии. Результат идёт в порядке employees.
+type Scorer func(ctx context.Context, vacancy models.Vacancy, employees []models.Employee) []Score
+
+// Text собирает текст профиля для сопоставления так же, как текст резюме:
+// должность, подразделение, навыки, стаж и описание опыта
+func Text(e models.Employee) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", e.FullName)
+	if e.Title != "" {
+		fmt.Fprintf(&b, "Должность: %s\n", e.Title)
+	}
+	if e.Department != "" {
+		fmt.Fprintf(&b, "Подразделение: %s\n", e.Department)
+	}
+	if e.City != "" {
+		fmt.Fprintf(&b, "Город: %s\n", e.City)
+	}
+	if e.Experience > 0 {
+		fmt.Fprintf(&b, "Опыт работы: %.1f лет\n", e.Experience)
+	}
+	if e.Skills != "" {
+		fmt.Fprintf(&b, "Навыки: %s\n", e.Skills)
+	}
+	if e.Summary != "" {
+		fmt.Fprintf(&b, "\n%s\n", e.Summary)
+	}
+	return b.String()
+}
+
+// Timeline строит историю работы из профиля сотрудника: текущая должность
+// в банке с даты приёма и предыдущий опыт - стаж из профиля сверх работы
+// в банке, вплотную до приёма (без даты приёма - до текущего месяца).
+// Навыки профиля считаются используемыми в обоих периодах: так работает
+// затухание давно не используемых навыков и стаж по навыку.
+func Timeline(e models.Employee, now time.Time) timeline.Timeline {
+	var skills []string
+	for _, s := range strings.Split(e.Skills, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			skills = append(skills, s)
+		}
+	}
+	month := func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC) }
+
+	var periods []timeline.Period
+	priorEnd := month(now)
+	tenure := 0
+	if e.HiredAt != nil && !e.HiredAt.After(now) {
+		bank := timeline.Period{
+			Start:   month(*e.HiredAt),
+			End:     month(now),
+			Current: true,
+			Company: e.Department,
+			Title:   e.Title,
+			Skills:  skills,
+		}
+		periods = append(periods, bank)
+		priorEnd = bank.Start.AddDate(0, -1, 0)
+		tenure = bank.Months()
+	}
+	if prior := int(math.Round(e.Experience*12)) - tenure; prior > 0 {
+		periods = append(periods, timeline.Period{
+			Start:   priorEnd.AddDate(0, 1-prior, 0),
+			End:     priorEnd,
+			Current: tenure == 0,
+			Skills:  skills,
+		})
+	}
+	return timeline.Build(periods)
+}
+
+// Years общий стаж: из профиля или по истории в банке, если она больше
+func Years(e models.Employee, t timeline.Timeline) float64 {
+	return max(e.Experience, t.Years())
+}
+
+// Runner оценивает сотрудников в фоне. Запросы на одну вакансию или одного
+// сотрудника, пришедшие до начала прохода, объединяются.
+type Runner struct {
+	db        *gorm.DB
+	newScorer func() Scorer // Оценщик на текущем снимке таксономии
+
+	mu        sync.Mutex
+	vacancies map[uuid.UUID]bool
+	employees map[uuid.UUID]bool
+	wake      chan struct{}
+}
+
+// NewRunner запускает фоновую оценку
+func NewRunner(db *gorm.DB, newScorer func() Scorer) *Runner {
+	r := &Runner{
+		db:        db,
+		newScorer: newScorer,
+		vacancies: map[uuid.UUID]bool{},
+		employees: map[uuid.UUID]bool{},
+		wake:      make(chan struct{}, 1),
+	}
+	go r.loop()
+	return r
+}
+
+// Vacancy ставит в очередь оценку всех активных сотрудников для вакансии.
+// Для неопубликованной вакансии оценки удаляются.
+func (r *Runner) Vacancy(id uuid.UUID) {
+	r.mu.Lock()
+	r.vacancies[id] = true
+	r.mu.Unlock()
+	r.signal()
+}
+
+// Employees ставит в очередь оценку сотрудников для опубликованных
+// вакансий. Оценки неактивных сотрудников удаляются.
+func (r *Runner) Employees(ids []uuid.UUID) {
+	r.mu.Lock()
+	for _, id := range ids {
+		r.employees[id] = true
+	}
+	r.mu.Unlock()
+	r.signal()
+}
+
+func (r *Runner) signal() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (r *Runner) loop() {
+	for range r.wake {
+		r.mu.Lock()
+		vacancies, employees := r.vacancies, r.employees
+		r.vacancies, r.employees = map[uuid.UUID]bool{}, map[uuid.UUID]bool{}
+		r.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), jobTimeout)
+		scorer := r.newScorer()
+		for id := range vacancies {
+			if err := ScoreVacancy(ctx, r.db, scorer, id); err != nil {
+				log.WithError(err).WithField("vacancy_id", id).Error("Ошибка оценки сотрудников для вакансии")
+			}
+		}
+		if len(employees) > 0 {
+			ids := make([]uuid.UUID, 0, len(employees))
+			for id := range employees {
+				ids = append(ids, id)
+			}
+			if err := ScoreEmployees(ctx, r.db, scorer, ids); err != nil {
+				log.WithError(err).Error("Ошибка оценки сотрудников")
+			}
+		}
+		cancel()
+	}
+}
+
+// ScoreVacancy заново оценивает всех активных сотрудников для вакансии
+func ScoreVacancy(ctx context.Context, db *gorm.DB, scorer Scorer, vacancyID uuid.UUID) error {
+	var vacancy models.Vacancy
+	if err := db.Where("id = ?", vacancyID).Limit(1).Find(&vacancy).Error; err != nil {
+		return err
+	}
+	if vacancy.ID == uuid.Nil || vacancy.Status != models.VacancyPublished {
+		return db.Where("vacancy_id = ?", vacancyID).Delete(&models.EmployeeMatch{}).Error
+	}
+
+	var employees []models.Employee
+	return db.Where("active = ?", true).Order("id").FindInBatches(&employees, batchSize, func(tx *gorm.DB, _ int) error {
+		return store(ctx, db, vacancy, employees, scorer(ctx, vacancy, employees))
+	}).Error
+}
+
+// ScoreEmployees заново оценивает сотрудников для всех опубликованных вакансий
+func ScoreEmployees(ctx context.Context, db *gorm.DB, scorer Scorer, ids []uuid.UUID) error {
+	if err := db.Where("employee_id IN (?)", db.Model(&models.Employee{}).Select("id").Where("id IN ? AND active = ?", ids, false)).
+		Delete(&models.EmployeeMatch{}).Error; err != nil {
+		return err
+	}
+
+	var vacancies []models.Vacancy
+	if err := db.Where("status = ?", models.VacancyPublished).Find(&vacancies).Error; err != nil {
+		return err
+	}
+	for start := 0; start < len(ids); start += batchSize {
+		var employees []models.Employee
+		if err := db.Where("id IN ? AND active = ?", ids[start:min(start+batchSize, len(ids))], true).Find(&employees).Error; err != nil {
+			return err
+		}
+		if len(employees) == 0 {
+			continue
+		}
+		for _, vacancy := range vacancies {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := store(ctx, db, vacancy, employees, scorer(ctx, vacancy, employees)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// store сохраняет оценки; неудавшиеся оценки не заменяют прежние
+func store(ctx context.Context, db *gorm.DB, vacancy models.Vacancy, employees []models.Employee, scores []Score) error {
+	now := time.Now()
+	rows := make([]models.EmployeeMatch, 0, len(employees))
+	for i, e := range employees {
+		if scores[i].Err != nil {
+			log.WithError(scores[i].Err).WithField("employee_id", e.ID).Warn("Сотрудник не оценён")
+			continue
+		}
+		explanation, err := json.Marshal(scores[i].Explanation)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, models.EmployeeMatch{
+			EmployeeID:  e.ID,
+			VacancyID:   vacancy.ID,
+			Score:       scores[i].Explanation.Score,
+			Degraded:    scores[i].Degraded,
+			Explanation: string(explanation),
+			ScoredAt:    now,
+		})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "employee_id"}, {Name: "vacancy_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"score", "degraded", "explanation", "scored_at"}),
+	}).Create(&rows).Error
+}
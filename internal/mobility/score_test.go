@@ -0,0 +1,95 @@
+package mobility
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moverq1337/VTBHack/internal/models"
+)
+
+func date(year int, month time.Month) time.Time {
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestTimeline(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	hired := time.Date(2024, time.October, 15, 0, 0, 0, 0, time.UTC)
+	future := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		employee   models.Employee
+		months     int
+		periods    int
+		firstStart time.Time
+		lastUsed   time.Time
+	}{
+		{
+			// 25 месяцев в банке и 35 до него, вплотную до приёма
+			name:       "опыт до банка",
+			employee:   models.Employee{HiredAt: &hired, Experience: 5, Skills: "Go, Kafka"},
+			months:     60,
+			periods:    2,
+			firstStart: date(2021, time.November),
+			lastUsed:   date(2026, time.October),
+		},
+		{
+			name:       "опыт меньше работы в банке",
+			employee:   models.Employee{HiredAt: &hired, Experience: 1, Skills: "Go, Kafka"},
+			months:     25,
+			periods:    1,
+			firstStart: date(2024, time.October),
+			lastUsed:   date(2026, time.October),
+		},
+		{
+			name:       "без даты приёма",
+			employee:   models.Employee{Experience: 2.5, Skills: "Go, Kafka"},
+			months:     30,
+			periods:    1,
+			firstStart: date(2024, time.May),
+			lastUsed:   date(2026, time.October),
+		},
+		{
+			name:       "приём в будущем",
+			employee:   models.Employee{HiredAt: &future, Experience: 1, Skills: "Go, Kafka"},
+			months:     12,
+			periods:    1,
+			firstStart: date(2025, time.November),
+			lastUsed:   date(2026, time.October),
+		},
+		{
+			name:     "нет ни даты, ни опыта",
+			employee: models.Employee{Skills: "Go, Kafka"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := Timeline(tt.employee, now)
+			if history.TotalMonths != tt.months || len(history.Periods) != tt.periods {
+				t.Fatalf("стаж %d мес. в %d периодах, want %d в %d", history.TotalMonths, len(history.Periods), tt.months, tt.periods)
+			}
+			if len(history.Gaps) != 0 {
+				t.Errorf("перерывы = %+v", history.Gaps)
+			}
+			if tt.periods == 0 {
+				return
+			}
+			if !history.Periods[0].Start.Equal(tt.firstStart) {
+				t.Errorf("начало = %v, want %v", history.Periods[0].Start, tt.firstStart)
+			}
+			if !history.Periods[len(history.Periods)-1].Current {
+				t.Error("последний период должен быть текущим")
+			}
+			// Навыки профиля засчитываются на всём стаже
+			for _, skill := range []string{"Go", "Kafka"} {
+				lastUsed, years, ok := history.SkillUsed(skill)
+				if !ok || !lastUsed.Equal(tt.lastUsed) || years != float64(tt.months)/12 {
+					t.Errorf("%s: %v, %.2f лет, %v", skill, lastUsed, years, ok)
+				}
+			}
+			if got := Years(tt.employee, history); got != max(tt.employee.Experience, float64(tt.months)/12) {
+				t.Errorf("Years = %v", got)
+			}
+		})
+	}
+}
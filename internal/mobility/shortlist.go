@@ -0,0 +1,52 @@
+package mobility
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"github.com/moverq1337/VTBHack/internal/config"
+	"gorm.io/gorm"
+)
+
+// Options доступ к данным сотрудников
+type Options struct {
+	HRKey string // Ключ HR: без него профили сотрудников и шорт-лист для HR недоступны
+}
+
+// OptionsFrom собирает настройки доступа из конфигурации сервиса
+func OptionsFrom(cfg *config.Config) Options {
+	return Options{HRKey: cfg.HRAPIKey}
+}
+
+// Configured задан ли ключ HR
+func (o Options) Configured() bool {
+	return o.HRKey != ""
+}
+
+// HR сверяет ключ из запроса с ключом HR. Пока ключ не задан, доступа нет ни у кого.
+func (o Options) HR(key string) bool {
+	return o.Configured() && subtle.ConstantTimeCompare([]byte(key), []byte(o.HRKey)) == 1
+}
+
+// Кто смотрит внутренний шорт-лист
+const (
+	AudienceManager = "manager" // Нанимающий руководитель
+	AudienceHR      = "hr"
+)
+
+// ErrAudience неизвестная аудитория шорт-листа
+var ErrAudience = errors.New("audience: ожидается manager или hr")
+
+// Visible оставляет сотрудников, которых аудитория вправе видеть: активных,
+// согласных на переход и, для руководителей, разрешивших показ руководителям.
+// table - имя или псевдоним таблицы сотрудников в запросе.
+func Visible(tx *gorm.DB, audience, table string) (*gorm.DB, error) {
+	tx = tx.Where(table+".active = ? AND "+table+".open_to_move = ?", true, true)
+	switch audience {
+	case AudienceHR:
+		return tx, nil
+	case AudienceManager:
+		return tx.Where(table+".share_with_managers = ?", true), nil
+	}
+	return nil, ErrAudience
+}
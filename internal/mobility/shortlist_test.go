@@ -0,0 +1,81 @@
+package mobility
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/moverq1337/VTBHack/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRun сессия gorm, которая только собирает SQL
+func dryRun(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestVisible(t *testing.T) {
+	tests := []struct {
+		audience string
+		sql      string
+		vars     []any
+	}{
+		{
+			AudienceManager,
+			`SELECT * FROM "employees" WHERE (e.active = $1 AND e.open_to_move = $2) AND e.share_with_managers = $3`,
+			[]any{true, true, true},
+		},
+		{
+			AudienceHR,
+			`SELECT * FROM "employees" WHERE e.active = $1 AND e.open_to_move = $2`,
+			[]any{true, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.audience, func(t *testing.T) {
+			tx, err := Visible(dryRun(t).Model(&models.Employee{}), tt.audience, "e")
+			if err != nil {
+				t.Fatal(err)
+			}
+			stmt := tx.Find(&[]models.Employee{}).Statement
+			if got := stmt.SQL.String(); got != tt.sql {
+				t.Errorf("SQL =\n%s\nwant\n%s", got, tt.sql)
+			}
+			if !reflect.DeepEqual(stmt.Vars, tt.vars) {
+				t.Errorf("Vars = %v, want %v", stmt.Vars, tt.vars)
+			}
+		})
+	}
+
+	if _, err := Visible(dryRun(t), "all", "e"); !errors.Is(err, ErrAudience) {
+		t.Errorf("неизвестная аудитория: %v", err)
+	}
+}
+
+func TestOptionsHR(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		key  string
+		want bool
+	}{
+		{"ключ не задан", Options{}, "", false},
+		{"ключ не задан, в запросе что-то есть", Options{}, "secret", false},
+		{"верный ключ", Options{HRKey: "secret"}, "secret", true},
+		{"неверный ключ", Options{HRKey: "secret"}, "secret2", false},
+		{"нет ключа в запросе", Options{HRKey: "secret"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.HR(tt.key); got != tt.want {
+				t.Errorf("HR(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
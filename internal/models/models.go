@@ -6,54 +6,362 @@ import (
 )
 
 type Vacancy struct {
-	ID               uuid.UUID `gorm:"primaryKey;autoIncrement" json:"-"`
-	Title            string    `gorm:"type:varchar(255)"`
-	Requirements     string    `gorm:"type:text"`
-	Responsibilities string    `gorm:"type:text"`
-	Region           string    `gorm:"type:varchar(100)"`
-	City             string    `gorm:"type:varchar(100)"`
-	EmploymentType   string    `gorm:"type:varchar(50)"`  // Полная, частичная, удаленная
-	WorkSchedule     string    `gorm:"type:varchar(50)"`  // Полный день, сменный и т.д.
-	Experience       string    `gorm:"type:varchar(50)"`  // Требуемый опыт
-	Education        string    `gorm:"type:varchar(100)"` // Требуемое образование
-	SalaryMin        int       `gorm:"type:integer"`
-	SalaryMax        int       `gorm:"type:integer"`
-	Languages        string    `gorm:"type:text"` // Требуемые языки
-	Skills           string    `gorm:"type:text"` // Ключевые навыки
-	CreatedAt        time.Time
+	ID               uuid.UUID  `gorm:"primaryKey;autoIncrement" json:"id"`
+	Title            string     `gorm:"type:varchar(255)" json:"title"`
+	Requirements     string     `gorm:"type:text" json:"requirements"`
+	Responsibilities string     `gorm:"type:text" json:"responsibilities"`
+	Region           string     `gorm:"type:varchar(100)" json:"region"`
+	City             string     `gorm:"type:varchar(100)" json:"city"`
+	EmploymentType   string     `gorm:"type:varchar(50)" json:"employment_type"` // Полная, частичная, удаленная
+	WorkSchedule     string     `gorm:"type:varchar(50)" json:"work_schedule"`   // Полный день, сменный и т.д.
+	Experience       string     `gorm:"type:varchar(50)" json:"experience"`      // Требуемый опыт
+	Education        string     `gorm:"type:varchar(100)" json:"education"`      // Требуемое образование
+	SalaryMin        int        `gorm:"type:integer" json:"salary_min"`
+	SalaryMax        int        `gorm:"type:integer" json:"salary_max"`
+	SalaryCurrency   string     `gorm:"type:varchar(3);default:'RUB'" json:"salary_currency"`     // Валюта вилки: RUB, USD, EUR
+	SalaryBasis      string     `gorm:"type:varchar(5)" json:"salary_basis"`                      // gross, net; пусто - не указано
+	Languages        string     `gorm:"type:text" json:"languages"`                               // Требуемые языки
+	Skills           string     `gorm:"type:text" json:"skills"`                                  // Ключевые навыки
+	RoleFamily       string     `gorm:"type:varchar(50);index" json:"role_family"`                // Направление по названию: backend, qa, analyst
+	Seniority        string     `gorm:"type:varchar(20)" json:"seniority"`                        // intern, junior, middle, senior, lead, head
+	RequirementItems string     `gorm:"type:jsonb;default:'[]'" json:"-"`                         // Пункты требований: тип, обязательность, уровень
+	ItemsEdited      bool       `gorm:"default:false" json:"items_edited"`                        // Пункты правил рекрутер; изменение текста вакансии их не перезаписывает
+	Status           string     `gorm:"type:varchar(20);default:'published';index" json:"status"` // draft, published
+	Lint             string     `gorm:"type:jsonb;default:'{}'" json:"-"`                         // Результат последней проверки вакансии
+	LintErrors       int        `gorm:"type:integer;default:0" json:"lint_errors"`
+	LintWarnings     int        `gorm:"type:integer;default:0" json:"lint_warnings"`
+	ArchivedAt       *time.Time `gorm:"index" json:"archived_at"` // Архивная вакансия снята с публикации и не попадает в списки по умолчанию
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
+// Статусы вакансии
+const (
+	VacancyDraft     = "draft"
+	VacancyPublished = "published"
+)
+
 type Resume struct {
-	ID           uuid.UUID `gorm:"primaryKey;autoIncrement" json:"-"`
-	CandidateID  uuid.UUID `gorm:"type:uuid"`
-	Text         string    `gorm:"type:text"`
-	ParsedData   string    `gorm:"type:jsonb"`
-	FileURL      string    `gorm:"type:text"`
-	Experience   int       `gorm:"type:integer"` // Опыт в годах
-	Education    string    `gorm:"type:varchar(100)"`
-	Skills       string    `gorm:"type:text"`
-	Languages    string    `gorm:"type:text"`
-	SalaryExpect int       `gorm:"type:integer"`
-	CreatedAt    time.Time
+	ID             uuid.UUID  `gorm:"primaryKey;autoIncrement" json:"id"`
+	CandidateID    uuid.UUID  `gorm:"type:uuid" json:"candidate_id"`
+	Text           string     `gorm:"type:text" json:"text"`
+	ParsedData     string     `gorm:"type:jsonb" json:"-"`
+	FileURL        string     `gorm:"type:text" json:"file_url"`
+	Experience     int        `gorm:"type:integer" json:"experience"` // Опыт в годах
+	Education      string     `gorm:"type:varchar(100)" json:"education"`
+	Skills         string     `gorm:"type:text" json:"skills"`
+	Languages      string     `gorm:"type:text" json:"languages"`
+	SalaryExpect   int        `gorm:"type:integer" json:"salary_expect"`      // Ожидаемая зарплата в месяц
+	SalaryCurrency string     `gorm:"type:varchar(3)" json:"salary_currency"` // Валюта ожиданий
+	SalaryBasis    string     `gorm:"type:varchar(5)" json:"salary_basis"`    // gross, net; пусто - не указано
+	Sections       string     `gorm:"type:jsonb;default:'[]'" json:"-"`       // Разделы резюме с границами в тексте
+	City           string     `gorm:"type:varchar(100);index" json:"city"`    // Город проживания по справочнику
+	Region         string     `gorm:"type:varchar(100)" json:"region"`
+	Relocation     string     `gorm:"type:varchar(20)" json:"relocation"` // ready, not_ready; пусто - не указано
+	RelocateTo     string     `gorm:"type:text" json:"relocate_to"`       // Города для переезда через запятую
+	Remote         bool       `gorm:"default:false" json:"remote"`        // Рассматривает удалённую работу
+	ArchivedAt     *time.Time `gorm:"index" json:"archived_at"`           // Архивное резюме не попадает в списки по умолчанию
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// SavedQuery сохранённый запрос поиска по резюме. Запрос с Alert служит
+// подпиской: резюме, загруженные после CheckedAt, считаются новыми.
+type SavedQuery struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid" json:"id"`
+	Name      string    `gorm:"type:varchar(255)" json:"name"`
+	Query     string    `gorm:"type:text" json:"query"` // Булев запрос: (go OR golang) AND experience>=3
+	Alert     bool      `gorm:"default:false;index" json:"alert"`
+	CheckedAt time.Time `json:"checked_at"`                                                        // Последняя проверка новых резюме
+	CheckedID uuid.UUID `gorm:"type:uuid;default:'00000000-0000-0000-0000-000000000000'" json:"-"` // С CheckedAt - последнее отданное резюме
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CurrencyRate курс валюты к рублю для пересчёта зарплат
+type CurrencyRate struct {
+	Currency  string    `gorm:"primaryKey;type:varchar(3)" json:"currency"`
+	Rate      float64   `gorm:"type:decimal(12,4)" json:"rate"` // Рублей за единицу валюты
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Candidate кандидат с контактами из резюме. Резюме одного человека
+// связываются с одной записью по почте, телефону и профилям. Непустые почта
+// и телефон уникальны (индексы создаёт scripts.Migrate).
+type Candidate struct {
+	ID        uuid.UUID  `gorm:"primaryKey;type:uuid" json:"-"`
+	Email     string     `gorm:"type:varchar(255);index"`
+	Phone     string     `gorm:"type:varchar(20);index"` // В формате E.164
+	Telegram  string     `gorm:"type:varchar(64);index"`
+	GitHub    string     `gorm:"column:github;type:varchar(255);index"`
+	GitLab    string     `gorm:"column:gitlab;type:varchar(255)"`
+	LinkedIn  string     `gorm:"column:linkedin;type:varchar(255);index"`
+	HHURL     string     `gorm:"column:hh_url;type:varchar(255);index"`
+	City      string     `gorm:"type:varchar(100)"`
+	BirthDate *time.Time `gorm:"type:date"`
+	Contacts  string     `gorm:"type:jsonb;default:'[]'"` // Все найденные контакты с уверенностью
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 type AnalysisResult struct {
-	ID         uuid.UUID `gorm:"primaryKey;type:uuid" json:"-"`
-	ResumeID   uuid.UUID `gorm:"type:uuid"`
-	VacancyID  uuid.UUID `gorm:"type:uuid"`
-	MatchScore float64   `gorm:"type:decimal(5,2)"`
-	Details    string    `gorm:"type:jsonb;default:'{}'"`
-	CreatedAt  time.Time
+	ID          uuid.UUID  `gorm:"primaryKey;type:uuid" json:"id"`
+	ResumeID    uuid.UUID  `gorm:"type:uuid;index" json:"resume_id"`
+	VacancyID   uuid.UUID  `gorm:"type:uuid;index" json:"vacancy_id"`
+	MatchScore  float64    `gorm:"type:decimal(5,2)" json:"match_score"` // Оценка NLP-сервиса 0..1
+	Score       float64    `gorm:"type:decimal(5,4);index" json:"score"` // Итоговая оценка 0..1: сумма вкладов критериев обоснования
+	Details     string     `gorm:"type:jsonb;default:'{}'" json:"-"`
+	Degraded    bool       `gorm:"default:false" json:"degraded"`    // Оценка получена резервным скорером без NLP-сервиса
+	Explanation string     `gorm:"type:jsonb;default:'{}'" json:"-"` // Критерии оценки с фрагментами резюме
+	Decision    string     `gorm:"type:varchar(20)" json:"decision"` // Решение рекрутера: shortlisted, rejected; пусто - не принято
+	Note        string     `gorm:"type:text" json:"note"`
+	ArchivedAt  *time.Time `gorm:"index" json:"archived_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
+// Решения рекрутера по анализу
+const (
+	DecisionShortlisted = "shortlisted"
+	DecisionRejected    = "rejected"
+)
+
 type AnalysisDetail struct {
-	ID               uuid.UUID `gorm:"primaryKey;type:uuid" json:"-"`
-	AnalysisResultID uuid.UUID `gorm:"type:uuid"`
-	Category         string    `gorm:"type:varchar(100)"` // Например: "skills", "experience", "education"
-	Criteria         string    `gorm:"type:text"`         // Конкретный критерий
-	ResumeValue      string    `gorm:"type:text"`         // Значение из резюме
-	VacancyValue     string    `gorm:"type:text"`         // Требование из вакансии
-	MatchScore       float64   `gorm:"type:decimal(3,2)"` // Оценка соответствия (0-1)
-	Weight           float64   `gorm:"type:decimal(3,2)"` // Вес критерия в общей оценке
-	CreatedAt        time.Time
+	ID               uuid.UUID `gorm:"primaryKey;type:uuid" json:"id"`
+	AnalysisResultID uuid.UUID `gorm:"type:uuid;index" json:"analysis_result_id"`
+	Category         string    `gorm:"type:varchar(100)" json:"category"`    // Например: "skills", "experience", "education"
+	Criteria         string    `gorm:"type:text" json:"criteria"`            // Конкретный критерий
+	ResumeValue      string    `gorm:"type:text" json:"resume_value"`        // Значение из резюме
+	VacancyValue     string    `gorm:"type:text" json:"vacancy_value"`       // Требование из вакансии
+	MatchScore       float64   `gorm:"type:decimal(5,4)" json:"match_score"` // Оценка соответствия (0-1)
+	Weight           float64   `gorm:"type:decimal(6,4)" json:"weight"`      // Вес критерия в общей оценке
+	Evidence         string    `gorm:"type:jsonb;default:'[]'" json:"-"`     // Фрагменты резюме, подтверждающие критерий
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Skill навык таксономии. Slug - канонический идентификатор (kubernetes, postgresql)
+type Skill struct {
+	ID        uuid.UUID    `gorm:"primaryKey;type:uuid" json:"id"`
+	Slug      string       `gorm:"type:varchar(100);uniqueIndex" json:"slug"`
+	Name      string       `gorm:"type:varchar(255)" json:"name"`
+	Category  string       `gorm:"type:varchar(50);index" json:"category"`
+	ParentID  *uuid.UUID   `gorm:"type:uuid;index" json:"parent_id"` // Более общий навык: PostgreSQL -> SQL
+	Aliases   []SkillAlias `gorm:"foreignKey:SkillID;constraint:OnDelete:CASCADE" json:"aliases"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// SkillAlias написание навыка в нормализованном виде (k8s, кубернетес)
+type SkillAlias struct {
+	ID      uuid.UUID `gorm:"primaryKey;type:uuid" json:"-"`
+	SkillID uuid.UUID `gorm:"type:uuid;index" json:"-"`
+	Alias   string    `gorm:"type:varchar(255);uniqueIndex" json:"alias"`
+	Lang    string    `gorm:"type:varchar(2)" json:"lang"` // ru или en
+}
+
+// SkillRelation взвешенная связь близких навыков (MySQL - PostgreSQL).
+// Связь неориентированная, пара хранится один раз на источник.
+type SkillRelation struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid" json:"id"`
+	SkillID   uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_skill_relation" json:"skill_id"`
+	RelatedID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_skill_relation" json:"related_id"`
+	Source    string    `gorm:"type:varchar(20);uniqueIndex:idx_skill_relation" json:"source"` // curated или cooccurrence
+	Weight    float64   `gorm:"type:decimal(4,3)" json:"weight"`                               // Доля зачёта 0..1
+	Support   int       `gorm:"type:integer" json:"support"`                                   // Документов с обоими навыками
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WorkExperience период работы из истории резюме
+type WorkExperience struct {
+	ID          uuid.UUID `gorm:"primaryKey;type:uuid" json:"-"`
+	ResumeID    uuid.UUID `gorm:"type:uuid;index"`
+	Company     string    `gorm:"type:varchar(255)"`
+	Title       string    `gorm:"type:varchar(255)"`
+	RoleFamily  string    `gorm:"type:varchar(50);index"` // Направление по должности
+	Seniority   string    `gorm:"type:varchar(20)"`       // Уровень по должности
+	StartDate   time.Time `gorm:"type:date"`
+	EndDate     time.Time `gorm:"type:date"` // Для текущей работы - месяц разбора резюме
+	Current     bool      `gorm:"default:false"`
+	Months      int       `gorm:"type:integer"`
+	Skills      string    `gorm:"type:text"` // Канонические навыки через запятую
+	Description string    `gorm:"type:text"`
+	SpanStart   int       `gorm:"type:integer"` // Смещение блока в Resume.Text, в символах
+	SpanEnd     int       `gorm:"type:integer"`
+	CreatedAt   time.Time
+}
+
+// Embedding вектор текста резюме или вакансии для поиска похожих
+type Embedding struct {
+	OwnerType string    `gorm:"primaryKey;type:varchar(10)" json:"owner_type"` // resume или vacancy
+	OwnerID   uuid.UUID `gorm:"primaryKey;type:uuid" json:"owner_id"`
+	Model     string    `gorm:"type:varchar(100);index" json:"model"` // Модель NLP-сервиса: векторы разных моделей несравнимы
+	Vector    []byte    `gorm:"type:bytea" json:"-"`                  // float32 little-endian
+	UpdatedAt time.Time `gorm:"index" json:"updated_at"`
+}
+
+// Состояния запуска кластеризации
+const (
+	ClusterRunRunning = "running"
+	ClusterRunDone    = "done"
+	ClusterRunFailed  = "failed"
+)
+
+// ClusterRun запуск кластеризации базы резюме
+type ClusterRun struct {
+	ID         uuid.UUID  `gorm:"primaryKey;type:uuid" json:"id"`
+	Source     string     `gorm:"type:varchar(20)" json:"source"` // embeddings или skills
+	K          int        `gorm:"type:integer" json:"k"`
+	Resumes    int        `gorm:"type:integer" json:"resumes"`
+	Cohesion   float64    `json:"cohesion"` // Средняя косинусная близость резюме к центру своего кластера
+	Status     string     `gorm:"type:varchar(20);index" json:"status"`
+	Error      string     `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt  time.Time  `gorm:"index" json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// Cluster группа похожих резюме с подписью по преобладающим навыкам
+type Cluster struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid" json:"id"`
+	RunID     uuid.UUID `gorm:"type:uuid;index" json:"run_id"`
+	Number    int       `gorm:"type:integer" json:"number"`
+	Label     string    `gorm:"type:varchar(255)" json:"label"`
+	Size      int       `gorm:"type:integer" json:"size"`
+	Cohesion  float64   `json:"cohesion"`
+	TopSkills string    `gorm:"type:jsonb;default:'[]'" json:"-"` // Навыки с долей в кластере и подъёмом относительно базы
+}
+
+// ClusterMember резюме в кластере и его близость к центру кластера
+type ClusterMember struct {
+	RunID      uuid.UUID `gorm:"primaryKey;type:uuid"`
+	ResumeID   uuid.UUID `gorm:"primaryKey;type:uuid"`
+	ClusterID  uuid.UUID `gorm:"type:uuid;index"`
+	Similarity float64
+}
+
+// Состояния расчёта матрицы сопоставления
+const (
+	MatchRunRunning = "running"
+	MatchRunDone    = "done"
+	MatchRunFailed  = "failed"
+)
+
+// MatchRun расчёт оценок всех пар резюме × вакансии кампании найма и
+// распределение кандидатов по вакансиям
+type MatchRun struct {
+	ID         uuid.UUID  `gorm:"primaryKey;type:uuid" json:"id"`
+	Name       string     `gorm:"type:varchar(255)" json:"name"`
+	Resumes    int        `gorm:"type:integer" json:"resumes"`
+	Vacancies  int        `gorm:"type:integer" json:"vacancies"`
+	Scored     int        `gorm:"type:integer" json:"scored"` // Оценено пар, в том числе с ошибкой
+	Failed     int        `gorm:"type:integer" json:"failed"` // Пар, которые оценить не удалось
+	Degraded   bool       `gorm:"default:false" json:"degraded"`
+	MinScore   float64    `json:"min_score"` // Пары с меньшей оценкой не назначаются
+	Assigned   int        `gorm:"type:integer" json:"assigned"`
+	TotalScore float64    `json:"total_score"` // Сумма оценок назначенных пар
+	Status     string     `gorm:"type:varchar(20);index" json:"status"`
+	Error      string     `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt  time.Time  `gorm:"index" json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// MatchRunVacancy вакансия кампании и число мест на ней
+type MatchRunVacancy struct {
+	RunID     uuid.UUID `gorm:"primaryKey;type:uuid" json:"-"`
+	VacancyID uuid.UUID `gorm:"primaryKey;type:uuid" json:"vacancy_id"`
+	Position  int       `gorm:"type:integer" json:"-"` // Порядок вакансий в запросе
+	Headcount int       `gorm:"type:integer" json:"headcount"`
+}
+
+// MatchCell оценка резюме для вакансии в матрице кампании
+type MatchCell struct {
+	RunID     uuid.UUID `gorm:"primaryKey;type:uuid" json:"-"`
+	ResumeID  uuid.UUID `gorm:"primaryKey;type:uuid" json:"resume_id"`
+	VacancyID uuid.UUID `gorm:"primaryKey;type:uuid" json:"vacancy_id"`
+	Score     float64   `json:"score"`
+	Degraded  bool      `gorm:"default:false" json:"degraded,omitempty"`
+	Error     string    `gorm:"type:text" json:"error,omitempty"`
+	Assigned  bool      `gorm:"default:false" json:"assigned"`
+}
+
+// InterviewResult итог голосового интервью, который присылает сервис интервью
+type InterviewResult struct {
+	ID          uuid.UUID `gorm:"primaryKey;type:uuid" json:"id"`
+	SessionID   string    `gorm:"type:varchar(64);uniqueIndex" json:"session_id"`
+	CandidateID uuid.UUID `gorm:"type:uuid;index" json:"candidate_id"` // Кандидат или резюме: то, что передано при старте интервью
+	VacancyID   uuid.UUID `gorm:"type:uuid;index" json:"vacancy_id"`
+	Score       float64   `json:"score"` // Средняя оценка ответа 0..1
+	Questions   int       `gorm:"type:integer" json:"questions"`
+	Answers     string    `gorm:"type:jsonb;default:'[]'" json:"-"` // Вопросы, распознанные ответы и их оценки
+	Duration    float64   `json:"duration"`                         // Секунды
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `gorm:"index" json:"completed_at"`
+}
+
+// Форматы учебных материалов
+const (
+	LearningCourse    = "course"
+	LearningBook      = "book"
+	LearningVideo     = "video"
+	LearningArticle   = "article"
+	LearningMentoring = "mentoring"
+)
+
+// LearningResource курс или другой учебный материал каталога развития.
+// Материал закрывает пробел по навыкам таксономии или по ключевым словам
+// требования (например, "английский язык").
+type LearningResource struct {
+	ID          uuid.UUID  `gorm:"primaryKey;type:uuid" json:"id"`
+	Title       string     `gorm:"type:varchar(255)" json:"title"`
+	Provider    string     `gorm:"type:varchar(255)" json:"provider"` // Площадка или внутренняя академия
+	URL         string     `gorm:"type:text" json:"url"`
+	Format      string     `gorm:"type:varchar(20);index" json:"format"` // course, book, video, article, mentoring
+	Level       string     `gorm:"type:varchar(20)" json:"level"`        // junior, middle, senior; пусто - любой
+	Hours       int        `gorm:"type:integer" json:"hours"`            // Примерная длительность
+	Language    string     `gorm:"type:varchar(2)" json:"language"`      // ru или en
+	Free        bool       `gorm:"default:false" json:"free"`
+	Internal    bool       `gorm:"default:false" json:"internal"` // Внутреннее обучение банка
+	Skills      string     `gorm:"type:text" json:"skills"`       // Канонические навыки через запятую
+	Keywords    string     `gorm:"type:text" json:"keywords"`     // Фразы требований через запятую
+	Description string     `gorm:"type:text" json:"description"`
+	ArchivedAt  *time.Time `gorm:"index" json:"archived_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Employee сотрудник банка из выгрузки HR-системы. Во внутренний шорт-лист
+// вакансии попадают только сотрудники с согласием OpenToMove: HR видит всех
+// согласившихся, руководители - тех, кто также разрешил ShareWithManagers.
+type Employee struct {
+	ID                uuid.UUID  `gorm:"primaryKey;type:uuid" json:"id"`
+	ExternalID        string     `gorm:"type:varchar(64);uniqueIndex" json:"external_id"` // Табельный номер в HR-системе
+	CandidateID       *uuid.UUID `gorm:"type:uuid;index" json:"candidate_id"`             // Кандидат с той же почтой, если сотрудник откликался
+	FullName          string     `gorm:"type:varchar(255)" json:"full_name"`
+	Email             string     `gorm:"type:varchar(255);index" json:"email"`
+	Department        string     `gorm:"type:varchar(255);index" json:"department"`
+	Title             string     `gorm:"type:varchar(255)" json:"title"`
+	RoleFamily        string     `gorm:"type:varchar(50);index" json:"role_family"` // Направление по должности
+	Seniority         string     `gorm:"type:varchar(20)" json:"seniority"`         // Уровень по должности
+	City              string     `gorm:"type:varchar(100)" json:"city"`
+	Region            string     `gorm:"type:varchar(100)" json:"region"`
+	Relocation        string     `gorm:"type:varchar(20)" json:"relocation"`  // ready, not_ready; пусто - не указано
+	Skills            string     `gorm:"type:text" json:"skills"`             // Канонические навыки через запятую
+	Experience        float64    `gorm:"type:decimal(4,1)" json:"experience"` // Общий стаж в годах
+	HiredAt           *time.Time `gorm:"type:date" json:"hired_at"`
+	Summary           string     `gorm:"type:text" json:"summary"` // Описание опыта из профиля
+	OpenToMove        bool       `gorm:"default:false;index" json:"open_to_move"`
+	ShareWithManagers bool       `gorm:"default:false" json:"share_with_managers"`
+	Active            bool       `gorm:"index" json:"active"` // Уволенные остаются в базе неактивными
+	ImportedAt        time.Time  `json:"imported_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// EmployeeMatch оценка сотрудника для вакансии тем же движком, что и резюме
+type EmployeeMatch struct {
+	EmployeeID  uuid.UUID `gorm:"primaryKey;type:uuid" json:"employee_id"`
+	VacancyID   uuid.UUID `gorm:"primaryKey;type:uuid;index" json:"vacancy_id"`
+	Score       float64   `gorm:"index" json:"score"`
+	Degraded    bool      `gorm:"default:false" json:"degraded"`
+	Explanation string    `gorm:"type:jsonb;default:'{}'" json:"-"` // Критерии оценки
+	ScoredAt    time.Time `json:"scored_at"`
 }
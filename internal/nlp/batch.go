@@ -0,0 +1,200 @@
+package nlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/moverq1337/VTBHack/internal/pb"
+)
+
+const (
+	// DefaultBatchSize количество резюме в одном вызове BatchMatch
+	DefaultBatchSize = 50
+	// DefaultStreamWindow максимальное число пар, отправленных в поток без ответа
+	DefaultStreamWindow = 64
+)
+
+// MatchPair пара резюме-вакансия для потокового сопоставления
+type MatchPair struct {
+	ResumeID    string
+	ResumeText  string
+	VacancyID   string
+	VacancyText string
+}
+
+// MatchOutcome результат сопоставления одного элемента.
+// Err заполняется, если именно этот элемент обработать не удалось.
+type MatchOutcome struct {
+	ResumeID  string
+	VacancyID string
+	Score     float32
+	Err       error
+	Degraded  bool // Оценка получена резервным скорером
+}
+
+// BatchMatch сопоставляет вакансию с резюме, разбивая их на чанки по batchSize.
+// Ошибка вызова для чанка не прерывает обработку: она записывается в Err
+// каждого элемента этого чанка. Результаты возвращаются в порядке resumes.
+func BatchMatch(ctx context.Context, client pb.NLPServiceClient, vacancyID, vacancyText string, resumes []*pb.ResumeItem, batchSize int) []MatchOutcome {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	outcomes := make([]MatchOutcome, len(resumes))
+	for i, r := range resumes {
+		outcomes[i] = MatchOutcome{ResumeID: r.Id, VacancyID: vacancyID}
+	}
+
+	for start := 0; start < len(resumes); start += batchSize {
+		end := min(start+batchSize, len(resumes))
+		chunk := resumes[start:end]
+
+		if err := ctx.Err(); err != nil {
+			failOutcomes(outcomes[start:], err)
+			break
+		}
+
+		resp, err := client.BatchMatch(ctx, &pb.BatchMatchRequest{
+			VacancyId:   vacancyID,
+			VacancyText: vacancyText,
+			Resumes:     chunk,
+		})
+		if err != nil {
+			failOutcomes(outcomes[start:end], fmt.Errorf("batch match failed: %w", err))
+			continue
+		}
+		if len(resp.Results) != len(chunk) {
+			failOutcomes(outcomes[start:end], fmt.Errorf("batch match returned %d results for %d resumes", len(resp.Results), len(chunk)))
+			continue
+		}
+
+		for i, res := range resp.Results {
+			outcomes[start+i].Score = res.Score
+			if res.Error != "" {
+				outcomes[start+i].Err = errors.New(res.Error)
+			}
+		}
+	}
+
+	return outcomes
+}
+
+// StreamMatch сопоставляет произвольные пары через двунаправленный поток.
+// Одновременно в полёте держится не больше window пар, поэтому клиент
+// не опережает сервер сильнее, чем позволяет окно. Текст резюме и вакансии
+// отправляется только при первом упоминании идентификатора.
+// Результаты возвращаются в порядке pairs; необработанные элементы получают Err.
+func StreamMatch(ctx context.Context, client pb.NLPServiceClient, pairs []MatchPair, window int) ([]MatchOutcome, error) {
+	if window <= 0 {
+		window = DefaultStreamWindow
+	}
+
+	outcomes := make([]MatchOutcome, len(pairs))
+	for i, p := range pairs {
+		outcomes[i] = MatchOutcome{ResumeID: p.ResumeID, VacancyID: p.VacancyID}
+	}
+	if len(pairs) == 0 {
+		return outcomes, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.StreamMatch(ctx)
+	if err != nil {
+		failOutcomes(outcomes, err)
+		return outcomes, fmt.Errorf("failed to open match stream: %w", err)
+	}
+
+	// Семафор ограничивает число пар без ответа
+	inflight := make(chan struct{}, window)
+	sendErr := make(chan error, 1)
+
+	go func() {
+		sentResumes := make(map[string]bool)
+		sentVacancies := make(map[string]bool)
+
+		for i, p := range pairs {
+			select {
+			case inflight <- struct{}{}:
+			case <-ctx.Done():
+				sendErr <- ctx.Err()
+				return
+			}
+
+			item := &pb.MatchItem{
+				Seq:       uint64(i),
+				ResumeId:  p.ResumeID,
+				VacancyId: p.VacancyID,
+			}
+			if !sentResumes[p.ResumeID] {
+				item.ResumeText = p.ResumeText
+				sentResumes[p.ResumeID] = true
+			}
+			if !sentVacancies[p.VacancyID] {
+				item.VacancyText = p.VacancyText
+				sentVacancies[p.VacancyID] = true
+			}
+
+			if err := stream.Send(item); err != nil {
+				// Настоящая причина будет получена из Recv
+				sendErr <- nil
+				return
+			}
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	done := make([]bool, len(pairs))
+	received := 0
+	var streamErr error
+
+	for received < len(pairs) {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			streamErr = fmt.Errorf("match stream closed after %d of %d results", received, len(pairs))
+			break
+		}
+		if err != nil {
+			streamErr = fmt.Errorf("match stream failed: %w", err)
+			break
+		}
+
+		<-inflight
+		if res.Seq >= uint64(len(pairs)) || done[res.Seq] {
+			continue
+		}
+		done[res.Seq] = true
+		received++
+
+		outcomes[res.Seq].Score = res.Score
+		if res.Error != "" {
+			outcomes[res.Seq].Err = errors.New(res.Error)
+		}
+	}
+
+	if streamErr == nil {
+		if err := <-sendErr; err != nil {
+			streamErr = fmt.Errorf("failed to close match stream: %w", err)
+		}
+	}
+
+	if streamErr != nil {
+		for i := range outcomes {
+			if !done[i] {
+				outcomes[i].Err = streamErr
+			}
+		}
+	}
+
+	return outcomes, streamErr
+}
+
+// failOutcomes помечает элементы ошибкой
+func failOutcomes(outcomes []MatchOutcome, err error) {
+	for i := range outcomes {
+		outcomes[i].Err = err
+	}
+}
@@ -0,0 +1,97 @@
+package nlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/moverq1337/VTBHack/internal/fallback"
+	"github.com/moverq1337/VTBHack/internal/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// downService NLP-сервис, поток к которому не открывается
+type downService struct {
+	pb.NLPServiceClient
+}
+
+func (downService) StreamMatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[pb.MatchItem, pb.MatchResult], error) {
+	return nil, status.Error(codes.Unavailable, "connection refused")
+}
+
+// matrix пары резюме × вакансии, как их строит расчёт кампании
+func matrix(resumes, vacancies int) []MatchPair {
+	var pairs []MatchPair
+	for v := 0; v < vacancies; v++ {
+		for r := 0; r < resumes; r++ {
+			pairs = append(pairs, MatchPair{
+				ResumeID:    fmt.Sprintf("r%d", r),
+				ResumeText:  fmt.Sprintf("Go разработчик, опыт %d лет, PostgreSQL, Kafka", r+1),
+				VacancyID:   fmt.Sprintf("v%d", v),
+				VacancyText: "Go разработчик, опыт от 3 лет, PostgreSQL",
+			})
+		}
+	}
+	return pairs
+}
+
+func TestStreamMatch(t *testing.T) {
+	pairs := matrix(7, 3)
+	// Окно меньше числа пар: отправка ждёт ответов
+	outcomes, err := StreamMatch(context.Background(), fallback.New(), pairs, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcomes) != len(pairs) {
+		t.Fatalf("outcomes = %d, want %d", len(outcomes), len(pairs))
+	}
+	for i, o := range outcomes {
+		if o.Err != nil || o.ResumeID != pairs[i].ResumeID || o.VacancyID != pairs[i].VacancyID {
+			t.Fatalf("outcome %d = %+v", i, o)
+		}
+		// Текст резюме уходит только с первой парой, но оценки одинаковы для всех вакансий
+		if i >= 7 && o.Score != outcomes[i%7].Score {
+			t.Errorf("пара %d: оценка %v, у той же пары первой вакансии %v", i, o.Score, outcomes[i%7].Score)
+		}
+	}
+	if outcomes[0].Score >= outcomes[6].Score {
+		t.Errorf("больший опыт должен давать не меньшую оценку: %v и %v", outcomes[0].Score, outcomes[6].Score)
+	}
+
+	empty, err := StreamMatch(context.Background(), fallback.New(), nil, 0)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("пустой список: %v, %v", empty, err)
+	}
+}
+
+func TestStreamMatchWithFallback(t *testing.T) {
+	pairs := matrix(3, 2)
+
+	outcomes, err := StreamMatch(context.Background(), downService{}, pairs, 0)
+	if !IsUnavailable(err) {
+		t.Fatalf("err = %v, want Unavailable", err)
+	}
+	for _, o := range outcomes {
+		if !IsUnavailable(o.Err) {
+			t.Fatalf("Err = %v", o.Err)
+		}
+	}
+
+	c := &Client{NLPServiceClient: downService{}, fallback: fallback.New()}
+	outcomes = c.StreamMatchWithFallback(context.Background(), pairs, 0)
+	for i, o := range outcomes {
+		if o.Err != nil || !o.Degraded || o.Score <= 0 || o.ResumeID != pairs[i].ResumeID {
+			t.Errorf("outcome %d = %+v", i, o)
+		}
+	}
+
+	c = &Client{NLPServiceClient: downService{}}
+	for _, o := range c.StreamMatchWithFallback(context.Background(), pairs, 0) {
+		if o.Err == nil || errors.Is(o.Err, context.Canceled) || o.Degraded {
+			t.Errorf("без резервного скорера: %+v", o)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package nlp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moverq1337/VTBHack/internal/config"
+	"github.com/moverq1337/VTBHack/internal/fallback"
+	"github.com/moverq1337/VTBHack/internal/pb"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer/leastrequest"
+	"google.golang.org/grpc/balancer/roundrobin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/health" // Клиентские проверки здоровья по healthCheckConfig
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultHost = "scoring-service"
+	defaultPort = "50051"
+
+	// BalancerRoundRobin равномерно распределяет вызовы по репликам
+	BalancerRoundRobin = "round_robin"
+	// BalancerLeastRequest выбирает реплику с наименьшим числом активных вызовов
+	BalancerLeastRequest = "least_request"
+
+	staticScheme = "nlp-static"
+)
+
+var log = logrus.New()
+
+// ErrCircuitOpen возвращается без обращения к сервису, пока выключатель разомкнут
+var ErrCircuitOpen = status.Error(codes.Unavailable, "nlp service circuit breaker is open")
+
+// Config параметры клиента NLP-сервиса
+type Config struct {
+	Host             string
+	Port             string
+	Targets          []string // Статический список реплик; если пуст, реплики ищутся через DNS по Host
+	Balancer         string   // round_robin или least_request
+	HealthInterval   time.Duration
+	Fallback         bool          // Переключаться на резервный скорер при недоступности сервиса
+	Timeout          time.Duration // Дедлайн вызова, если вызывающий его не задал
+	MaxAttempts      int           // Попыток на вызов, включая первую
+	BreakerThreshold int           // Отказов подряд до размыкания цепи
+	BreakerCooldown  time.Duration // Пауза перед пробным вызовом
+	KeepaliveTime    time.Duration
+}
+
+// Client долгоживущий клиент NLP-сервиса, общий для всех обработчиков
+type Client struct {
+	pb.NLPServiceClient
+	fallback pb.NLPServiceClient
+	conn     *grpc.ClientConn
+	breaker  *breaker
+	monitor  *healthMonitor
+	target   string
+	balancer string
+}
+
+// Status состояние клиента и реплик для административного эндпоинта
+type Status struct {
+	Target       string          `json:"target"`
+	Balancer     string          `json:"balancer"`
+	Fallback     bool            `json:"fallback"`
+	State        string          `json:"state"`
+	Breaker      string          `json:"breaker"`
+	Backends     []BackendStatus `json:"backends"`
+	ResolveError string          `json:"resolve_error,omitempty"`
+}
+
+// NewClient создаёт клиент с балансировкой между репликами NLP-сервиса.
+// Реплики, не прошедшие проверку grpc.health.v1, исключаются из балансировки.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 15 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 3
+	}
+	if cfg.BreakerThreshold <= 0 {
+		cfg.BreakerThreshold = 5
+	}
+	if cfg.BreakerCooldown <= 0 {
+		cfg.BreakerCooldown = 30 * time.Second
+	}
+	if cfg.KeepaliveTime <= 0 {
+		cfg.KeepaliveTime = 30 * time.Second
+	}
+	if cfg.HealthInterval <= 0 {
+		cfg.HealthInterval = 10 * time.Second
+	}
+	if cfg.Balancer == "" {
+		cfg.Balancer = BalancerRoundRobin
+	}
+
+	lbConfig, err := loadBalancingConfig(cfg.Balancer)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		target   string
+		backends func(ctx context.Context) ([]string, error)
+		opts     []grpc.DialOption
+	)
+	if len(cfg.Targets) > 0 {
+		r := manual.NewBuilderWithScheme(staticScheme)
+		state := resolver.State{}
+		for _, addr := range cfg.Targets {
+			state.Addresses = append(state.Addresses, resolver.Address{Addr: addr})
+		}
+		r.InitialState(state)
+		opts = append(opts, grpc.WithResolvers(r))
+		target = staticScheme + ":///" + strings.Join(cfg.Targets, ",")
+		backends = staticBackends(cfg.Targets)
+	} else {
+		addr := Target(cfg.Host, cfg.Port)
+		host, port, _ := strings.Cut(addr, ":")
+		target = "dns:///" + addr
+		backends = dnsBackends(host, port)
+	}
+
+	b := newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown)
+
+	conn, err := grpc.NewClient(target, append(opts,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(serviceConfig(cfg.MaxAttempts, lbConfig)),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                cfg.KeepaliveTime,
+			Timeout:             10 * time.Second,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithChainUnaryInterceptor(b.unaryInterceptor(), deadlineInterceptor(cfg.Timeout)),
+		grpc.WithChainStreamInterceptor(b.streamInterceptor()),
+	)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nlp client for %s: %w", target, err)
+	}
+	// Без этого соединение не устанавливается до первого вызова,
+	// и балансировщик не начинает проверять реплики
+	conn.Connect()
+
+	monitor := newHealthMonitor(backends, cfg.HealthInterval)
+	go monitor.run()
+
+	client := &Client{
+		NLPServiceClient: pb.NewNLPServiceClient(conn),
+		conn:             conn,
+		breaker:          b,
+		monitor:          monitor,
+		target:           target,
+		balancer:         cfg.Balancer,
+	}
+	if cfg.Fallback {
+		client.fallback = fallback.New()
+	}
+	return client, nil
+}
+
+// Close останавливает проверки реплик и закрывает соединение
+func (c *Client) Close() error {
+	c.monitor.close()
+	return c.conn.Close()
+}
+
+// Status возвращает состояние соединения, выключателя и каждой реплики
+func (c *Client) Status() Status {
+	backends, resolveErr := c.monitor.snapshot()
+	return Status{
+		Target:       c.target,
+		Balancer:     c.balancer,
+		Fallback:     c.fallback != nil,
+		State:        c.conn.GetState().String(),
+		Breaker:      c.breaker.State(),
+		Backends:     backends,
+		ResolveError: resolveErr,
+	}
+}
+
+// Target адрес сервиса
+func (c *Client) Target() string {
+	return c.target
+}
+
+// Target собирает адрес из GRPC_HOST и GRPC_PORT.
+// Порт допускается как в виде "50051", так и ":50051".
+func Target(host, port string) string {
+	if host == "" {
+		host = defaultHost
+	}
+	port = strings.TrimPrefix(port, ":")
+	if port == "" {
+		port = defaultPort
+	}
+	return host + ":" + port
+}
+
+// IsUnavailable сообщает, что вызов не выполнен из-за недоступности сервиса,
+// а не из-за ошибки в самом запросе
+func IsUnavailable(err error) bool {
+	return isServiceFailure(err)
+}
+
+// loadBalancingConfig возвращает конфигурацию политики балансировки
+func loadBalancingConfig(balancer string) (string, error) {
+	switch balancer {
+	case BalancerRoundRobin:
+		return fmt.Sprintf(`[{"%s": {}}]`, roundrobin.Name), nil
+	case BalancerLeastRequest:
+		return fmt.Sprintf(`[{"%s": {"choiceCount": 2}}]`, leastrequest.Name), nil
+	}
+	return "", fmt.Errorf("unknown nlp balancer %q", balancer)
+}
+
+// serviceConfig задаёт политику балансировки, проверки здоровья реплик
+// и повторы с экспоненциальной задержкой для временных отказов сервиса
+func serviceConfig(maxAttempts int, lbConfig string) string {
+	return fmt.Sprintf(`{
+  "loadBalancingConfig": %s,
+  "healthCheckConfig": {"serviceName": %q},
+  "methodConfig": [{
+    "name": [{"service": "pb.NLPService"}],
+    "waitForReady": false,
+    "retryPolicy": {
+      "maxAttempts": %d,
+      "initialBackoff": "0.2s",
+      "maxBackoff": "2s",
+      "backoffMultiplier": 2.0,
+      "retryableStatusCodes": ["UNAVAILABLE", "RESOURCE_EXHAUSTED"]
+    }
+  }]
+}`, lbConfig, healthService, maxAttempts)
+}
+
+// deadlineInterceptor ограничивает вызов по времени, если дедлайн не задан вызывающим
+func deadlineInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// ConfigFrom берёт параметры клиента из конфигурации сервиса
+func ConfigFrom(cfg *config.Config) Config {
+	return Config{
+		Host:             cfg.GRPCHost,
+		Port:             cfg.GRPCPort,
+		Targets:          cfg.NLPTargets,
+		Balancer:         cfg.NLPBalancer,
+		HealthInterval:   cfg.NLPHealthInterval,
+		Fallback:         cfg.NLPFallback,
+		Timeout:          cfg.NLPTimeout,
+		MaxAttempts:      cfg.NLPMaxAttempts,
+		BreakerThreshold: cfg.NLPBreakerThreshold,
+		BreakerCooldown:  cfg.NLPBreakerCooldown,
+		KeepaliveTime:    cfg.NLPKeepaliveTime,
+	}
+}
@@ -0,0 +1,91 @@
+package nlp
+
+import (
+	"context"
+
+	"github.com/moverq1337/VTBHack/internal/pb"
+)
+
+// Match сопоставляет резюме с вакансией. Если NLP-сервис недоступен,
+// вызов переключается на резервный скорер, а degraded становится true.
+func (c *Client) Match(ctx context.Context, in *pb.MatchRequest) (resp *pb.MatchResponse, degraded bool, err error) {
+	resp, err = c.MatchResumeVacancy(ctx, in)
+	if err == nil || c.fallback == nil || !IsUnavailable(err) {
+		return resp, false, err
+	}
+
+	log.WithError(err).Warn("NLP-сервис недоступен, сопоставление выполняет резервный скорер")
+	resp, err = c.fallback.MatchResumeVacancy(ctx, in)
+	return resp, true, err
+}
+
+// Parse разбирает резюме с переключением на резервный скорер
+func (c *Client) Parse(ctx context.Context, in *pb.ParseRequest) (resp *pb.ParseResponse, degraded bool, err error) {
+	resp, err = c.ParseResume(ctx, in)
+	if err == nil || c.fallback == nil || !IsUnavailable(err) {
+		return resp, false, err
+	}
+
+	log.WithError(err).Warn("NLP-сервис недоступен, разбор выполняет резервный скорер")
+	resp, err = c.fallback.ParseResume(ctx, in)
+	return resp, true, err
+}
+
+// BatchMatchWithFallback работает как BatchMatch, но элементы, не обработанные
+// из-за недоступности сервиса, пересчитываются резервным скорером
+// и помечаются Degraded
+func (c *Client) BatchMatchWithFallback(ctx context.Context, vacancyID, vacancyText string, resumes []*pb.ResumeItem, batchSize int) []MatchOutcome {
+	outcomes := BatchMatch(ctx, c, vacancyID, vacancyText, resumes, batchSize)
+	if c.fallback == nil {
+		return outcomes
+	}
+
+	var retry []*pb.ResumeItem
+	var positions []int
+	for i, o := range outcomes {
+		if o.Err != nil && IsUnavailable(o.Err) {
+			retry = append(retry, resumes[i])
+			positions = append(positions, i)
+		}
+	}
+	if len(retry) == 0 {
+		return outcomes
+	}
+
+	log.Warnf("NLP-сервис недоступен, %d резюме оценивает резервный скорер", len(retry))
+	for i, o := range BatchMatch(ctx, c.fallback, vacancyID, vacancyText, retry, batchSize) {
+		o.Degraded = true
+		outcomes[positions[i]] = o
+	}
+	return outcomes
+}
+
+// StreamMatchWithFallback работает как StreamMatch, но пары, не оценённые
+// из-за недоступности сервиса, пересчитываются резервным скорером
+// и помечаются Degraded. Ошибка потока в целом уже записана в Err пар.
+func (c *Client) StreamMatchWithFallback(ctx context.Context, pairs []MatchPair, window int) []MatchOutcome {
+	outcomes, _ := StreamMatch(ctx, c, pairs, window)
+	if c.fallback == nil {
+		return outcomes
+	}
+
+	var retry []MatchPair
+	var positions []int
+	for i, o := range outcomes {
+		if o.Err != nil && IsUnavailable(o.Err) {
+			retry = append(retry, pairs[i])
+			positions = append(positions, i)
+		}
+	}
+	if len(retry) == 0 {
+		return outcomes
+	}
+
+	log.Warnf("NLP-сервис недоступен, %d пар оценивает резервный скорер", len(retry))
+	fallback, _ := StreamMatch(ctx, c.fallback, retry, window)
+	for i, o := range fallback {
+		o.Degraded = true
+		outcomes[positions[i]] = o
+	}
+	return outcomes
+}
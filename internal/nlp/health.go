@@ -0,0 +1,189 @@
+package nlp
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthService имя сервиса в протоколе grpc.health.v1
+const healthService = "pb.NLPService"
+
+// BackendStatus состояние одной реплики по последней проверке
+type BackendStatus struct {
+	Address   string    `json:"address"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMS int64     `json:"latency_ms"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// healthMonitor периодически опрашивает каждую реплику напрямую.
+// Исключением нездоровых реплик из балансировки занимается сам gRPC
+// через healthCheckConfig, монитор нужен для наблюдаемости.
+type healthMonitor struct {
+	resolve  func(ctx context.Context) ([]string, error)
+	interval time.Duration
+
+	mu       sync.RWMutex
+	conns    map[string]*grpc.ClientConn
+	statuses map[string]BackendStatus
+	lastErr  string
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newHealthMonitor(resolve func(ctx context.Context) ([]string, error), interval time.Duration) *healthMonitor {
+	return &healthMonitor{
+		resolve:  resolve,
+		interval: interval,
+		conns:    make(map[string]*grpc.ClientConn),
+		statuses: make(map[string]BackendStatus),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (m *healthMonitor) run() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.checkAll()
+		select {
+		case <-ticker.C:
+		case <-m.stop:
+			m.mu.Lock()
+			for addr, conn := range m.conns {
+				conn.Close()
+				delete(m.conns, addr)
+			}
+			m.mu.Unlock()
+			return
+		}
+	}
+}
+
+func (m *healthMonitor) close() {
+	close(m.stop)
+	<-m.done
+}
+
+// checkAll обновляет список реплик и проверяет каждую
+func (m *healthMonitor) checkAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), m.interval)
+	defer cancel()
+
+	addrs, err := m.resolve(ctx)
+	m.mu.Lock()
+	if err != nil {
+		m.lastErr = err.Error()
+		m.mu.Unlock()
+		return
+	}
+	m.lastErr = ""
+
+	current := make(map[string]bool, len(addrs))
+	for _, addr := range addrs {
+		current[addr] = true
+	}
+	for addr, conn := range m.conns {
+		if !current[addr] {
+			conn.Close()
+			delete(m.conns, addr)
+			delete(m.statuses, addr)
+		}
+	}
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, addr := range addrs {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			st := m.check(ctx, addr)
+			m.mu.Lock()
+			m.statuses[addr] = st
+			m.mu.Unlock()
+		}(addr)
+	}
+	wg.Wait()
+}
+
+func (m *healthMonitor) check(ctx context.Context, addr string) BackendStatus {
+	st := BackendStatus{Address: addr, CheckedAt: time.Now()}
+
+	m.mu.Lock()
+	conn, ok := m.conns[addr]
+	if !ok {
+		var err error
+		conn, err = grpc.NewClient("passthrough:///"+addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			m.mu.Unlock()
+			st.Status = "UNKNOWN"
+			st.Error = err.Error()
+			return st
+		}
+		m.conns[addr] = conn
+	}
+	m.mu.Unlock()
+
+	checkCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := healthpb.NewHealthClient(conn).Check(checkCtx, &healthpb.HealthCheckRequest{Service: healthService})
+	st.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		st.Status = "UNREACHABLE"
+		st.Error = err.Error()
+		return st
+	}
+	st.Status = resp.Status.String()
+	return st
+}
+
+// snapshot возвращает состояния реплик, отсортированные по адресу
+func (m *healthMonitor) snapshot() ([]BackendStatus, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]BackendStatus, 0, len(m.statuses))
+	for _, st := range m.statuses {
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Address < out[j].Address })
+	return out, m.lastErr
+}
+
+// staticBackends возвращает фиксированный список адресов
+func staticBackends(addrs []string) func(ctx context.Context) ([]string, error) {
+	return func(ctx context.Context) ([]string, error) {
+		return addrs, nil
+	}
+}
+
+// dnsBackends разрешает имя хоста во все адреса реплик
+func dnsBackends(host, port string) func(ctx context.Context) ([]string, error) {
+	return func(ctx context.Context) ([]string, error) {
+		ips, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		addrs := make([]string, 0, len(ips))
+		for _, ip := range ips {
+			addrs = append(addrs, net.JoinHostPort(ip, port))
+		}
+		sort.Strings(addrs)
+		return addrs, nil
+	}
+}
@@ -23,8 +23,10 @@ const (
 )
 
 type ParseRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Text  string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// Разделы резюме; если пусто, сервис разбирает текст целиком
+	Sections      []*Section `protobuf:"bytes,2,rep,name=sections,proto3" json:"sections,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,6 +68,91 @@ func (x *ParseRequest) GetText() string {
 	return ""
 }
 
+func (x *ParseRequest) GetSections() []*Section {
+	if x != nil {
+		return x.Sections
+	}
+	return nil
+}
+
+// Раздел резюме: contacts, summary, experience, education, skills,
+// courses, languages, projects или other. Смещения в символах text.
+type Section struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Heading       string                 `protobuf:"bytes,2,opt,name=heading,proto3" json:"heading,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	Start         int32                  `protobuf:"varint,4,opt,name=start,proto3" json:"start,omitempty"`
+	End           int32                  `protobuf:"varint,5,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Section) Reset() {
+	*x = Section{}
+	mi := &file_proto_nlp_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Section) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Section) ProtoMessage() {}
+
+func (x *Section) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_nlp_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Section.ProtoReflect.Descriptor instead.
+func (*Section) Descriptor() ([]byte, []int) {
+	return file_proto_nlp_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Section) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Section) GetHeading() string {
+	if x != nil {
+		return x.Heading
+	}
+	return ""
+}
+
+func (x *Section) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Section) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Section) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
 type ParseResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ParsedData    string                 `protobuf:"bytes,1,opt,name=parsed_data,json=parsedData,proto3" json:"parsed_data,omitempty"`
@@ -75,7 +162,7 @@ type ParseResponse struct {
 
 func (x *ParseResponse) Reset() {
 	*x = ParseResponse{}
-	mi := &file_proto_nlp_proto_msgTypes[1]
+	mi := &file_proto_nlp_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -87,7 +174,7 @@ func (x *ParseResponse) String() string {
 func (*ParseResponse) ProtoMessage() {}
 
 func (x *ParseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_nlp_proto_msgTypes[1]
+	mi := &file_proto_nlp_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -100,7 +187,7 @@ func (x *ParseResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ParseResponse.ProtoReflect.Descriptor instead.
 func (*ParseResponse) Descriptor() ([]byte, []int) {
-	return file_proto_nlp_proto_rawDescGZIP(), []int{1}
+	return file_proto_nlp_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ParseResponse) GetParsedData() string {
@@ -120,7 +207,7 @@ type MatchRequest struct {
 
 func (x *MatchRequest) Reset() {
 	*x = MatchRequest{}
-	mi := &file_proto_nlp_proto_msgTypes[2]
+	mi := &file_proto_nlp_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -132,7 +219,7 @@ func (x *MatchRequest) String() string {
 func (*MatchRequest) ProtoMessage() {}
 
 func (x *MatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_nlp_proto_msgTypes[2]
+	mi := &file_proto_nlp_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -145,7 +232,7 @@ func (x *MatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MatchRequest.ProtoReflect.Descriptor instead.
 func (*MatchRequest) Descriptor() ([]byte, []int) {
-	return file_proto_nlp_proto_rawDescGZIP(), []int{2}
+	return file_proto_nlp_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *MatchRequest) GetResumeText() string {
@@ -171,7 +258,7 @@ type MatchResponse struct {
 
 func (x *MatchResponse) Reset() {
 	*x = MatchResponse{}
-	mi := &file_proto_nlp_proto_msgTypes[3]
+	mi := &file_proto_nlp_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -183,7 +270,7 @@ func (x *MatchResponse) String() string {
 func (*MatchResponse) ProtoMessage() {}
 
 func (x *MatchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_nlp_proto_msgTypes[3]
+	mi := &file_proto_nlp_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -196,7 +283,7 @@ func (x *MatchResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MatchResponse.ProtoReflect.Descriptor instead.
 func (*MatchResponse) Descriptor() ([]byte, []int) {
-	return file_proto_nlp_proto_rawDescGZIP(), []int{3}
+	return file_proto_nlp_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *MatchResponse) GetScore() float32 {
@@ -206,13 +293,474 @@ func (x *MatchResponse) GetScore() float32 {
 	return 0
 }
 
+type ResumeItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeItem) Reset() {
+	*x = ResumeItem{}
+	mi := &file_proto_nlp_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeItem) ProtoMessage() {}
+
+func (x *ResumeItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_nlp_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeItem.ProtoReflect.Descriptor instead.
+func (*ResumeItem) Descriptor() ([]byte, []int) {
+	return file_proto_nlp_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResumeItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ResumeItem) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type BatchMatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VacancyId     string                 `protobuf:"bytes,1,opt,name=vacancy_id,json=vacancyId,proto3" json:"vacancy_id,omitempty"`
+	VacancyText   string                 `protobuf:"bytes,2,opt,name=vacancy_text,json=vacancyText,proto3" json:"vacancy_text,omitempty"`
+	Resumes       []*ResumeItem          `protobuf:"bytes,3,rep,name=resumes,proto3" json:"resumes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchMatchRequest) Reset() {
+	*x = BatchMatchRequest{}
+	mi := &file_proto_nlp_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchMatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchMatchRequest) ProtoMessage() {}
+
+func (x *BatchMatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_nlp_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchMatchRequest.ProtoReflect.Descriptor instead.
+func (*BatchMatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_nlp_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BatchMatchRequest) GetVacancyId() string {
+	if x != nil {
+		return x.VacancyId
+	}
+	return ""
+}
+
+func (x *BatchMatchRequest) GetVacancyText() string {
+	if x != nil {
+		return x.VacancyText
+	}
+	return ""
+}
+
+func (x *BatchMatchRequest) GetResumes() []*ResumeItem {
+	if x != nil {
+		return x.Resumes
+	}
+	return nil
+}
+
+type BatchMatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Результаты в порядке следования resumes в запросе
+	Results       []*MatchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchMatchResponse) Reset() {
+	*x = BatchMatchResponse{}
+	mi := &file_proto_nlp_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchMatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchMatchResponse) ProtoMessage() {}
+
+func (x *BatchMatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_nlp_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchMatchResponse.ProtoReflect.Descriptor instead.
+func (*BatchMatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_nlp_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BatchMatchResponse) GetResults() []*MatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Пара резюме-вакансия в потоке. Текст достаточно передать один раз
+// для каждого идентификатора: сервер кэширует эмбеддинги в пределах потока.
+type MatchItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	ResumeId      string                 `protobuf:"bytes,2,opt,name=resume_id,json=resumeId,proto3" json:"resume_id,omitempty"`
+	ResumeText    string                 `protobuf:"bytes,3,opt,name=resume_text,json=resumeText,proto3" json:"resume_text,omitempty"`
+	VacancyId     string                 `protobuf:"bytes,4,opt,name=vacancy_id,json=vacancyId,proto3" json:"vacancy_id,omitempty"`
+	VacancyText   string                 `protobuf:"bytes,5,opt,name=vacancy_text,json=vacancyText,proto3" json:"vacancy_text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchItem) Reset() {
+	*x = MatchItem{}
+	mi := &file_proto_nlp_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchItem) ProtoMessage() {}
+
+func (x *MatchItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_nlp_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchItem.ProtoReflect.Descriptor instead.
+func (*MatchItem) Descriptor() ([]byte, []int) {
+	return file_proto_nlp_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MatchItem) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *MatchItem) GetResumeId() string {
+	if x != nil {
+		return x.ResumeId
+	}
+	return ""
+}
+
+func (x *MatchItem) GetResumeText() string {
+	if x != nil {
+		return x.ResumeText
+	}
+	return ""
+}
+
+func (x *MatchItem) GetVacancyId() string {
+	if x != nil {
+		return x.VacancyId
+	}
+	return ""
+}
+
+func (x *MatchItem) GetVacancyText() string {
+	if x != nil {
+		return x.VacancyText
+	}
+	return ""
+}
+
+type MatchResult struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Seq       uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	ResumeId  string                 `protobuf:"bytes,2,opt,name=resume_id,json=resumeId,proto3" json:"resume_id,omitempty"`
+	VacancyId string                 `protobuf:"bytes,3,opt,name=vacancy_id,json=vacancyId,proto3" json:"vacancy_id,omitempty"`
+	Score     float32                `protobuf:"fixed32,4,opt,name=score,proto3" json:"score,omitempty"`
+	// Непустая строка означает ошибку обработки конкретного элемента
+	Error         string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchResult) Reset() {
+	*x = MatchResult{}
+	mi := &file_proto_nlp_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchResult) ProtoMessage() {}
+
+func (x *MatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_nlp_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchResult.ProtoReflect.Descriptor instead.
+func (*MatchResult) Descriptor() ([]byte, []int) {
+	return file_proto_nlp_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MatchResult) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *MatchResult) GetResumeId() string {
+	if x != nil {
+		return x.ResumeId
+	}
+	return ""
+}
+
+func (x *MatchResult) GetVacancyId() string {
+	if x != nil {
+		return x.VacancyId
+	}
+	return ""
+}
+
+func (x *MatchResult) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *MatchResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type EmbedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Texts         []string               `protobuf:"bytes,1,rep,name=texts,proto3" json:"texts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmbedRequest) Reset() {
+	*x = EmbedRequest{}
+	mi := &file_proto_nlp_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmbedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmbedRequest) ProtoMessage() {}
+
+func (x *EmbedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_nlp_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmbedRequest.ProtoReflect.Descriptor instead.
+func (*EmbedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_nlp_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *EmbedRequest) GetTexts() []string {
+	if x != nil {
+		return x.Texts
+	}
+	return nil
+}
+
+type Embedding struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []float32              `protobuf:"fixed32,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Embedding) Reset() {
+	*x = Embedding{}
+	mi := &file_proto_nlp_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Embedding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Embedding) ProtoMessage() {}
+
+func (x *Embedding) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_nlp_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Embedding.ProtoReflect.Descriptor instead.
+func (*Embedding) Descriptor() ([]byte, []int) {
+	return file_proto_nlp_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Embedding) GetValues() []float32 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type EmbedResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Векторы нормализованы и идут в порядке texts в запросе
+	Embeddings []*Embedding `protobuf:"bytes,1,rep,name=embeddings,proto3" json:"embeddings,omitempty"`
+	// Модель, которой посчитаны векторы: векторы разных моделей несравнимы
+	Model         string `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmbedResponse) Reset() {
+	*x = EmbedResponse{}
+	mi := &file_proto_nlp_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmbedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmbedResponse) ProtoMessage() {}
+
+func (x *EmbedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_nlp_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmbedResponse.ProtoReflect.Descriptor instead.
+func (*EmbedResponse) Descriptor() ([]byte, []int) {
+	return file_proto_nlp_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *EmbedResponse) GetEmbeddings() []*Embedding {
+	if x != nil {
+		return x.Embeddings
+	}
+	return nil
+}
+
+func (x *EmbedResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
 var File_proto_nlp_proto protoreflect.FileDescriptor
 
 const file_proto_nlp_proto_rawDesc = "" +
 	"\n" +
-	"\x0fproto/nlp.proto\x12\x02pb\"\"\n" +
+	"\x0fproto/nlp.proto\x12\x02pb\"K\n" +
 	"\fParseRequest\x12\x12\n" +
-	"\x04text\x18\x01 \x01(\tR\x04text\"0\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12'\n" +
+	"\bsections\x18\x02 \x03(\v2\v.pb.SectionR\bsections\"s\n" +
+	"\aSection\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x18\n" +
+	"\aheading\x18\x02 \x01(\tR\aheading\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x12\x14\n" +
+	"\x05start\x18\x04 \x01(\x05R\x05start\x12\x10\n" +
+	"\x03end\x18\x05 \x01(\x05R\x03end\"0\n" +
 	"\rParseResponse\x12\x1f\n" +
 	"\vparsed_data\x18\x01 \x01(\tR\n" +
 	"parsedData\"R\n" +
@@ -221,11 +769,50 @@ const file_proto_nlp_proto_rawDesc = "" +
 	"resumeText\x12!\n" +
 	"\fvacancy_text\x18\x02 \x01(\tR\vvacancyText\"%\n" +
 	"\rMatchResponse\x12\x14\n" +
-	"\x05score\x18\x01 \x01(\x02R\x05score2\x7f\n" +
+	"\x05score\x18\x01 \x01(\x02R\x05score\"0\n" +
+	"\n" +
+	"ResumeItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\"\x7f\n" +
+	"\x11BatchMatchRequest\x12\x1d\n" +
+	"\n" +
+	"vacancy_id\x18\x01 \x01(\tR\tvacancyId\x12!\n" +
+	"\fvacancy_text\x18\x02 \x01(\tR\vvacancyText\x12(\n" +
+	"\aresumes\x18\x03 \x03(\v2\x0e.pb.ResumeItemR\aresumes\"?\n" +
+	"\x12BatchMatchResponse\x12)\n" +
+	"\aresults\x18\x01 \x03(\v2\x0f.pb.MatchResultR\aresults\"\x9d\x01\n" +
+	"\tMatchItem\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12\x1b\n" +
+	"\tresume_id\x18\x02 \x01(\tR\bresumeId\x12\x1f\n" +
+	"\vresume_text\x18\x03 \x01(\tR\n" +
+	"resumeText\x12\x1d\n" +
+	"\n" +
+	"vacancy_id\x18\x04 \x01(\tR\tvacancyId\x12!\n" +
+	"\fvacancy_text\x18\x05 \x01(\tR\vvacancyText\"\x87\x01\n" +
+	"\vMatchResult\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12\x1b\n" +
+	"\tresume_id\x18\x02 \x01(\tR\bresumeId\x12\x1d\n" +
+	"\n" +
+	"vacancy_id\x18\x03 \x01(\tR\tvacancyId\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x02R\x05score\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"$\n" +
+	"\fEmbedRequest\x12\x14\n" +
+	"\x05texts\x18\x01 \x03(\tR\x05texts\"#\n" +
+	"\tEmbedding\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\x02R\x06values\"T\n" +
+	"\rEmbedResponse\x12-\n" +
+	"\n" +
+	"embeddings\x18\x01 \x03(\v2\r.pb.EmbeddingR\n" +
+	"embeddings\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model2\xa3\x02\n" +
 	"\n" +
 	"NLPService\x124\n" +
 	"\vParseResume\x12\x10.pb.ParseRequest\x1a\x11.pb.ParseResponse\"\x00\x12;\n" +
-	"\x12MatchResumeVacancy\x12\x10.pb.MatchRequest\x1a\x11.pb.MatchResponse\"\x00B+Z)github.com/moverq1337/VTBHack/internal/pbb\x06proto3"
+	"\x12MatchResumeVacancy\x12\x10.pb.MatchRequest\x1a\x11.pb.MatchResponse\"\x00\x12=\n" +
+	"\n" +
+	"BatchMatch\x12\x15.pb.BatchMatchRequest\x1a\x16.pb.BatchMatchResponse\"\x00\x123\n" +
+	"\vStreamMatch\x12\r.pb.MatchItem\x1a\x0f.pb.MatchResult\"\x00(\x010\x01\x12.\n" +
+	"\x05Embed\x12\x10.pb.EmbedRequest\x1a\x11.pb.EmbedResponse\"\x00B+Z)github.com/moverq1337/VTBHack/internal/pbb\x06proto3"
 
 var (
 	file_proto_nlp_proto_rawDescOnce sync.Once
@@ -239,23 +826,42 @@ func file_proto_nlp_proto_rawDescGZIP() []byte {
 	return file_proto_nlp_proto_rawDescData
 }
 
-var file_proto_nlp_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_nlp_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_proto_nlp_proto_goTypes = []any{
-	(*ParseRequest)(nil),  // 0: pb.ParseRequest
-	(*ParseResponse)(nil), // 1: pb.ParseResponse
-	(*MatchRequest)(nil),  // 2: pb.MatchRequest
-	(*MatchResponse)(nil), // 3: pb.MatchResponse
+	(*ParseRequest)(nil),       // 0: pb.ParseRequest
+	(*Section)(nil),            // 1: pb.Section
+	(*ParseResponse)(nil),      // 2: pb.ParseResponse
+	(*MatchRequest)(nil),       // 3: pb.MatchRequest
+	(*MatchResponse)(nil),      // 4: pb.MatchResponse
+	(*ResumeItem)(nil),         // 5: pb.ResumeItem
+	(*BatchMatchRequest)(nil),  // 6: pb.BatchMatchRequest
+	(*BatchMatchResponse)(nil), // 7: pb.BatchMatchResponse
+	(*MatchItem)(nil),          // 8: pb.MatchItem
+	(*MatchResult)(nil),        // 9: pb.MatchResult
+	(*EmbedRequest)(nil),       // 10: pb.EmbedRequest
+	(*Embedding)(nil),          // 11: pb.Embedding
+	(*EmbedResponse)(nil),      // 12: pb.EmbedResponse
 }
 var file_proto_nlp_proto_depIdxs = []int32{
-	0, // 0: pb.NLPService.ParseResume:input_type -> pb.ParseRequest
-	2, // 1: pb.NLPService.MatchResumeVacancy:input_type -> pb.MatchRequest
-	1, // 2: pb.NLPService.ParseResume:output_type -> pb.ParseResponse
-	3, // 3: pb.NLPService.MatchResumeVacancy:output_type -> pb.MatchResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1,  // 0: pb.ParseRequest.sections:type_name -> pb.Section
+	5,  // 1: pb.BatchMatchRequest.resumes:type_name -> pb.ResumeItem
+	9,  // 2: pb.BatchMatchResponse.results:type_name -> pb.MatchResult
+	11, // 3: pb.EmbedResponse.embeddings:type_name -> pb.Embedding
+	0,  // 4: pb.NLPService.ParseResume:input_type -> pb.ParseRequest
+	3,  // 5: pb.NLPService.MatchResumeVacancy:input_type -> pb.MatchRequest
+	6,  // 6: pb.NLPService.BatchMatch:input_type -> pb.BatchMatchRequest
+	8,  // 7: pb.NLPService.StreamMatch:input_type -> pb.MatchItem
+	10, // 8: pb.NLPService.Embed:input_type -> pb.EmbedRequest
+	2,  // 9: pb.NLPService.ParseResume:output_type -> pb.ParseResponse
+	4,  // 10: pb.NLPService.MatchResumeVacancy:output_type -> pb.MatchResponse
+	7,  // 11: pb.NLPService.BatchMatch:output_type -> pb.BatchMatchResponse
+	9,  // 12: pb.NLPService.StreamMatch:output_type -> pb.MatchResult
+	12, // 13: pb.NLPService.Embed:output_type -> pb.EmbedResponse
+	9,  // [9:14] is the sub-list for method output_type
+	4,  // [4:9] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_proto_nlp_proto_init() }
@@ -269,7 +875,7 @@ func file_proto_nlp_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_nlp_proto_rawDesc), len(file_proto_nlp_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,6 +22,9 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	NLPService_ParseResume_FullMethodName        = "/pb.NLPService/ParseResume"
 	NLPService_MatchResumeVacancy_FullMethodName = "/pb.NLPService/MatchResumeVacancy"
+	NLPService_BatchMatch_FullMethodName         = "/pb.NLPService/BatchMatch"
+	NLPService_StreamMatch_FullMethodName        = "/pb.NLPService/StreamMatch"
+	NLPService_Embed_FullMethodName              = "/pb.NLPService/Embed"
 )
 
 // NLPServiceClient is the client API for NLPService service.
@@ -30,6 +33,12 @@ const (
 type NLPServiceClient interface {
 	ParseResume(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
 	MatchResumeVacancy(ctx context.Context, in *MatchRequest, opts ...grpc.CallOption) (*MatchResponse, error)
+	// Пакетное сопоставление одной вакансии с несколькими резюме
+	BatchMatch(ctx context.Context, in *BatchMatchRequest, opts ...grpc.CallOption) (*BatchMatchResponse, error)
+	// Потоковое сопоставление многих резюме со многими вакансиями
+	StreamMatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MatchItem, MatchResult], error)
+	// Векторы текстов для семантического поиска похожих резюме
+	Embed(ctx context.Context, in *EmbedRequest, opts ...grpc.CallOption) (*EmbedResponse, error)
 }
 
 type nLPServiceClient struct {
@@ -60,12 +69,51 @@ func (c *nLPServiceClient) MatchResumeVacancy(ctx context.Context, in *MatchRequ
 	return out, nil
 }
 
+func (c *nLPServiceClient) BatchMatch(ctx context.Context, in *BatchMatchRequest, opts ...grpc.CallOption) (*BatchMatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchMatchResponse)
+	err := c.cc.Invoke(ctx, NLPService_BatchMatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nLPServiceClient) StreamMatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MatchItem, MatchResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NLPService_ServiceDesc.Streams[0], NLPService_StreamMatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[MatchItem, MatchResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NLPService_StreamMatchClient = grpc.BidiStreamingClient[MatchItem, MatchResult]
+
+func (c *nLPServiceClient) Embed(ctx context.Context, in *EmbedRequest, opts ...grpc.CallOption) (*EmbedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmbedResponse)
+	err := c.cc.Invoke(ctx, NLPService_Embed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NLPServiceServer is the server API for NLPService service.
 // All implementations must embed UnimplementedNLPServiceServer
 // for forward compatibility.
 type NLPServiceServer interface {
 	ParseResume(context.Context, *ParseRequest) (*ParseResponse, error)
 	MatchResumeVacancy(context.Context, *MatchRequest) (*MatchResponse, error)
+	// Пакетное сопоставление одной вакансии с несколькими резюме
+	BatchMatch(context.Context, *BatchMatchRequest) (*BatchMatchResponse, error)
+	// Потоковое сопоставление многих резюме со многими вакансиями
+	StreamMatch(grpc.BidiStreamingServer[MatchItem, MatchResult]) error
+	// Векторы текстов для семантического поиска похожих резюме
+	Embed(context.Context, *EmbedRequest) (*EmbedResponse, error)
 	mustEmbedUnimplementedNLPServiceServer()
 }
 
@@ -82,6 +130,15 @@ func (UnimplementedNLPServiceServer) ParseResume(context.Context, *ParseRequest)
 func (UnimplementedNLPServiceServer) MatchResumeVacancy(context.Context, *MatchRequest) (*MatchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MatchResumeVacancy not implemented")
 }
+func (UnimplementedNLPServiceServer) BatchMatch(context.Context, *BatchMatchRequest) (*BatchMatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchMatch not implemented")
+}
+func (UnimplementedNLPServiceServer) StreamMatch(grpc.BidiStreamingServer[MatchItem, MatchResult]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamMatch not implemented")
+}
+func (UnimplementedNLPServiceServer) Embed(context.Context, *EmbedRequest) (*EmbedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Embed not implemented")
+}
 func (UnimplementedNLPServiceServer) mustEmbedUnimplementedNLPServiceServer() {}
 func (UnimplementedNLPServiceServer) testEmbeddedByValue()                    {}
 
@@ -139,6 +196,49 @@ func _NLPService_MatchResumeVacancy_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NLPService_BatchMatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchMatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NLPServiceServer).BatchMatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NLPService_BatchMatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NLPServiceServer).BatchMatch(ctx, req.(*BatchMatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NLPService_StreamMatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NLPServiceServer).StreamMatch(&grpc.GenericServerStream[MatchItem, MatchResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NLPService_StreamMatchServer = grpc.BidiStreamingServer[MatchItem, MatchResult]
+
+func _NLPService_Embed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmbedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NLPServiceServer).Embed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NLPService_Embed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NLPServiceServer).Embed(ctx, req.(*EmbedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NLPService_ServiceDesc is the grpc.ServiceDesc for NLPService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -154,7 +254,22 @@ var NLPService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MatchResumeVacancy",
 			Handler:    _NLPService_MatchResumeVacancy_Handler,
 		},
+		{
+			MethodName: "BatchMatch",
+			Handler:    _NLPService_BatchMatch_Handler,
+		},
+		{
+			MethodName: "Embed",
+			Handler:    _NLPService_Embed_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamMatch",
+			Handler:       _NLPService_StreamMatch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/nlp.proto",
 }
@@ -0,0 +1,190 @@
+package requirements
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// educationRank порядок уровней образования
+var educationRank = map[string]int{
+	EducationSecondary:  1,
+	EducationVocational: 2,
+	EducationIncomplete: 3,
+	EducationHigher:     4,
+	EducationMaster:     5,
+	EducationDoctorate:  6,
+}
+
+var skillRank = map[string]int{SkillBasic: 1, SkillConfident: 2, SkillExpert: 3}
+
+var cefrRank = map[string]int{"A1": 1, "A2": 2, "B1": 3, "B2": 4, "C1": 5, "C2": 6}
+
+// levelRank порядковый номер уровня для сравнения; 0 - уровень неизвестен
+func levelRank(kind Kind, level string) int {
+	switch kind {
+	case KindSkill:
+		return skillRank[level]
+	case KindLanguage:
+		return cefrRank[strings.ToUpper(level)]
+	case KindEducation:
+		return educationRank[level]
+	}
+	return 0
+}
+
+// Rank порядковый номер уровня пункта; 0 - уровень не указан
+func (i Item) Rank() int {
+	return levelRank(i.Kind, i.Level)
+}
+
+// educationIn уровни образования, упомянутые в тексте. "Неоконченное высшее"
+// вырезается до поиска высшего, иначе оно засчитывалось бы как высшее.
+func educationIn(text string) []string {
+	var found []string
+	rest := text
+	for _, e := range educationLevels {
+		if e.level == EducationIncomplete && e.pattern.MatchString(text) {
+			found = append(found, e.level)
+			rest = e.pattern.ReplaceAllString(rest, " ")
+		}
+	}
+	for _, e := range educationLevels {
+		if e.level != EducationIncomplete && e.pattern.MatchString(rest) {
+			found = append(found, e.level)
+		}
+	}
+	return found
+}
+
+// minEducation минимальный из названных уровней: "высшее или среднее специальное"
+func minEducation(text string) string {
+	best := ""
+	for _, level := range educationIn(text) {
+		if best == "" || educationRank[level] < educationRank[best] {
+			best = level
+		}
+	}
+	return best
+}
+
+// Education высший уровень образования, упомянутый в резюме
+func Education(text string) string {
+	best := ""
+	for _, level := range educationIn(text) {
+		if educationRank[level] > educationRank[best] {
+			best = level
+		}
+	}
+	return best
+}
+
+// EducationRank порядковый номер уровня образования; 0 - неизвестен
+func EducationRank(level string) int {
+	return educationRank[level]
+}
+
+// languageIn первый упомянутый иностранный язык
+func languageIn(text string) (string, bool) {
+	for _, l := range languages {
+		if l.pattern.MatchString(text) {
+			return l.name, true
+		}
+	}
+	return "", false
+}
+
+// languageLevel уровень языка по CEFR или словесному описанию
+func languageLevel(text string) string {
+	if m := cefrPattern.FindStringSubmatch(text); m != nil {
+		return strings.ToUpper(m[1])
+	}
+	for _, l := range languageLevels {
+		if l.pattern.MatchString(text) {
+			return l.level
+		}
+	}
+	return ""
+}
+
+// Language ищет язык в тексте резюме и уровень в той же строке. Если язык
+// упомянут несколько раз, берётся самый высокий уровень.
+func Language(text, name string) (level string, found bool) {
+	var pattern *regexp.Regexp
+	for _, l := range languages {
+		if l.name == name {
+			pattern = l.pattern
+		}
+	}
+	if pattern == nil {
+		return "", false
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		loc := pattern.FindStringIndex(line)
+		if loc == nil {
+			continue
+		}
+		found = true
+		// Уровень ищется после названия языка: "Русский - родной; Английский - B2"
+		tail := line[loc[0]:]
+		if next := strings.IndexAny(tail, ";,"); next > 0 {
+			tail = tail[:next]
+		}
+		if l := languageLevel(tail); cefrRank[l] > cefrRank[level] {
+			level = l
+		}
+	}
+	return level, found
+}
+
+// Languages иностранные языки, упомянутые в тексте резюме, с уровнем,
+// если он указан: ["английский B2", "немецкий"]
+func Languages(text string) []string {
+	var out []string
+	for _, l := range languages {
+		level, found := Language(text, l.name)
+		if !found {
+			continue
+		}
+		if level != "" {
+			out = append(out, l.name+" "+level)
+		} else {
+			out = append(out, l.name)
+		}
+	}
+	return out
+}
+
+// LanguageName каноническое название языка: "English" -> "английский"
+func LanguageName(s string) (string, bool) {
+	return languageIn(s)
+}
+
+// LanguageRank порядковый номер уровня CEFR; 0 - неизвестен
+func LanguageRank(level string) int {
+	return cefrRank[strings.ToUpper(level)]
+}
+
+// skillLevel уровень владения навыком по формулировке пункта
+func skillLevel(text string) string {
+	for _, l := range skillLevels {
+		if l.pattern.MatchString(text) {
+			return l.level
+		}
+	}
+	return ""
+}
+
+// experienceYearsIn требуемый стаж в пункте требований
+func experienceYearsIn(text string) float64 {
+	m := experienceYears.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+	years, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", "."), 64)
+	if err != nil {
+		return 0
+	}
+	return years
+}
@@ -0,0 +1,285 @@
+package requirements
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+)
+
+// Kind тип требования
+type Kind string
+
+const (
+	KindSkill         Kind = "skill"
+	KindExperience    Kind = "experience"
+	KindEducation     Kind = "education"
+	KindLanguage      Kind = "language"
+	KindCertification Kind = "certification"
+	KindOther         Kind = "other"
+)
+
+// Priority обязательность требования
+type Priority string
+
+const (
+	PriorityMust Priority = "must"
+	PriorityNice Priority = "nice"
+)
+
+// Уровни владения навыком
+const (
+	SkillBasic     = "basic"
+	SkillConfident = "confident"
+	SkillExpert    = "expert"
+)
+
+// Уровни образования
+const (
+	EducationSecondary  = "secondary"
+	EducationVocational = "vocational" // Среднее специальное
+	EducationIncomplete = "incomplete_higher"
+	EducationHigher     = "higher"
+	EducationMaster     = "master"
+	EducationDoctorate  = "doctorate"
+)
+
+// Item пункт требований вакансии. Name - канонический навык, язык или
+// сертификат; Level зависит от типа: basic/confident/expert для навыка,
+// A1-C2 для языка, secondary...doctorate для образования.
+type Item struct {
+	Kind     Kind     `json:"kind"`
+	Priority Priority `json:"priority"`
+	Text     string   `json:"text"`
+	Name     string   `json:"name,omitempty"`
+	Level    string   `json:"level,omitempty"`
+	Years    float64  `json:"years,omitempty"` // Требуемый стаж для experience
+}
+
+// Stored возвращает сохранённые пункты требований вакансии. Для вакансий,
+// загруженных до появления пунктов, они разбираются из текста; ошибка
+// означает повреждённые сохранённые пункты.
+func Stored(v models.Vacancy, t *taxonomy.Taxonomy) ([]Item, error) {
+	var items []Item
+	var err error
+	if v.RequirementItems != "" {
+		err = json.Unmarshal([]byte(v.RequirementItems), &items)
+	}
+	if len(items) == 0 && !v.ItemsEdited {
+		items = Parse(v, t)
+	}
+	return items, err
+}
+
+// Parse разбирает требования вакансии на пункты. Источники: текст
+// требований (с учётом заголовков "Будет плюсом:"), ключевые навыки и поля
+// опыта, образования и языков. Навык, упомянутый в нескольких местах,
+// остаётся одним пунктом; обязательность побеждает.
+func Parse(v models.Vacancy, t *taxonomy.Taxonomy) []Item {
+	var items []Item
+	if years := requiredYears(v.Experience); years > 0 {
+		items = append(items, Item{Kind: KindExperience, Priority: PriorityMust, Text: "Опыт работы: " + v.Experience, Years: years})
+	}
+	if v.Education != "" {
+		items = append(items, Item{Kind: KindEducation, Priority: PriorityMust, Text: "Образование: " + v.Education, Level: minEducation(v.Education)})
+	}
+	for _, lang := range splitSkills(v.Languages) {
+		items = append(items, classify(lang, PriorityMust, t)...)
+	}
+	for _, skill := range splitSkills(v.Skills) {
+		item := Item{Kind: KindSkill, Priority: PriorityMust, Text: skill, Name: skill}
+		if s, ok := t.Lookup(skill); ok {
+			item.Name = s.Name
+		}
+		items = append(items, item)
+	}
+
+	priority := PriorityMust
+	for _, line := range strings.Split(v.Requirements, "\n") {
+		head, rest, isHeading := heading(line)
+		if isHeading {
+			priority = PriorityMust
+			if niceMarker.MatchString(head) {
+				priority = PriorityNice
+			}
+			line = rest
+		}
+		for _, part := range splitRequirements(line) {
+			p := priority
+			if niceMarker.MatchString(part) {
+				p = PriorityNice
+			}
+			items = append(items, classify(part, p, t)...)
+		}
+	}
+	return merge(items)
+}
+
+// heading распознаёт заголовок блока требований: "Будет плюсом:", "Требования",
+// "Nice to have: Kafka". Возвращает заголовок и текст после двоеточия.
+// Без двоеточия заголовком считается только строка из самого маркера:
+// "Желательно знание Python" - это пункт, а не заголовок.
+func heading(line string) (string, string, bool) {
+	line = strings.TrimSpace(listMarker.ReplaceAllString(line, ""))
+	head, rest, colon := strings.Cut(line, ":")
+	head = strings.TrimSpace(head)
+	if len([]rune(head)) > 40 {
+		return "", "", false
+	}
+	if niceMarker.MatchString(head) && (colon || onlyMarker(niceMarker, head)) {
+		return head, rest, true
+	}
+	if mustHeading.MatchString(head) && (colon && strings.TrimSpace(rest) == "" || !colon && onlyMarker(mustHeading, head)) {
+		return head, rest, true
+	}
+	return "", "", false
+}
+
+// onlyMarker сообщает, что строка состоит из маркера и окончания его последнего слова
+func onlyMarker(marker *regexp.Regexp, s string) bool {
+	loc := marker.FindStringIndex(s)
+	return loc != nil && loc[0] == 0 && !strings.ContainsFunc(s[loc[1]:], unicode.IsSpace)
+}
+
+// classify определяет тип пункта. Пункт с несколькими навыками
+// ("Знание Go, PostgreSQL и Redis") даёт по пункту на навык.
+func classify(text string, p Priority, t *taxonomy.Taxonomy) []Item {
+	item := Item{Kind: KindOther, Priority: p, Text: text}
+	skills := uniqueSkills(t.Extract(text))
+
+	switch {
+	case experienceMarker.MatchString(text):
+		item.Kind = KindExperience
+		item.Years = experienceYearsIn(text)
+		if len(skills) == 1 {
+			item.Name = skills[0]
+		}
+		return []Item{item}
+	case certificateMarker.MatchString(text):
+		item.Kind = KindCertification
+		item.Name = text
+		return []Item{item}
+	case educationMarker.MatchString(text):
+		item.Kind = KindEducation
+		item.Level = minEducation(text)
+		return []Item{item}
+	}
+	if lang, ok := languageIn(text); ok {
+		item.Kind = KindLanguage
+		item.Name = lang
+		item.Level = languageLevel(text)
+		return []Item{item}
+	}
+	if len(skills) == 0 {
+		return []Item{item}
+	}
+
+	level := skillLevel(text)
+	out := make([]Item, len(skills))
+	for i, s := range skills {
+		out[i] = Item{Kind: KindSkill, Priority: p, Text: text, Name: s, Level: level}
+	}
+	return out
+}
+
+// merge схлопывает повторы навыков и языков: остаётся первый пункт,
+// обязательность и более высокий уровень берутся из всех повторов
+func merge(items []Item) []Item {
+	out := items[:0]
+	index := make(map[string]int)
+	for _, item := range items {
+		if item.Kind != KindSkill && item.Kind != KindLanguage {
+			out = append(out, item)
+			continue
+		}
+		key := string(item.Kind) + ":" + strings.ToLower(item.Name)
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(out)
+			out = append(out, item)
+			continue
+		}
+		if item.Priority == PriorityMust {
+			out[i].Priority = PriorityMust
+		}
+		if levelRank(item.Kind, item.Level) > levelRank(out[i].Kind, out[i].Level) {
+			out[i].Level = item.Level
+		}
+		if out[i].Text == out[i].Name && item.Text != item.Name {
+			out[i].Text = item.Text
+		}
+	}
+	return out
+}
+
+func uniqueSkills(names []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, n := range names {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// Validate проверяет пункт, заданный рекрутером, и дополняет пропуски
+// по тексту: тип other, обязательность must, канонический навык
+func Validate(item Item, t *taxonomy.Taxonomy) (Item, error) {
+	item.Text = strings.TrimSpace(item.Text)
+	item.Name = strings.TrimSpace(item.Name)
+	if item.Text == "" && item.Name == "" {
+		return item, fmt.Errorf("пустое требование")
+	}
+	if item.Text == "" {
+		item.Text = item.Name
+	}
+
+	switch item.Priority {
+	case "":
+		item.Priority = PriorityMust
+	case PriorityMust, PriorityNice:
+	default:
+		return item, fmt.Errorf("неизвестная обязательность %q: must или nice", item.Priority)
+	}
+
+	switch item.Kind {
+	case "":
+		item.Kind = KindOther
+	case KindSkill:
+		if item.Name == "" {
+			item.Name = item.Text
+		}
+		if s, ok := t.Lookup(item.Name); ok {
+			item.Name = s.Name
+		}
+	case KindExperience:
+		if item.Years <= 0 {
+			item.Years = experienceYearsIn(item.Text)
+		}
+		if item.Years <= 0 {
+			return item, fmt.Errorf("не указан стаж: %s", item.Text)
+		}
+	case KindLanguage:
+		if item.Name == "" {
+			lang, ok := languageIn(item.Text)
+			if !ok {
+				return item, fmt.Errorf("не указан язык: %s", item.Text)
+			}
+			item.Name = lang
+		}
+	case KindEducation, KindCertification, KindOther:
+	default:
+		return item, fmt.Errorf("неизвестный тип требования %q", item.Kind)
+	}
+
+	if item.Level != "" && levelRank(item.Kind, item.Level) == 0 {
+		return item, fmt.Errorf("неизвестный уровень %q для требования типа %s", item.Level, item.Kind)
+	}
+	return item, nil
+}
@@ -0,0 +1,130 @@
+package requirements
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+)
+
+func testTaxonomy() *taxonomy.Taxonomy {
+	return taxonomy.New([]models.Skill{
+		{ID: uuid.New(), Slug: "go", Name: "Go", Aliases: []models.SkillAlias{{Alias: "golang"}}},
+		{ID: uuid.New(), Slug: "postgresql", Name: "PostgreSQL", Aliases: []models.SkillAlias{{Alias: "postgres"}}},
+		{ID: uuid.New(), Slug: "redis", Name: "Redis"},
+		{ID: uuid.New(), Slug: "kafka", Name: "Kafka"},
+		{ID: uuid.New(), Slug: "kubernetes", Name: "Kubernetes", Aliases: []models.SkillAlias{{Alias: "k8s"}}},
+	}, nil)
+}
+
+func TestParse(t *testing.T) {
+	v := models.Vacancy{
+		Experience: "3–6 лет",
+		Skills:     "golang, Kafka",
+		Languages:  "Английский B2",
+		Requirements: "Требования:\n" +
+			"- Уверенное знание Go, PostgreSQL и Redis;\n" +
+			"- Опыт работы с Kubernetes от 2 лет\n" +
+			"- Желательно знание Kafka\n" +
+			"Будет плюсом:\n" +
+			"• Высшее техническое образование\n" +
+			"• Сертификат CKA\n" +
+			"• Глубокое знание Go",
+	}
+	got := Parse(v, testTaxonomy())
+	want := []Item{
+		{Kind: KindExperience, Priority: PriorityMust, Text: "Опыт работы: 3–6 лет", Years: 3},
+		{Kind: KindLanguage, Priority: PriorityMust, Text: "Английский B2", Name: "английский", Level: "B2"},
+		// Повтор навыка берёт высший уровень и обязательность из всех упоминаний
+		{Kind: KindSkill, Priority: PriorityMust, Text: "golang", Name: "Go", Level: SkillExpert},
+		{Kind: KindSkill, Priority: PriorityMust, Text: "Желательно знание Kafka", Name: "Kafka"},
+		{Kind: KindSkill, Priority: PriorityMust, Text: "Уверенное знание Go, PostgreSQL и Redis", Name: "PostgreSQL", Level: SkillConfident},
+		{Kind: KindSkill, Priority: PriorityMust, Text: "Уверенное знание Go, PostgreSQL и Redis", Name: "Redis", Level: SkillConfident},
+		{Kind: KindExperience, Priority: PriorityMust, Text: "Опыт работы с Kubernetes от 2 лет", Name: "Kubernetes", Years: 2},
+		{Kind: KindEducation, Priority: PriorityNice, Text: "Высшее техническое образование", Level: EducationHigher},
+		{Kind: KindCertification, Priority: PriorityNice, Text: "Сертификат CKA", Name: "Сертификат CKA"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestParseNice(t *testing.T) {
+	tests := []struct {
+		requirements string
+		want         []Priority
+	}{
+		// Маркер в самом пункте
+		{"Знание Go\nЖелательно знание Kafka", []Priority{PriorityMust, PriorityNice}},
+		// Заголовок с пунктом в той же строке
+		{"Nice to have: Kafka\nRedis", []Priority{PriorityNice, PriorityNice}},
+		// Заголовок обязательных требований снова переключает на must
+		{"Будет плюсом:\nKafka\nТребования:\nRedis", []Priority{PriorityNice, PriorityMust}},
+		// Строка из одного маркера без двоеточия - тоже заголовок
+		{"Желательно\nKafka", []Priority{PriorityNice}},
+		{"Kafka is a plus", []Priority{PriorityNice}},
+	}
+	for _, tt := range tests {
+		var got []Priority
+		for _, item := range Parse(models.Vacancy{Requirements: tt.requirements}, testTaxonomy()) {
+			got = append(got, item.Priority)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.requirements, got, tt.want)
+		}
+	}
+}
+
+func TestSplitRequirements(t *testing.T) {
+	got := splitRequirements("1) Знание C++ и C#. Опыт с Go; • Docker\n- Java 8.")
+	want := []string{"Знание C++ и C#", "Опыт с Go", "Docker", "Java 8"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitRequirements = %q, want %q", got, want)
+	}
+}
+
+func TestRequiredYears(t *testing.T) {
+	tests := map[string]float64{
+		"":                   0,
+		"Нет опыта":          0,
+		"От 1 года до 3 лет": 1,
+		"3–6 лет":            3,
+		"Более 6 лет":        6,
+		"1,5 года":           1.5,
+	}
+	for in, want := range tests {
+		if got := requiredYears(in); got != want {
+			t.Errorf("requiredYears(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tx := testTaxonomy()
+	tests := []struct {
+		in   Item
+		want Item
+		ok   bool
+	}{
+		{Item{Kind: KindSkill, Text: "golang"}, Item{Kind: KindSkill, Priority: PriorityMust, Text: "golang", Name: "Go"}, true},
+		{Item{Kind: KindExperience, Text: "опыт от 3 лет", Priority: PriorityNice}, Item{Kind: KindExperience, Priority: PriorityNice, Text: "опыт от 3 лет", Years: 3}, true},
+		{Item{Kind: KindLanguage, Text: "English C1", Level: "C1"}, Item{Kind: KindLanguage, Priority: PriorityMust, Text: "English C1", Name: "английский", Level: "C1"}, true},
+		{Item{Text: "Коммуникабельность"}, Item{Kind: KindOther, Priority: PriorityMust, Text: "Коммуникабельность"}, true},
+		{Item{}, Item{}, false},
+		{Item{Text: "Go", Priority: "maybe"}, Item{}, false},
+		{Item{Kind: KindExperience, Text: "большой опыт"}, Item{}, false},
+		{Item{Kind: KindSkill, Text: "Go", Level: "guru"}, Item{}, false},
+	}
+	for _, tt := range tests {
+		got, err := Validate(tt.in, tx)
+		if (err == nil) != tt.ok {
+			t.Errorf("Validate(%+v) error = %v", tt.in, err)
+			continue
+		}
+		if tt.ok && got != tt.want {
+			t.Errorf("Validate(%+v) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package requirements
+
+import "regexp"
+
+var (
+	// niceMarker пункт или заголовок необязательных требований
+	niceMarker = regexp.MustCompile(`(?i)желательн|будет\s+(?:плюсом|преимуществом)|плюсом\s+будет|приветству|как\s+преимущество|не\s+обязательн|необязательн|nice[\s\-]+to[\s\-]+have|is\s+a\s+plus|(?:^|[^\pL])(?:plus|bonus|preferred)(?:$|[^\pL])`)
+	// mustHeading заголовок обязательных требований: "Требования:", "Мы ждём:", "Must have:"
+	mustHeading = regexp.MustCompile(`(?i)^(?:требовани\pL*|обязательн\pL*|мы\s+ждем|ждем\s+от\s+вас|ожидаем|что\s+нужно|must[\s\-]+have|requirements|required)`)
+
+	// experienceMarker пункт с требуемым стажем: "опыт от 3 лет", "3+ years"
+	experienceMarker = regexp.MustCompile(`(?i)(?:опыт|стаж)\pL*[^\n]*?\d+(?:[.,]\d+)?\s*\+?\s*(?:год|лет)|\d+(?:[.,]\d+)?\s*\+?\s*(?:years?|yrs)`)
+	// experienceYears число лет в пункте; в отличие от yearsPattern пропускает "Java 8" до числа лет
+	experienceYears = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(?:[-–—]\s*\d+\s*)?\+?\s*(?:год|лет|years?|yrs)`)
+
+	educationMarker   = regexp.MustCompile(`(?i)образовани|диплом|бакалавр|магистр|учен\pL*\s+степен|degree|bachelor|master'?s|phd`)
+	certificateMarker = regexp.MustCompile(`(?i)сертифик|certifi|(?:^|[^\pL])(?:ccna|ccnp|ccie|pmp|cissp|cisa|ocp|oca|itil|rhce|rhcsa|cka|ckad)(?:$|[^\pL])`)
+)
+
+// skillLevels признаки уровня владения навыком, от высокого к низкому
+var skillLevels = []struct {
+	level   string
+	pattern *regexp.Regexp
+}{
+	{SkillExpert, regexp.MustCompile(`(?i)глубок|отличн|экспертн|продвинут|свободн\pL*\s+владени|deep|expert|advanced|strong|excellent`)},
+	{SkillConfident, regexp.MustCompile(`(?i)уверенн|хорош|опыт\s+(?:работы|разработки)|solid|good|proficien|hands[\s\-]+on`)},
+	{SkillBasic, regexp.MustCompile(`(?i)базов|начальн|основ|понимани|знакомств|basic|familiar|understanding`)},
+}
+
+// languages иностранные языки; Name - каноническое название
+var languages = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"английский", regexp.MustCompile(`(?i)английск|english`)},
+	{"немецкий", regexp.MustCompile(`(?i)немецк|german|deutsch`)},
+	{"французский", regexp.MustCompile(`(?i)французск|french`)},
+	{"испанский", regexp.MustCompile(`(?i)испанск|spanish`)},
+	{"китайский", regexp.MustCompile(`(?i)китайск|chinese|mandarin`)},
+	{"итальянский", regexp.MustCompile(`(?i)итальянск|italian`)},
+	{"японский", regexp.MustCompile(`(?i)японск|japanese`)},
+	{"корейский", regexp.MustCompile(`(?i)корейск|korean`)},
+	{"турецкий", regexp.MustCompile(`(?i)турецк|turkish`)},
+	{"арабский", regexp.MustCompile(`(?i)арабск|arabic`)},
+}
+
+// cefrPattern уровень по шкале CEFR; границы проверяются вручную из-за кириллицы
+var cefrPattern = regexp.MustCompile(`(?i)(?:^|[^\pL\pN])([abc][12])(?:$|[^\pL\pN])`)
+
+// languageLevels словесные обозначения уровня языка. Порядок важен:
+// "upper-intermediate" проверяется раньше "intermediate".
+var languageLevels = []struct {
+	level   string
+	pattern *regexp.Regexp
+}{
+	{"C2", regexp.MustCompile(`(?i)родной|носитель|native|proficiency|билингв|bilingual`)},
+	{"C1", regexp.MustCompile(`(?i)свободн|advanced|fluent`)},
+	{"B2", regexp.MustCompile(`(?i)upper[\s\-]*intermediate|выше\s+среднего|разговорн`)},
+	{"A2", regexp.MustCompile(`(?i)pre[\s\-]*intermediate|elementary|базов|начальн|чтени\pL*\s+(?:технической\s+)?документаци|технический|со\s+словарем`)},
+	{"B1", regexp.MustCompile(`(?i)intermediate|средн|рабоч|working`)},
+	{"A1", regexp.MustCompile(`(?i)beginner|начинающ`)},
+}
+
+// educationLevels уровни образования от высшего к низшему: в тексте
+// "высшее или неоконченное высшее" требуется минимальный из названных
+var educationLevels = []struct {
+	level   string
+	pattern *regexp.Regexp
+}{
+	{EducationDoctorate, regexp.MustCompile(`(?i)кандидат\pL*\s+наук|доктор\pL*\s+наук|учен\pL*\s+степен|phd|ph\.d`)},
+	{EducationMaster, regexp.MustCompile(`(?i)магистр|master`)},
+	{EducationHigher, regexp.MustCompile(`(?i)(?:^|[^\pL])высш\pL*|бакалавр|специалитет|bachelor|degree|university`)},
+	{EducationIncomplete, regexp.MustCompile(`(?i)неоконч\pL*\s+высш|незаконч\pL*\s+высш|студент`)},
+	{EducationVocational, regexp.MustCompile(`(?i)средн\pL*[\s\-]+(?:специальн|профессиональн)|колледж|техникум`)},
+	{EducationSecondary, regexp.MustCompile(`(?i)среднее(?:\s+общее)?\s+образовани|школ`)},
+}
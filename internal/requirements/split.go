@@ -0,0 +1,80 @@
+package requirements
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// listMarker маркеры пунктов списка в начале строки
+	listMarker = regexp.MustCompile(`^\s*(?:[-–—•*·]+|\d+[.)])\s*`)
+	// yearsPattern первое число лет в требовании к опыту: "от 3 лет", "3-6 лет", "1–3 года"
+	yearsPattern = regexp.MustCompile(`(\d+(?:[.,]\d+)?)`)
+	noExperience = regexp.MustCompile(`(?i)нет опыта|без опыта|не требуется|no experience`)
+)
+
+// splitRequirements делит текст требований на отдельные пункты
+// по строкам, маркерам списка, точкам с запятой и предложениям
+func splitRequirements(text string) []string {
+	var items []string
+	for _, line := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == ';' || r == '•' }) {
+		line = listMarker.ReplaceAllString(line, "")
+		for _, part := range splitSentences(line) {
+			part = strings.TrimFunc(part, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsPunct(r) && r != '+' && r != '#' })
+			if len([]rune(part)) >= 3 {
+				items = append(items, part)
+			}
+		}
+	}
+	return items
+}
+
+// splitSkills делит перечень ключевых навыков
+func splitSkills(text string) []string {
+	var skills []string
+	seen := make(map[string]bool)
+	for _, s := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ';' || r == '\n' || r == '•' }) {
+		s = strings.TrimSpace(listMarker.ReplaceAllString(s, ""))
+		key := strings.ToLower(s)
+		if s != "" && !seen[key] {
+			seen[key] = true
+			skills = append(skills, s)
+		}
+	}
+	return skills
+}
+
+// splitSentences делит строку по точкам, за которыми следует пробел
+func splitSentences(line string) []string {
+	var out []string
+	runes := []rune(line)
+	start := 0
+	for i, r := range runes {
+		if (r == '.' || r == '!' || r == '?') && (i+1 == len(runes) || unicode.IsSpace(runes[i+1])) {
+			out = append(out, string(runes[start:i]))
+			start = i + 1
+		}
+	}
+	if start < len(runes) {
+		out = append(out, string(runes[start:]))
+	}
+	return out
+}
+
+// requiredYears извлекает минимальный требуемый опыт в годах; 0 - опыт не требуется
+func requiredYears(experience string) float64 {
+	if experience == "" || noExperience.MatchString(experience) {
+		return 0
+	}
+	m := yearsPattern.FindString(experience)
+	if m == "" {
+		return 0
+	}
+	years, err := strconv.ParseFloat(strings.ReplaceAll(m, ",", "."), 64)
+	if err != nil {
+		return 0
+	}
+	return years
+}
@@ -0,0 +1,194 @@
+package salary
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/moverq1337/VTBHack/internal/segment"
+	"github.com/moverq1337/VTBHack/internal/textproc"
+)
+
+const number = `(\d{1,3}(?:[ \x{00a0}.,]\d{3})+|\d+(?:[.,]\d+)?)`
+
+var (
+	// amountPattern сумма с необязательными валютой и множителем: "от 250 000 руб.", "$4k", "200-250 тыс. ₽"
+	amountPattern = regexp.MustCompile(`(?i)([$€₽])?\s*` + number + `(?:\s*[-–—]\s*` + number + `)?\s*(k|к|тыс\.?|тысяч\pL*|млн\.?|m)?(?:\s*([$€₽₸]|руб\pL*\.?|р\.|rub|rur|usd|долл\pL*\.?|eur|евро|kzt|тенге|byn|cny|юан\pL*))?`)
+	// labelPattern подпись суммы в резюме; слово подписи в группе 1, после
+	// неё не должна идти буква ("доходность")
+	labelPattern = regexp.MustCompile(`(?i)(?:^|[^\pL])((?:желаемая\s+)?(?:зарплата|заработная\s+плата|зп|з/п|оклад|доход|зарплатные\s+ожидания|ожидания|(?:expected\s+|desired\s+)?salary|compensation))`)
+	// percentPattern число оказалось процентом: "на 30%", "12 процентов"
+	percentPattern = regexp.MustCompile(`^\s*(?:%|процент|percent)`)
+
+	netPattern   = regexp.MustCompile(`на\s+руки|нетто|чистыми|после\s+(?:вычета\s+)?налогов|после\s+вычета|\bnet\b`)
+	grossPattern = regexp.MustCompile(`до\s+(?:вычета\s+)?налогов|до\s+вычета|брутто|гросс|\bgross\b`)
+	yearPattern  = regexp.MustCompile(`в\s+год|/\s*год|годовых|per\s+(?:year|annum)|/\s*year|a\s+year`)
+	hourPattern  = regexp.MustCompile(`в\s+час|/\s*час|per\s+hour|/\s*h(?:our)?\b|an\s+hour`)
+)
+
+// hoursPerMonth рабочих часов в месяце для почасовой ставки
+const hoursPerMonth = 160
+
+// Extract находит в резюме ожидаемую зарплату. Сначала ищется сумма
+// в строке с подписью ("Желаемая зарплата: ...") в шапке и разделе о себе,
+// затем сумма с валютой в разделе о себе (в шаблоне hh.ru это "Желаемая
+// должность и зарплата"). Суммы в опыте работы не рассматриваются: там
+// это обороты и бюджеты ("увеличил доход компании на 30%").
+func Extract(text string, sections []segment.Section) (Amount, bool) {
+	header := segment.Of(sections, segment.KindContacts, segment.KindSummary)
+	if len(sections) == 0 {
+		header = []segment.Section{{Kind: segment.KindContacts, Text: text}}
+	}
+	for _, s := range header {
+		if a, ok := findLabelled(s.Text); ok {
+			a.Start += s.Start
+			a.End += s.Start
+			return a, true
+		}
+	}
+
+	for _, s := range segment.Of(sections, segment.KindSummary) {
+		if a, ok := findAmount(s.Text, false); ok {
+			a.Start += s.Start
+			a.End += s.Start
+			return a, true
+		}
+	}
+	return Amount{}, false
+}
+
+// findLabelled ищет сумму в строке после подписи
+func findLabelled(text string) (Amount, bool) {
+	for _, loc := range labelPattern.FindAllStringSubmatchIndex(text, -1) {
+		end := loc[3]
+		if r := []rune(text[end:]); len(r) > 0 && unicode.IsLetter(r[0]) {
+			continue
+		}
+		lineEnd := strings.IndexByte(text[end:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(text) - end
+		}
+		if a, ok := findAmount(text[end:end+lineEnd], true); ok {
+			a.Start += len([]rune(text[:end]))
+			a.End += len([]rune(text[:end]))
+			return a, true
+		}
+	}
+	return Amount{}, false
+}
+
+// findAmount ищет первую сумму в тексте. Проценты суммой не считаются. Без подписи сумма принимается,
+// только если указана валюта или множитель, иначе это может быть любое число.
+func findAmount(text string, labelled bool) (Amount, bool) {
+	for _, m := range amountPattern.FindAllStringSubmatchIndex(text, -1) {
+		group := func(i int) string {
+			if m[2*i] < 0 {
+				return ""
+			}
+			return text[m[2*i]:m[2*i+1]]
+		}
+		prefix, multiplier, suffix := group(1), group(4), group(5)
+		// "200 курсов": однобуквенный множитель оказался началом слова
+		if len([]rune(multiplier)) == 1 && m[9] < len(text) && unicode.IsLetter([]rune(text[m[9]:])[0]) {
+			multiplier, suffix = "", ""
+			m[1] = m[8]
+		}
+		currency := currencyOf(prefix + suffix)
+		if !labelled && currency == "" && multiplier == "" {
+			continue
+		}
+		if currency == "" && multiplier == "" && percentPattern.MatchString(text[m[1]:]) {
+			continue
+		}
+
+		value, ok := parseNumber(group(2))
+		if !ok {
+			continue
+		}
+		value *= multiplierOf(multiplier)
+
+		tail := textproc.Normalize(text[m[1]:min(len(text), m[1]+60)])
+		if line := strings.IndexByte(tail, '\n'); line >= 0 {
+			tail = tail[:line]
+		}
+		switch {
+		case yearPattern.MatchString(tail):
+			value /= 12
+		case hourPattern.MatchString(tail):
+			value *= hoursPerMonth
+		}
+		if value < 1 {
+			continue
+		}
+
+		raw := text[m[0]:m[1]]
+		start := m[0] + len(raw) - len(strings.TrimLeftFunc(raw, unicode.IsSpace))
+		end := m[0] + len(strings.TrimRightFunc(raw, unicode.IsSpace))
+		a := Amount{
+			Value:    int(math.Round(value)),
+			Currency: currency,
+			Raw:      text[start:end],
+			Start:    len([]rune(text[:start])),
+			End:      len([]rune(text[:end])),
+		}
+		if a.Currency == "" {
+			a.Currency = RUB
+		}
+		switch {
+		case netPattern.MatchString(tail):
+			a.Basis = BasisNet
+		case grossPattern.MatchString(tail):
+			a.Basis = BasisGross
+		}
+		return a, true
+	}
+	return Amount{}, false
+}
+
+// parseNumber разбирает число с разделителями разрядов ("250 000", "250.000")
+// или дробное ("4,5")
+func parseNumber(s string) (float64, bool) {
+	s = strings.NewReplacer(" ", "", " ", "").Replace(s)
+	if thousands.MatchString(s) {
+		s = strings.NewReplacer(".", "", ",", "").Replace(s)
+	}
+	v, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+	return v, err == nil
+}
+
+var thousands = regexp.MustCompile(`^\d{1,3}(?:[.,]\d{3})+$`)
+
+func multiplierOf(s string) float64 {
+	s = textproc.Normalize(s)
+	switch {
+	case s == "":
+		return 1
+	case strings.HasPrefix(s, "млн"), s == "m":
+		return 1_000_000
+	default:
+		return 1000
+	}
+}
+
+// currencyOf определяет валюту по символу или названию; пустая строка, если валюта не указана
+func currencyOf(s string) string {
+	s = textproc.Normalize(strings.TrimSpace(s))
+	switch {
+	case s == "":
+		return ""
+	case strings.Contains(s, "$"), strings.HasPrefix(s, "usd"), strings.HasPrefix(s, "долл"):
+		return USD
+	case strings.Contains(s, "€"), strings.HasPrefix(s, "eur"), strings.HasPrefix(s, "евро"):
+		return EUR
+	case strings.Contains(s, "₸"), strings.HasPrefix(s, "kzt"), strings.HasPrefix(s, "тенге"):
+		return KZT
+	case strings.HasPrefix(s, "byn"):
+		return BYN
+	case strings.HasPrefix(s, "cny"), strings.HasPrefix(s, "юан"):
+		return CNY
+	default:
+		return RUB
+	}
+}
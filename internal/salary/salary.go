@@ -0,0 +1,86 @@
+package salary
+
+import "strings"
+
+// Валюты
+const (
+	RUB = "RUB"
+	USD = "USD"
+	EUR = "EUR"
+	KZT = "KZT"
+	BYN = "BYN"
+	CNY = "CNY"
+)
+
+// Basis сумма до или после вычета налогов
+type Basis string
+
+const (
+	BasisUnknown Basis = ""
+	BasisGross   Basis = "gross"
+	BasisNet     Basis = "net"
+)
+
+// incomeTax ставка НДФЛ для пересчёта "на руки" в "до вычета"
+const incomeTax = 0.13
+
+// Amount месячная сумма в валюте
+type Amount struct {
+	Value    int    `json:"value"`
+	Currency string `json:"currency"`
+	Basis    Basis  `json:"basis"`
+	Raw      string `json:"raw,omitempty"`
+	Start    int    `json:"start,omitempty"` // Смещение в тексте резюме, в рунах
+	End      int    `json:"end,omitempty"`
+}
+
+// Rates курсы валют: рублей за единицу валюты
+type Rates map[string]float64
+
+// DefaultRates курсы по умолчанию; действующие курсы хранятся в таблице
+// currency_rates и правятся через административный API
+var DefaultRates = Rates{
+	RUB: 1,
+	USD: 90,
+	EUR: 98,
+	KZT: 0.18,
+	BYN: 27.5,
+	CNY: 12.5,
+}
+
+// Rate курс валюты; false для неизвестной валюты
+func (r Rates) Rate(currency string) (float64, bool) {
+	rate, ok := r[strings.ToUpper(currency)]
+	return rate, ok && rate > 0
+}
+
+// GrossRUB пересчитывает сумму в рубли до вычета налогов. Сумма без
+// указания налогов считается указанной до вычета.
+func (r Rates) GrossRUB(a Amount) (float64, bool) {
+	currency := a.Currency
+	if currency == "" {
+		currency = RUB
+	}
+	rate, ok := r.Rate(currency)
+	if !ok {
+		return 0, false
+	}
+	value := float64(a.Value) * rate
+	if a.Basis == BasisNet {
+		value /= 1 - incomeTax
+	}
+	return value, true
+}
+
+// ParseBasis проверяет обозначение налоговой базы
+func ParseBasis(s string) (Basis, bool) {
+	switch Basis(strings.ToLower(s)) {
+	case BasisUnknown:
+		return BasisUnknown, true
+	case BasisGross:
+		return BasisGross, true
+	case BasisNet:
+		return BasisNet, true
+	}
+	return "", false
+}
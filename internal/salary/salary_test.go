@@ -0,0 +1,73 @@
+package salary
+
+import (
+	"math"
+	"testing"
+
+	"github.com/moverq1337/VTBHack/internal/segment"
+)
+
+func TestFindAmount(t *testing.T) {
+	tests := []struct {
+		text     string
+		labelled bool
+		want     Amount
+		ok       bool
+	}{
+		{": от 250 000 руб. на руки", true, Amount{Value: 250000, Currency: RUB, Basis: BasisNet, Raw: "250 000 руб."}, true},
+		{": 4,5 тыс. $", true, Amount{Value: 4500, Currency: USD, Raw: "4,5 тыс. $"}, true},
+		{"$4k gross", false, Amount{Value: 4000, Currency: USD, Basis: BasisGross, Raw: "$4k"}, true},
+		{"200-250 тыс. ₽", false, Amount{Value: 200000, Currency: RUB, Raw: "200-250 тыс. ₽"}, true},
+		{"3 млн руб. в год", false, Amount{Value: 250000, Currency: RUB, Raw: "3 млн руб."}, true},
+		{"2 000 € в час", false, Amount{Value: 320000, Currency: EUR, Raw: "2 000 €"}, true},
+		{"250.000 тенге", false, Amount{Value: 250000, Currency: KZT, Raw: "250.000 тенге"}, true},
+		{": 180000", true, Amount{Value: 180000, Currency: RUB, Raw: "180000"}, true},
+		{"180000", false, Amount{}, false}, // Число без подписи и валюты
+		{": рост на 30%", true, Amount{}, false},
+		{"200 курсов", false, Amount{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := findAmount(tt.text, tt.labelled)
+		got.Start, got.End = 0, 0
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("findAmount(%q) = %+v, %v; want %+v, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	text := "Иванов Иван\nЖелаемая зарплата: от 250 000 руб. на руки\n\n" +
+		"Опыт работы\nУвеличил доход компании на 30%, бюджет 5 млн руб.\n"
+	got, ok := Extract(text, segment.Segment(text))
+	if !ok || got.Value != 250000 || got.Basis != BasisNet {
+		t.Fatalf("Extract = %+v, %v", got, ok)
+	}
+	if span := string([]rune(text)[got.Start:got.End]); span != got.Raw {
+		t.Errorf("смещения указывают на %q, want %q", span, got.Raw)
+	}
+
+	// Суммы из опыта работы не считаются ожиданиями
+	text = "Иванов Иван\nМосква\n\nОпыт работы\nВыручка проекта 5 млн руб.\n"
+	if got, ok := Extract(text, segment.Segment(text)); ok {
+		t.Errorf("Extract = %+v, want none", got)
+	}
+}
+
+func TestGrossRUB(t *testing.T) {
+	tests := []struct {
+		a    Amount
+		want float64
+		ok   bool
+	}{
+		{Amount{Value: 200000}, 200000, true},
+		{Amount{Value: 87000, Currency: RUB, Basis: BasisNet}, 100000, true},
+		{Amount{Value: 4500, Currency: "usd"}, 405000, true},
+		{Amount{Value: 1000, Currency: "GBP"}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := DefaultRates.GrossRUB(tt.a)
+		if ok != tt.ok || math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("GrossRUB(%+v) = %v, %v; want %v, %v", tt.a, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/moverq1337/VTBHack/internal/salary"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"gorm.io/gorm"
+)
+
+// Env данные для перевода запроса в SQL
+type Env struct {
+	Rates  salary.Rates       // Курсы для сравнения зарплат в рублях
+	Skills *taxonomy.Taxonomy // Приводит навыки к каноническим названиям; может быть nil
+}
+
+// Compile переводит разобранный запрос в условие SQL над таблицей resumes
+func Compile(node Node, env Env) (string, []any) {
+	var b strings.Builder
+	var args []any
+	compile(node, env, &b, &args)
+	return b.String(), args
+}
+
+func compile(node Node, env Env, b *strings.Builder, args *[]any) {
+	switch n := node.(type) {
+	case And:
+		compileList(n.Nodes, " AND ", env, b, args)
+	case Or:
+		compileList(n.Nodes, " OR ", env, b, args)
+	case Not:
+		b.WriteString("NOT ")
+		compile(n.Node, env, b, args)
+	case Term:
+		b.WriteString("(search_ru @@ phraseto_tsquery('russian', ?) OR search_en @@ phraseto_tsquery('english', ?))")
+		*args = append(*args, n.Text, n.Text)
+	case Field:
+		compileField(n, env, b, args)
+	}
+}
+
+func compileList(nodes []Node, sep string, env Env, b *strings.Builder, args *[]any) {
+	b.WriteByte('(')
+	for i, n := range nodes {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		compile(n, env, b, args)
+	}
+	b.WriteByte(')')
+}
+
+// compileField условие на поле. Текстовые колонки приводятся к пустой
+// строке: у резюме, загруженных до появления колонки, в ней NULL, и без
+// этого NOT city:Москва не находил бы их.
+func compileField(f Field, env Env, b *strings.Builder, args *[]any) {
+	switch f.Name {
+	case "experience":
+		b.WriteString("coalesce(experience, 0) " + sqlOp(f.Op) + " ?")
+		*args = append(*args, f.Number)
+	case "salary":
+		expr, exprArgs := grossRUB(env.Rates)
+		b.WriteString("(salary_expect > 0 AND " + expr + " " + sqlOp(f.Op) + " ?)")
+		*args = append(append(*args, exprArgs...), f.Number)
+	case "city", "region", "relocation":
+		b.WriteString("coalesce(" + f.Name + ", '') = ?")
+		*args = append(*args, f.Value)
+	case "skill":
+		value := f.Value
+		if env.Skills != nil {
+			value = env.Skills.NormalizeList(value)
+		}
+		b.WriteString(SkillCondition("skills"))
+		*args = append(*args, value)
+	case "language":
+		b.WriteString("coalesce(languages, '') ILIKE ?")
+		*args = append(*args, "%"+EscapeLike(f.Value)+"%")
+	case "remote":
+		b.WriteString("coalesce(remote, false) = ?")
+		*args = append(*args, f.Value == "true")
+	}
+}
+
+// SkillCondition условие "в списке навыков column через запятую есть навык ?".
+// Элементы сравниваются целиком без учёта регистра: Go не находит MongoDB.
+func SkillCondition(column string) string {
+	return "EXISTS (SELECT 1 FROM unnest(string_to_array(" + column + ", ',')) AS s WHERE lower(trim(s)) = lower(?))"
+}
+
+func sqlOp(op string) string {
+	if op == ":" {
+		return "="
+	}
+	return op
+}
+
+// Terms слова и фразы запроса, кроме отрицаемых, в синтаксисе
+// websearch_to_tsquery через or: по ним считается релевантность и
+// подсвечиваются фрагменты
+func Terms(node Node) string {
+	var parts []string
+	var walk func(Node)
+	walk = func(node Node) {
+		switch n := node.(type) {
+		case And:
+			for _, c := range n.Nodes {
+				walk(c)
+			}
+		case Or:
+			for _, c := range n.Nodes {
+				walk(c)
+			}
+		case Term:
+			text := strings.ReplaceAll(n.Text, `"`, " ")
+			if n.Phrase {
+				text = `"` + text + `"`
+			}
+			parts = append(parts, text)
+		}
+	}
+	walk(node)
+	return strings.Join(parts, " or ")
+}
+
+// Where отбирает резюме по запросу и добавляет колонку rank. Без слов и
+// фраз в запросе релевантность нулевая.
+func Where(tx *gorm.DB, node Node, env Env) (*gorm.DB, error) {
+	columns, err := modelColumns(tx)
+	if err != nil {
+		return nil, err
+	}
+	cond, args := Compile(node, env)
+	tx = tx.Where(cond, args...)
+
+	terms := Terms(node)
+	if terms == "" {
+		return tx.Select(columns + ", 0::float4 AS rank"), nil
+	}
+	return tx.Select(columns+", "+rankExpr, map[string]any{"q": terms}), nil
+}
+
+// EscapeLike экранирует спецсимволы шаблона LIKE в значении
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
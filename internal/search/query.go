@@ -0,0 +1,380 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/moverq1337/VTBHack/internal/geo"
+	"github.com/moverq1337/VTBHack/internal/requirements"
+)
+
+// Node узел разобранного запроса
+type Node interface {
+	node()
+}
+
+// And все условия должны выполняться
+type And struct {
+	Nodes []Node `json:"and"`
+}
+
+// Or должно выполняться хотя бы одно условие
+type Or struct {
+	Nodes []Node `json:"or"`
+}
+
+// Not условие не должно выполняться
+type Not struct {
+	Node Node `json:"not"`
+}
+
+// Term слово или "фраза", которые ищутся в тексте и навыках резюме
+type Term struct {
+	Text   string `json:"term"`
+	Phrase bool   `json:"phrase,omitempty"`
+	Pos    int    `json:"pos"`
+}
+
+// Field условие на поле резюме: experience>=3, city:Москва
+type Field struct {
+	Name   string  `json:"field"`
+	Op     string  `json:"op"`
+	Value  string  `json:"value"`
+	Number float64 `json:"-"` // Значение числового поля
+	Pos    int     `json:"pos"`
+}
+
+func (And) node()   {}
+func (Or) node()    {}
+func (Not) node()   {}
+func (Term) node()  {}
+func (Field) node() {}
+
+// SyntaxError ошибка в запросе; Pos - смещение в символах от начала запроса
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("позиция %d: %s", e.Pos, e.Msg)
+}
+
+// fieldKind тип значения поля
+type fieldKind int
+
+const (
+	fieldNumber fieldKind = iota
+	fieldText
+	fieldBool
+)
+
+// fields поля запроса и их русские синонимы
+var fields = map[string]struct {
+	name string
+	kind fieldKind
+}{
+	"experience": {"experience", fieldNumber}, "опыт": {"experience", fieldNumber},
+	"salary": {"salary", fieldNumber}, "зарплата": {"salary", fieldNumber},
+	"city": {"city", fieldText}, "город": {"city", fieldText},
+	"region": {"region", fieldText}, "регион": {"region", fieldText},
+	"skill": {"skill", fieldText}, "навык": {"skill", fieldText},
+	"language": {"language", fieldText}, "язык": {"language", fieldText},
+	"relocation": {"relocation", fieldText}, "переезд": {"relocation", fieldText},
+	"remote": {"remote", fieldBool}, "удаленка": {"remote", fieldBool},
+}
+
+// Parse разбирает запрос вида
+//
+//	(go OR golang) AND kafka AND NOT стажер AND experience>=3 AND city:Москва
+//
+// Операторы AND, OR, NOT (и И, ИЛИ, НЕ заглавными), скобки; соседние условия
+// без оператора объединяются через AND. Слова и "фразы" ищутся в тексте и
+// навыках, поле:значение и поле>=число - условия на поля резюме.
+func Parse(query string) (Node, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, end: len([]rune(query))}
+	if p.peek().kind == tokenEOF {
+		return nil, &SyntaxError{Pos: 0, Msg: "пустой запрос"}
+	}
+	node, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("лишний символ %q", t.text)}
+	}
+	return node, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenPhrase
+	tokenOp // : = > >= < <=
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// lex делит запрос на слова, фразы в кавычках, скобки и операторы сравнения
+func lex(query string) ([]token, error) {
+	runes := []rune(query)
+	var out []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			out = append(out, token{tokenLParen, "(", i})
+			i++
+		case r == ')':
+			out = append(out, token{tokenRParen, ")", i})
+			i++
+		case r == '"' || r == '«':
+			closing := '"'
+			if r == '«' {
+				closing = '»'
+			}
+			j := i + 1
+			for j < len(runes) && runes[j] != closing {
+				j++
+			}
+			if j == len(runes) {
+				return nil, &SyntaxError{Pos: i, Msg: "не закрыта кавычка"}
+			}
+			out = append(out, token{tokenPhrase, string(runes[i+1 : j]), i})
+			i = j + 1
+		case isOpRune(r):
+			j := i + 1
+			if (r == '>' || r == '<') && j < len(runes) && runes[j] == '=' {
+				j++
+			}
+			out = append(out, token{tokenOp, string(runes[i:j]), i})
+			i = j
+		default:
+			j := i
+			for j < len(runes) && !isDelimiter(runes[j]) {
+				j++
+			}
+			word := string(runes[i:j])
+			kind := tokenWord
+			switch {
+			case strings.EqualFold(word, "AND") || word == "И":
+				kind = tokenAnd
+			case strings.EqualFold(word, "OR") || word == "ИЛИ":
+				kind = tokenOr
+			case strings.EqualFold(word, "NOT") || word == "НЕ":
+				kind = tokenNot
+			}
+			out = append(out, token{kind, word, i})
+			i = j
+		}
+	}
+	return append(out, token{tokenEOF, "", len(runes)}), nil
+}
+
+func isOpRune(r rune) bool {
+	return r == ':' || r == '=' || r == '>' || r == '<'
+}
+
+func isDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' || r == '«' || isOpRune(r)
+}
+
+type parser struct {
+	tokens []token
+	i      int
+	end    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) or() (Node, error) {
+	var nodes []Node
+	for {
+		node, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+		if p.peek().kind != tokenOr {
+			break
+		}
+		p.next()
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return Or{Nodes: nodes}, nil
+}
+
+func (p *parser) and() (Node, error) {
+	var nodes []Node
+	for {
+		node, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+
+		// AND явный или подразумеваемый перед следующим условием
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+			continue
+		case tokenWord, tokenPhrase, tokenLParen, tokenNot:
+			continue
+		}
+		break
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return And{Nodes: nodes}, nil
+}
+
+func (p *parser) unary() (Node, error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+		node, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Node: node}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLParen:
+		node, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &SyntaxError{Pos: t.pos, Msg: "не закрыта скобка"}
+		}
+		return node, nil
+	case tokenPhrase:
+		if strings.TrimSpace(t.text) == "" {
+			return nil, &SyntaxError{Pos: t.pos, Msg: "пустая фраза"}
+		}
+		return Term{Text: t.text, Phrase: true, Pos: t.pos}, nil
+	case tokenWord:
+		if p.peek().kind == tokenOp {
+			return p.field(t)
+		}
+		return Term{Text: t.text, Pos: t.pos}, nil
+	case tokenEOF:
+		return nil, &SyntaxError{Pos: t.pos, Msg: "запрос оборвался: ожидается слово, фраза или скобка"}
+	case tokenOp:
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("оператор %s без имени поля", t.text)}
+	}
+	return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("неожиданное %q: ожидается слово, фраза или скобка", t.text)}
+}
+
+// field разбирает условие на поле и проверяет значение
+func (p *parser) field(name token) (Node, error) {
+	op := p.next()
+	def, ok := fields[strings.ToLower(name.text)]
+	if !ok {
+		return nil, &SyntaxError{Pos: name.pos, Msg: "неизвестное поле " + name.text}
+	}
+
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenPhrase {
+		return nil, &SyntaxError{Pos: value.pos, Msg: "ожидается значение поля " + name.text}
+	}
+	f := Field{Name: def.name, Op: op.text, Value: strings.TrimSpace(value.text), Pos: name.pos}
+	if f.Value == "" {
+		return nil, &SyntaxError{Pos: value.pos, Msg: "пустое значение поля " + name.text}
+	}
+	if f.Op == "=" {
+		f.Op = ":"
+	}
+
+	switch def.kind {
+	case fieldNumber:
+		n, err := strconv.ParseFloat(strings.Replace(f.Value, ",", ".", 1), 64)
+		if err != nil || n < 0 {
+			return nil, &SyntaxError{Pos: value.pos, Msg: fmt.Sprintf("значение поля %s должно быть числом", name.text)}
+		}
+		f.Number = n
+	case fieldBool:
+		if f.Op != ":" {
+			return nil, &SyntaxError{Pos: op.pos, Msg: fmt.Sprintf("поле %s сравнивается только через :", name.text)}
+		}
+		switch strings.ToLower(f.Value) {
+		case "true", "да", "yes":
+			f.Value = "true"
+		case "false", "нет", "no":
+			f.Value = "false"
+		default:
+			return nil, &SyntaxError{Pos: value.pos, Msg: fmt.Sprintf("значение поля %s: true или false", name.text)}
+		}
+	case fieldText:
+		if f.Op != ":" {
+			return nil, &SyntaxError{Pos: op.pos, Msg: fmt.Sprintf("поле %s сравнивается только через :", name.text)}
+		}
+		if err := normalizeValue(&f); err != nil {
+			return nil, &SyntaxError{Pos: value.pos, Msg: err.Error()}
+		}
+	}
+	return f, nil
+}
+
+// normalizeValue приводит значение к виду, в котором оно хранится в резюме
+func normalizeValue(f *Field) error {
+	switch f.Name {
+	case "city":
+		if city, ok := geo.LookupCity(f.Value); ok {
+			f.Value = city.Name
+		}
+	case "region":
+		if region, ok := geo.LookupRegion(f.Value); ok {
+			f.Value = region.Name
+		}
+	case "language":
+		name, ok := requirements.LanguageName(f.Value)
+		if !ok {
+			return fmt.Errorf("неизвестный язык %s", f.Value)
+		}
+		f.Value = name
+	case "relocation":
+		switch geo.Relocation(f.Value) {
+		case geo.RelocationReady, geo.RelocationNotReady:
+		default:
+			return fmt.Errorf("значение поля relocation: ready или not_ready")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  Node
+	}{
+		{
+			name:  "слово",
+			query: "go",
+			want:  Term{Text: "go", Pos: 0},
+		},
+		{
+			name:  "соседние слова через AND",
+			query: "go kafka",
+			want:  And{Nodes: []Node{Term{Text: "go", Pos: 0}, Term{Text: "kafka", Pos: 3}}},
+		},
+		{
+			name:  "AND связывает сильнее OR",
+			query: "go OR rust AND kafka",
+			want: Or{Nodes: []Node{
+				Term{Text: "go", Pos: 0},
+				And{Nodes: []Node{Term{Text: "rust", Pos: 6}, Term{Text: "kafka", Pos: 15}}},
+			}},
+		},
+		{
+			name:  "скобки",
+			query: "(go OR golang) AND kafka",
+			want: And{Nodes: []Node{
+				Or{Nodes: []Node{Term{Text: "go", Pos: 1}, Term{Text: "golang", Pos: 7}}},
+				Term{Text: "kafka", Pos: 19},
+			}},
+		},
+		{
+			name:  "русские операторы",
+			query: "go ИЛИ rust И НЕ стажер",
+			want: Or{Nodes: []Node{
+				Term{Text: "go", Pos: 0},
+				And{Nodes: []Node{Term{Text: "rust", Pos: 7}, Not{Node: Term{Text: "стажер", Pos: 17}}}},
+			}},
+		},
+		{
+			name:  "фраза в кавычках",
+			query: `"machine learning" «тех лид»`,
+			want: And{Nodes: []Node{
+				Term{Text: "machine learning", Phrase: true, Pos: 0},
+				Term{Text: "тех лид", Phrase: true, Pos: 19},
+			}},
+		},
+		{
+			name:  "числовое поле",
+			query: "experience>=3",
+			want:  Field{Name: "experience", Op: ">=", Value: "3", Number: 3, Pos: 0},
+		},
+		{
+			name:  "русский синоним поля и дробь через запятую",
+			query: "опыт<2,5",
+			want:  Field{Name: "experience", Op: "<", Value: "2,5", Number: 2.5, Pos: 0},
+		},
+		{
+			name:  "город приводится к названию справочника",
+			query: "город:спб",
+			want:  Field{Name: "city", Op: ":", Value: "Санкт-Петербург", Pos: 0},
+		},
+		{
+			name:  "равенство как двоеточие",
+			query: "city=Москва",
+			want:  Field{Name: "city", Op: ":", Value: "Москва", Pos: 0},
+		},
+		{
+			name:  "логическое поле",
+			query: "удаленка:да",
+			want:  Field{Name: "remote", Op: ":", Value: "true", Pos: 0},
+		},
+		{
+			name:  "значение поля во фразе",
+			query: `skill:"Spring Boot"`,
+			want:  Field{Name: "skill", Op: ":", Value: "Spring Boot", Pos: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{"", 0},
+		{"   ", 0},
+		{`go "kafka`, 3},
+		{"(go OR rust", 0},
+		{"go)", 2},
+		{"go AND", 6},
+		{">=3", 0},
+		{"age>=3", 0},
+		{"experience>=много", 12},
+		{"experience>=-1", 12},
+		{"city>Москва", 4},
+		{"remote:может", 7},
+		{"language:клингонский", 9},
+		{"relocation:maybe", 11},
+		{`skill:""`, 6},
+		{`""`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			var syntax *SyntaxError
+			if !errors.As(err, &syntax) {
+				t.Fatalf("Parse(%q) error = %v, want *SyntaxError", tt.query, err)
+			}
+			if syntax.Pos != tt.pos {
+				t.Errorf("Parse(%q) pos = %d (%s), want %d", tt.query, syntax.Pos, syntax.Msg, tt.pos)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	const term = "(search_ru @@ phraseto_tsquery('russian', ?) OR search_en @@ phraseto_tsquery('english', ?))"
+	tests := []struct {
+		query string
+		sql   string
+		args  []any
+	}{
+		{
+			query: "go",
+			sql:   term,
+			args:  []any{"go", "go"},
+		},
+		{
+			query: "(go OR golang) AND experience>=3 AND city:Москва",
+			sql:   "((" + term + " OR " + term + ") AND coalesce(experience, 0) >= ? AND coalesce(city, '') = ?)",
+			args:  []any{"go", "go", "golang", "golang", 3.0, "Москва"},
+		},
+		{
+			query: "NOT город:мск",
+			sql:   "NOT coalesce(city, '') = ?",
+			args:  []any{"Москва"},
+		},
+		{
+			query: "skill:c_sharp%",
+			sql:   SkillCondition("skills"),
+			args:  []any{"c_sharp%"},
+		},
+		{
+			query: "remote:no relocation:ready",
+			sql:   "(coalesce(remote, false) = ? AND coalesce(relocation, '') = ?)",
+			args:  []any{false, "ready"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			node, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.query, err)
+			}
+			sql, args := Compile(node, Env{})
+			if sql != tt.sql {
+				t.Errorf("Compile(%q) sql\n got: %s\nwant: %s", tt.query, sql, tt.sql)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("Compile(%q) args = %#v, want %#v", tt.query, args, tt.args)
+			}
+		})
+	}
+}
+
+// TestCompileSkill проверяет, что навык сравнивается с элементами списка
+// целиком. listed повторяет условие SkillCondition на стороне Go.
+func TestCompileSkill(t *testing.T) {
+	skills := taxonomy.New([]models.Skill{
+		{ID: uuid.New(), Slug: "go", Name: "Go", Aliases: []models.SkillAlias{{Alias: "golang"}}},
+		{ID: uuid.New(), Slug: "mongodb", Name: "MongoDB"},
+	}, nil)
+	node, err := Parse("skill:golang")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, args := Compile(node, Env{Skills: skills})
+	if sql != "EXISTS (SELECT 1 FROM unnest(string_to_array(skills, ',')) AS s WHERE lower(trim(s)) = lower(?))" {
+		t.Fatalf("sql = %s", sql)
+	}
+	if !reflect.DeepEqual(args, []any{"Go"}) {
+		t.Fatalf("args = %#v", args)
+	}
+
+	listed := func(list, skill string) bool {
+		for _, s := range strings.Split(list, ",") {
+			if strings.EqualFold(strings.TrimSpace(s), skill) {
+				return true
+			}
+		}
+		return false
+	}
+	tests := map[string]bool{
+		"Go, PostgreSQL":       true,
+		"go":                   true,
+		"MongoDB, Django":      false,
+		"Google Cloud, Go Kit": false,
+		"":                     false,
+	}
+	for list, want := range tests {
+		if got := listed(list, args[0].(string)); got != want {
+			t.Errorf("skill:go в %q = %v, want %v", list, got, want)
+		}
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := map[string]string{
+		"go":      "go",
+		"100%":    `100\%`,
+		"snake_c": `snake\_c`,
+		`a\b`:     `a\\b`,
+	}
+	for in, want := range tests {
+		if got := EscapeLike(in); got != want {
+			t.Errorf("EscapeLike(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package search
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/salary"
+	"gorm.io/gorm"
+)
+
+// Поисковые векторы резюме. Навыки весят больше текста: совпадение по
+// навыку поднимает резюме выше упоминания слова в описании проекта.
+// Колонки вычисляемые, поэтому обновляются при любом изменении текста
+// или навыков без участия приложения.
+const (
+	vectorRU = `setweight(to_tsvector('russian', coalesce(skills, '')), 'A') || setweight(to_tsvector('russian', coalesce(text, '')), 'B')`
+	vectorEN = `setweight(to_tsvector('english', coalesce(skills, '')), 'A') || setweight(to_tsvector('english', coalesce(text, '')), 'B')`
+
+	queryRU = `websearch_to_tsquery('russian', @q)`
+	queryEN = `websearch_to_tsquery('english', @q)`
+)
+
+// Migrate добавляет в resumes поисковые колонки search_ru, search_en и
+// GIN-индексы по ним. Повторный запуск ничего не меняет.
+func Migrate(db *gorm.DB) error {
+	statements := []string{
+		`ALTER TABLE resumes ADD COLUMN IF NOT EXISTS search_ru tsvector GENERATED ALWAYS AS (` + vectorRU + `) STORED`,
+		`ALTER TABLE resumes ADD COLUMN IF NOT EXISTS search_en tsvector GENERATED ALWAYS AS (` + vectorEN + `) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_resumes_search_ru ON resumes USING GIN (search_ru)`,
+		`CREATE INDEX IF NOT EXISTS idx_resumes_search_en ON resumes USING GIN (search_en)`,
+	}
+	for _, s := range statements {
+		if err := db.Exec(s).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rankExpr релевантность резюме запросу @q: лучшая из двух конфигураций
+const rankExpr = "GREATEST(ts_rank_cd(search_ru, " + queryRU + "), ts_rank_cd(search_en, " + queryEN + ")) AS rank"
+
+// Match отбирает резюме, подходящие под запрос хотя бы в одной из
+// конфигураций, и добавляет колонку rank. Запрос в синтаксисе
+// websearch_to_tsquery: слова через пробел, "точная фраза", or, -исключение.
+func Match(tx *gorm.DB, query string) (*gorm.DB, error) {
+	columns, err := modelColumns(tx)
+	if err != nil {
+		return nil, err
+	}
+	args := map[string]any{"q": query}
+	return tx.
+		Select(columns+", "+rankExpr, args).
+		Where("search_ru @@ "+queryRU+" OR search_en @@ "+queryEN, args), nil
+}
+
+// modelColumns колонки модели запроса через запятую. Выбираются только они:
+// поисковые векторы в ответ не попадают.
+func modelColumns(tx *gorm.DB) (string, error) {
+	if err := tx.Statement.Parse(tx.Statement.Model); err != nil {
+		return "", err
+	}
+	columns := make([]string, len(tx.Statement.Schema.DBNames))
+	for i, name := range tx.Statement.Schema.DBNames {
+		columns[i] = tx.Statement.Schema.Table + "." + name
+	}
+	return strings.Join(columns, ", "), nil
+}
+
+// SalaryUpTo отбирает резюме с ожиданиями не выше max рублей до вычета
+// налогов. Резюме без ожиданий подходят под любой бюджет.
+func SalaryUpTo(tx *gorm.DB, rates salary.Rates, max float64) *gorm.DB {
+	expr, args := grossRUB(rates)
+	return tx.Where("(salary_expect = 0 OR "+expr+" <= ?)", append(args, max)...)
+}
+
+// SalaryFrom отбирает резюме с ожиданиями не ниже min рублей до вычета налогов
+func SalaryFrom(tx *gorm.DB, rates salary.Rates, min float64) *gorm.DB {
+	expr, args := grossRUB(rates)
+	return tx.Where("(salary_expect > 0 AND "+expr+" >= ?)", append(args, min)...)
+}
+
+// grossRUB выражение ожиданий резюме в рублях до вычета налогов.
+// Множители берутся из тех же курсов, что и при сопоставлении; для
+// неизвестной валюты выражение равно NULL и условие не выполняется.
+func grossRUB(rates salary.Rates) (string, []any) {
+	currencies := make([]string, 0, len(rates))
+	for currency := range rates {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	var b strings.Builder
+	var args []any
+	b.WriteString("salary_expect * CASE")
+	for _, currency := range currencies {
+		for _, basis := range []salary.Basis{salary.BasisNet, salary.BasisGross} {
+			factor, ok := rates.GrossRUB(salary.Amount{Value: 1, Currency: currency, Basis: basis})
+			if !ok {
+				continue
+			}
+			cond := "coalesce(salary_basis, '') = 'net'"
+			if basis != salary.BasisNet {
+				cond = "coalesce(salary_basis, '') <> 'net'"
+			}
+			b.WriteString(" WHEN coalesce(nullif(salary_currency, ''), 'RUB') = ? AND " + cond + " THEN ?::float8")
+			args = append(args, currency, factor)
+		}
+	}
+	b.WriteString(" END")
+	return b.String(), args
+}
+
+// Snippets находит фрагменты текста резюме с совпадениями для страницы результатов
+func Snippets(db *gorm.DB, ids []uuid.UUID, query string) (map[uuid.UUID][]Snippet, error) {
+	out := make(map[uuid.UUID][]Snippet, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	var rows []struct {
+		ID       uuid.UUID
+		Headline string
+	}
+	err := db.Raw(`SELECT id, ts_headline('russian', text, `+queryRU+`, @options) AS headline
+		FROM resumes WHERE id IN @ids`,
+		map[string]any{"q": query, "options": headlineOptions, "ids": ids}).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		out[row.ID] = parseHeadline(row.Headline)
+	}
+	return out, nil
+}
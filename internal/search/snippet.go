@@ -0,0 +1,64 @@
+package search
+
+import "strings"
+
+// Разметка ts_headline: начало и конец совпадения, граница фрагментов.
+// Управляющие символы не встречаются в тексте резюме, поэтому разметку
+// можно снять без экранирования.
+const (
+	startSel          = "\x01"
+	stopSel           = "\x02"
+	fragmentDelimiter = "\x03"
+)
+
+// headlineOptions до трёх фрагментов по 10-25 слов
+const headlineOptions = "StartSel=" + startSel + ", StopSel=" + stopSel +
+	", MaxFragments=3, MinWords=10, MaxWords=25, FragmentDelimiter=" + fragmentDelimiter
+
+// Snippet фрагмент текста резюме с совпадениями
+type Snippet struct {
+	Text       string  `json:"text"`
+	Highlights []Range `json:"highlights"`
+}
+
+// Range совпадение во фрагменте; смещения в символах, конец не включается
+type Range struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// parseHeadline разбирает результат ts_headline на фрагменты и снимает
+// разметку совпадений, запоминая их смещения
+func parseHeadline(headline string) []Snippet {
+	var out []Snippet
+	for _, part := range strings.Split(headline, fragmentDelimiter) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		s := Snippet{Highlights: []Range{}}
+		var b strings.Builder
+		pos, start := 0, -1
+		for _, r := range part {
+			switch string(r) {
+			case startSel:
+				start = pos
+			case stopSel:
+				if start >= 0 && pos > start {
+					s.Highlights = append(s.Highlights, Range{Start: start, End: pos})
+				}
+				start = -1
+			default:
+				b.WriteRune(r)
+				pos++
+			}
+		}
+		s.Text = b.String()
+		out = append(out, s)
+	}
+	if out == nil {
+		out = []Snippet{}
+	}
+	return out
+}
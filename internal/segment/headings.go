@@ -0,0 +1,26 @@
+package segment
+
+import "regexp"
+
+// maxHeadingRunes строки длиннее не считаются заголовками
+const maxHeadingRunes = 60
+
+// headingRules заголовки разделов в шаблонах hh.ru, SuperJob и в свободных
+// резюме на русском и английском. Сравниваются с нормализованной строкой
+// без двоеточия в конце.
+var headingRules = []struct {
+	kind    Kind
+	pattern *regexp.Regexp
+}{
+	{KindContacts, regexp.MustCompile(`^(?:контакты|контактная информация|контактные данные|личная информация|личные данные|contacts?|contact (?:information|info|details)|personal (?:information|info|details))$`)},
+	{KindSummary, regexp.MustCompile(`^(?:обо мне|о себе|коротко о себе|дополнительная информация|профиль|цель|желаемая должность(?: и зарплата)?|summary|professional summary|about(?: me)?|profile|objective|career objective)$`)},
+	// hh.ru дописывает к заголовку общий стаж: "Опыт работы — 5 лет 2 месяца"
+	{KindExperience, regexp.MustCompile(`^(?:опыт работы|опыт|профессиональный опыт|трудовая деятельность|места работы|(?:work|professional) experience|experience|employment(?: history)?|work history)(?:\s*[-–—]\s*\d.*)?$`)},
+	{KindEducation, regexp.MustCompile(`^(?:образование|основное образование|высшее образование|education|academic background)$`)},
+	{KindSkills, regexp.MustCompile(`^(?:навыки|ключевые навыки|профессиональные навыки|технические навыки|компетенции|технологии|стек(?: технологий)?|(?:key |technical |hard |soft |core )?skills|core competencies|technologies|tech stack)$`)},
+	{KindCourses, regexp.MustCompile(`^(?:курсы|повышение квалификации(?:,? курсы)?|курсы и тренинги|тренинги|сертификаты|электронные сертификаты|тесты,? экзамены|сертификация|courses|trainings?|certifications?|certificates|licenses (?:&|and) certifications)$`)},
+	{KindLanguages, regexp.MustCompile(`^(?:знание языков|языки|иностранные языки|владение языками|languages|language skills)$`)},
+	{KindProjects, regexp.MustCompile(`^(?:проекты|pet-проекты|личные проекты|портфолио|(?:personal |pet |side )?projects|portfolio|open source)$`)},
+	// Разделы, которые не разбираются, но закрывают предыдущий
+	{KindOther, regexp.MustCompile(`^(?:гражданство.*|рекомендации|хобби|интересы|увлечения|достижения|публикации|hobbies|interests|references|achievements|publications)$`)},
+}
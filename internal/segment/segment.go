@@ -0,0 +1,146 @@
+package segment
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/moverq1337/VTBHack/internal/textproc"
+)
+
+// Kind тип раздела резюме
+type Kind string
+
+const (
+	KindContacts   Kind = "contacts"
+	KindSummary    Kind = "summary"
+	KindExperience Kind = "experience"
+	KindEducation  Kind = "education"
+	KindSkills     Kind = "skills"
+	KindCourses    Kind = "courses"
+	KindLanguages  Kind = "languages"
+	KindProjects   Kind = "projects"
+	KindOther      Kind = "other"
+)
+
+// Section раздел резюме. Смещения указаны в рунах исходного текста,
+// Text совпадает с фрагментом [Start, End) без строки заголовка.
+type Section struct {
+	Kind    Kind   `json:"kind"`
+	Heading string `json:"heading"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	Text    string `json:"text"`
+}
+
+// Segment делит текст резюме на разделы по строкам-заголовкам.
+// Текст до первого заголовка считается контактами: в шаблонах hh.ru и
+// SuperJob там имя, телефон и почта. Заголовок с содержимым в той же строке
+// ("Навыки: Go, Docker") начинает раздел только вне опыта работы, иначе
+// описание стека в должности разрывало бы историю работы.
+func Segment(text string) []Section {
+	runes := []rune(text)
+	var sections []Section
+
+	kind, heading, contentStart := KindContacts, "", 0
+	closeSection := func(end int) {
+		start := contentStart
+		for start < end && unicode.IsSpace(runes[start]) {
+			start++
+		}
+		for end > start && unicode.IsSpace(runes[end-1]) {
+			end--
+		}
+		if start < end {
+			sections = append(sections, Section{Kind: kind, Heading: heading, Start: start, End: end, Text: string(runes[start:end])})
+		}
+	}
+
+	for lineStart := 0; lineStart < len(runes); {
+		lineEnd := lineStart
+		for lineEnd < len(runes) && runes[lineEnd] != '\n' {
+			lineEnd++
+		}
+		line := string(runes[lineStart:lineEnd])
+
+		if k, h, ok := classify(line); ok {
+			closeSection(lineStart)
+			kind, heading, contentStart = k, h, lineEnd
+		} else if k, h, offset, ok := classifyInline(line); ok && kind != KindExperience {
+			closeSection(lineStart)
+			kind, heading, contentStart = k, h, lineStart+offset
+		}
+		lineStart = lineEnd + 1
+	}
+	closeSection(len(runes))
+
+	return sections
+}
+
+// Of возвращает разделы указанных типов в порядке следования в тексте
+func Of(sections []Section, kinds ...Kind) []Section {
+	var out []Section
+	for _, s := range sections {
+		for _, k := range kinds {
+			if s.Kind == k {
+				out = append(out, s)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Work возвращает разделы, в которых описана работа: опыт работы, а если
+// заголовка опыта в резюме нет - всё, кроме учёбы, курсов, навыков и языков
+func Work(sections []Section) []Section {
+	if exp := Of(sections, KindExperience); len(exp) > 0 {
+		return exp
+	}
+	var out []Section
+	for _, s := range sections {
+		switch s.Kind {
+		case KindEducation, KindCourses, KindSkills, KindLanguages:
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// classify проверяет, что строка целиком является заголовком раздела
+func classify(line string) (Kind, string, bool) {
+	heading := strings.TrimSpace(line)
+	if heading == "" || len([]rune(heading)) > maxHeadingRunes {
+		return "", "", false
+	}
+	key := headingKey(heading)
+	for _, rule := range headingRules {
+		if rule.pattern.MatchString(key) {
+			return rule.kind, strings.TrimRight(heading, " :"), true
+		}
+	}
+	return "", "", false
+}
+
+// classifyInline проверяет строку вида "Заголовок: содержимое" и возвращает
+// смещение содержимого в строке, в рунах
+func classifyInline(line string) (Kind, string, int, bool) {
+	prefix, rest, found := strings.Cut(line, ":")
+	if !found || strings.TrimSpace(rest) == "" {
+		return "", "", 0, false
+	}
+	kind, heading, ok := classify(prefix)
+	if !ok {
+		return "", "", 0, false
+	}
+	return kind, heading, len([]rune(prefix)) + 1, true
+}
+
+// headingKey нормализует заголовок для сравнения: регистр, ё, маркеры списков,
+// двоеточие в конце и повторяющиеся пробелы
+func headingKey(heading string) string {
+	key := textproc.Normalize(heading)
+	key = strings.TrimLeft(key, "•*#-–—■▪► \t")
+	key = strings.TrimRight(key, ":. \t")
+	return strings.Join(strings.Fields(key), " ")
+}
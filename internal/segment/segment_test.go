@@ -0,0 +1,125 @@
+package segment
+
+import (
+	"reflect"
+	"testing"
+)
+
+const hhResume = `Иванов Иван
++7 (999) 123-45-67
+ivan@example.com
+
+Желаемая должность и зарплата
+Go-разработчик
+
+Опыт работы — 5 лет 2 месяца
+Январь 2019 — настоящее время
+ООО Ромашка
+Навыки: Go, PostgreSQL, Kafka
+Разработка платёжных сервисов
+
+Образование
+2010 – 2015 МГУ
+
+Ключевые навыки
+Go  Docker  Kubernetes
+
+Знание языков:
+Английский — B2
+
+Хобби
+Шахматы
+`
+
+func TestSegment(t *testing.T) {
+	sections := Segment(hhResume)
+
+	type view struct {
+		kind    Kind
+		heading string
+		text    string
+	}
+	var got []view
+	for _, s := range sections {
+		got = append(got, view{s.Kind, s.Heading, s.Text})
+	}
+	want := []view{
+		{KindContacts, "", "Иванов Иван\n+7 (999) 123-45-67\nivan@example.com"},
+		{KindSummary, "Желаемая должность и зарплата", "Go-разработчик"},
+		// "Навыки:" внутри опыта - описание стека, а не новый раздел
+		{KindExperience, "Опыт работы — 5 лет 2 месяца", "Январь 2019 — настоящее время\nООО Ромашка\nНавыки: Go, PostgreSQL, Kafka\nРазработка платёжных сервисов"},
+		{KindEducation, "Образование", "2010 – 2015 МГУ"},
+		{KindSkills, "Ключевые навыки", "Go  Docker  Kubernetes"},
+		{KindLanguages, "Знание языков", "Английский — B2"},
+		{KindOther, "Хобби", "Шахматы"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Segment =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// Смещения в рунах указывают ровно на текст раздела
+	runes := []rune(hhResume)
+	for _, s := range sections {
+		if string(runes[s.Start:s.End]) != s.Text {
+			t.Errorf("%s: [%d, %d) = %q, Text %q", s.Kind, s.Start, s.End, string(runes[s.Start:s.End]), s.Text)
+		}
+	}
+}
+
+func TestSegmentInline(t *testing.T) {
+	text := "Анна Смирнова\n• Skills: Go, Docker\nEducation:\nMIT, 2015\nEXPERIENCE\n2016 - 2020 Acme\n"
+	var got []Kind
+	for _, s := range Segment(text) {
+		got = append(got, s.Kind)
+	}
+	want := []Kind{KindContacts, KindSkills, KindEducation, KindExperience}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("kinds = %v, want %v", got, want)
+	}
+	skills := Of(Segment(text), KindSkills)
+	if len(skills) != 1 || skills[0].Text != "Go, Docker" || skills[0].Heading != "• Skills" {
+		t.Errorf("skills = %+v", skills)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		line string
+		kind Kind
+		ok   bool
+	}{
+		{"ОПЫТ РАБОТЫ", KindExperience, true},
+		{"Опыт работы — 10 лет", KindExperience, true},
+		{"  Work Experience:", KindExperience, true},
+		{"## Education", KindEducation, true},
+		{"Повышение квалификации, курсы", KindCourses, true},
+		{"Pet projects", KindProjects, true},
+		{"Гражданство: Россия", KindOther, true},
+		{"Опыт работы с высоконагруженными системами и микросервисами", "", false},
+		{"Разработчик", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		kind, _, ok := classify(tt.line)
+		if kind != tt.kind || ok != tt.ok {
+			t.Errorf("classify(%q) = %q, %v; want %q, %v", tt.line, kind, ok, tt.kind, tt.ok)
+		}
+	}
+}
+
+func TestWork(t *testing.T) {
+	with := []Section{{Kind: KindContacts}, {Kind: KindExperience, Text: "a"}, {Kind: KindEducation}, {Kind: KindExperience, Text: "b"}}
+	if got := Work(with); len(got) != 2 || got[0].Text != "a" || got[1].Text != "b" {
+		t.Errorf("Work с опытом = %+v", got)
+	}
+
+	// Без заголовка опыта работой считается всё, кроме учёбы, курсов, навыков и языков
+	without := []Section{{Kind: KindContacts}, {Kind: KindSummary}, {Kind: KindEducation}, {Kind: KindCourses}, {Kind: KindSkills}, {Kind: KindLanguages}, {Kind: KindProjects}}
+	var kinds []Kind
+	for _, s := range Work(without) {
+		kinds = append(kinds, s.Kind)
+	}
+	if !reflect.DeepEqual(kinds, []Kind{KindContacts, KindSummary, KindProjects}) {
+		t.Errorf("Work без опыта = %v", kinds)
+	}
+}
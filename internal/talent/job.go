@@ -0,0 +1,357 @@
+// Package talent описывает базу кандидатов: кластеры похожих резюме,
+// частые сочетания навыков и спрос на навыки относительно предложения.
+package talent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"github.com/moverq1337/VTBHack/internal/vectors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var log = logrus.New()
+
+// Источники признаков для кластеризации
+const (
+	SourceEmbeddings = "embeddings" // Векторы текстов резюме от NLP-сервиса
+	SourceSkills     = "skills"     // Навыки резюме, взвешенные по редкости
+)
+
+const (
+	// runTimeout после которого незавершённый запуск считается упавшим
+	runTimeout = time.Hour
+	// keepRuns последних завершённых запусков хранятся с кластерами
+	keepRuns = 5
+	// maxSkillFeatures самых частых навыков становятся признаками
+	maxSkillFeatures = 500
+	// minResumes для кластеризации
+	minResumes = 4
+	// minEmbeddingCoverage доля резюме вне архива с векторами, с которой
+	// кластеризация по умолчанию идёт по векторам
+	minEmbeddingCoverage = 0.95
+	// startLock ключ advisory-блокировки проверки и создания запуска
+	startLock = 7234003
+)
+
+// ErrRunning кластеризация уже выполняется
+var ErrRunning = errors.New("кластеризация уже выполняется")
+
+// Options параметры запуска кластеризации
+type Options struct {
+	Source string `json:"source"` // embeddings, skills или пусто: векторы, если они посчитаны
+	K      int    `json:"k"`      // Число кластеров; 0 - по числу резюме
+}
+
+// Validate проверяет параметры запуска
+func (o Options) Validate() error {
+	if o.Source != "" && o.Source != SourceEmbeddings && o.Source != SourceSkills {
+		return fmt.Errorf("неизвестный источник признаков %s: embeddings или skills", o.Source)
+	}
+	if o.K < 0 || o.K == 1 {
+		return errors.New("число кластеров должно быть не меньше 2")
+	}
+	return nil
+}
+
+// Start создаёт запуск и выполняет кластеризацию в фоне. Одновременно
+// выполняется один запуск на всю базу, в том числе из разных сервисов:
+// проверка и создание запуска идут под advisory-блокировкой.
+func Start(db *gorm.DB, skills *taxonomy.Store, opts Options) (models.ClusterRun, error) {
+	if err := opts.Validate(); err != nil {
+		return models.ClusterRun{}, err
+	}
+
+	run := models.ClusterRun{ID: uuid.New(), Source: opts.Source, K: opts.K, Status: models.ClusterRunRunning}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", startLock).Error; err != nil {
+			return err
+		}
+		var running int64
+		err := tx.Model(&models.ClusterRun{}).
+			Where("status = ? AND created_at > ?", models.ClusterRunRunning, time.Now().Add(-runTimeout)).
+			Count(&running).Error
+		if err != nil {
+			return err
+		}
+		if running > 0 {
+			return ErrRunning
+		}
+		return tx.Create(&run).Error
+	})
+	if err != nil {
+		return models.ClusterRun{}, err
+	}
+	go execute(db, skills.Current(), run)
+	return run, nil
+}
+
+// Schedule запускает кластеризацию, когда последний запуск старше
+// interval, и проверяет это раз в час, пока не отменён ctx. Нулевой
+// interval отключает расписание.
+func Schedule(ctx context.Context, db *gorm.DB, skills *taxonomy.Store, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	check := min(interval, time.Hour)
+	for {
+		var last models.ClusterRun
+		err := db.Order("created_at DESC").Limit(1).Find(&last).Error
+		switch {
+		case err != nil:
+			log.WithError(err).Error("Ошибка проверки расписания кластеризации")
+		case last.ID == uuid.Nil || time.Since(last.CreatedAt) >= interval:
+			if _, err := Start(db, skills, Options{}); err != nil && !errors.Is(err, ErrRunning) {
+				log.WithError(err).Error("Ошибка запуска кластеризации")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(check):
+		}
+	}
+}
+
+// execute выполняет запуск и записывает результат или ошибку
+func execute(db *gorm.DB, t *taxonomy.Taxonomy, run models.ClusterRun) {
+	started := time.Now()
+	err := cluster(db, t, &run)
+	now := time.Now()
+	run.FinishedAt = &now
+	if err != nil {
+		log.WithError(err).Error("Ошибка кластеризации резюме")
+		run.Status, run.Error = models.ClusterRunFailed, err.Error()
+		if err := db.Select("status", "error", "finished_at").Updates(&run).Error; err != nil {
+			log.WithError(err).Error("Ошибка сохранения запуска кластеризации")
+		}
+		return
+	}
+	log.Infof("Кластеризация: %d резюме, %d кластеров за %s", run.Resumes, run.K, time.Since(started).Round(time.Second))
+	prune(db)
+}
+
+// cluster строит признаки, делит резюме на кластеры и сохраняет их
+// вместе с итогом запуска в одной транзакции
+func cluster(db *gorm.DB, t *taxonomy.Taxonomy, run *models.ClusterRun) error {
+	profiles, err := loadProfiles(db, t)
+	if err != nil {
+		return err
+	}
+
+	source := run.Source
+	if source == "" {
+		embeddings, err := resumeEmbeddings(db)
+		if err != nil {
+			return err
+		}
+		var count int64
+		if err := embeddings.Count(&count).Error; err != nil {
+			return err
+		}
+		source = autoSource(int(count), len(profiles))
+	}
+
+	var ids []uuid.UUID
+	var points [][]float32
+	if source == SourceEmbeddings {
+		ids, points, err = embeddingFeatures(db)
+	} else {
+		ids, points = skillFeatures(profiles)
+	}
+	if err != nil {
+		return err
+	}
+	if len(points) < minResumes {
+		return fmt.Errorf("недостаточно резюме для кластеризации: %d, нужно не меньше %d", len(points), minResumes)
+	}
+
+	k := run.K
+	if k == 0 {
+		k = defaultK(len(points))
+	}
+	k = min(k, len(points))
+	p := kmeans(points, k, run.CreatedAt.UnixNano())
+
+	// Подписи кластеров по навыкам резюме; база для подъёма - все резюме вне архива
+	byID := make(map[uuid.UUID]profile, len(profiles))
+	base := map[string]int{}
+	for _, pr := range profiles {
+		byID[pr.id] = pr
+		for _, s := range pr.skills {
+			base[s]++
+		}
+	}
+
+	groups := make([][]int, k)
+	for i, c := range p.assign {
+		groups[c] = append(groups[c], i)
+	}
+	// Номера кластеров по убыванию размера: первый - самый большой
+	sort.SliceStable(groups, func(i, j int) bool { return len(groups[i]) > len(groups[j]) })
+
+	var clusters []models.Cluster
+	var members []models.ClusterMember
+	var total float64
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		c := models.Cluster{ID: uuid.New(), RunID: run.ID, Number: len(clusters) + 1, Size: len(group)}
+		memberProfiles := make([]profile, 0, len(group))
+		var cohesion float64
+		for _, i := range group {
+			members = append(members, models.ClusterMember{RunID: run.ID, ResumeID: ids[i], ClusterID: c.ID, Similarity: round(p.similarity[i])})
+			memberProfiles = append(memberProfiles, byID[ids[i]])
+			cohesion += p.similarity[i]
+		}
+		total += cohesion
+		c.Cohesion = round(cohesion / float64(len(group)))
+
+		top, label := describe(memberProfiles, base, len(profiles))
+		data, err := json.Marshal(top)
+		if err != nil {
+			return err
+		}
+		c.TopSkills, c.Label = string(data), label
+		clusters = append(clusters, c)
+	}
+
+	run.Source, run.K, run.Resumes = source, len(clusters), len(points)
+	run.Cohesion = round(total / float64(len(points)))
+	run.Status = models.ClusterRunDone
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(&clusters, 500).Error; err != nil {
+			return err
+		}
+		if err := tx.CreateInBatches(&members, 1000).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		run.FinishedAt = &now
+		return tx.Select("source", "k", "resumes", "cohesion", "status", "finished_at").Updates(run).Error
+	})
+}
+
+// autoSource выбирает признаки, когда источник не задан: векторы, только
+// если они посчитаны почти для всех резюме вне архива. Пока векторы
+// догружаются, кластеризация по ним охватила бы лишь часть базы.
+func autoSource(embedded, resumes int) string {
+	if embedded >= minResumes && float64(embedded) >= minEmbeddingCoverage*float64(resumes) {
+		return SourceEmbeddings
+	}
+	return SourceSkills
+}
+
+// resumeEmbeddings запрос векторов резюме вне архива, посчитанных
+// последней моделью: векторы разных моделей несравнимы
+func resumeEmbeddings(db *gorm.DB) (*gorm.DB, error) {
+	var latest models.Embedding
+	if err := db.Where("owner_type = ?", vectors.KindResume).Order("updated_at DESC").Limit(1).Find(&latest).Error; err != nil {
+		return nil, err
+	}
+	return db.Model(&models.Embedding{}).
+		Joins("JOIN resumes ON resumes.id = embeddings.owner_id AND resumes.archived_at IS NULL").
+		Where("embeddings.owner_type = ? AND embeddings.model = ?", vectors.KindResume, latest.Model), nil
+}
+
+// embeddingFeatures векторы резюме для кластеризации
+func embeddingFeatures(db *gorm.DB) ([]uuid.UUID, [][]float32, error) {
+	embeddings, err := resumeEmbeddings(db)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err := embeddings.Select("embeddings.owner_id, embeddings.vector").Rows()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	var points [][]float32
+	for rows.Next() {
+		var id uuid.UUID
+		var data []byte
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, id)
+		points = append(points, vectors.Decode(data))
+	}
+	return ids, points, rows.Err()
+}
+
+// skillFeatures векторы навыков: самые частые навыки базы с весом по
+// редкости (idf), чтобы общие навыки вроде Git не склеивали кластеры.
+// Резюме без навыков в кластеризацию не входят.
+func skillFeatures(profiles []profile) ([]uuid.UUID, [][]float32) {
+	df := map[string]int{}
+	for _, p := range profiles {
+		for _, s := range p.skills {
+			df[s]++
+		}
+	}
+	vocab := make([]string, 0, len(df))
+	for s, n := range df {
+		if n >= 2 {
+			vocab = append(vocab, s)
+		}
+	}
+	sort.Slice(vocab, func(i, j int) bool {
+		if df[vocab[i]] != df[vocab[j]] {
+			return df[vocab[i]] > df[vocab[j]]
+		}
+		return vocab[i] < vocab[j]
+	})
+	if len(vocab) > maxSkillFeatures {
+		vocab = vocab[:maxSkillFeatures]
+	}
+	index := make(map[string]int, len(vocab))
+	for i, s := range vocab {
+		index[s] = i
+	}
+
+	var ids []uuid.UUID
+	var points [][]float32
+	for _, p := range profiles {
+		point := make([]float32, len(vocab))
+		found := false
+		for _, s := range p.skills {
+			if i, ok := index[s]; ok {
+				point[i] = float32(math.Log(1 + float64(len(profiles))/float64(df[s])))
+				found = true
+			}
+		}
+		if found {
+			ids = append(ids, p.id)
+			points = append(points, point)
+		}
+	}
+	return ids, points
+}
+
+// prune удаляет кластеры старых запусков, оставляя keepRuns последних
+// завершённых; сами записи о запусках остаются для истории
+func prune(db *gorm.DB) {
+	keep := db.Model(&models.ClusterRun{}).Select("id").
+		Where("status = ?", models.ClusterRunDone).Order("created_at DESC").Limit(keepRuns)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("run_id NOT IN (?)", keep).Delete(&models.ClusterMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("run_id NOT IN (?)", keep).Delete(&models.Cluster{}).Error
+	})
+	if err != nil {
+		log.WithError(err).Error("Ошибка удаления старых кластеров")
+	}
+}
@@ -0,0 +1,147 @@
+package talent
+
+import (
+	"math"
+	"math/rand"
+)
+
+// maxIterations итераций k-средних; обычно сходится за 10-20
+const maxIterations = 50
+
+// partition результат кластеризации: номер кластера и близость к его
+// центру для каждой точки
+type partition struct {
+	assign     []int
+	similarity []float64
+	centroids  [][]float32
+}
+
+// kmeans сферические k-средних: точки и центры нормализованы, близость -
+// косинусная. Начальные центры выбираются по k-means++: каждый следующий
+// с вероятностью, пропорциональной расстоянию до ближайшего выбранного.
+func kmeans(points [][]float32, k int, seed int64) partition {
+	rng := rand.New(rand.NewSource(seed))
+	for i, p := range points {
+		points[i] = normalize(p)
+	}
+
+	centroids := seedCentroids(points, k, rng)
+	p := partition{assign: make([]int, len(points)), similarity: make([]float64, len(points))}
+	for i := range p.assign {
+		p.assign[i] = -1
+	}
+
+	for iter := 0; iter < maxIterations; iter++ {
+		changed := 0
+		for i, point := range points {
+			best, bestSim := 0, math.Inf(-1)
+			for c, centroid := range centroids {
+				if s := dot(point, centroid); s > bestSim {
+					best, bestSim = c, s
+				}
+			}
+			if p.assign[i] != best {
+				p.assign[i] = best
+				changed++
+			}
+			p.similarity[i] = bestSim
+		}
+		if changed == 0 {
+			break
+		}
+
+		sums := make([][]float64, k)
+		counts := make([]int, k)
+		for c := range sums {
+			sums[c] = make([]float64, len(points[0]))
+		}
+		for i, point := range points {
+			c := p.assign[i]
+			counts[c]++
+			for j, v := range point {
+				sums[c][j] += float64(v)
+			}
+		}
+		for c := range centroids {
+			if counts[c] == 0 {
+				// Пустой кластер получает точку, хуже всех попавшую в свой
+				worst := 0
+				for i := range points {
+					if p.similarity[i] < p.similarity[worst] {
+						worst = i
+					}
+				}
+				centroids[c] = append([]float32(nil), points[worst]...)
+				p.similarity[worst] = 1
+				continue
+			}
+			centroid := make([]float32, len(sums[c]))
+			for j, v := range sums[c] {
+				centroid[j] = float32(v)
+			}
+			centroids[c] = normalize(centroid)
+		}
+	}
+	p.centroids = centroids
+	return p
+}
+
+func seedCentroids(points [][]float32, k int, rng *rand.Rand) [][]float32 {
+	centroids := [][]float32{points[rng.Intn(len(points))]}
+	nearest := make([]float64, len(points))
+	for i, point := range points {
+		nearest[i] = 1 - dot(point, centroids[0])
+	}
+	for len(centroids) < k {
+		var total float64
+		for _, d := range nearest {
+			total += d * d
+		}
+		next := rng.Intn(len(points))
+		if total > 0 {
+			r := rng.Float64() * total
+			for i, d := range nearest {
+				if r -= d * d; r <= 0 {
+					next = i
+					break
+				}
+			}
+		}
+		centroid := points[next]
+		centroids = append(centroids, centroid)
+		for i, point := range points {
+			nearest[i] = min(nearest[i], 1-dot(point, centroid))
+		}
+	}
+	return centroids
+}
+
+// defaultK число кластеров по правилу sqrt(n/2) в пределах 2..30
+func defaultK(n int) int {
+	k := int(math.Round(math.Sqrt(float64(n) / 2)))
+	return max(2, min(k, 30, n))
+}
+
+func dot(a, b []float32) float64 {
+	var s float64
+	for i := range a {
+		s += float64(a[i]) * float64(b[i])
+	}
+	return s
+}
+
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, f := range v {
+		sum += float64(f) * float64(f)
+	}
+	out := make([]float32, len(v))
+	if sum == 0 {
+		return out
+	}
+	norm := 1 / math.Sqrt(sum)
+	for i, f := range v {
+		out[i] = float32(float64(f) * norm)
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+package talent
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestKmeans(t *testing.T) {
+	tests := []struct {
+		name   string
+		points [][]float32
+		k      int
+		groups [][]int // Точки, которые должны попасть в один кластер
+	}{
+		{
+			name: "три направления",
+			points: [][]float32{
+				{1, 0.1, 0}, {0.9, 0, 0.1}, {1, 0, 0},
+				{0, 1, 0.1}, {0.1, 0.9, 0}, {0, 1, 0},
+				{0, 0.1, 1}, {0.1, 0, 0.9}, {0, 0, 1},
+			},
+			k:      3,
+			groups: [][]int{{0, 1, 2}, {3, 4, 5}, {6, 7, 8}},
+		},
+		{
+			name:   "норма не важна",
+			points: [][]float32{{10, 0}, {0.1, 0}, {0, 5}, {0, 0.2}},
+			k:      2,
+			groups: [][]int{{0, 1}, {2, 3}},
+		},
+		{
+			name:   "кластеров столько же, сколько точек",
+			points: [][]float32{{1, 0}, {0, 1}, {-1, 0}},
+			k:      3,
+			groups: [][]int{{0}, {1}, {2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := kmeans(tt.points, tt.k, 1)
+			if len(p.centroids) != tt.k {
+				t.Fatalf("центров %d, want %d", len(p.centroids), tt.k)
+			}
+			used := map[int]bool{}
+			for _, group := range tt.groups {
+				c := p.assign[group[0]]
+				if used[c] {
+					t.Errorf("группы %v и другая попали в кластер %d", group, c)
+				}
+				used[c] = true
+				for _, i := range group {
+					if p.assign[i] != c {
+						t.Errorf("точка %d в кластере %d, а %d - в %d", i, p.assign[i], group[0], c)
+					}
+				}
+			}
+			for i, s := range p.similarity {
+				if s < 0.9 || s > 1+1e-6 {
+					t.Errorf("близость точки %d к центру %.3f", i, s)
+				}
+			}
+		})
+	}
+}
+
+func TestKmeansDeterministic(t *testing.T) {
+	points := func() [][]float32 {
+		return [][]float32{{1, 0.2}, {0.8, 0.3}, {0.1, 1}, {0.3, 0.9}, {1, 1}, {0.5, 0.4}}
+	}
+	a := kmeans(points(), 2, 42)
+	b := kmeans(points(), 2, 42)
+	if !reflect.DeepEqual(a.assign, b.assign) {
+		t.Errorf("одно зерно - разные разбиения: %v и %v", a.assign, b.assign)
+	}
+}
+
+func TestDefaultK(t *testing.T) {
+	tests := []struct{ n, want int }{
+		{2, 2},
+		{4, 2},
+		{18, 3},
+		{200, 10},
+		{5000, 30},
+	}
+	for _, tt := range tests {
+		if got := defaultK(tt.n); got != tt.want {
+			t.Errorf("defaultK(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	v := normalize([]float32{3, 4})
+	if math.Abs(float64(v[0])-0.6) > 1e-6 || math.Abs(float64(v[1])-0.8) > 1e-6 {
+		t.Errorf("normalize(3, 4) = %v", v)
+	}
+	if z := normalize([]float32{0, 0}); z[0] != 0 || z[1] != 0 {
+		t.Errorf("normalize(0, 0) = %v", z)
+	}
+}
+
+func TestSkillFeatures(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	profiles := []profile{
+		{id: a, skills: []string{"Go", "Kafka"}},
+		{id: b, skills: []string{"Go", "PostgreSQL"}},
+		{id: c, skills: []string{"Kafka", "Rust"}}, // Rust только у одного: не признак
+		{id: d, skills: []string{"1С"}},            // Без признаков: не кластеризуется
+	}
+	ids, points := skillFeatures(profiles)
+	if !reflect.DeepEqual(ids, []uuid.UUID{a, b, c}) {
+		t.Fatalf("ids = %v", ids)
+	}
+	// Словарь: Go и Kafka, по два резюме; вес log(1 + 4/2)
+	weight := float32(math.Log(3))
+	want := [][]float32{{weight, weight}, {weight, 0}, {0, weight}}
+	if !reflect.DeepEqual(points, want) {
+		t.Errorf("points = %v, want %v", points, want)
+	}
+}
+
+func TestAutoSource(t *testing.T) {
+	tests := []struct {
+		embedded, resumes int
+		want              string
+	}{
+		{0, 0, SourceSkills},
+		{3, 3, SourceSkills},         // меньше minResumes
+		{4, 100, SourceSkills},       // векторы ещё догружаются
+		{94, 100, SourceSkills},      // ниже порога покрытия
+		{95, 100, SourceEmbeddings},  // на пороге
+		{100, 100, SourceEmbeddings}, // все резюме с векторами
+	}
+	for _, tt := range tests {
+		if got := autoSource(tt.embedded, tt.resumes); got != tt.want {
+			t.Errorf("autoSource(%d, %d) = %s, want %s", tt.embedded, tt.resumes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package talent
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/requirements"
+	"github.com/moverq1337/VTBHack/internal/taxonomy"
+	"gorm.io/gorm"
+)
+
+// profile навыки резюме в каноническом написании, без повторов, по алфавиту
+type profile struct {
+	id     uuid.UUID
+	skills []string
+}
+
+// loadProfiles навыки резюме вне архива. У резюме, загруженных до появления
+// таксономии, навыки извлекаются из текста.
+func loadProfiles(db *gorm.DB, t *taxonomy.Taxonomy) ([]profile, error) {
+	var resumes []models.Resume
+	if err := db.Select("id", "text", "skills").Where("archived_at IS NULL").Find(&resumes).Error; err != nil {
+		return nil, err
+	}
+	profiles := make([]profile, 0, len(resumes))
+	for _, r := range resumes {
+		var names []string
+		switch {
+		case r.Skills != "":
+			names = strings.Split(r.Skills, ",")
+		case t != nil:
+			names = t.Extract(r.Text)
+		}
+		profiles = append(profiles, profile{id: r.ID, skills: canonical(names, t)})
+	}
+	return profiles, nil
+}
+
+// canonical приводит навыки к названиям таксономии и убирает повторы
+func canonical(names []string, t *taxonomy.Taxonomy) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if t != nil {
+			if s, ok := t.Lookup(name); ok {
+				name = s.Name
+			}
+		}
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Combination сочетание навыков и число резюме, в которых есть все они
+type Combination struct {
+	Skills  []string `json:"skills"`
+	Resumes int      `json:"resumes"`
+	Share   float64  `json:"share"` // Доля резюме вне архива
+}
+
+// SkillCombinations самые частые сочетания из size навыков (2 или 3),
+// встречающиеся не менее чем в minSupport резюме
+func SkillCombinations(db *gorm.DB, t *taxonomy.Taxonomy, size, minSupport, limit int) ([]Combination, error) {
+	profiles, err := loadProfiles(db, t)
+	if err != nil {
+		return nil, err
+	}
+	return combinations(profiles, size, minSupport, limit), nil
+}
+
+// combinations считает сочетания по схеме Apriori: в пары входят только
+// частые навыки, в тройки - только те, все пары которых частые
+func combinations(profiles []profile, size, minSupport, limit int) []Combination {
+	minSupport = max(minSupport, 1)
+	single := map[string]int{}
+	for _, p := range profiles {
+		for _, s := range p.skills {
+			single[s]++
+		}
+	}
+
+	pairs := map[[2]string]int{}
+	frequent := make([][]string, len(profiles))
+	for i, p := range profiles {
+		for _, s := range p.skills {
+			if single[s] >= minSupport {
+				frequent[i] = append(frequent[i], s)
+			}
+		}
+		skills := frequent[i]
+		for a := range skills {
+			for b := a + 1; b < len(skills); b++ {
+				pairs[[2]string{skills[a], skills[b]}]++
+			}
+		}
+	}
+
+	counts := map[string]int{}
+	members := map[string][]string{}
+	if size == 2 {
+		for pair, n := range pairs {
+			key := pair[0] + "\x00" + pair[1]
+			counts[key], members[key] = n, pair[:]
+		}
+	} else {
+		for _, skills := range frequent {
+			for a := range skills {
+				for b := a + 1; b < len(skills); b++ {
+					if pairs[[2]string{skills[a], skills[b]}] < minSupport {
+						continue
+					}
+					for c := b + 1; c < len(skills); c++ {
+						if pairs[[2]string{skills[a], skills[c]}] < minSupport || pairs[[2]string{skills[b], skills[c]}] < minSupport {
+							continue
+						}
+						key := skills[a] + "\x00" + skills[b] + "\x00" + skills[c]
+						counts[key]++
+						if members[key] == nil {
+							members[key] = []string{skills[a], skills[b], skills[c]}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	out := []Combination{}
+	for key, n := range counts {
+		if n < minSupport {
+			continue
+		}
+		out = append(out, Combination{
+			Skills:  append([]string(nil), members[key]...),
+			Resumes: n,
+			Share:   round(float64(n) / float64(len(profiles))),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Resumes != out[j].Resumes {
+			return out[i].Resumes > out[j].Resumes
+		}
+		return strings.Join(out[i].Skills, ",") < strings.Join(out[j].Skills, ",")
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// SkillBalance предложение навыка в базе резюме и спрос в вакансиях
+type SkillBalance struct {
+	Skill     string  `json:"skill"`
+	Resumes   int     `json:"resumes"`   // Резюме вне архива с навыком
+	Vacancies int     `json:"vacancies"` // Опубликованные вакансии, требующие навык
+	Must      int     `json:"must"`      // Из них обязательным требованием
+	Supply    float64 `json:"supply"`    // Доля резюме
+	Demand    float64 `json:"demand"`    // Доля вакансий
+	// Scarcity отношение доли спроса к доле предложения со сглаживанием
+	// (+1 к числителям и знаменателям): больше 1 - навыка не хватает
+	Scarcity float64 `json:"scarcity"`
+	// ResumesPerVacancy резюме с навыком на одну вакансию, где он нужен
+	ResumesPerVacancy float64 `json:"resumes_per_vacancy"`
+}
+
+// SupplyDemand сравнивает навыки из требований опубликованных вакансий
+// с навыками резюме. Навыки, которые не требуются ни в одной вакансии,
+// в отчёт не входят.
+func SupplyDemand(db *gorm.DB, t *taxonomy.Taxonomy) ([]SkillBalance, error) {
+	profiles, err := loadProfiles(db, t)
+	if err != nil {
+		return nil, err
+	}
+	supply := map[string]int{}
+	for _, p := range profiles {
+		for _, s := range p.skills {
+			supply[s]++
+		}
+	}
+
+	var vacancies []models.Vacancy
+	if err := db.Where("status = ?", models.VacancyPublished).Find(&vacancies).Error; err != nil {
+		return nil, err
+	}
+	demand := map[string]*SkillBalance{}
+	for _, v := range vacancies {
+		items, _ := requirements.Stored(v, t)
+		seen, must := map[string]bool{}, map[string]bool{}
+		for _, item := range items {
+			if item.Kind != requirements.KindSkill || item.Name == "" {
+				continue
+			}
+			name := item.Name
+			if t != nil {
+				if s, ok := t.Lookup(name); ok {
+					name = s.Name
+				}
+			}
+			b := demand[name]
+			if b == nil {
+				b = &SkillBalance{Skill: name}
+				demand[name] = b
+			}
+			if !seen[name] {
+				seen[name] = true
+				b.Vacancies++
+			}
+			if item.Priority == requirements.PriorityMust && !must[name] {
+				must[name] = true
+				b.Must++
+			}
+		}
+	}
+
+	resumes, total := float64(len(profiles)), float64(len(vacancies))
+	out := make([]SkillBalance, 0, len(demand))
+	for _, b := range demand {
+		b.Resumes = supply[b.Skill]
+		if resumes > 0 {
+			b.Supply = round(float64(b.Resumes) / resumes)
+		}
+		b.Demand = round(float64(b.Vacancies) / total)
+		b.Scarcity = round(((float64(b.Vacancies) + 1) / (total + 1)) / ((float64(b.Resumes) + 1) / (resumes + 1)))
+		b.ResumesPerVacancy = round(float64(b.Resumes) / float64(b.Vacancies))
+		out = append(out, *b)
+	}
+	SortBalances(out, "scarcity")
+	return out, nil
+}
+
+// BalanceSorts поля сортировки отчёта о спросе и предложении, по убыванию
+var BalanceSorts = map[string]func(SkillBalance) float64{
+	"scarcity":  func(b SkillBalance) float64 { return b.Scarcity },
+	"demand":    func(b SkillBalance) float64 { return float64(b.Vacancies) },
+	"supply":    func(b SkillBalance) float64 { return float64(b.Resumes) },
+	"shortfall": func(b SkillBalance) float64 { return -b.ResumesPerVacancy },
+}
+
+// SortBalances упорядочивает отчёт по полю из BalanceSorts; при равенстве -
+// по названию навыка
+func SortBalances(balances []SkillBalance, by string) {
+	key := BalanceSorts[by]
+	sort.Slice(balances, func(i, j int) bool {
+		if a, b := key(balances[i]), key(balances[j]); a != b {
+			return a > b
+		}
+		return balances[i].Skill < balances[j].Skill
+	})
+}
+
+// TopSkill навык, отличающий кластер
+type TopSkill struct {
+	Skill string  `json:"skill"`
+	Share float64 `json:"share"` // Доля резюме кластера с навыком
+	Lift  float64 `json:"lift"`  // Во сколько раз доля выше, чем во всей базе
+}
+
+// Отбор навыков для подписи кластера
+const (
+	topSkills    = 10
+	labelSkills  = 3
+	minLabelLift = 1.2 // Навык в подписи встречается в кластере заметно чаще, чем в базе
+	minLabelSize = 2   // и хотя бы у двух резюме кластера
+)
+
+// describe навыки кластера по убыванию доли и подпись из трёх самых
+// частых среди тех, что встречаются в кластере чаще, чем в базе
+func describe(members []profile, base map[string]int, total int) ([]TopSkill, string) {
+	counts := map[string]int{}
+	for _, p := range members {
+		for _, s := range p.skills {
+			counts[s]++
+		}
+	}
+
+	var skills []TopSkill
+	for name, n := range counts {
+		if n < minLabelSize && len(members) >= minLabelSize {
+			continue
+		}
+		share := float64(n) / float64(len(members))
+		lift := share / (float64(base[name]) / float64(total))
+		skills = append(skills, TopSkill{Skill: name, Share: round(share), Lift: round(lift)})
+	}
+	sort.Slice(skills, func(i, j int) bool {
+		if skills[i].Share != skills[j].Share {
+			return skills[i].Share > skills[j].Share
+		}
+		if skills[i].Lift != skills[j].Lift {
+			return skills[i].Lift > skills[j].Lift
+		}
+		return skills[i].Skill < skills[j].Skill
+	})
+
+	var label []string
+	for _, s := range skills {
+		if len(label) == labelSkills {
+			break
+		}
+		if s.Lift >= minLabelLift {
+			label = append(label, s.Skill)
+		}
+	}
+	// В кластере нет выделяющихся навыков: подпись по самым частым
+	if len(label) == 0 {
+		for _, s := range skills[:min(labelSkills, len(skills))] {
+			label = append(label, s.Skill)
+		}
+	}
+
+	if len(skills) > topSkills {
+		skills = skills[:topSkills]
+	}
+	if skills == nil {
+		skills = []TopSkill{}
+	}
+	if len(label) == 0 {
+		return skills, "Без выраженных навыков"
+	}
+	return skills, strings.Join(label, " / ")
+}
+
+// round до трёх знаков для ответа API
+func round(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
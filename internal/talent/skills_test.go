@@ -0,0 +1,79 @@
+package talent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCombinations(t *testing.T) {
+	profiles := []profile{
+		{skills: []string{"Docker", "Go", "Kafka", "PostgreSQL"}},
+		{skills: []string{"Docker", "Go", "Kafka"}},
+		{skills: []string{"Go", "Kafka", "PostgreSQL"}},
+		{skills: []string{"Docker", "Go", "PostgreSQL"}},
+		{skills: []string{"Java", "Spring"}},
+	}
+	tests := []struct {
+		name       string
+		size       int
+		minSupport int
+		limit      int
+		want       []Combination
+	}{
+		{
+			name: "пары", size: 2, minSupport: 3, limit: 10,
+			want: []Combination{
+				{Skills: []string{"Docker", "Go"}, Resumes: 3, Share: 0.6},
+				{Skills: []string{"Go", "Kafka"}, Resumes: 3, Share: 0.6},
+				{Skills: []string{"Go", "PostgreSQL"}, Resumes: 3, Share: 0.6},
+			},
+		},
+		{
+			name: "ограничение числа", size: 2, minSupport: 3, limit: 1,
+			want: []Combination{
+				{Skills: []string{"Docker", "Go"}, Resumes: 3, Share: 0.6},
+			},
+		},
+		{
+			name: "тройки из частых пар", size: 3, minSupport: 2, limit: 10,
+			want: []Combination{
+				{Skills: []string{"Docker", "Go", "Kafka"}, Resumes: 2, Share: 0.4},
+				{Skills: []string{"Docker", "Go", "PostgreSQL"}, Resumes: 2, Share: 0.4},
+				{Skills: []string{"Go", "Kafka", "PostgreSQL"}, Resumes: 2, Share: 0.4},
+			},
+		},
+		{
+			// Пары без Go встречаются дважды: с порогом 3 у каждой тройки
+			// есть нечастая пара, и тройки отсекаются
+			name: "нет троек выше порога", size: 3, minSupport: 3, limit: 10,
+			want: []Combination{},
+		},
+		{
+			name: "редкий навык не входит в пары", size: 2, minSupport: 2, limit: 100,
+			want: []Combination{
+				{Skills: []string{"Docker", "Go"}, Resumes: 3, Share: 0.6},
+				{Skills: []string{"Go", "Kafka"}, Resumes: 3, Share: 0.6},
+				{Skills: []string{"Go", "PostgreSQL"}, Resumes: 3, Share: 0.6},
+				{Skills: []string{"Docker", "Kafka"}, Resumes: 2, Share: 0.4},
+				{Skills: []string{"Docker", "PostgreSQL"}, Resumes: 2, Share: 0.4},
+				{Skills: []string{"Kafka", "PostgreSQL"}, Resumes: 2, Share: 0.4},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := combinations(profiles, tt.size, tt.minSupport, tt.limit)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("combinations(%d, %d) =\n%v\nwant\n%v", tt.size, tt.minSupport, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	got := canonical([]string{" Kafka", "Go", "", "Kafka", "Docker "}, nil)
+	want := []string{"Docker", "Go", "Kafka"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("canonical = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,236 @@
+package taxonomy
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/textproc"
+	"gorm.io/gorm"
+)
+
+// Источники связей навыков
+const (
+	SourceCurated      = "curated"
+	SourceCooccurrence = "cooccurrence"
+)
+
+// Параметры извлечения связей из совместной встречаемости
+const (
+	minSupport      = 3   // Минимум документов, где навыки встречаются вместе
+	minMinedWeight  = 0.2 // Более слабые связи отбрасываются
+	maxMinedWeight  = 0.5 // Статистическая связь не даёт полного зачёта
+	minedWeightBase = 0.8 // Множитель коэффициента Жаккара
+)
+
+// Ошибки изменения связей навыков
+var (
+	ErrSelfRelation  = errors.New("навык не может быть связан сам с собой")
+	ErrInvalidWeight = errors.New("вес связи должен быть в диапазоне (0, 1]")
+)
+
+// Related близкий навык с весом связи
+type Related struct {
+	Skill  models.Skill
+	Entry  textproc.SkillEntry // Словарная запись для поиска в тексте
+	Weight float64
+	Source string
+}
+
+// Related возвращает близкие навыки по убыванию веса. Курируемая связь
+// имеет приоритет над статистической для той же пары.
+func (t *Taxonomy) Related(name string) []Related {
+	s, ok := t.Lookup(name)
+	if !ok {
+		return nil
+	}
+
+	edges := t.related[s.ID]
+	out := make([]Related, 0, len(edges))
+	for relatedID, e := range edges {
+		i, ok := t.byID[relatedID]
+		if !ok {
+			continue
+		}
+		out = append(out, Related{Skill: t.skills[i], Entry: t.own[i], Weight: e.weight, Source: e.source})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Weight != out[j].Weight {
+			return out[i].Weight > out[j].Weight
+		}
+		return out[i].Skill.Slug < out[j].Skill.Slug
+	})
+	return out
+}
+
+type edge struct {
+	weight float64
+	source string
+}
+
+// buildGraph строит симметричный граф; курируемая связь перекрывает статистическую
+func buildGraph(relations []models.SkillRelation) map[uuid.UUID]map[uuid.UUID]edge {
+	graph := make(map[uuid.UUID]map[uuid.UUID]edge)
+	set := func(a, b uuid.UUID, e edge) {
+		if graph[a] == nil {
+			graph[a] = make(map[uuid.UUID]edge)
+		}
+		if cur, ok := graph[a][b]; ok && cur.source == SourceCurated && e.source != SourceCurated {
+			return
+		}
+		graph[a][b] = e
+	}
+	for _, r := range relations {
+		e := edge{weight: r.Weight, source: r.Source}
+		set(r.SkillID, r.RelatedID, e)
+		set(r.RelatedID, r.SkillID, e)
+	}
+	return graph
+}
+
+// orderedPair упорядочивает пару, чтобы неориентированная связь хранилась один раз
+func orderedPair(a, b uuid.UUID) (uuid.UUID, uuid.UUID) {
+	if a.String() > b.String() {
+		return b, a
+	}
+	return a, b
+}
+
+// SetRelation создаёт или меняет курируемую связь навыков
+func (s *Store) SetRelation(skillID, relatedID uuid.UUID, weight float64) (models.SkillRelation, error) {
+	if skillID == relatedID {
+		return models.SkillRelation{}, ErrSelfRelation
+	}
+	if weight <= 0 || weight > 1 {
+		return models.SkillRelation{}, ErrInvalidWeight
+	}
+	t := s.Current()
+	if _, ok := t.Get(skillID); !ok {
+		return models.SkillRelation{}, ErrNotFound
+	}
+	if _, ok := t.Get(relatedID); !ok {
+		return models.SkillRelation{}, ErrNotFound
+	}
+
+	a, b := orderedPair(skillID, relatedID)
+	rel := models.SkillRelation{SkillID: a, RelatedID: b, Source: SourceCurated}
+	err := s.db.Where(&rel).Attrs(models.SkillRelation{ID: uuid.New()}).FirstOrInit(&rel).Error
+	if err != nil {
+		return models.SkillRelation{}, err
+	}
+	rel.Weight = weight
+	if err := s.db.Save(&rel).Error; err != nil {
+		return models.SkillRelation{}, err
+	}
+	return rel, s.Reload()
+}
+
+// DeleteRelation удаляет курируемую связь; статистическая остаётся до следующего пересчёта
+func (s *Store) DeleteRelation(skillID, relatedID uuid.UUID) error {
+	a, b := orderedPair(skillID, relatedID)
+	res := s.db.Where("skill_id = ? AND related_id = ? AND source = ?", a, b, SourceCurated).Delete(&models.SkillRelation{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return s.Reload()
+}
+
+// MineRelations пересчитывает статистические связи по совместной встречаемости
+// навыков в сохранённых резюме и вакансиях. Вес - коэффициент Жаккара,
+// ограниченный сверху, чтобы статистика не давала полного зачёта.
+func (s *Store) MineRelations() (int, error) {
+	t := s.Current()
+
+	var docs [][]uuid.UUID
+	collect := func(names []string) {
+		known, _ := t.Normalize(names)
+		if len(known) < 2 {
+			return
+		}
+		ids := make([]uuid.UUID, len(known))
+		for i, k := range known {
+			ids[i] = k.ID
+		}
+		docs = append(docs, ids)
+	}
+
+	var resumes []models.Resume
+	if err := s.db.Select("text", "skills").Find(&resumes).Error; err != nil {
+		return 0, err
+	}
+	for _, r := range resumes {
+		// Навыки резюме, загруженных до появления таксономии, извлекаются из текста
+		if r.Skills != "" {
+			collect(splitList(r.Skills))
+		} else {
+			collect(t.Extract(r.Text))
+		}
+	}
+
+	var vacancies []models.Vacancy
+	if err := s.db.Select("title", "requirements", "skills").Find(&vacancies).Error; err != nil {
+		return 0, err
+	}
+	for _, v := range vacancies {
+		collect(append(splitList(v.Skills), t.Extract(v.Requirements)...))
+	}
+
+	relations := mine(docs)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("source = ?", SourceCooccurrence).Delete(&models.SkillRelation{}).Error; err != nil {
+			return err
+		}
+		if len(relations) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&relations, 500).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(relations), s.Reload()
+}
+
+// mine считает связи по наборам навыков документов
+func mine(docs [][]uuid.UUID) []models.SkillRelation {
+	type pair struct{ a, b uuid.UUID }
+	single := make(map[uuid.UUID]int)
+	joint := make(map[pair]int)
+	for _, doc := range docs {
+		for i, a := range doc {
+			single[a]++
+			for _, b := range doc[i+1:] {
+				x, y := orderedPair(a, b)
+				joint[pair{x, y}]++
+			}
+		}
+	}
+
+	now := time.Now()
+	var out []models.SkillRelation
+	for p, n := range joint {
+		if n < minSupport {
+			continue
+		}
+		jaccard := float64(n) / float64(single[p.a]+single[p.b]-n)
+		weight := min(maxMinedWeight, minedWeightBase*jaccard)
+		if weight < minMinedWeight {
+			continue
+		}
+		out = append(out, models.SkillRelation{
+			ID:        uuid.New(),
+			SkillID:   p.a,
+			RelatedID: p.b,
+			Source:    SourceCooccurrence,
+			Weight:    weight,
+			Support:   n,
+			UpdatedAt: now,
+		})
+	}
+	return out
+}
@@ -0,0 +1,154 @@
+package taxonomy
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/textproc"
+	"gorm.io/gorm"
+)
+
+// seedSlugs идентификаторы навыков, которые нельзя получить из названия
+var seedSlugs = map[string]string{
+	"C#":              "csharp",
+	"C++":             "cpp",
+	"1С":              "1c",
+	"Лидерство":       "leadership",
+	"Коммуникация":    "communication",
+	"Аналитика":       "analytics",
+	"Решение проблем": "problem-solving",
+	"Тайм-менеджмент": "time-management",
+}
+
+// seedParents связи "общий навык - частный" встроенного словаря
+var seedParents = map[string]string{
+	"mysql":      "sql",
+	"postgresql": "sql",
+	"oracle":     "sql",
+	"clickhouse": "sql",
+	"jenkins":    "ci-cd",
+	"gitlab-ci":  "ci-cd",
+	"ubuntu":     "linux",
+	"debian":     "linux",
+	"centos":     "linux",
+	"typescript": "javascript",
+	"react":      "javascript",
+	"angular":    "javascript",
+	"vue":        "javascript",
+	"nodejs":     "javascript",
+	"django":     "python",
+	"flask":      "python",
+}
+
+// Seed заполняет таксономию встроенным словарём textproc.DefaultSkills
+func Seed(db *gorm.DB) error {
+	bySlug := make(map[string]*models.Skill, len(textproc.DefaultSkills))
+	skills := make([]models.Skill, 0, len(textproc.DefaultSkills))
+	for _, entry := range textproc.DefaultSkills {
+		skills = append(skills, models.Skill{ID: uuid.New(), Slug: Slug(entry.Name), Name: entry.Name, Category: entry.Category})
+	}
+	for i := range skills {
+		bySlug[skills[i].Slug] = &skills[i]
+	}
+	for child, parent := range seedParents {
+		if c, ok := bySlug[child]; ok {
+			if p, ok := bySlug[parent]; ok {
+				c.ParentID = &p.ID
+			}
+		}
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Aliases").Create(&skills).Error; err != nil {
+			return err
+		}
+		for i, entry := range textproc.DefaultSkills {
+			if err := replaceAliases(tx, &skills[i], entry.Aliases); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Slug строит идентификатор навыка из названия: "GitLab CI" -> "gitlab-ci"
+func Slug(name string) string {
+	if slug, ok := seedSlugs[name]; ok {
+		return slug
+	}
+
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case r == '.':
+			// node.js -> nodejs
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// seedRelations курируемые связи близких навыков встроенного словаря
+var seedRelations = []struct {
+	a, b   string
+	weight float64
+}{
+	{"postgresql", "mysql", 0.7},
+	{"postgresql", "oracle", 0.6},
+	{"mysql", "oracle", 0.6},
+	{"postgresql", "clickhouse", 0.4},
+	{"mongodb", "redis", 0.3},
+	{"jenkins", "gitlab-ci", 0.7},
+	{"docker", "kubernetes", 0.5},
+	{"ansible", "terraform", 0.5},
+	{"java", "kotlin", 0.7},
+	{"java", "scala", 0.5},
+	{"java", "csharp", 0.5},
+	{"javascript", "typescript", 0.8},
+	{"react", "vue", 0.6},
+	{"react", "angular", 0.5},
+	{"vue", "angular", 0.5},
+	{"django", "flask", 0.7},
+	{"nodejs", "express", 0.7},
+	{"ubuntu", "debian", 0.8},
+	{"debian", "centos", 0.6},
+	{"aws", "azure", 0.6},
+	{"aws", "google-cloud", 0.6},
+	{"azure", "google-cloud", 0.6},
+	{"aws", "yandex-cloud", 0.5},
+	{"go", "rust", 0.3},
+}
+
+// SeedRelations добавляет курируемые связи для навыков, которые есть в таксономии
+func SeedRelations(db *gorm.DB) error {
+	var skills []models.Skill
+	if err := db.Select("id", "slug").Find(&skills).Error; err != nil {
+		return err
+	}
+	ids := make(map[string]uuid.UUID, len(skills))
+	for _, s := range skills {
+		ids[s.Slug] = s.ID
+	}
+
+	var relations []models.SkillRelation
+	for _, r := range seedRelations {
+		a, okA := ids[r.a]
+		b, okB := ids[r.b]
+		if !okA || !okB {
+			continue
+		}
+		a, b = orderedPair(a, b)
+		relations = append(relations, models.SkillRelation{ID: uuid.New(), SkillID: a, RelatedID: b, Source: SourceCurated, Weight: r.weight})
+	}
+	if len(relations) == 0 {
+		return nil
+	}
+	return db.Create(&relations).Error
+}
@@ -0,0 +1,344 @@
+package taxonomy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var log = logrus.New()
+
+// Ошибки изменения таксономии
+var (
+	ErrNotFound    = errors.New("навык не найден")
+	ErrInvalid     = errors.New("не заданы slug или название навыка")
+	ErrSlugTaken   = errors.New("slug уже занят другим навыком")
+	ErrAliasTaken  = errors.New("синоним уже принадлежит другому навыку")
+	ErrParentCycle = errors.New("родительский навык создаёт цикл")
+)
+
+// Store хранит таксономию в Postgres и держит актуальный снимок в памяти.
+// Снимок перестраивается после каждого изменения через этот Store, а
+// изменения из другого сервиса подхватывает Watch.
+type Store struct {
+	db *gorm.DB
+
+	mu       sync.RWMutex
+	current  *Taxonomy
+	revision string // Отпечаток таблиц, по которому построен снимок
+}
+
+// SkillInput изменяемые поля навыка. Nil-поля при обновлении не меняются.
+type SkillInput struct {
+	Slug     *string
+	Name     *string
+	Category *string
+	ParentID *uuid.UUID
+	// ClearParent снимает родителя, ParentID при этом игнорируется
+	ClearParent bool
+	Aliases     []string // nil - синонимы не меняются
+}
+
+// seedLock ключ advisory-блокировки заполнения словаря
+const seedLock = 7234002
+
+// NewStore загружает таксономию; пустая таблица заполняется встроенным словарём
+// и курируемыми связями
+func NewStore(db *gorm.DB) (*Store, error) {
+	s := &Store{db: db}
+
+	// Словарь заполняют api-gateway и resume-service; блокировка не даёт им
+	// заполнить пустую таблицу одновременно
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", seedLock).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.Skill{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+		// Связи заполняются только вместе со словарём: курируемые связи,
+		// удалённые через API, после перезапуска не возвращаются
+		if err := Seed(tx); err != nil {
+			return err
+		}
+		return SeedRelations(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Current возвращает актуальный снимок таксономии
+func (s *Store) Current() *Taxonomy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// Reload перечитывает таксономию из базы
+func (s *Store) Reload() error {
+	// Отпечаток берётся до чтения: изменение во время чтения подхватит
+	// следующая сверка
+	rev, err := revision(s.db)
+	if err != nil {
+		return err
+	}
+	var skills []models.Skill
+	if err := s.db.Preload("Aliases").Order("slug").Find(&skills).Error; err != nil {
+		return err
+	}
+	var relations []models.SkillRelation
+	if err := s.db.Find(&relations).Error; err != nil {
+		return err
+	}
+
+	t := New(skills, relations)
+	s.mu.Lock()
+	s.current = t
+	s.revision = rev
+	s.mu.Unlock()
+	return nil
+}
+
+// Watch раз в interval сверяет отпечаток таблиц таксономии и перечитывает
+// её, если навыки, синонимы или связи изменил другой сервис: api-gateway и
+// resume-service оба обслуживают /admin/skills, а связи по встречаемости
+// пересчитывает только api-gateway. interval 0 - сверка отключена.
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rev, err := revision(s.db)
+			if err != nil {
+				log.WithError(err).Warn("Ошибка сверки таксономии с БД")
+				continue
+			}
+			s.mu.RLock()
+			changed := rev != s.revision
+			s.mu.RUnlock()
+			if !changed {
+				continue
+			}
+			if err := s.Reload(); err != nil {
+				log.WithError(err).Error("Ошибка перечитывания таксономии")
+			}
+		}
+	}
+}
+
+// revisionSQL отпечаток таблиц таксономии: число строк и время последнего
+// изменения. Удаление меняет число строк, создание и изменение навыка или
+// связи - время (синонимы меняются вместе с навыком).
+const revisionSQL = `SELECT concat_ws('|',
+	(SELECT count(*) || '/' || coalesce(max(updated_at)::text, '') FROM skills),
+	(SELECT count(*) FROM skill_aliases),
+	(SELECT count(*) || '/' || coalesce(max(updated_at)::text, '') FROM skill_relations))`
+
+func revision(db *gorm.DB) (string, error) {
+	var rev string
+	err := db.Raw(revisionSQL).Scan(&rev).Error
+	return rev, err
+}
+
+// Create добавляет навык с синонимами
+func (s *Store) Create(in SkillInput) (models.Skill, error) {
+	if in.Slug == nil || in.Name == nil || strings.TrimSpace(*in.Slug) == "" || strings.TrimSpace(*in.Name) == "" {
+		return models.Skill{}, ErrInvalid
+	}
+
+	skill := models.Skill{ID: uuid.New()}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := apply(tx, &skill, in); err != nil {
+			return err
+		}
+		if err := tx.Omit("Aliases").Create(&skill).Error; err != nil {
+			return err
+		}
+		return replaceAliases(tx, &skill, in.Aliases)
+	})
+	if err != nil {
+		return models.Skill{}, err
+	}
+	return s.reloadAndGet(skill.ID)
+}
+
+// Update меняет навык; переданные синонимы заменяют прежние
+func (s *Store) Update(id uuid.UUID, in SkillInput) (models.Skill, error) {
+	var skill models.Skill
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&skill, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if err := apply(tx, &skill, in); err != nil {
+			return err
+		}
+		if err := tx.Omit("Aliases").Save(&skill).Error; err != nil {
+			return err
+		}
+		if in.Aliases == nil && in.Name == nil {
+			return nil
+		}
+		aliases := in.Aliases
+		if aliases == nil {
+			var current []models.SkillAlias
+			if err := tx.Where("skill_id = ?", id).Find(&current).Error; err != nil {
+				return err
+			}
+			for _, a := range current {
+				aliases = append(aliases, a.Alias)
+			}
+		}
+		return replaceAliases(tx, &skill, aliases)
+	})
+	if err != nil {
+		return models.Skill{}, err
+	}
+	return s.reloadAndGet(id)
+}
+
+// Delete удаляет навык; дочерние навыки поднимаются к его родителю
+func (s *Store) Delete(id uuid.UUID) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var skill models.Skill
+		if err := tx.First(&skill, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if err := tx.Model(&models.Skill{}).Where("parent_id = ?", id).Update("parent_id", skill.ParentID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("skill_id = ?", id).Delete(&models.SkillAlias{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("skill_id = ? OR related_id = ?", id, id).Delete(&models.SkillRelation{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&skill).Error
+	})
+	if err != nil {
+		return err
+	}
+	return s.Reload()
+}
+
+func (s *Store) reloadAndGet(id uuid.UUID) (models.Skill, error) {
+	if err := s.Reload(); err != nil {
+		return models.Skill{}, err
+	}
+	skill, ok := s.Current().Get(id)
+	if !ok {
+		return models.Skill{}, ErrNotFound
+	}
+	return skill, nil
+}
+
+// apply переносит поля ввода в навык с проверкой slug и родителя
+func apply(tx *gorm.DB, skill *models.Skill, in SkillInput) error {
+	if in.Slug != nil {
+		slug := strings.ToLower(strings.TrimSpace(*in.Slug))
+		if slug == "" {
+			return ErrInvalid
+		}
+		var count int64
+		if err := tx.Model(&models.Skill{}).Where("slug = ? AND id <> ?", slug, skill.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrSlugTaken
+		}
+		skill.Slug = slug
+	}
+	if in.Name != nil {
+		if strings.TrimSpace(*in.Name) == "" {
+			return ErrInvalid
+		}
+		skill.Name = strings.TrimSpace(*in.Name)
+	}
+	if in.Category != nil {
+		skill.Category = strings.TrimSpace(*in.Category)
+	}
+
+	switch {
+	case in.ClearParent:
+		skill.ParentID = nil
+	case in.ParentID != nil:
+		// Поднимаемся по цепочке родителей: встретить сам навык - цикл
+		for cur := *in.ParentID; ; {
+			if cur == skill.ID {
+				return ErrParentCycle
+			}
+			var parent models.Skill
+			if err := tx.First(&parent, "id = ?", cur).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrNotFound
+				}
+				return err
+			}
+			if parent.ParentID == nil {
+				break
+			}
+			cur = *parent.ParentID
+		}
+		parentID := *in.ParentID
+		skill.ParentID = &parentID
+	}
+	return nil
+}
+
+// replaceAliases заменяет синонимы навыка; название и slug входят в синонимы всегда
+func replaceAliases(tx *gorm.DB, skill *models.Skill, aliases []string) error {
+	set := make(map[string]bool)
+	var ordered []string
+	for _, a := range append([]string{skill.Name, skill.Slug}, aliases...) {
+		a = NormalizeAlias(a)
+		if a != "" && !set[a] {
+			set[a] = true
+			ordered = append(ordered, a)
+		}
+	}
+
+	var count int64
+	if err := tx.Model(&models.SkillAlias{}).Where("alias IN ? AND skill_id <> ?", ordered, skill.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAliasTaken
+	}
+
+	if err := tx.Where("skill_id = ?", skill.ID).Delete(&models.SkillAlias{}).Error; err != nil {
+		return err
+	}
+	rows := make([]models.SkillAlias, len(ordered))
+	for i, a := range ordered {
+		rows[i] = models.SkillAlias{ID: uuid.New(), SkillID: skill.ID, Alias: a, Lang: AliasLang(a)}
+	}
+	return tx.Create(&rows).Error
+}
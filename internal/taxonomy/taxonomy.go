@@ -0,0 +1,202 @@
+package taxonomy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/textproc"
+)
+
+// Taxonomy неизменяемый снимок таксономии навыков для нормализации
+type Taxonomy struct {
+	skills   []models.Skill
+	byID     map[uuid.UUID]int
+	byAlias  map[string]int
+	children map[uuid.UUID][]uuid.UUID
+	entries  []textproc.SkillEntry // Написания навыка вместе с дочерними
+	own      []textproc.SkillEntry // Только собственные написания
+	related  map[uuid.UUID]map[uuid.UUID]edge
+}
+
+// New строит снимок по навыкам с загруженными синонимами и связям между ними
+func New(skills []models.Skill, relations []models.SkillRelation) *Taxonomy {
+	t := &Taxonomy{
+		skills:   skills,
+		byID:     make(map[uuid.UUID]int, len(skills)),
+		byAlias:  make(map[string]int),
+		children: make(map[uuid.UUID][]uuid.UUID),
+		related:  buildGraph(relations),
+	}
+
+	for i, s := range skills {
+		t.byID[s.ID] = i
+		t.byAlias[NormalizeAlias(s.Name)] = i
+		t.byAlias[NormalizeAlias(s.Slug)] = i
+		for _, a := range s.Aliases {
+			t.byAlias[NormalizeAlias(a.Alias)] = i
+		}
+		if s.ParentID != nil {
+			t.children[*s.ParentID] = append(t.children[*s.ParentID], s.ID)
+		}
+	}
+
+	// Словарь для поиска в тексте: общий навык находится и по написаниям
+	// дочерних, поэтому требование SQL закрывается упоминанием PostgreSQL
+	t.entries = make([]textproc.SkillEntry, 0, len(skills))
+	t.own = make([]textproc.SkillEntry, 0, len(skills))
+	for _, s := range skills {
+		aliases := t.aliases(s.ID, make(map[uuid.UUID]bool))
+		t.entries = append(t.entries, textproc.SkillEntry{Name: s.Name, Category: s.Category, Aliases: aliases})
+		own := []string{NormalizeAlias(s.Name)}
+		for _, a := range s.Aliases {
+			own = append(own, a.Alias)
+		}
+		t.own = append(t.own, textproc.SkillEntry{Name: s.Name, Category: s.Category, Aliases: own})
+	}
+
+	return t
+}
+
+// NormalizeAlias приводит написание навыка к виду для сравнения
+func NormalizeAlias(alias string) string {
+	return strings.Join(strings.Fields(textproc.Normalize(alias)), " ")
+}
+
+// AliasLang определяет язык написания по алфавиту
+func AliasLang(alias string) string {
+	for _, r := range alias {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return "ru"
+		}
+	}
+	return "en"
+}
+
+// Skills возвращает все навыки снимка
+func (t *Taxonomy) Skills() []models.Skill {
+	return t.skills
+}
+
+// Entries возвращает словарь навыков для textproc.FindSkills и движка сопоставления
+func (t *Taxonomy) Entries() []textproc.SkillEntry {
+	return t.entries
+}
+
+// Lookup находит навык по названию, идентификатору или синониму
+func (t *Taxonomy) Lookup(name string) (models.Skill, bool) {
+	i, ok := t.byAlias[NormalizeAlias(name)]
+	if !ok {
+		return models.Skill{}, false
+	}
+	return t.skills[i], true
+}
+
+// Get находит навык по ID
+func (t *Taxonomy) Get(id uuid.UUID) (models.Skill, bool) {
+	i, ok := t.byID[id]
+	if !ok {
+		return models.Skill{}, false
+	}
+	return t.skills[i], true
+}
+
+// Normalize сопоставляет список навыков с таксономией. Повторы одного
+// навыка в разных написаниях схлопываются, неизвестные возвращаются отдельно.
+func (t *Taxonomy) Normalize(names []string) (known []models.Skill, unknown []string) {
+	seen := make(map[uuid.UUID]bool)
+	seenUnknown := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if s, ok := t.Lookup(name); ok {
+			if !seen[s.ID] {
+				seen[s.ID] = true
+				known = append(known, s)
+			}
+			continue
+		}
+		if key := NormalizeAlias(name); !seenUnknown[key] {
+			seenUnknown[key] = true
+			unknown = append(unknown, name)
+		}
+	}
+	return known, unknown
+}
+
+// NormalizeList нормализует перечень навыков через запятую: известные
+// заменяются каноническими названиями, неизвестные сохраняются как есть
+func (t *Taxonomy) NormalizeList(list string) string {
+	known, unknown := t.Normalize(splitList(list))
+	names := make([]string, 0, len(known)+len(unknown))
+	for _, s := range known {
+		names = append(names, s.Name)
+	}
+	return strings.Join(append(names, unknown...), ", ")
+}
+
+// Extract находит в тексте навыки таксономии и возвращает их канонические названия.
+// Общие навыки находятся только по собственным написаниям.
+func (t *Taxonomy) Extract(text string) []string {
+	var names []string
+	for _, m := range textproc.FindSkills(text, t.own) {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+// Descendants возвращает ID всех дочерних навыков
+func (t *Taxonomy) Descendants(id uuid.UUID) []uuid.UUID {
+	var out []uuid.UUID
+	visited := map[uuid.UUID]bool{id: true}
+	queue := []uuid.UUID{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, child := range t.children[cur] {
+			if !visited[child] {
+				visited[child] = true
+				out = append(out, child)
+				queue = append(queue, child)
+			}
+		}
+	}
+	return out
+}
+
+// aliases собирает написания навыка и его потомков
+func (t *Taxonomy) aliases(id uuid.UUID, visited map[uuid.UUID]bool) []string {
+	if visited[id] {
+		return nil
+	}
+	visited[id] = true
+
+	s := t.skills[t.byID[id]]
+	set := map[string]bool{NormalizeAlias(s.Name): true}
+	for _, a := range s.Aliases {
+		set[NormalizeAlias(a.Alias)] = true
+	}
+	for _, child := range t.children[id] {
+		for _, a := range t.aliases(child, visited) {
+			set[a] = true
+		}
+	}
+
+	out := make([]string, 0, len(set))
+	for a := range set {
+		out = append(out, a)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// splitList делит перечень навыков по запятым, точкам с запятой и строкам
+func splitList(list string) []string {
+	return strings.FieldsFunc(list, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n' || r == '•'
+	})
+}
@@ -0,0 +1,179 @@
+package textproc
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SkillEntry навык словаря с каноническим названием и синонимами
+type SkillEntry struct {
+	Name     string
+	Category string
+	Aliases  []string
+}
+
+// DefaultSkills встроенный словарь навыков. Категории совпадают
+// с категориями Python-сервиса, синонимы покрывают русские и английские написания.
+var DefaultSkills = []SkillEntry{
+	{"Python", "programming", []string{"python", "питон", "пайтон"}},
+	{"Java", "programming", []string{"java", "джава"}},
+	{"JavaScript", "programming", []string{"javascript", "js", "джаваскрипт"}},
+	{"TypeScript", "programming", []string{"typescript"}},
+	{"C#", "programming", []string{"c#", "csharp", ".net", "dotnet"}},
+	{"C++", "programming", []string{"c++", "cpp"}},
+	{"PHP", "programming", []string{"php"}},
+	{"Ruby", "programming", []string{"ruby"}},
+	{"Go", "programming", []string{"go", "golang", "голанг"}},
+	{"Rust", "programming", []string{"rust"}},
+	{"Kotlin", "programming", []string{"kotlin", "котлин"}},
+	{"Scala", "programming", []string{"scala"}},
+	{"1С", "programming", []string{"1с", "1c"}},
+	{"HTML", "web", []string{"html", "html5"}},
+	{"CSS", "web", []string{"css", "css3"}},
+	{"React", "web", []string{"react", "reactjs", "react.js"}},
+	{"Angular", "web", []string{"angular"}},
+	{"Vue", "web", []string{"vue", "vuejs", "vue.js"}},
+	{"Django", "web", []string{"django", "джанго"}},
+	{"Flask", "web", []string{"flask"}},
+	{"Node.js", "web", []string{"node.js", "nodejs", "node"}},
+	{"Express", "web", []string{"express"}},
+	{"SQL", "database", []string{"sql"}},
+	{"MySQL", "database", []string{"mysql"}},
+	{"PostgreSQL", "database", []string{"postgresql", "postgres", "постгрес", "pg"}},
+	{"MongoDB", "database", []string{"mongodb", "mongo"}},
+	{"Redis", "database", []string{"redis"}},
+	{"Oracle", "database", []string{"oracle"}},
+	{"ClickHouse", "database", []string{"clickhouse", "кликхаус"}},
+	{"Kafka", "database", []string{"kafka", "кафка"}},
+	{"Docker", "devops", []string{"docker", "докер"}},
+	{"Kubernetes", "devops", []string{"kubernetes", "k8s", "кубернетес", "кубер"}},
+	{"Jenkins", "devops", []string{"jenkins"}},
+	{"Git", "devops", []string{"git"}},
+	{"GitLab CI", "devops", []string{"gitlab ci", "gitlab-ci"}},
+	{"CI/CD", "devops", []string{"ci/cd", "ci cd"}},
+	{"Ansible", "devops", []string{"ansible"}},
+	{"Terraform", "devops", []string{"terraform"}},
+	{"Linux", "os", []string{"linux", "линукс"}},
+	{"Windows", "os", []string{"windows"}},
+	{"macOS", "os", []string{"macos"}},
+	{"Ubuntu", "os", []string{"ubuntu"}},
+	{"Debian", "os", []string{"debian"}},
+	{"CentOS", "os", []string{"centos"}},
+	{"TCP/IP", "networking", []string{"tcp/ip"}},
+	{"DNS", "networking", []string{"dns"}},
+	{"DHCP", "networking", []string{"dhcp"}},
+	{"VPN", "networking", []string{"vpn"}},
+	{"LAN", "networking", []string{"lan"}},
+	{"WAN", "networking", []string{"wan"}},
+	{"AWS", "cloud", []string{"aws", "amazon web services"}},
+	{"Azure", "cloud", []string{"azure"}},
+	{"Google Cloud", "cloud", []string{"google cloud", "gcp"}},
+	{"DigitalOcean", "cloud", []string{"digitalocean"}},
+	{"Yandex Cloud", "cloud", []string{"yandex cloud", "яндекс облако"}},
+	{"Лидерство", "soft", []string{"лидерство", "leadership"}},
+	{"Коммуникация", "soft", []string{"коммуникация", "коммуникабельность", "communication"}},
+	{"Аналитика", "soft", []string{"аналитика", "аналитическое мышление"}},
+	{"Решение проблем", "soft", []string{"решение проблем", "problem solving"}},
+	{"Тайм-менеджмент", "soft", []string{"тайм-менеджмент", "time management"}},
+}
+
+// SkillMatch найденное в тексте упоминание навыка
+type SkillMatch struct {
+	Name     string
+	Category string
+	Alias    string
+	Start    int // Смещение в рунах
+	End      int
+}
+
+// FindSkills ищет навыки словаря в тексте по границам слов.
+// Однословные синонимы от четырёх букв находятся и в других словоформах
+// ("кафкой", "докером"). Каждый навык возвращается один раз, по первому упоминанию.
+func FindSkills(text string, dict []SkillEntry) []SkillMatch {
+	runes := []rune(Normalize(text))
+	tokens := Tokenize(text)
+	seen := make(map[string]bool)
+	var found []SkillMatch
+
+	for _, entry := range dict {
+		best, bestEnd := -1, -1
+		var bestAlias string
+		for _, alias := range entry.Aliases {
+			alias = Normalize(alias)
+			aliasRunes := []rune(alias)
+			if pos := indexWord(runes, aliasRunes); pos >= 0 && (best < 0 || pos < best) {
+				best, bestEnd, bestAlias = pos, pos+len(aliasRunes), alias
+			}
+			if len(aliasRunes) < 4 || strings.ContainsAny(alias, " -/.") {
+				continue
+			}
+			stem := Stem(alias)
+			for _, t := range tokens {
+				if best >= 0 && t.Start >= best {
+					break
+				}
+				if t.Stem == stem {
+					best, bestEnd, bestAlias = t.Start, t.End, alias
+					break
+				}
+			}
+		}
+		if best >= 0 && !seen[entry.Name] {
+			seen[entry.Name] = true
+			found = append(found, SkillMatch{
+				Name:     entry.Name,
+				Category: entry.Category,
+				Alias:    bestAlias,
+				Start:    best,
+				End:      bestEnd,
+			})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Start < found[j].Start })
+	return found
+}
+
+// SkillsByCategory группирует навыки так же, как parsed_data Python-сервиса
+func SkillsByCategory(matches []SkillMatch) map[string][]string {
+	out := make(map[string][]string)
+	for _, m := range matches {
+		out[m.Category] = append(out[m.Category], strings.ToLower(m.Name))
+	}
+	return out
+}
+
+// indexWord ищет фразу как отдельное слово и возвращает смещение в рунах или -1
+func indexWord(text, phrase []rune) int {
+	if len(phrase) == 0 {
+		return -1
+	}
+	for i := 0; i+len(phrase) <= len(text); i++ {
+		if !equalRunes(text[i:i+len(phrase)], phrase) {
+			continue
+		}
+		if i > 0 && isBoundaryRune(text[i-1]) {
+			continue
+		}
+		if end := i + len(phrase); end < len(text) && isBoundaryRune(text[end]) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// isBoundaryRune сообщает, что символ продолжает слово
+func isBoundaryRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '#'
+}
+
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,168 @@
+package textproc
+
+import "strings"
+
+// Окончания русского стеммера Snowball. Группы "after а/я" снимаются,
+// только если перед окончанием стоит а или я.
+var (
+	ruPerfectiveGerund1 = []string{"вшись", "вши", "в"}
+	ruPerfectiveGerund2 = []string{"ившись", "ывшись", "ивши", "ывши", "ив", "ыв"}
+	ruAdjective         = []string{"ими", "ыми", "его", "ого", "ему", "ому", "ее", "ие", "ые", "ое", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею"}
+	ruParticiple1       = []string{"ем", "нн", "вш", "ющ", "щ"}
+	ruParticiple2       = []string{"ивш", "ывш", "ующ"}
+	ruReflexive         = []string{"ся", "сь"}
+	ruVerb1             = []string{"ете", "йте", "ешь", "нно", "ла", "на", "ли", "ем", "ло", "но", "ет", "ют", "ны", "ть", "й", "л", "н"}
+	ruVerb2             = []string{"ейте", "уйте", "ила", "ыла", "ена", "ите", "или", "ыли", "ило", "ыло", "ено", "ует", "уют", "ены", "ить", "ыть", "ишь", "ей", "уй", "ил", "ыл", "им", "ым", "ен", "ят", "ит", "ыт", "ую", "ю"}
+	ruNoun              = []string{"иями", "ями", "ами", "ией", "иям", "ием", "иях", "ев", "ов", "ие", "ье", "еи", "ии", "ей", "ой", "ий", "ям", "ем", "ам", "ом", "ах", "ях", "ию", "ью", "ия", "ья", "а", "е", "и", "й", "о", "у", "ы", "ь", "ю", "я"}
+	ruSuperlative       = []string{"ейше", "ейш"}
+	ruDerivational      = []string{"ость", "ост"}
+)
+
+func isRuVowel(r rune) bool {
+	return strings.ContainsRune("аеиоуыэюя", r)
+}
+
+// StemRussian реализует русский стеммер Snowball
+func StemRussian(word string) string {
+	w := []rune(word)
+
+	// RV - область после первой гласной, R2 - по определению Snowball
+	rv := len(w)
+	for i, r := range w {
+		if isRuVowel(r) {
+			rv = i + 1
+			break
+		}
+	}
+	r1 := region(w, 0)
+	r2 := region(w, r1)
+
+	// Шаг 1
+	if n := ruSuffix(w, rv, ruPerfectiveGerund1, ruPerfectiveGerund2); n > 0 {
+		w = w[:len(w)-n]
+	} else {
+		if n := ruSuffix(w, rv, nil, ruReflexive); n > 0 {
+			w = w[:len(w)-n]
+		}
+		if n := ruAdjectival(w, rv); n > 0 {
+			w = w[:len(w)-n]
+		} else if n := ruSuffix(w, rv, ruVerb1, ruVerb2); n > 0 {
+			w = w[:len(w)-n]
+		} else if n := ruSuffix(w, rv, nil, ruNoun); n > 0 {
+			w = w[:len(w)-n]
+		}
+	}
+
+	// Шаг 2
+	if len(w) > rv && w[len(w)-1] == 'и' {
+		w = w[:len(w)-1]
+	}
+
+	// Шаг 3
+	if n := ruSuffix(w, r2, nil, ruDerivational); n > 0 {
+		w = w[:len(w)-n]
+	}
+
+	// Шаг 4
+	if hasSuffix(w, rv, "нн") {
+		w = w[:len(w)-1]
+	} else if n := ruSuffix(w, rv, nil, ruSuperlative); n > 0 {
+		w = w[:len(w)-n]
+		if hasSuffix(w, rv, "нн") {
+			w = w[:len(w)-1]
+		}
+	} else if len(w) > rv && w[len(w)-1] == 'ь' {
+		w = w[:len(w)-1]
+	}
+
+	return string(w)
+}
+
+// region возвращает начало области после первой согласной, следующей за гласной
+func region(w []rune, from int) int {
+	for i := from + 1; i < len(w); i++ {
+		if !isRuVowel(w[i]) && isRuVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// hasSuffix проверяет окончание, целиком лежащее в области с позиции limit
+func hasSuffix(w []rune, limit int, suffix string) bool {
+	s := []rune(suffix)
+	if len(w)-len(s) < limit {
+		return false
+	}
+	return string(w[len(w)-len(s):]) == suffix
+}
+
+// ruSuffix ищет самое длинное окончание из обеих групп и возвращает его длину.
+// Для группы afterAYa окончание засчитывается, только если перед ним стоит а или я.
+func ruSuffix(w []rune, limit int, afterAYa, plain []string) int {
+	best, bestAfter := 0, false
+	for _, s := range afterAYa {
+		if n := len([]rune(s)); n > best && hasSuffix(w, limit, s) {
+			best, bestAfter = n, true
+		}
+	}
+	for _, s := range plain {
+		if n := len([]rune(s)); n > best && hasSuffix(w, limit, s) {
+			best, bestAfter = n, false
+		}
+	}
+	if best == 0 {
+		return 0
+	}
+	if bestAfter {
+		i := len(w) - best - 1
+		if i < limit || (w[i] != 'а' && w[i] != 'я') {
+			return 0
+		}
+	}
+	return best
+}
+
+// ruAdjectival снимает прилагательное окончание вместе с суффиксом причастия
+func ruAdjectival(w []rune, limit int) int {
+	n := ruSuffix(w, limit, nil, ruAdjective)
+	if n == 0 {
+		return 0
+	}
+	if p := ruSuffix(w[:len(w)-n], limit, ruParticiple1, ruParticiple2); p > 0 {
+		return n + p
+	}
+	return n
+}
+
+// englishSuffixes упрощённые правила Портера: окончание и замена
+var englishSuffixes = []struct{ suffix, repl string }{
+	{"ational", "ate"}, {"tional", "tion"}, {"ization", "ize"}, {"ations", "ate"}, {"ation", "ate"},
+	{"iveness", "ive"}, {"fulness", "ful"}, {"ousness", "ous"}, {"alities", "al"}, {"ality", "al"},
+	{"ivity", "ive"}, {"bility", "ble"}, {"ements", ""}, {"ement", ""}, {"ments", ""}, {"ment", ""},
+	{"ness", ""}, {"ings", ""}, {"ing", ""}, {"ers", ""}, {"er", ""}, {"ies", "y"}, {"ied", "y"},
+	{"sses", "ss"}, {"edly", ""}, {"ed", ""}, {"ly", ""}, {"es", ""}, {"s", ""},
+}
+
+// StemEnglish лёгкий стеммер для английских слов резюме и вакансий
+func StemEnglish(word string) string {
+	if len(word) <= 3 || strings.HasSuffix(word, "ss") || strings.HasSuffix(word, "us") || strings.HasSuffix(word, "is") {
+		return word
+	}
+	for _, r := range englishSuffixes {
+		if !strings.HasSuffix(word, r.suffix) {
+			continue
+		}
+		stem := word[:len(word)-len(r.suffix)] + r.repl
+		// Основа должна оставаться осмысленной: не короче трёх букв и с гласной
+		if len(stem) < 3 || !strings.ContainsAny(stem, "aeiouy") {
+			return word
+		}
+		// developing -> develop, running -> run
+		if r.repl == "" && len(stem) > 3 && stem[len(stem)-1] == stem[len(stem)-2] && !strings.ContainsRune("lsz", rune(stem[len(stem)-1])) {
+			stem = stem[:len(stem)-1]
+		}
+		return strings.TrimSuffix(stem, "e")
+	}
+	return strings.TrimSuffix(word, "e")
+}
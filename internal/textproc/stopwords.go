@@ -0,0 +1,35 @@
+package textproc
+
+// stopWords частотные служебные слова, не несущие смысла для сопоставления
+var stopWords = map[string]bool{}
+
+func init() {
+	for _, w := range []string{
+		// Русские
+		"и", "в", "во", "не", "что", "он", "на", "я", "с", "со", "как", "а", "то", "все", "она", "так",
+		"его", "но", "да", "ты", "к", "у", "же", "вы", "за", "бы", "по", "только", "ее", "мне", "было",
+		"вот", "от", "меня", "еще", "нет", "о", "из", "ему", "теперь", "когда", "даже", "ну", "ли",
+		"если", "уже", "или", "ни", "быть", "был", "него", "до", "вас", "нибудь", "опять", "уж", "вам",
+		"ведь", "там", "потом", "себя", "ничего", "ей", "может", "они", "тут", "где", "есть", "надо",
+		"ней", "для", "мы", "тебя", "их", "чем", "была", "сам", "чтоб", "без", "будто", "чего", "раз",
+		"тоже", "себе", "под", "будет", "ж", "тогда", "кто", "этот", "того", "потому", "этого", "какой",
+		"совсем", "ним", "здесь", "этом", "один", "почти", "мой", "тем", "чтобы", "нее", "были", "куда",
+		"зачем", "всех", "никогда", "можно", "при", "наконец", "два", "об", "другой", "хоть", "после",
+		"над", "больше", "тот", "через", "эти", "нас", "про", "всего", "них", "какая", "много", "разве",
+		"три", "эту", "моя", "впрочем", "хорошо", "свою", "этой", "перед", "иногда", "лучше", "чуть",
+		"том", "нельзя", "такой", "им", "более", "всегда", "конечно", "всю", "между", "также",
+		// Английские
+		"a", "an", "the", "and", "or", "of", "to", "in", "on", "for", "with", "at", "by", "from", "as",
+		"is", "are", "was", "were", "be", "been", "being", "this", "that", "these", "those", "it", "its",
+		"i", "we", "you", "he", "she", "they", "my", "our", "your", "their", "not", "no", "but", "if",
+		"so", "than", "then", "there", "have", "has", "had", "do", "does", "did", "will", "would",
+		"can", "could", "should", "may", "might", "must", "about", "into", "over", "under", "also",
+	} {
+		stopWords[w] = true
+	}
+}
+
+// IsStopWord сообщает, является ли нормализованное слово стоп-словом
+func IsStopWord(word string) bool {
+	return stopWords[word]
+}
@@ -0,0 +1,134 @@
+package textproc
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Normalize приводит текст к нижнему регистру и заменяет ё на е
+func Normalize(text string) string {
+	text = strings.ToLower(text)
+	return strings.ReplaceAll(text, "ё", "е")
+}
+
+// Token слово текста с позицией в рунах исходной строки
+type Token struct {
+	Text  string // Нормализованная форма
+	Stem  string // Основа для сопоставления
+	Start int    // Смещение первой руны
+	End   int    // Смещение за последней руной
+}
+
+// Tokenize разбивает текст на слова. Символы "+" и "#" в конце слова
+// сохраняются, чтобы не терять c++ и c#. Стоп-слова пропускаются.
+func Tokenize(text string) []Token {
+	runes := []rune(text)
+	var tokens []Token
+
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(runes) && isWordRune(runes[i]) {
+			i++
+		}
+		for i < len(runes) && (runes[i] == '+' || runes[i] == '#') {
+			i++
+		}
+
+		word := Normalize(string(runes[start:i]))
+		if IsStopWord(word) {
+			continue
+		}
+		tokens = append(tokens, Token{Text: word, Stem: Stem(word), Start: start, End: i})
+	}
+
+	return tokens
+}
+
+// Stems возвращает основы слов текста
+func Stems(text string) []string {
+	tokens := Tokenize(text)
+	stems := make([]string, len(tokens))
+	for i, t := range tokens {
+		stems[i] = t.Stem
+	}
+	return stems
+}
+
+// Stem выбирает стеммер по алфавиту слова
+func Stem(word string) string {
+	if len([]rune(word)) < 3 {
+		return word
+	}
+	cyrillic, latin := 0, 0
+	for _, r := range word {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case r >= 'a' && r <= 'z':
+			latin++
+		case !unicode.IsLetter(r):
+			// Слова с цифрами и символами (c++, 1c, k8s) не стеммируются
+			return word
+		}
+	}
+	if cyrillic > 0 && latin == 0 {
+		return StemRussian(word)
+	}
+	if latin > 0 && cyrillic == 0 {
+		return StemEnglish(word)
+	}
+	return word
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Sentence предложение или строка текста с позицией в рунах
+type Sentence struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// Sentences делит текст на предложения по знакам конца предложения,
+// точке с запятой и переводам строк. Пустые фрагменты пропускаются.
+func Sentences(text string) []Sentence {
+	runes := []rune(text)
+	var out []Sentence
+
+	flush := func(start, end int) {
+		for start < end && unicode.IsSpace(runes[start]) {
+			start++
+		}
+		for end > start && unicode.IsSpace(runes[end-1]) {
+			end--
+		}
+		if start < end {
+			out = append(out, Sentence{Text: string(runes[start:end]), Start: start, End: end})
+		}
+	}
+
+	start := 0
+	for i, r := range runes {
+		switch r {
+		case '\n', ';', '!', '?', '•':
+			flush(start, i)
+			start = i + 1
+		case '.':
+			// Точка внутри слова (node.js, 03.2020) не завершает предложение
+			if i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+				continue
+			}
+			flush(start, i)
+			start = i + 1
+		}
+	}
+	flush(start, len(runes))
+
+	return out
+}
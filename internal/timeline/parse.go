@@ -0,0 +1,96 @@
+package timeline
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Названия месяцев в русских и английских резюме, включая сокращения и родительный падеж
+var monthNames = []struct {
+	month    time.Month
+	prefixes []string
+}{
+	{time.January, []string{"январ", "янв", "jan"}},
+	{time.February, []string{"феврал", "фев", "feb"}},
+	{time.March, []string{"март", "мар", "mar"}},
+	{time.April, []string{"апрел", "апр", "apr"}},
+	{time.May, []string{"май", "мая", "may"}},
+	{time.June, []string{"июн", "jun"}},
+	{time.July, []string{"июл", "jul"}},
+	{time.August, []string{"август", "авг", "aug"}},
+	{time.September, []string{"сентябр", "сен", "sep"}},
+	{time.October, []string{"октябр", "окт", "oct"}},
+	{time.November, []string{"ноябр", "ноя", "nov"}},
+	{time.December, []string{"декабр", "дек", "dec"}},
+}
+
+const (
+	monthWord = `(?:январ[ья]|янв|феврал[ья]|фев|марта?|мар|апрел[ья]|апр|ма[йя]|июн[ья]|июн|июл[ья]|июл|августа?|авг|сентябр[ья]|сент?|октябр[ья]|окт|ноябр[ья]|ноя|декабр[ья]|дек|january|jan|february|feb|march|mar|april|apr|may|june|jun|july|jul|august|aug|september|sept?|october|oct|november|nov|december|dec)\.?`
+	datePart  = `(?:` + monthWord + `\s*\d{4}|\d{1,2}[./]\d{4}|\d{4}[./-]\d{1,2}|\d{4})`
+	present   = `(?:(?:по\s+)?(?:настоящее|текущее)\s+время|(?:по\s+)?н\.\s?в\.?|сейчас|present|now|current|till\s+now|to\s+date)`
+	separator = `(?:\s*[-–—−]+\s*|\s+(?:по|to|until|till)\s+)`
+)
+
+var (
+	rangePattern = regexp.MustCompile(`(?i)(` + datePart + `)` + separator + `(` + datePart + `|` + present + `)`)
+	monthYear    = regexp.MustCompile(`(?i)^(\pL+)\.?\s*(\d{4})$`)
+	numericMY    = regexp.MustCompile(`^(\d{1,2})[./](\d{4})$`)
+	numericYM    = regexp.MustCompile(`^(\d{4})[./-](\d{1,2})$`)
+	yearOnly     = regexp.MustCompile(`^\d{4}$`)
+	presentWord  = regexp.MustCompile(`(?i)^` + present + `$`)
+)
+
+// parseDate разбирает дату начала или конца периода. Для конца периода,
+// заданного только годом, берётся декабрь, для начала - январь.
+func parseDate(s string, isEnd bool) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	var year, month int
+
+	switch {
+	case monthYear.MatchString(s):
+		m := monthYear.FindStringSubmatch(s)
+		month = int(MonthByName(m[1]))
+		year, _ = strconv.Atoi(m[2])
+	case numericMY.MatchString(s):
+		m := numericMY.FindStringSubmatch(s)
+		month, _ = strconv.Atoi(m[1])
+		year, _ = strconv.Atoi(m[2])
+	case numericYM.MatchString(s):
+		m := numericYM.FindStringSubmatch(s)
+		year, _ = strconv.Atoi(m[1])
+		month, _ = strconv.Atoi(m[2])
+	case yearOnly.MatchString(s):
+		year, _ = strconv.Atoi(s)
+		month = 1
+		if isEnd {
+			month = 12
+		}
+	default:
+		return time.Time{}, false
+	}
+
+	if month < 1 || month > 12 || year < minYear || year > time.Now().Year()+1 {
+		return time.Time{}, false
+	}
+	return monthStart(year, time.Month(month)), true
+}
+
+// MonthByName определяет месяц по русскому или английскому названию,
+// включая сокращения и родительный падеж; 0 если название не распознано
+func MonthByName(name string) time.Month {
+	name = strings.ToLower(name)
+	for _, m := range monthNames {
+		for _, p := range m.prefixes {
+			if strings.HasPrefix(name, p) {
+				return m.month
+			}
+		}
+	}
+	return 0
+}
+
+func monthStart(year int, month time.Month) time.Time {
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,277 @@
+package timeline
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// minYear отсекает номера телефонов и прочие числа, похожие на годы
+const minYear = 1960
+
+// gapThreshold перерыв короче этого числа месяцев считается сменой работы, а не паузой
+const gapThreshold = 2
+
+var (
+	// durationLine строка с длительностью работы в шаблоне hh.ru: "5 лет 2 месяца"
+	durationLine = regexp.MustCompile(`(?i)^\s*(?:\d+\s*(?:год|года|лет|месяц|месяца|месяцев|years?|months?|yrs?|mos?)\.?\s*)+$`)
+	// educationWords признаки периода учёбы, а не работы
+	educationWords = regexp.MustCompile(`(?i)университет|институт|академи[яи]|колледж|техникум|училищ|школа|бакалавр|магистр|специалитет|аспирантур|факультет|university|college|institute|bachelor|master'?s|faculty`)
+	// universityAbbr сокращения вузов без слова "университет": МГУ, СПбГУ,
+	// МГТУ, КФУ и известные МФТИ, ВШЭ, ИТМО. Регистр важен: иначе
+	// совпадали бы обычные слова.
+	universityAbbr = regexp.MustCompile(`(?:^|[^\pL])(?:[А-ЯЁ][А-ЯЁа-яё]{0,3}[ГТФ]У|МФТИ|МИФИ|МЭИ|МАИ|ВШЭ|НИУ|ИТМО|МГИМО|РАНХиГС|МИСиС|[Вв][Уу][Зз])(?:[^\pL]|$)`)
+)
+
+// isEducation похоже ли начало блока на период учёбы
+func isEducation(head string) bool {
+	return educationWords.MatchString(head) || universityAbbr.MatchString(head)
+}
+
+// Period период работы на одном месте
+type Period struct {
+	Start       time.Time // Первый месяц
+	End         time.Time // Последний месяц; для текущей работы - текущий месяц
+	Current     bool
+	Company     string
+	Title       string
+	Description string   // Текст блока без строки с датами
+	Skills      []string // Канонические навыки, упомянутые в блоке
+	SpanStart   int      // Смещение блока в тексте резюме, в рунах
+	SpanEnd     int
+}
+
+// Months длительность периода в месяцах, включая первый и последний
+func (p Period) Months() int {
+	return monthsBetween(p.Start, p.End) + 1
+}
+
+// Gap перерыв между периодами работы
+type Gap struct {
+	From   time.Time // Первый месяц без работы
+	To     time.Time // Последний месяц без работы
+	Months int
+}
+
+// Timeline история работы кандидата
+type Timeline struct {
+	Periods       []Period
+	Gaps          []Gap
+	TotalMonths   int                  // Стаж без двойного учёта пересекающихся периодов
+	SkillMonths   map[string]int       // Стаж по каждому навыку
+	SkillLastUsed map[string]time.Time // Последний месяц работы с навыком
+}
+
+// Years стаж в годах
+func (t Timeline) Years() float64 {
+	return float64(t.TotalMonths) / 12
+}
+
+// SkillYears стаж по навыку в годах
+func (t Timeline) SkillYears(skill string) float64 {
+	return float64(t.SkillMonths[skill]) / 12
+}
+
+// SkillUsed сообщает, когда навык использовался последний раз и сколько лет всего
+func (t Timeline) SkillUsed(skill string) (lastUsed time.Time, years float64, ok bool) {
+	lastUsed, ok = t.SkillLastUsed[skill]
+	return lastUsed, t.SkillYears(skill), ok
+}
+
+// SkillExtractor возвращает канонические навыки, упомянутые в тексте
+type SkillExtractor func(text string) []string
+
+// Extract находит в тексте периоды работы и строит по ним историю.
+// Блоком периода считается текст от строки с датами до следующей такой строки;
+// периоды учёбы отбрасываются. now задаёт конец текущей работы.
+func Extract(text string, now time.Time, skills SkillExtractor) Timeline {
+	return Build(findPeriods(text, now, skills))
+}
+
+// Build объединяет пересекающиеся периоды, находит перерывы и считает стаж
+func Build(periods []Period) Timeline {
+	sort.SliceStable(periods, func(i, j int) bool { return periods[i].Start.Before(periods[j].Start) })
+
+	t := Timeline{Periods: periods, SkillMonths: make(map[string]int), SkillLastUsed: make(map[string]time.Time)}
+
+	merged := merge(periods)
+	for i, iv := range merged {
+		t.TotalMonths += monthsBetween(iv.start, iv.end) + 1
+		if i == 0 {
+			continue
+		}
+		prev := merged[i-1]
+		if gap := monthsBetween(prev.end, iv.start) - 1; gap >= gapThreshold {
+			t.Gaps = append(t.Gaps, Gap{From: prev.end.AddDate(0, 1, 0), To: iv.start.AddDate(0, -1, 0), Months: gap})
+		}
+	}
+
+	bySkill := make(map[string][]Period)
+	for _, p := range periods {
+		for _, s := range p.Skills {
+			bySkill[s] = append(bySkill[s], p)
+			if p.End.After(t.SkillLastUsed[s]) {
+				t.SkillLastUsed[s] = p.End
+			}
+		}
+	}
+	for skill, ps := range bySkill {
+		for _, iv := range merge(ps) {
+			t.SkillMonths[skill] += monthsBetween(iv.start, iv.end) + 1
+		}
+	}
+
+	return t
+}
+
+type interval struct{ start, end time.Time }
+
+// merge объединяет пересекающиеся и смежные периоды, отсортированные по началу
+func merge(periods []Period) []interval {
+	var out []interval
+	for _, p := range periods {
+		if n := len(out); n > 0 && !p.Start.After(out[n-1].end.AddDate(0, 1, 0)) {
+			if p.End.After(out[n-1].end) {
+				out[n-1].end = p.End
+			}
+			continue
+		}
+		out = append(out, interval{p.Start, p.End})
+	}
+	return out
+}
+
+// findPeriods находит диапазоны дат и делит текст на блоки периодов
+func findPeriods(text string, now time.Time, skills SkillExtractor) []Period {
+	current := monthStart(now.Year(), now.Month())
+	runes := []rune(text)
+	byteToRune := runeIndex(text)
+
+	type found struct {
+		period     Period
+		start, end int // Границы строки с датами, в рунах
+	}
+	var ranges []found
+	for _, loc := range rangePattern.FindAllStringSubmatchIndex(text, -1) {
+		// Число перед датой - часть другого числа (телефона, суммы)
+		if loc[0] > 0 && unicode.IsDigit(runes[byteToRune[loc[0]]-1]) {
+			continue
+		}
+		start, ok := parseDate(text[loc[2]:loc[3]], false)
+		if !ok {
+			continue
+		}
+		p := Period{Start: start}
+		endText := text[loc[4]:loc[5]]
+		if presentWord.MatchString(endText) {
+			p.End, p.Current = current, true
+		} else if end, ok := parseDate(endText, true); ok {
+			p.End = end
+		} else {
+			continue
+		}
+		if p.End.After(current) {
+			p.End = current
+		}
+		if p.End.Before(p.Start) {
+			continue
+		}
+		ranges = append(ranges, found{period: p, start: byteToRune[loc[0]], end: byteToRune[loc[1]]})
+	}
+
+	var periods []Period
+	for i, r := range ranges {
+		blockEnd := len(runes)
+		if i+1 < len(ranges) {
+			blockEnd = lineStart(runes, ranges[i+1].start)
+		}
+		blockStart := lineStart(runes, r.start)
+		if blockEnd < r.end {
+			blockEnd = r.end
+		}
+
+		p := r.period
+		p.SpanStart, p.SpanEnd = blockStart, blockEnd
+		before := strings.TrimSpace(string(runes[blockStart:r.start]))
+		after := string(runes[r.end:blockEnd])
+		p.Description = strings.TrimSpace(before + "\n" + after)
+
+		if isEducation(firstLines(p.Description, 3)) {
+			continue
+		}
+		p.Company, p.Title = headings(before, after)
+		if skills != nil {
+			p.Skills = skills(p.Description)
+		}
+		periods = append(periods, p)
+	}
+	return periods
+}
+
+// headings извлекает компанию и должность: текст в строке с датами
+// или первые содержательные строки блока (шаблон hh.ru: компания, затем должность)
+func headings(before, after string) (company, title string) {
+	var lines []string
+	if before = strings.Trim(before, " ,:|\t"); before != "" {
+		lines = append(lines, before)
+	}
+	for _, line := range strings.Split(after, "\n") {
+		line = strings.Trim(line, " ,:|\t•-–—")
+		if line == "" || durationLine.MatchString(line) {
+			continue
+		}
+		lines = append(lines, line)
+		if len(lines) == 2 {
+			break
+		}
+	}
+
+	if len(lines) > 0 {
+		company = truncate(lines[0], 255)
+	}
+	if len(lines) > 1 {
+		title = truncate(lines[1], 255)
+	}
+	return company, title
+}
+
+func firstLines(text string, n int) string {
+	lines := strings.SplitN(text, "\n", n+1)
+	if len(lines) > n {
+		lines = lines[:n]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func lineStart(runes []rune, pos int) int {
+	for pos > 0 && runes[pos-1] != '\n' {
+		pos--
+	}
+	return pos
+}
+
+// runeIndex сопоставляет байтовым смещениям строки смещения в рунах
+func runeIndex(text string) map[int]int {
+	idx := make(map[int]int, len(text)+1)
+	n := 0
+	for i := range text {
+		idx[i] = n
+		n++
+	}
+	idx[len(text)] = n
+	return idx
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+func monthsBetween(a, b time.Time) int {
+	return (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+}
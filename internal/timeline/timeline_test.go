@@ -0,0 +1,173 @@
+package timeline
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC)
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in    string
+		isEnd bool
+		want  time.Time
+		ok    bool
+	}{
+		{"Январь 2019", false, month(2019, time.January), true},
+		{"сентября 2020", false, month(2020, time.September), true},
+		{"Sept. 2018", false, month(2018, time.September), true},
+		{"03.2017", false, month(2017, time.March), true},
+		{"2017-11", false, month(2017, time.November), true},
+		{"2015", false, month(2015, time.January), true},
+		{"2015", true, month(2015, time.December), true},
+		{"13.2017", false, time.Time{}, false},
+		{"1955", false, time.Time{}, false},
+		{"вчера", false, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseDate(tt.in, tt.isEnd)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q, %v) = %v, %v; want %v, %v", tt.in, tt.isEnd, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	type period struct {
+		start, end time.Time
+		current    bool
+		company    string
+		title      string
+	}
+	tests := []struct {
+		name    string
+		text    string
+		periods []period
+		months  int
+		gaps    []Gap
+	}{
+		{
+			name: "шаблон hh.ru с настоящим временем",
+			text: "Опыт работы\nЯнварь 2019 — настоящее время\n5 лет 6 месяцев\nООО Ромашка\nGo-разработчик\nПисал сервисы на Go\n",
+			periods: []period{
+				{month(2019, time.January), month(2024, time.June), true, "ООО Ромашка", "Go-разработчик"},
+			},
+			months: 66,
+		},
+		{
+			name: "пересекающиеся периоды считаются один раз",
+			text: "2015 – 2018 Яндекс\nразработчик\n\nмарт 2017 - 12.2019 Совместительство, Авито\nконсультант\n",
+			periods: []period{
+				{month(2015, time.January), month(2018, time.December), false, "Яндекс", "разработчик"},
+				{month(2017, time.March), month(2019, time.December), false, "Совместительство, Авито", "консультант"},
+			},
+			months: 60,
+		},
+		{
+			name: "перерыв между работами",
+			text: "01.2010 - 12.2011 Банк\n\n06.2012 по 05.2013 Стартап\n",
+			periods: []period{
+				{month(2010, time.January), month(2011, time.December), false, "Банк", ""},
+				{month(2012, time.June), month(2013, time.May), false, "Стартап", ""},
+			},
+			months: 36,
+			gaps:   []Gap{{From: month(2012, time.January), To: month(2012, time.May), Months: 5}},
+		},
+		{
+			name: "учёба по сокращению вуза не считается работой",
+			text: "2010 – 2015 МГУ им. Ломоносова\nмеханико-математический\n\n" +
+				"09.2015 — 06.2017 СПбГУ\nмагистратура\n\n" +
+				"2017 - 2018 НИУ ВШЭ\n\n" +
+				"2018 – 2020 ГБУ «Жилищник»\nинженер\n",
+			periods: []period{
+				{month(2018, time.January), month(2020, time.December), false, "ГБУ «Жилищник»", "инженер"},
+			},
+			months: 36,
+		},
+		{
+			name: "учёба по словам",
+			text: "2008 - 2013 Институт радиотехники\n\n2013 - present Acme Corp\nBackend engineer\n",
+			periods: []period{
+				{month(2013, time.January), month(2024, time.June), true, "Acme Corp", "Backend engineer"},
+			},
+			months: 138,
+		},
+		{
+			name:   "телефон не принимается за период",
+			text:   "Телефон: +7 999 1234-2015\nДата рождения 1990\n",
+			months: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Extract(tt.text, now, nil)
+			var periods []period
+			for _, p := range got.Periods {
+				periods = append(periods, period{p.Start, p.End, p.Current, p.Company, p.Title})
+			}
+			if !reflect.DeepEqual(periods, tt.periods) {
+				t.Errorf("periods =\n%+v\nwant\n%+v", periods, tt.periods)
+			}
+			if got.TotalMonths != tt.months {
+				t.Errorf("TotalMonths = %d, want %d", got.TotalMonths, tt.months)
+			}
+			if !reflect.DeepEqual(got.Gaps, tt.gaps) {
+				t.Errorf("Gaps = %+v, want %+v", got.Gaps, tt.gaps)
+			}
+		})
+	}
+}
+
+func TestExtractSkills(t *testing.T) {
+	text := "2016 - 2018 Компания А\nJava, PostgreSQL\n\n2018 - 2020 Компания Б\nGo, PostgreSQL\n"
+	extract := func(text string) []string {
+		var out []string
+		for _, s := range []string{"Go", "Java", "PostgreSQL"} {
+			if strings.Contains(text, s) {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	got := Extract(text, now, extract)
+
+	// Смежные периоды сливаются: PostgreSQL - пять лет без разрыва
+	want := map[string]int{"Java": 36, "PostgreSQL": 60, "Go": 36}
+	if !reflect.DeepEqual(got.SkillMonths, want) {
+		t.Errorf("SkillMonths = %v, want %v", got.SkillMonths, want)
+	}
+	if last, years, ok := got.SkillUsed("Java"); !ok || !last.Equal(month(2018, time.December)) || years != 3 {
+		t.Errorf("SkillUsed(Java) = %v, %v, %v", last, years, ok)
+	}
+	if got.Years() != 5 {
+		t.Errorf("Years = %v, want 5", got.Years())
+	}
+}
+
+func TestIsEducation(t *testing.T) {
+	tests := map[string]bool{
+		"МГУ им. Ломоносова":           true,
+		"СПбГУ, прикладная математика": true,
+		"МГТУ им. Баумана":             true,
+		"МФТИ":                         true,
+		"НИУ ВШЭ, бизнес-информатика":  true,
+		"Окончил вуз с отличием":       true,
+		"Stanford University":          true,
+		"ООО Ромашка, разработчик":     false,
+		"ГБУ «Жилищник», инженер":      false,
+		"Курьер в службе доставки Агу": false,
+		"ПАО ВТБ, аналитик":            false,
+	}
+	for text, want := range tests {
+		if got := isEducation(text); got != want {
+			t.Errorf("isEducation(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
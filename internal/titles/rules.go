@@ -0,0 +1,75 @@
+package titles
+
+import (
+	"regexp"
+	"strings"
+)
+
+// levelRules признаки уровня в названии должности. Порядок важен:
+// "Senior Team Lead" - это lead, "Руководитель группы" - lead, а не head.
+var levelRules = []struct {
+	level   Level
+	pattern *regexp.Regexp
+}{
+	{LevelHead, keywords("head", "руководитель отдела", "руководитель направления", "руководитель департамента",
+		"руководитель управления", "руководитель службы", "начальник*", "директор*", "director", "cto", "cio", "vp")},
+	{LevelLead, keywords("team lead", "teamlead", "tech lead", "techlead", "тимлид", "техлид", "лид", "lead",
+		"руководитель группы", "руководитель команды", "главный", "principal", "staff", "архитектор", "architect")},
+	{LevelIntern, keywords("стажер*", "intern", "internship", "trainee", "практикант*")},
+	{LevelJunior, keywords("junior", "jr", "младший", "джуниор", "начинающий", "entry level")},
+	{LevelSenior, keywords("senior", "sr", "старший", "ведущий", "сеньор", "синьор")},
+	{LevelMiddle, keywords("middle", "mid", "мидл")},
+}
+
+// familyRules признаки направления. Более узкие направления проверяются
+// раньше общих: "Data Engineer" - не просто инженер, "Программист 1С" - 1c.
+var familyRules = []struct {
+	family  string
+	pattern *regexp.Regexp
+}{
+	{FamilyFullstack, keywords("fullstack", "full stack", "фулстек", "фуллстек")},
+	{FamilyDataScience, keywords("data scien*", "machine learning", "ml", "дата сайентист", "нейросет*", "computer vision", "nlp")},
+	{FamilyDataEngineering, keywords("data engineer*", "инженер данных", "etl", "dwh", "big data")},
+	{FamilyDevOps, keywords("devops", "девопс", "sre", "site reliability", "platform engineer")},
+	{FamilyQA, keywords("qa", "aqa", "sdet", "тестировщик*", "тестирован*", "tester", "test", "quality assurance")},
+	{FamilySecurity, keywords("security", "безопасност*", "пентест*", "pentest*")},
+	{FamilyDBA, keywords("dba", "администратор баз данных", "database administrator")},
+	{FamilyMobile, keywords("mobile", "мобильн*", "ios", "android", "flutter")},
+	{FamilyFrontend, keywords("frontend", "front end", "фронтенд*", "фронтэнд*", "верстальщик")},
+	{FamilyBackend, keywords("backend", "back end", "бэкенд*", "бекенд*", "бэк", "server side")},
+	{FamilyAnalyst, keywords("аналитик*", "analyst")},
+	{FamilyProduct, keywords("product manager", "product owner", "продакт*", "менеджер продукт*", "владелец продукта")},
+	{FamilyProject, keywords("project manager", "руководитель проект*", "менеджер проект*", "pm", "scrum master", "delivery manager")},
+	{FamilyDesigner, keywords("дизайнер*", "designer", "ux", "ui")},
+	{Family1C, keywords("1с", "1c")},
+	{FamilySysadmin, keywords("системный администратор", "сисадмин", "system administrator", "sysadmin", "сетевой инженер", "network engineer")},
+	{FamilySupport, keywords("поддержк*", "техподдержк*", "support", "helpdesk", "service desk")},
+	{FamilySoftware, keywords("разработчик*", "разработк*", "программист*", "developer", "dev", "software engineer", "инженер*", "engineer")},
+}
+
+// stackHints уточняют общее направление по технологии в названии: "Tech Lead Go", "Java developer"
+var stackHints = []struct {
+	family  string
+	pattern *regexp.Regexp
+}{
+	{FamilyMobile, keywords("swift", "kotlin", "objective-c")},
+	{FamilyFrontend, keywords("react", "vue", "angular", "javascript", "typescript", "js")},
+	{FamilyBackend, keywords("go", "golang", "java", "python", "php", "c#", ".net", "ruby", "scala", "node.js", "nodejs", "c++", "rust")},
+}
+
+// keywords строит шаблон, совпадающий с любым из слов целиком.
+// Звёздочка в конце слова разрешает любое окончание: "мобильн*".
+// Границы слов проверяются вручную: \b в Go не учитывает кириллицу.
+func keywords(words ...string) *regexp.Regexp {
+	alts := make([]string, len(words))
+	for i, w := range words {
+		prefix := strings.HasSuffix(w, "*")
+		w = regexp.QuoteMeta(strings.TrimSuffix(w, "*"))
+		w = strings.ReplaceAll(w, " ", `[\s\-]+`)
+		if prefix {
+			w += `\pL*`
+		}
+		alts[i] = w
+	}
+	return regexp.MustCompile(`(?:^|[^\pL\pN+#.])(?:` + strings.Join(alts, "|") + `)(?:$|[^\pL\pN+#])`)
+}
@@ -0,0 +1,154 @@
+package titles
+
+import (
+	"github.com/moverq1337/VTBHack/internal/textproc"
+	"github.com/moverq1337/VTBHack/internal/timeline"
+)
+
+// Level уровень специалиста
+type Level string
+
+const (
+	LevelIntern Level = "intern"
+	LevelJunior Level = "junior"
+	LevelMiddle Level = "middle"
+	LevelSenior Level = "senior"
+	LevelLead   Level = "lead"
+	LevelHead   Level = "head"
+)
+
+// levels уровни по возрастанию
+var levels = []Level{LevelIntern, LevelJunior, LevelMiddle, LevelSenior, LevelLead, LevelHead}
+
+// Направления (семейства ролей)
+const (
+	FamilySoftware        = "software_engineer"
+	FamilyBackend         = "backend"
+	FamilyFrontend        = "frontend"
+	FamilyFullstack       = "fullstack"
+	FamilyMobile          = "mobile"
+	FamilyDevOps          = "devops"
+	FamilyQA              = "qa"
+	FamilyDataScience     = "data_science"
+	FamilyDataEngineering = "data_engineering"
+	FamilyAnalyst         = "analyst"
+	FamilyProject         = "project_manager"
+	FamilyProduct         = "product_manager"
+	FamilyDesigner        = "designer"
+	FamilySysadmin        = "sysadmin"
+	FamilyDBA             = "dba"
+	FamilySecurity        = "security"
+	FamilySupport         = "support"
+	Family1C              = "1c"
+)
+
+// familyNames названия направлений для пояснений
+var familyNames = map[string]string{
+	FamilySoftware:        "Разработка ПО",
+	FamilyBackend:         "Backend-разработка",
+	FamilyFrontend:        "Frontend-разработка",
+	FamilyFullstack:       "Fullstack-разработка",
+	FamilyMobile:          "Мобильная разработка",
+	FamilyDevOps:          "DevOps",
+	FamilyQA:              "Тестирование",
+	FamilyDataScience:     "Data Science",
+	FamilyDataEngineering: "Инженерия данных",
+	FamilyAnalyst:         "Аналитика",
+	FamilyProject:         "Управление проектами",
+	FamilyProduct:         "Управление продуктом",
+	FamilyDesigner:        "Дизайн",
+	FamilySysadmin:        "Системное администрирование",
+	FamilyDBA:             "Администрирование БД",
+	FamilySecurity:        "Информационная безопасность",
+	FamilySupport:         "Техническая поддержка",
+	Family1C:              "1С",
+}
+
+// Title нормализованная должность. Пустые поля - направление или уровень не распознаны.
+type Title struct {
+	Family    string `json:"family"`
+	Seniority Level  `json:"seniority"`
+}
+
+// Known сообщает, что распознано направление или уровень
+func (t Title) Known() bool {
+	return t.Family != "" || t.Seniority != ""
+}
+
+// Normalize определяет направление и уровень по названию должности:
+// "Ведущий инженер-программист" - software_engineer/senior,
+// "Senior Backend Dev" - backend/senior, "Tech Lead Go" - backend/lead
+func Normalize(title string) Title {
+	key := textproc.Normalize(title)
+	var t Title
+
+	for _, rule := range levelRules {
+		if rule.pattern.MatchString(key) {
+			t.Seniority = rule.level
+			break
+		}
+	}
+	for _, rule := range familyRules {
+		if rule.pattern.MatchString(key) {
+			t.Family = rule.family
+			break
+		}
+	}
+	if t.Family == FamilySoftware || (t.Family == "" && t.Seniority != "") {
+		for _, hint := range stackHints {
+			if hint.pattern.MatchString(key) {
+				t.Family = hint.family
+				break
+			}
+		}
+	}
+	return t
+}
+
+// ForPeriod нормализует должность периода работы. В шаблоне hh.ru должность
+// идёт после компании, в других резюме порядок бывает обратным, поэтому
+// при нераспознанной должности проверяется строка компании.
+func ForPeriod(p timeline.Period) Title {
+	if t := Normalize(p.Title); t.Known() {
+		return t
+	}
+	return Normalize(p.Company)
+}
+
+// FamilyName название направления; пустая строка для неизвестного
+func FamilyName(family string) string {
+	return familyNames[family]
+}
+
+// ParseLevel проверяет название уровня
+func ParseLevel(s string) (Level, bool) {
+	for _, l := range levels {
+		if string(l) == s {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// Rank порядковый номер уровня от 0 (intern) до 5 (head); -1 для неизвестного
+func (l Level) Rank() int {
+	for i, v := range levels {
+		if v == l {
+			return i
+		}
+	}
+	return -1
+}
+
+// LevelForYears оценивает уровень по стажу, когда в должности он не указан.
+// Стажёра по стажу не отличить от junior, поэтому нижний уровень - junior.
+func LevelForYears(years float64) Level {
+	switch {
+	case years < 3:
+		return LevelJunior
+	case years < 6:
+		return LevelMiddle
+	default:
+		return LevelSenior
+	}
+}
@@ -0,0 +1,550 @@
+// Package vectors хранит векторы резюме и вакансий: строки в Postgres
+// и индексы HNSW в памяти процесса для поиска похожих.
+package vectors
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/ann"
+	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/pb"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var log = logrus.New()
+
+// Виды векторов: у резюме и вакансий отдельные индексы
+const (
+	KindResume  = "resume"
+	KindVacancy = "vacancy"
+)
+
+var kinds = []string{KindResume, KindVacancy}
+
+const (
+	// embedBatch текстов в одном вызове Embed при дозаполнении
+	embedBatch = 32
+	// embedTimeout на вычисление вектора одного документа в фоне
+	embedTimeout = 30 * time.Second
+	// syncOverlap запас при догрузке строк по updated_at: строка, записанная
+	// другим процессом чуть раньше отметки, но позже её видимая, не теряется
+	syncOverlap = time.Minute
+	// compactThreshold доля удалённых узлов, после которой граф перестраивается
+	compactThreshold = 0.3
+)
+
+// Embedder вычисляет векторы текстов; его реализует nlp.Client
+type Embedder interface {
+	Embed(ctx context.Context, in *pb.EmbedRequest, opts ...grpc.CallOption) (*pb.EmbedResponse, error)
+}
+
+// Store векторы в Postgres и индексы в памяти. Индексы сохраняются в
+// каталог dir и при старте читаются оттуда; строки, изменённые после
+// сохранения, догружаются из БД. Без файла, при повреждённом файле или
+// смене модели индекс строится из БД заново.
+type Store struct {
+	db       *gorm.DB
+	embedder Embedder
+	dir      string
+
+	mu       sync.RWMutex
+	model    string
+	indexes  map[string]*ann.Index
+	synced   time.Time // Отметка updated_at, до которой строки БД учтены в индексах
+	dirty    bool
+	building sync.Mutex // Одно дозаполнение за раз
+}
+
+// Open загружает индексы из каталога dir и сверяет их с БД
+func Open(db *gorm.DB, embedder Embedder, dir string) (*Store, error) {
+	s := &Store{db: db, embedder: embedder, dir: dir, indexes: map[string]*ann.Index{}}
+
+	var latest models.Embedding
+	err := db.Order("updated_at DESC").Limit(1).Find(&latest).Error
+	if err != nil {
+		return nil, fmt.Errorf("чтение векторов: %w", err)
+	}
+	s.model = latest.Model
+
+	if s.load() {
+		if err := s.Sync(); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	log.Info("Индексы векторов строятся из БД")
+	if err := s.rebuild(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load читает индексы из файлов; false - хотя бы один файл непригоден
+func (s *Store) load() bool {
+	if s.model == "" {
+		return false
+	}
+	var synced time.Time
+	for i, kind := range kinds {
+		index, model, savedAt, err := ann.Load(s.path(kind))
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				log.WithError(err).Warn("Файл индекса векторов повреждён")
+			}
+			return false
+		}
+		if model != s.model {
+			log.Warnf("Индекс %s посчитан моделью %s, текущая %s", kind, model, s.model)
+			return false
+		}
+		if i == 0 || savedAt.Before(synced) {
+			synced = savedAt
+		}
+		// Пустой индекс сохраняется без размерности: вид без векторов
+		// начинается заново с первого добавленного
+		if index.Dim() == 0 {
+			continue
+		}
+		s.indexes[kind] = index
+	}
+	s.synced = synced
+	return true
+}
+
+// rebuild строит индексы заново по всем строкам текущей модели
+func (s *Store) rebuild() error {
+	s.mu.Lock()
+	s.indexes = map[string]*ann.Index{}
+	s.synced = time.Time{}
+	s.mu.Unlock()
+	if s.model == "" {
+		return nil
+	}
+	return s.Sync()
+}
+
+// Sync добавляет в индексы строки, изменённые после прошлой сверки, в том
+// числе записанные другими процессами
+func (s *Store) Sync() error {
+	s.mu.RLock()
+	model, last := s.model, s.synced
+	s.mu.RUnlock()
+	if model == "" {
+		return nil
+	}
+
+	tx := s.db.Where("model = ?", model)
+	if !last.IsZero() {
+		tx = tx.Where("updated_at > ?", last.Add(-syncOverlap))
+	}
+	// Строки читаются по одной: при перестройке их сотни тысяч
+	rows, err := tx.Model(&models.Embedding{}).Rows()
+	if err != nil {
+		return fmt.Errorf("чтение векторов: %w", err)
+	}
+	defer rows.Close()
+
+	synced := last
+	for rows.Next() {
+		var row models.Embedding
+		if err := s.db.ScanRows(rows, &row); err != nil {
+			return fmt.Errorf("чтение векторов: %w", err)
+		}
+		s.add(row.OwnerType, row.OwnerID, Decode(row.Vector))
+		if row.UpdatedAt.After(synced) {
+			synced = row.UpdatedAt
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("чтение векторов: %w", err)
+	}
+
+	s.mu.Lock()
+	if s.model == model && synced.After(s.synced) {
+		s.synced = synced
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// add кладёт вектор в индекс; совпадающий с уже добавленным пропускается,
+// чтобы повторная сверка не плодила удалённые узлы
+func (s *Store) add(kind string, id uuid.UUID, vec []float32) {
+	if len(vec) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := s.indexes[kind]
+	if index == nil {
+		index = ann.New(len(vec))
+		s.indexes[kind] = index
+	}
+	if old, ok := index.Vector(id); ok && sameDirection(old, vec) {
+		return
+	}
+	if index.Add(id, vec) {
+		s.dirty = true
+	}
+}
+
+// Put вычисляет вектор документа и сохраняет его в БД и индекс
+func (s *Store) Put(ctx context.Context, kind string, id uuid.UUID, text string) error {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	resp, err := s.embedder.Embed(ctx, &pb.EmbedRequest{Texts: []string{text}})
+	if err != nil {
+		return err
+	}
+	if len(resp.Embeddings) != 1 {
+		return fmt.Errorf("NLP-сервис вернул %d векторов вместо 1", len(resp.Embeddings))
+	}
+	return s.store(resp.Model, []models.Embedding{{
+		OwnerType: kind,
+		OwnerID:   id,
+		Vector:    encode(resp.Embeddings[0].Values),
+	}})
+}
+
+// Refresh пересчитывает вектор документа в фоне: загрузка и
+// редактирование не ждут NLP-сервис и не падают из-за него
+func (s *Store) Refresh(kind string, id uuid.UUID, text string) {
+	if s == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), embedTimeout)
+		defer cancel()
+		if err := s.Put(ctx, kind, id, text); err != nil {
+			log.WithError(err).Warnf("Не удалось вычислить вектор %s %s", kind, id)
+		}
+	}()
+}
+
+// Remove удаляет вектор документа из БД и индекса
+func (s *Store) Remove(kind string, id uuid.UUID) {
+	if s == nil {
+		return
+	}
+	if err := s.db.Delete(&models.Embedding{}, "owner_type = ? AND owner_id = ?", kind, id).Error; err != nil {
+		log.WithError(err).Warnf("Ошибка удаления вектора %s %s", kind, id)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if index := s.indexes[kind]; index != nil {
+		index.Remove(id)
+		s.dirty = true
+	}
+}
+
+// store сохраняет посчитанные векторы. Если NLP-сервис перешёл на другую
+// модель, прежние векторы несравнимы с новыми: они удаляются, индексы
+// начинаются заново, а Backfill пересчитывает остальные документы.
+func (s *Store) store(model string, rows []models.Embedding) error {
+	s.mu.RLock()
+	current := s.model
+	s.mu.RUnlock()
+
+	if model != current {
+		if current != "" {
+			log.Warnf("Модель векторов сменилась: %s -> %s, векторы пересчитываются", current, model)
+		}
+		if err := s.db.Where("model <> ?", model).Delete(&models.Embedding{}).Error; err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.model = model
+		s.indexes = map[string]*ann.Index{}
+		s.synced = time.Time{}
+		s.dirty = true
+		s.mu.Unlock()
+	}
+
+	for i := range rows {
+		rows[i].Model = model
+		rows[i].UpdatedAt = time.Now()
+	}
+	err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&rows).Error
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		s.add(row.OwnerType, row.OwnerID, Decode(row.Vector))
+	}
+	return nil
+}
+
+// Vector вектор документа из индекса
+func (s *Store) Vector(kind string, id uuid.UUID) ([]float32, bool) {
+	s.mu.RLock()
+	index := s.indexes[kind]
+	s.mu.RUnlock()
+	if index == nil {
+		return nil, false
+	}
+	return index.Vector(id)
+}
+
+// Search ищет k ближайших к вектору документов вида kind; ef - ширина поиска
+func (s *Store) Search(kind string, vec []float32, k, ef int) []ann.Result {
+	s.mu.RLock()
+	index := s.indexes[kind]
+	s.mu.RUnlock()
+	if index == nil {
+		return nil
+	}
+	return index.Search(vec, k, ef)
+}
+
+// Len число векторов вида kind
+func (s *Store) Len(kind string) int {
+	s.mu.RLock()
+	index := s.indexes[kind]
+	s.mu.RUnlock()
+	if index == nil {
+		return 0
+	}
+	return index.Len()
+}
+
+// Save записывает изменённые индексы на диск, перед этим перестраивая
+// графы, в которых накопилось много удалённых узлов
+func (s *Store) Save() error {
+	s.mu.Lock()
+	if !s.dirty || s.model == "" {
+		s.mu.Unlock()
+		return nil
+	}
+	indexes := make(map[string]*ann.Index, len(s.indexes))
+	for kind, index := range s.indexes {
+		indexes[kind] = index
+	}
+	model, synced := s.model, s.synced
+	s.dirty = false
+	s.mu.Unlock()
+
+	for kind, index := range indexes {
+		if compacted, ok := s.compact(kind, index); ok {
+			indexes[kind] = compacted
+		}
+	}
+
+	for _, kind := range kinds {
+		index := indexes[kind]
+		if index == nil {
+			index = ann.New(0)
+		}
+		if err := index.Save(s.path(kind), model, synced); err != nil {
+			s.mu.Lock()
+			s.dirty = true
+			s.mu.Unlock()
+			return fmt.Errorf("сохранение индекса %s: %w", kind, err)
+		}
+	}
+	return nil
+}
+
+// compact перестраивает граф вне блокировки: поиск и добавление в это время
+// идут по прежнему индексу. Копия подменяет его, только если индекс за это
+// время не менялся; иначе перестройка повторяется при следующем сохранении.
+func (s *Store) compact(kind string, index *ann.Index) (*ann.Index, bool) {
+	if index.Garbage() <= compactThreshold {
+		return nil, false
+	}
+	version := index.Version()
+	compacted := index.Compact()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.indexes[kind] != index || index.Version() != version {
+		s.dirty = true
+		return nil, false
+	}
+	s.indexes[kind] = compacted
+	return compacted, true
+}
+
+// Run сразу и затем раз в interval дозаполняет недостающие векторы,
+// догружает векторы других процессов и сохраняет индексы на диск, пока
+// не отменён ctx
+func (s *Store) Run(ctx context.Context, interval time.Duration) {
+	go s.backfill(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Save(); err != nil {
+				log.WithError(err).Error("Ошибка сохранения индексов векторов")
+			}
+			return
+		case <-ticker.C:
+			if err := s.Sync(); err != nil {
+				log.WithError(err).Error("Ошибка сверки векторов с БД")
+			}
+			if err := s.Save(); err != nil {
+				log.WithError(err).Error("Ошибка сохранения индексов векторов")
+			}
+			go s.backfill(ctx)
+		}
+	}
+}
+
+func (s *Store) backfill(ctx context.Context) {
+	count, err := s.Backfill(ctx)
+	if err != nil {
+		log.WithError(err).Warn("Ошибка вычисления недостающих векторов")
+	}
+	if count > 0 {
+		log.Infof("Вычислено недостающих векторов: %d", count)
+	}
+}
+
+// Backfill вычисляет векторы резюме и вакансий, у которых их нет:
+// загруженных до появления поиска похожих или при недоступном NLP-сервисе.
+// Если дозаполнение уже идёт, сразу возвращает 0.
+func (s *Store) Backfill(ctx context.Context) (int, error) {
+	if !s.building.TryLock() {
+		return 0, nil
+	}
+	defer s.building.Unlock()
+
+	total := 0
+	for {
+		docs, err := s.missing(embedBatch)
+		if err != nil || len(docs) == 0 {
+			return total, err
+		}
+
+		texts := make([]string, len(docs))
+		for i, d := range docs {
+			texts[i] = d.text
+		}
+		callCtx, cancel := context.WithTimeout(ctx, embedTimeout)
+		resp, err := s.embedder.Embed(callCtx, &pb.EmbedRequest{Texts: texts})
+		cancel()
+		if err != nil {
+			return total, err
+		}
+		if len(resp.Embeddings) != len(docs) {
+			return total, fmt.Errorf("NLP-сервис вернул %d векторов вместо %d", len(resp.Embeddings), len(docs))
+		}
+
+		rows := make([]models.Embedding, len(docs))
+		for i, d := range docs {
+			rows[i] = models.Embedding{OwnerType: d.kind, OwnerID: d.id, Vector: encode(resp.Embeddings[i].Values)}
+		}
+		if err := s.store(resp.Model, rows); err != nil {
+			return total, err
+		}
+		total += len(rows)
+	}
+}
+
+// document текст резюме или вакансии без вектора
+type document struct {
+	kind string
+	id   uuid.UUID
+	text string
+}
+
+// missing до limit документов без вектора текущей модели, сначала резюме.
+// Документы без текста пропускаются: у них вектора не будет.
+func (s *Store) missing(limit int) ([]document, error) {
+	s.mu.RLock()
+	model := s.model
+	s.mu.RUnlock()
+	has := func(kind, table string) *gorm.DB {
+		return s.db.Model(&models.Embedding{}).Select("1").
+			Where("owner_type = ? AND owner_id = "+table+".id AND model = ?", kind, model)
+	}
+
+	var resumes []models.Resume
+	err := s.db.Select("id", "text").
+		Where("coalesce(text, '') <> '' AND NOT EXISTS (?)", has(KindResume, "resumes")).
+		Limit(limit).Find(&resumes).Error
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]document, 0, limit)
+	for _, r := range resumes {
+		docs = append(docs, document{KindResume, r.ID, ResumeText(r)})
+	}
+	if len(docs) == limit {
+		return docs, nil
+	}
+
+	var vacancies []models.Vacancy
+	err = s.db.Where("concat(title, requirements, responsibilities, skills) <> '' AND NOT EXISTS (?)", has(KindVacancy, "vacancies")).
+		Limit(limit - len(docs)).Find(&vacancies).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range vacancies {
+		docs = append(docs, document{KindVacancy, v.ID, VacancyText(v)})
+	}
+	return docs, nil
+}
+
+// ResumeText текст резюме, по которому считается вектор
+func ResumeText(r models.Resume) string {
+	return r.Text
+}
+
+// VacancyText текст вакансии, по которому считается вектор
+func VacancyText(v models.Vacancy) string {
+	return strings.TrimSpace(strings.Join([]string{v.Title, v.Requirements, v.Responsibilities, v.Skills}, "\n"))
+}
+
+func (s *Store) path(kind string) string {
+	return filepath.Join(s.dir, kind+".hnsw")
+}
+
+// encode упаковывает вектор в bytea: float32 little-endian подряд
+func encode(vec []float32) []byte {
+	out := make([]byte, 4*len(vec))
+	for i, f := range vec {
+		binary.LittleEndian.PutUint32(out[4*i:], math.Float32bits(f))
+	}
+	return out
+}
+
+// Decode распаковывает вектор из колонки embeddings.vector
+func Decode(data []byte) []float32 {
+	out := make([]float32, len(data)/4)
+	for i := range out {
+		out[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return out
+}
+
+// sameDirection совпадают ли векторы с точностью до нормы
+func sameDirection(a, b []float32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	return na > 0 && nb > 0 && dot/math.Sqrt(na*nb) > 1-1e-6
+}
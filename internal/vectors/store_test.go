@@ -0,0 +1,80 @@
+package vectors
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moverq1337/VTBHack/internal/ann"
+)
+
+func TestSaveLoadEmptyKind(t *testing.T) {
+	dir := t.TempDir()
+	resume := uuid.New()
+
+	// Векторы есть только у резюме: индекс вакансий сохраняется пустым
+	s := &Store{dir: dir, model: "model-a", indexes: map[string]*ann.Index{}}
+	s.add(KindResume, resume, []float32{1, 0, 0})
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := &Store{dir: dir, model: "model-a", indexes: map[string]*ann.Index{}}
+	if !loaded.load() {
+		t.Fatal("сохранённые индексы не загрузились")
+	}
+	if got := loaded.Len(KindResume); got != 1 {
+		t.Errorf("резюме после загрузки: %d, want 1", got)
+	}
+
+	// Первый вектор вакансии после перезапуска попадает в индекс
+	vacancy := uuid.New()
+	loaded.add(KindVacancy, vacancy, []float32{0, 1, 0})
+	if got := loaded.Len(KindVacancy); got != 1 {
+		t.Fatalf("вакансии после добавления: %d, want 1", got)
+	}
+	res := loaded.Search(KindVacancy, []float32{0, 1, 0}, 1, 0)
+	if len(res) != 1 || res[0].ID != vacancy {
+		t.Errorf("Search = %+v, want %s", res, vacancy)
+	}
+}
+
+func TestSaveCompacts(t *testing.T) {
+	s := &Store{dir: t.TempDir(), model: "model-a", indexes: map[string]*ann.Index{}}
+	ids := make([]uuid.UUID, 10)
+	for i := range ids {
+		ids[i] = uuid.New()
+		vec := make([]float32, 4)
+		vec[i%4] = float32(i + 1)
+		s.add(KindResume, ids[i], vec)
+	}
+	for _, id := range ids[:5] {
+		s.indexes[KindResume].Remove(id)
+	}
+	before := s.indexes[KindResume]
+
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	after := s.indexes[KindResume]
+	if after == before || after.Garbage() != 0 || after.Len() != 5 {
+		t.Errorf("индекс не перестроен: garbage %v, len %d", after.Garbage(), after.Len())
+	}
+}
+
+func TestCompactSkipsChangedIndex(t *testing.T) {
+	s := &Store{model: "model-a", indexes: map[string]*ann.Index{}}
+	a, b := uuid.New(), uuid.New()
+	s.add(KindResume, a, []float32{1, 0})
+	s.add(KindResume, b, []float32{0, 1})
+	index := s.indexes[KindResume]
+	index.Remove(a)
+
+	// Индекс сменился, пока строилась копия: копия не подменяет его
+	s.indexes[KindResume] = ann.New(2)
+	if _, ok := s.compact(KindResume, index); ok {
+		t.Error("копия подменила индекс, который сменился")
+	}
+	if !s.dirty {
+		t.Error("после пропущенной перестройки индексы не помечены изменёнными")
+	}
+}
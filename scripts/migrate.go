@@ -6,8 +6,14 @@ import (
 	"github.com/moverq1337/VTBHack/internal/config"
 	"github.com/moverq1337/VTBHack/internal/db"
 	"github.com/moverq1337/VTBHack/internal/models"
+	"github.com/moverq1337/VTBHack/internal/search"
+	"gorm.io/gorm"
 )
 
+// migrateLock ключ advisory-блокировки: миграции запускают и api-gateway, и
+// resume-service, и при одновременном старте они не должны пересекаться
+const migrateLock = 7234001
+
 func Migrate() {
 	cfg, err := config.Load()
 	if err != nil {
@@ -19,16 +25,90 @@ func Migrate() {
 		log.Fatal(err)
 	}
 
+	err = dbConn.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrateLock).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrateLock)
+		return migrate(conn)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Println("Миграции завершены")
+}
+
+// migrate создаёт и обновляет схему на одном соединении с блокировкой
+func migrate(dbConn *gorm.DB) error {
 	// Автомиграция всех моделей
-	err = dbConn.AutoMigrate(
+	err := dbConn.AutoMigrate(
 		&models.Vacancy{},
 		&models.Resume{},
+		&models.Candidate{},
 		&models.AnalysisResult{},
 		&models.AnalysisDetail{}, // ← ДОБАВЬТЕ ЭТУ СТРОКУ
+		&models.Skill{},
+		&models.SkillAlias{},
+		&models.SkillRelation{},
+		&models.WorkExperience{},
+		&models.CurrencyRate{},
+		&models.SavedQuery{},
+		&models.Embedding{},
+		&models.ClusterRun{},
+		&models.Cluster{},
+		&models.ClusterMember{},
+		&models.MatchRun{},
+		&models.MatchRunVacancy{},
+		&models.MatchCell{},
+		&models.InterviewResult{},
+		&models.LearningResource{},
+		&models.Employee{},
+		&models.EmployeeMatch{},
 	)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	log.Println("Миграции завершены")
+	// Почта и телефон кандидата уникальны. У дублей, созданных до индексов,
+	// значение остаётся у самой ранней записи, в контактах оно сохраняется
+	for _, column := range []string{"email", "phone"} {
+		statements := []string{
+			`UPDATE candidates c SET ` + column + ` = '' WHERE c.` + column + ` <> '' AND EXISTS (
+				SELECT 1 FROM candidates o WHERE o.` + column + ` = c.` + column + ` AND (o.created_at, o.id) < (c.created_at, c.id))`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_candidates_` + column + `_unique ON candidates (` + column + `) WHERE ` + column + ` <> ''`,
+		}
+		for _, stmt := range statements {
+			if err := dbConn.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	// Архив у всех сущностей - archived_at: архивные вакансии раньше
+	// отмечались статусом. updated_at появился у резюме и анализов позже
+	// создания таблиц, у старых строк он пуст и ломает сортировку списков.
+	for _, stmt := range []string{
+		`UPDATE vacancies SET archived_at = COALESCE(updated_at, created_at, now()), status = 'draft' WHERE status = 'archived'`,
+		`UPDATE vacancies SET updated_at = created_at WHERE updated_at IS NULL`,
+		`UPDATE resumes SET updated_at = created_at WHERE updated_at IS NULL`,
+		`UPDATE analysis_results SET updated_at = created_at WHERE updated_at IS NULL`,
+	} {
+		if err := dbConn.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	// Итоговая оценка анализов хранилась в match_score: переносим её в score,
+	// а в match_score возвращаем сходство NLP-сервиса из обоснования
+	if err := dbConn.Exec(`UPDATE analysis_results SET score = match_score,
+		match_score = COALESCE((SELECT (c->>'score')::numeric FROM jsonb_array_elements(
+			CASE WHEN jsonb_typeof(explanation->'criteria') = 'array' THEN explanation->'criteria' ELSE '[]'::jsonb END) c
+			WHERE c->>'category' = 'semantic' LIMIT 1), match_score)
+		WHERE score IS NULL`).Error; err != nil {
+		return err
+	}
+
+	// Полнотекстовый поиск по резюме: вычисляемые tsvector-колонки и индексы
+	return search.Migrate(dbConn)
 }